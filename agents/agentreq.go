@@ -83,7 +83,7 @@ type AgentRequest struct {
 	filterS *engine.FilterS
 	Header  config.DataProvider
 	Trailer config.DataProvider
-	diamreq *config.NavigableMap // used in case of building requests (ie. DisconnectSession)
+	diamreq *config.NavigableMap // used in case of building requests (ie. DisconnectSession), Diameter or RADIUS
 	tmp     *config.NavigableMap // used in case you want to store temporary items and access them later
 }
 
@@ -110,7 +110,7 @@ func (ar *AgentRequest) FieldAsInterface(fldPath []string) (val interface{}, err
 		val, err = ar.CGRRequest.GetField(fldPath[1:])
 	case utils.MetaCgrep:
 		val, err = ar.CGRReply.GetField(fldPath[1:])
-	case utils.MetaDiamreq:
+	case utils.MetaDiamreq, utils.MetaRadDAReq:
 		val, err = ar.diamreq.FieldAsInterface(fldPath[1:])
 	case utils.MetaRep:
 		val, err = ar.Reply.GetField(fldPath[1:])
@@ -173,7 +173,7 @@ func (ar *AgentRequest) SetFields(tplFlds []*config.FCTemplate) (err error) {
 				ar.CGRReply.Remove(fldPath[1:])
 			case utils.MetaRep:
 				ar.Reply.Remove(fldPath[1:])
-			case utils.MetaDiamreq:
+			case utils.MetaDiamreq, utils.MetaRadDAReq:
 				ar.diamreq.Remove(fldPath[1:])
 			case utils.MetaTmp:
 				ar.tmp.Remove(fldPath[1:])
@@ -193,7 +193,7 @@ func (ar *AgentRequest) SetFields(tplFlds []*config.FCTemplate) (err error) {
 				ar.CGRReply.RemoveAll()
 			case utils.MetaRep:
 				ar.Reply.RemoveAll()
-			case utils.MetaDiamreq:
+			case utils.MetaDiamreq, utils.MetaRadDAReq:
 				ar.diamreq.RemoveAll()
 			case utils.MetaTmp:
 				ar.tmp.RemoveAll()
@@ -245,7 +245,7 @@ func (ar *AgentRequest) SetFields(tplFlds []*config.FCTemplate) (err error) {
 				ar.CGRReply.Set(fldPath[1:], valSet, false, true)
 			case utils.MetaRep:
 				ar.Reply.Set(fldPath[1:], valSet, false, true)
-			case utils.MetaDiamreq:
+			case utils.MetaDiamreq, utils.MetaRadDAReq:
 				ar.diamreq.Set(fldPath[1:], valSet, false, true)
 			case utils.MetaTmp:
 				ar.tmp.Set(fldPath[1:], valSet, false, true)
@@ -19,6 +19,7 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package agents
 
 import (
+	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"io/ioutil"
@@ -43,7 +44,10 @@ func newHADataProvider(reqPayload string,
 		return newHTTPUrlDP(req)
 	case utils.MetaXml:
 		return newHTTPXmlDP(req)
-
+	case utils.MetaJSON:
+		return newHTTPJSONDP(req)
+	case utils.MetaSIP:
+		return newHTTPSIPDP(req)
 	}
 }
 
@@ -199,6 +203,196 @@ func (hU *httpXmlDP) RemoteHost() net.Addr {
 	return utils.NewNetAddr("TCP", hU.addr)
 }
 
+func newHTTPJSONDP(req *http.Request) (dP config.DataProvider, err error) {
+	byteData, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	data := make(map[string]interface{})
+	if err = json.Unmarshal(byteData, &data); err != nil {
+		return nil, err
+	}
+	dP = &httpJSONDP{body: byteData, nM: config.NewNavigableMap(data), addr: req.RemoteAddr}
+	return
+}
+
+// httpJSONDP implements engine.DataProvider, serving as json data decoder
+// the body is decoded once and navigated as a NavigableMap, ie: a.b[0].c
+type httpJSONDP struct {
+	body []byte
+	nM   *config.NavigableMap
+	addr string
+}
+
+// String is part of engine.DataProvider interface
+func (hJ *httpJSONDP) String() string {
+	return string(hJ.body)
+}
+
+// FieldAsInterface is part of engine.DataProvider interface
+func (hJ *httpJSONDP) FieldAsInterface(fldPath []string) (data interface{}, err error) {
+	if data, err = hJ.nM.FieldAsInterface(fldPath); err == utils.ErrNotFound {
+		err = nil // keep the same behavior as the other http decoders
+	}
+	return
+}
+
+// FieldAsString is part of engine.DataProvider interface
+func (hJ *httpJSONDP) FieldAsString(fldPath []string) (data string, err error) {
+	var valIface interface{}
+	valIface, err = hJ.FieldAsInterface(fldPath)
+	if err != nil {
+		return
+	}
+	return utils.IfaceAsString(valIface), nil
+}
+
+// AsNavigableMap is part of engine.DataProvider interface
+func (hJ *httpJSONDP) AsNavigableMap([]*config.FCTemplate) (
+	nm *config.NavigableMap, err error) {
+	return nil, utils.ErrNotImplemented
+}
+
+// RemoteHost is part of engine.DataProvider interface
+func (hJ *httpJSONDP) RemoteHost() net.Addr {
+	return utils.NewNetAddr("TCP", hJ.addr)
+}
+
+// sipCompactHeaders maps the SIP compact header names to their long form
+var sipCompactHeaders = map[string]string{
+	"c": "content-type",
+	"e": "content-encoding",
+	"f": "from",
+	"i": "call-id",
+	"k": "supported",
+	"l": "content-length",
+	"m": "contact",
+	"s": "subject",
+	"t": "to",
+	"v": "via",
+}
+
+// parseSIPMessage returns the start line fields and the headers (lower case names) of the SIP message
+// only the first value is considered for repeated headers
+func parseSIPMessage(msg string) (hdrs map[string]string, err error) {
+	lines := strings.Split(strings.Replace(msg, "\r\n", "\n", -1), "\n")
+	startLine := strings.SplitN(strings.TrimSpace(lines[0]), " ", 3)
+	if len(startLine) != 3 {
+		return nil, fmt.Errorf("invalid SIP start line: <%s>", lines[0])
+	}
+	hdrs = make(map[string]string)
+	if strings.HasPrefix(startLine[0], "SIP/") { // reply
+		hdrs[strings.ToLower(utils.SIPStatusCode)] = startLine[1]
+		hdrs[strings.ToLower(utils.SIPReasonPhrase)] = startLine[2]
+	} else {
+		hdrs[strings.ToLower(utils.SIPMethod)] = startLine[0]
+		hdrs[strings.ToLower(utils.SIPRequestURI)] = startLine[1]
+	}
+	var lastHdr string
+	for _, line := range lines[1:] {
+		if strings.TrimSpace(line) == utils.EmptyString { // end of headers
+			break
+		}
+		if line[0] == ' ' || line[0] == '\t' { // folded header value
+			if lastHdr != utils.EmptyString {
+				hdrs[lastHdr] += " " + strings.TrimSpace(line)
+			}
+			continue
+		}
+		idx := strings.Index(line, utils.InInFieldSep)
+		if idx == -1 {
+			return nil, fmt.Errorf("invalid SIP header: <%s>", line)
+		}
+		lastHdr = strings.ToLower(strings.TrimSpace(line[:idx]))
+		if longHdr, isCompact := sipCompactHeaders[lastHdr]; isCompact {
+			lastHdr = longHdr
+		}
+		if _, has := hdrs[lastHdr]; has {
+			lastHdr = utils.EmptyString // ignore the repeated headers
+			continue
+		}
+		hdrs[lastHdr] = strings.TrimSpace(line[idx+1:])
+	}
+	return
+}
+
+func newHTTPSIPDP(req *http.Request) (dP config.DataProvider, err error) {
+	byteData, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	var hdrs map[string]string
+	if hdrs, err = parseSIPMessage(string(byteData)); err != nil {
+		return nil, err
+	}
+	dP = &httpSIPDP{body: byteData, hdrs: hdrs,
+		sipURIs: make(map[string]*utils.SIPURI), addr: req.RemoteAddr}
+	return
+}
+
+// httpSIPDP implements engine.DataProvider, serving as SIP message decoder
+// the first path item selects the header (case insensitive) or the start line field
+// the following ones select the SIP URI part, ie: From.user or To.param.tag
+// the SIP URIs are only parsed once and cached
+type httpSIPDP struct {
+	body    []byte
+	hdrs    map[string]string
+	sipURIs map[string]*utils.SIPURI
+	addr    string
+}
+
+// String is part of engine.DataProvider interface
+func (hS *httpSIPDP) String() string {
+	return string(hS.body)
+}
+
+// FieldAsInterface is part of engine.DataProvider interface
+func (hS *httpSIPDP) FieldAsInterface(fldPath []string) (data interface{}, err error) {
+	if len(fldPath) == 0 {
+		return nil, utils.ErrNotFound
+	}
+	hdrName := strings.ToLower(fldPath[0])
+	hdrVal, has := hS.hdrs[hdrName]
+	if !has {
+		return // keep the same behavior as the other http decoders
+	}
+	if len(fldPath) == 1 {
+		return hdrVal, nil
+	}
+	sipURI, has := hS.sipURIs[hdrName]
+	if !has {
+		if sipURI, err = utils.NewSIPURI(hdrVal); err != nil {
+			return
+		}
+		hS.sipURIs[hdrName] = sipURI
+	}
+	if data, err = sipURI.FieldAsString(fldPath[1:]); err == utils.ErrNotFound {
+		data, err = nil, nil
+	}
+	return
+}
+
+// FieldAsString is part of engine.DataProvider interface
+func (hS *httpSIPDP) FieldAsString(fldPath []string) (data string, err error) {
+	var valIface interface{}
+	valIface, err = hS.FieldAsInterface(fldPath)
+	if err != nil {
+		return
+	}
+	return utils.IfaceAsString(valIface), nil
+}
+
+// AsNavigableMap is part of engine.DataProvider interface
+func (hS *httpSIPDP) AsNavigableMap([]*config.FCTemplate) (
+	nm *config.NavigableMap, err error) {
+	return nil, utils.ErrNotImplemented
+}
+
+// RemoteHost is part of engine.DataProvider interface
+func (hS *httpSIPDP) RemoteHost() net.Addr {
+	return utils.NewNetAddr("TCP", hS.addr)
+}
+
 // httpAgentReplyEncoder will encode  []*engine.NMElement
 // and write content to http writer
 type httpAgentReplyEncoder interface {
@@ -215,6 +409,8 @@ func newHAReplyEncoder(encType string,
 		return newHAXMLEncoder(w)
 	case utils.MetaTextPlain:
 		return newHATextPlainEncoder(w)
+	case utils.MetaJSON:
+		return newHAJSONEncoder(w)
 	}
 }
 
@@ -279,3 +475,54 @@ func (xE *haTextPlainEncoder) Encode(nM *config.NavigableMap) (err error) {
 	_, err = xE.w.Write([]byte(str))
 	return
 }
+
+func newHAJSONEncoder(w http.ResponseWriter) (jE httpAgentReplyEncoder, err error) {
+	return &haJSONEncoder{w: w}, nil
+}
+
+type haJSONEncoder struct {
+	w http.ResponseWriter
+}
+
+// Encode implements httpAgentReplyEncoder
+// the path of the items builds the nested objects
+// while multiple items on the same path are encoded as array
+func (jE *haJSONEncoder) Encode(nM *config.NavigableMap) (err error) {
+	out := make(map[string]interface{})
+	for _, val := range nM.Values() {
+		nmItms, isNMItems := val.([]*config.NMItem)
+		if !isNMItems {
+			return fmt.Errorf("value: %+v is not []*NMItem", val)
+		}
+		for _, nmItem := range nmItms {
+			if len(nmItem.Path) == 0 {
+				continue
+			}
+			mp := out
+			for _, fld := range nmItem.Path[:len(nmItem.Path)-1] {
+				nextMp, canCast := mp[fld].(map[string]interface{})
+				if !canCast {
+					nextMp = make(map[string]interface{})
+					mp[fld] = nextMp
+				}
+				mp = nextMp
+			}
+			lastFld := nmItem.Path[len(nmItem.Path)-1]
+			switch prevVal := mp[lastFld].(type) {
+			case nil:
+				mp[lastFld] = nmItem.Data
+			case []interface{}:
+				mp[lastFld] = append(prevVal, nmItem.Data)
+			default:
+				mp[lastFld] = []interface{}{prevVal, nmItem.Data}
+			}
+		}
+	}
+	var jsnOut []byte
+	if jsnOut, err = json.Marshal(out); err != nil {
+		return
+	}
+	jE.w.Header().Set("Content-Type", "application/json")
+	_, err = jE.w.Write(jsnOut)
+	return
+}
@@ -22,9 +22,12 @@ import (
 	"bufio"
 	"bytes"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
-	//"github.com/cgrates/cgrates/utils"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
 )
 
 func TestHttpUrlDPFieldAsInterface(t *testing.T) {
@@ -191,3 +194,126 @@ func TestHttpXmlDPFieldAsInterface2(t *testing.T) {
 		t.Errorf("expecting: 0.0225, received: <%s>", data)
 	}
 }
+
+func TestHttpJSONDPFieldAsInterface(t *testing.T) {
+	body := `{"Event":{"Account":"1001","Usage":30,"Legs":[{"Number":"+4986517174963","Seconds":38},{"Number":"+4986517174964"}]}}`
+	req, err := http.NewRequest("POST", "http://localhost:8080/", bytes.NewBuffer([]byte(body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dP, err := newHTTPJSONDP(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err := dP.FieldAsString([]string{"Event", "Account"}); err != nil {
+		t.Error(err)
+	} else if data != "1001" {
+		t.Errorf("expecting: 1001, received: <%s>", data)
+	}
+	if data, err := dP.FieldAsString([]string{"Event", "Usage"}); err != nil {
+		t.Error(err)
+	} else if data != "30" {
+		t.Errorf("expecting: 30, received: <%s>", data)
+	}
+	if data, err := dP.FieldAsString([]string{"Event", "Legs[1]", "Number"}); err != nil {
+		t.Error(err)
+	} else if data != "+4986517174964" {
+		t.Errorf("expecting: +4986517174964, received: <%s>", data)
+	}
+	if data, err := dP.FieldAsString([]string{"Event", "Nonexistent"}); err != nil {
+		t.Error(err)
+	} else if data != "" {
+		t.Errorf("received: <%s>", data)
+	}
+	if dP.String() != body {
+		t.Errorf("expecting: %s, received: %s", body, dP.String())
+	}
+	req, _ = http.NewRequest("POST", "http://localhost:8080/", bytes.NewBuffer([]byte(`{"Event":`)))
+	if _, err := newHTTPJSONDP(req); err == nil {
+		t.Error("expecting error for invalid body")
+	}
+}
+
+func TestHttpSIPDPFieldAsInterface(t *testing.T) {
+	body := "INVITE sip:+4986517174963@127.0.0.1:5060;user=phone SIP/2.0\r\n" +
+		"Via: SIP/2.0/UDP 10.0.0.10:5060;branch=z9hG4bK776asdhds\r\n" +
+		"Via: SIP/2.0/UDP 10.0.0.11:5060;branch=z9hG4bK776asdhdt\r\n" +
+		"f: \"Alice Doe\" <sip:1001@cgrates.org;transport=tcp>;tag=1928301774\r\n" +
+		"To: <sip:+4986517174963@cgrates.org>\r\n" +
+		"Call-ID: a84b4c76e66710\r\n" +
+		"Subject: multi\r\n line\r\n" +
+		"\r\n" +
+		"v=0\r\n"
+	req, err := http.NewRequest("POST", "http://localhost:8080/", bytes.NewBuffer([]byte(body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dP, err := newHTTPSIPDP(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		fldPath []string
+		out     string
+	}{
+		{fldPath: []string{utils.SIPMethod}, out: "INVITE"},
+		{fldPath: []string{utils.SIPRequestURI, utils.SIPURIUser}, out: "+4986517174963"},
+		{fldPath: []string{utils.SIPRequestURI, utils.SIPURIPort}, out: "5060"},
+		{fldPath: []string{utils.SIPRequestURI, utils.SIPURIParam, "user"}, out: "phone"},
+		{fldPath: []string{"Via"}, out: "SIP/2.0/UDP 10.0.0.10:5060;branch=z9hG4bK776asdhds"},
+		{fldPath: []string{"From"}, out: `"Alice Doe" <sip:1001@cgrates.org;transport=tcp>;tag=1928301774`},
+		{fldPath: []string{"from", utils.SIPURIUser}, out: "1001"},
+		{fldPath: []string{"From", utils.SIPURIDisplay}, out: "Alice Doe"},
+		{fldPath: []string{"From", utils.SIPURIParam, "tag"}, out: "1928301774"},
+		{fldPath: []string{"From", utils.SIPURIParam, "transport"}, out: "tcp"},
+		{fldPath: []string{"To", utils.SIPURIURI}, out: "sip:+4986517174963@cgrates.org"},
+		{fldPath: []string{"To", utils.SIPURIParam, "tag"}, out: ""},
+		{fldPath: []string{"Call-ID"}, out: "a84b4c76e66710"},
+		{fldPath: []string{"Subject"}, out: "multi line"},
+		{fldPath: []string{"Contact"}, out: ""},
+	} {
+		if data, err := dP.FieldAsString(tc.fldPath); err != nil {
+			t.Errorf("path: %+v, error: %s", tc.fldPath, err)
+		} else if data != tc.out {
+			t.Errorf("path: %+v, expecting: <%s>, received: <%s>", tc.fldPath, tc.out, data)
+		}
+	}
+	req, _ = http.NewRequest("POST", "http://localhost:8080/", bytes.NewBuffer([]byte("SIP/2.0 200 OK\r\nCall-ID: a84b4c76e66710\r\n\r\n")))
+	if dP, err = newHTTPSIPDP(req); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := dP.FieldAsString([]string{utils.SIPStatusCode}); err != nil {
+		t.Error(err)
+	} else if data != "200" {
+		t.Errorf("expecting: 200, received: <%s>", data)
+	}
+	req, _ = http.NewRequest("POST", "http://localhost:8080/", bytes.NewBuffer([]byte("INVITE\r\n")))
+	if _, err := newHTTPSIPDP(req); err == nil {
+		t.Error("expecting error for invalid body")
+	}
+}
+
+func TestHAJSONEncoder(t *testing.T) {
+	nM := config.NewNavigableMap(nil)
+	nM.Set([]string{"Result", "MaxUsage"}, []*config.NMItem{
+		{Path: []string{"Result", "MaxUsage"}, Data: 30}}, false, true)
+	nM.Set([]string{"Result", "Suppliers"}, []*config.NMItem{
+		{Path: []string{"Result", "Suppliers"}, Data: "supplier1"},
+		{Path: []string{"Result", "Suppliers"}, Data: "supplier2"}}, false, true)
+	nM.Set([]string{"Status"}, []*config.NMItem{
+		{Path: []string{"Status"}, Data: "OK"}}, false, true)
+	w := httptest.NewRecorder()
+	enc, err := newHAReplyEncoder(utils.MetaJSON, w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = enc.Encode(nM); err != nil {
+		t.Fatal(err)
+	}
+	if exp := `{"Result":{"MaxUsage":30,"Suppliers":["supplier1","supplier2"]},"Status":"OK"}`; w.Body.String() != exp {
+		t.Errorf("expecting: %s, received: %s", exp, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("unexpected content type: %s", ct)
+	}
+}
@@ -19,6 +19,8 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package agents
 
 import (
+	"crypto/md5"
+	"crypto/subtle"
 	"fmt"
 	"net"
 
@@ -193,3 +195,30 @@ func radauthReq(flags utils.FlagsWithParams, req *radigo.Packet, aReq *AgentRequ
 
 	return true, nil
 }
+
+// encodeDARequest encodes the Dynamic Authorization request, computing the Request Authenticator
+// as per RFC 5176 3.5: MD5(Code+Identifier+Length+16 zero octets+Attributes+Secret)
+func encodeDARequest(daReq *radigo.Packet, secret string) (b []byte, err error) {
+	var buf [4096]byte
+	var n int
+	if n, err = daReq.Encode(buf[:]); err != nil {
+		return
+	}
+	b = buf[:n]
+	copy(b[4:20], make([]byte, 16))
+	daReq.Authenticator = md5.Sum(append(append(make([]byte, 0, n+len(secret)), b...), secret...))
+	copy(b[4:20], daReq.Authenticator[:])
+	return
+}
+
+// isAuthenticDAReply checks the Response Authenticator of the Dynamic Authorization reply
+// as per RFC 5176 3.5: MD5(Code+Identifier+Length+Request Authenticator+Attributes+Secret)
+func isAuthenticDAReply(rpl []byte, reqAuthenticator [16]byte, secret string) bool {
+	if len(rpl) < 20 {
+		return false
+	}
+	raw := append(make([]byte, 0, len(rpl)+len(secret)), rpl...)
+	copy(raw[4:20], reqAuthenticator[:])
+	acator := md5.Sum(append(raw, secret...))
+	return subtle.ConstantTimeCompare(acator[:], rpl[4:20]) == 1
+}
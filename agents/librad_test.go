@@ -19,6 +19,8 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package agents
 
 import (
+	"bytes"
+	"encoding/hex"
 	"reflect"
 	"strings"
 	"testing"
@@ -138,3 +140,45 @@ func TestRadiusDPFieldAsString(t *testing.T) {
 		t.Errorf("Expecting: flopsy, received: <%s>", data)
 	}
 }
+
+func TestEncodeDARequest(t *testing.T) {
+	daReq := radigo.NewPacket(DisconnectRequest, 1, radigo.RFC2865Dictionary(),
+		radigo.NewCoder(), "CGRateS.org")
+	if err := daReq.AddAVPWithName("User-Name", "1001", ""); err != nil {
+		t.Fatal(err)
+	}
+	b, err := encodeDARequest(daReq, "CGRateS.org")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// MD5(0x28 0x01 0x001a + 16 zero octets + User-Name AVP + "CGRateS.org")
+	expAcator, _ := hex.DecodeString("e27946279af482e5ead8d6be5dd329e7")
+	exp := append([]byte{0x28, 0x01, 0x00, 0x1a}, expAcator...)
+	exp = append(exp, 0x01, 0x06, '1', '0', '0', '1')
+	if !bytes.Equal(exp, b) {
+		t.Errorf("Expected: %x, received: %x", exp, b)
+	}
+	if !bytes.Equal(expAcator, daReq.Authenticator[:]) {
+		t.Errorf("Expected: %x, received: %x", expAcator, daReq.Authenticator)
+	}
+}
+
+func TestIsAuthenticDAReply(t *testing.T) {
+	var reqAcator [16]byte
+	hex.Decode(reqAcator[:], []byte("e27946279af482e5ead8d6be5dd329e7"))
+	// MD5(0x29 0x01 0x0014 + Request Authenticator + "CGRateS.org")
+	rplAcator, _ := hex.DecodeString("8a3c67c41177d9479d22d27cd8837178")
+	rpl := append([]byte{0x29, 0x01, 0x00, 0x14}, rplAcator...)
+	if !isAuthenticDAReply(rpl, reqAcator, "CGRateS.org") {
+		t.Error("Expected authentic reply")
+	}
+	if isAuthenticDAReply(rpl, reqAcator, "wrongSecret") {
+		t.Error("Expected not authentic reply for wrong secret")
+	}
+	if isAuthenticDAReply(rpl, [16]byte{}, "CGRateS.org") {
+		t.Error("Expected not authentic reply for other request")
+	}
+	if isAuthenticDAReply(rpl[:19], reqAcator, "CGRateS.org") {
+		t.Error("Expected not authentic reply for short packet")
+	}
+}
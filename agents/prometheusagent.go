@@ -0,0 +1,144 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package agents
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+const (
+	prometheusContentType    = "text/plain; version=0.0.4; charset=utf-8"
+	prometheusStatMetricName = "cgrates_stat_queue_metric"
+)
+
+// NewPrometheusAgent will construct a PrometheusAgent
+func NewPrometheusAgent(cfg *config.CGRConfig, filterS *engine.FilterS,
+	connMgr *engine.ConnManager) *PrometheusAgent {
+	return &PrometheusAgent{
+		cfg:     cfg,
+		filterS: filterS,
+		connMgr: connMgr,
+	}
+}
+
+// PrometheusAgent exposes the StatQueue metrics in the Prometheus text exposition format
+type PrometheusAgent struct {
+	cfg     *config.CGRConfig
+	filterS *engine.FilterS
+	connMgr *engine.ConnManager
+}
+
+// ServeHTTP implements http.Handler interface
+func (pa *PrometheusAgent) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var buf bytes.Buffer
+	if err := pa.writeStatMetrics(&buf); err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> error: %s exporting the StatQueue metrics",
+				utils.PrometheusAgent, err.Error()))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", prometheusContentType)
+	w.Write(buf.Bytes())
+}
+
+// writeStatMetrics writes the metrics of the exported StatQueues as one gauge family
+func (pa *PrometheusAgent) writeStatMetrics(buf *bytes.Buffer) (err error) {
+	paCfg := pa.cfg.PrometheusAgentCfg()
+	if len(paCfg.StatSConns) == 0 {
+		return
+	}
+	tnts := paCfg.StatsTenants
+	if len(tnts) == 0 {
+		tnts = []string{pa.cfg.GeneralCfg().DefaultTenant}
+	}
+	fmt.Fprintf(buf, "# HELP %s Value of the StatQueue metric computed by StatS.\n", prometheusStatMetricName)
+	fmt.Fprintf(buf, "# TYPE %s gauge\n", prometheusStatMetricName)
+	for _, tnt := range tnts {
+		var qIDs []string
+		if err = pa.connMgr.Call(paCfg.StatSConns, nil, utils.StatSv1GetQueueIDs,
+			&utils.TenantWithArgDispatcher{TenantArg: &utils.TenantArg{Tenant: tnt}},
+			&qIDs); err != nil {
+			if err.Error() == utils.ErrNotFound.Error() {
+				err = nil
+				continue
+			}
+			return
+		}
+		sort.Strings(qIDs)
+		for _, qID := range qIDs {
+			var pass bool
+			if pass, err = pa.filterS.Pass(tnt, paCfg.StatsFilters,
+				config.NewNavigableMap(map[string]interface{}{
+					utils.MetaReq: map[string]interface{}{
+						utils.Tenant: tnt,
+						utils.ID:     qID,
+					},
+				})); err != nil {
+				return
+			} else if !pass {
+				continue
+			}
+			metrics := make(map[string]float64)
+			if err = pa.connMgr.Call(paCfg.StatSConns, nil, utils.StatSv1GetQueueFloatMetrics,
+				&utils.TenantIDWithArgDispatcher{TenantID: &utils.TenantID{Tenant: tnt, ID: qID}},
+				&metrics); err != nil {
+				if err.Error() == utils.ErrNotFound.Error() { // queue removed in the meantime
+					err = nil
+					continue
+				}
+				return
+			}
+			writePrometheusStatQueue(buf, tnt, qID, metrics)
+		}
+	}
+	return
+}
+
+// writePrometheusStatQueue writes one sample for each metric of the StatQueue
+func writePrometheusStatQueue(buf *bytes.Buffer, tnt, qID string, metrics map[string]float64) {
+	mIDs := make([]string, 0, len(metrics))
+	for mID := range metrics {
+		mIDs = append(mIDs, mID)
+	}
+	sort.Strings(mIDs)
+	for _, mID := range mIDs {
+		fmt.Fprintf(buf, "%s{tenant=\"%s\",queue_id=\"%s\",metric_id=\"%s\"} %s\n",
+			prometheusStatMetricName,
+			utils.EscapePrometheusLabel(tnt),
+			utils.EscapePrometheusLabel(qID),
+			utils.EscapePrometheusLabel(mID),
+			prometheusValue(metrics[mID]))
+	}
+}
+
+// prometheusValue formats the metric value, the not available ones are exported as NaN
+func prometheusValue(val float64) string {
+	if val == engine.STATS_NA {
+		return "NaN"
+	}
+	return utils.FormatPrometheusValue(val)
+}
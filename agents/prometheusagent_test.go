@@ -0,0 +1,88 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package agents
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/cgrates/rpcclient"
+)
+
+func TestPrometheusAgentServeHTTP(t *testing.T) {
+	cfg, _ := config.NewDefaultCGRConfig()
+	cfg.PrometheusAgentCfg().StatSConns = []string{utils.ConcatenatedKey(utils.MetaInternal, utils.MetaStatS)}
+	cfg.PrometheusAgentCfg().StatsTenants = []string{"cgrates.org", "itsyscom.com"}
+	cfg.PrometheusAgentCfg().StatsFilters = []string{"*notstring:~*req.ID:SQ_2"}
+	sS := &testMockSessionConn{calls: map[string]func(arg interface{}, rply interface{}) error{
+		utils.StatSv1GetQueueIDs: func(arg interface{}, rply interface{}) error {
+			if arg.(*utils.TenantWithArgDispatcher).Tenant != "cgrates.org" {
+				return utils.ErrNotFound
+			}
+			*rply.(*[]string) = []string{"SQ_2", "SQ_1", "SQ\"3"}
+			return nil
+		},
+		utils.StatSv1GetQueueFloatMetrics: func(arg interface{}, rply interface{}) error {
+			switch arg.(*utils.TenantIDWithArgDispatcher).ID {
+			case "SQ_1":
+				*rply.(*map[string]float64) = map[string]float64{
+					utils.MetaTCC: 12.75,
+					utils.MetaASR: 50,
+					utils.MetaACD: engine.STATS_NA,
+				}
+			case "SQ\"3":
+				*rply.(*map[string]float64) = map[string]float64{
+					utils.MetaPDD: 1500000000,
+				}
+			default:
+				t.Errorf("Unexpected queue: %s", utils.ToJSON(arg))
+			}
+			return nil
+		},
+	}}
+	internalStatSChan := make(chan rpcclient.ClientConnector, 1)
+	internalStatSChan <- sS
+	connMgr := engine.NewConnManager(cfg, map[string]chan rpcclient.ClientConnector{
+		utils.ConcatenatedKey(utils.MetaInternal, utils.MetaStatS): internalStatSChan,
+	})
+	pa := NewPrometheusAgent(cfg, engine.NewFilterS(cfg, nil, nil), connMgr)
+
+	rec := httptest.NewRecorder()
+	pa.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status: %d, received: %d", http.StatusOK, rec.Code)
+	}
+	if cType := rec.Header().Get("Content-Type"); cType != prometheusContentType {
+		t.Errorf("Expected content type: %q, received: %q", prometheusContentType, cType)
+	}
+	exp := `# HELP cgrates_stat_queue_metric Value of the StatQueue metric computed by StatS.
+# TYPE cgrates_stat_queue_metric gauge
+cgrates_stat_queue_metric{tenant="cgrates.org",queue_id="SQ\"3",metric_id="*pdd"} 1.5e+09
+cgrates_stat_queue_metric{tenant="cgrates.org",queue_id="SQ_1",metric_id="*acd"} NaN
+cgrates_stat_queue_metric{tenant="cgrates.org",queue_id="SQ_1",metric_id="*asr"} 50
+cgrates_stat_queue_metric{tenant="cgrates.org",queue_id="SQ_1",metric_id="*tcc"} 12.75
+`
+	if rcv := rec.Body.String(); rcv != exp {
+		t.Errorf("Expected:\n%s\nreceived:\n%s", exp, rcv)
+	}
+}
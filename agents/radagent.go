@@ -19,7 +19,12 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package agents
 
 import (
+	"encoding/binary"
+	"errors"
 	"fmt"
+	"net"
+	"sync/atomic"
+	"time"
 
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/engine"
@@ -40,6 +45,19 @@ const (
 	MSCHAP2SuccessAVP  = "MS-CHAP2-Success"
 )
 
+// Dynamic Authorization packet codes and defaults as defined in RFC 5176
+const (
+	DisconnectRequest radigo.PacketCode = 40
+	DisconnectACK     radigo.PacketCode = 41
+	DisconnectNAK     radigo.PacketCode = 42
+	CoARequest        radigo.PacketCode = 43
+	CoAACK            radigo.PacketCode = 44
+	CoANAK            radigo.PacketCode = 45
+	errorCauseAttrNr  uint8             = 101
+	daDefaultPort                       = "3799"
+	daReplyTimeout                      = time.Second
+)
+
 func NewRadiusAgent(cgrCfg *config.CGRConfig, filterS *engine.FilterS,
 	connMgr *engine.ConnManager) (ra *RadiusAgent, err error) {
 	dts := make(map[string]*radigo.Dictionary, len(cgrCfg.RadiusAgentCfg().ClientDictionaries))
@@ -52,8 +70,9 @@ func NewRadiusAgent(cgrCfg *config.CGRConfig, filterS *engine.FilterS,
 		}
 	}
 	dicts := radigo.NewDictionaries(dts)
-	ra = &RadiusAgent{cgrCfg: cgrCfg, filterS: filterS, connMgr: connMgr}
 	secrets := radigo.NewSecrets(cgrCfg.RadiusAgentCfg().ClientSecrets)
+	ra = &RadiusAgent{cgrCfg: cgrCfg, filterS: filterS, connMgr: connMgr,
+		dicts: dicts, secrets: secrets}
 	ra.rsAuth = radigo.NewServer(cgrCfg.RadiusAgentCfg().ListenNet,
 		cgrCfg.RadiusAgentCfg().ListenAuth, secrets, dicts,
 		map[radigo.PacketCode]func(*radigo.Packet) (*radigo.Packet, error){
@@ -65,12 +84,23 @@ func NewRadiusAgent(cgrCfg *config.CGRConfig, filterS *engine.FilterS,
 	return
 }
 
+// radPacketData is cached when receiving the packet
+// so we can build the Dynamic Authorization requests out of it
+type radPacketData struct {
+	pkt        *radigo.Packet
+	remoteHost string
+}
+
 type RadiusAgent struct {
 	cgrCfg  *config.CGRConfig // reference for future config reloads
 	connMgr *engine.ConnManager
 	filterS *engine.FilterS
 	rsAuth  *radigo.Server
 	rsAcct  *radigo.Server
+	dicts   *radigo.Dictionaries
+	secrets *radigo.Secrets
+
+	daReqID uint32 // identifier of the last Dynamic Authorization request
 }
 
 // handleAuth handles RADIUS Authorization request
@@ -184,6 +214,16 @@ func (ra *RadiusAgent) processRequest(req *radigo.Packet, reqProcessor *config.R
 	}
 	cgrArgs := cgrEv.ExtractArgs(reqProcessor.Flags.HasKey(utils.MetaDispatchers),
 		reqType == utils.MetaAuthorize || reqType == utils.MetaMessage || reqType == utils.MetaEvent)
+	// cache the packet so we can build the Disconnect-Request/CoA-Request out of it
+	originID := utils.IfaceAsString(cgrEv.Event[utils.OriginID])
+	cacheDAPkt := originID != utils.EmptyString &&
+		(ra.cgrCfg.RadiusAgentCfg().DMRTemplate != utils.EmptyString ||
+			ra.cgrCfg.RadiusAgentCfg().CoATemplate != utils.EmptyString)
+	if cacheDAPkt && reqType != utils.MetaTerminate {
+		remoteHost, _ := agReq.Vars.FieldAsString([]string{utils.RemoteHost})
+		engine.Cache.Set(utils.CacheRadiusPackets, originID, &radPacketData{req, remoteHost},
+			nil, true, utils.NonTransactional)
+	}
 	if reqProcessor.Flags.HasKey(utils.MetaLog) {
 		utils.Logger.Info(
 			fmt.Sprintf("<%s> LOG, processorID: %s, radius message: %s",
@@ -213,7 +253,7 @@ func (ra *RadiusAgent) processRequest(req *radigo.Packet, reqProcessor *config.R
 			cgrEv, cgrArgs.ArgDispatcher, *cgrArgs.SupplierPaginator,
 		)
 		rply := new(sessions.V1AuthorizeReply)
-		err = ra.connMgr.Call(ra.cgrCfg.RadiusAgentCfg().SessionSConns, nil, utils.SessionSv1AuthorizeEvent,
+		err = ra.connMgr.Call(ra.cgrCfg.RadiusAgentCfg().SessionSConns, ra, utils.SessionSv1AuthorizeEvent,
 			authArgs, rply)
 		if err = agReq.setCGRReply(rply, err); err != nil {
 			return
@@ -230,7 +270,7 @@ func (ra *RadiusAgent) processRequest(req *radigo.Packet, reqProcessor *config.R
 			reqProcessor.Flags.HasKey(utils.MetaAccounts),
 			cgrEv, cgrArgs.ArgDispatcher)
 		rply := new(sessions.V1InitSessionReply)
-		err = ra.connMgr.Call(ra.cgrCfg.RadiusAgentCfg().SessionSConns, nil, utils.SessionSv1InitiateSession,
+		err = ra.connMgr.Call(ra.cgrCfg.RadiusAgentCfg().SessionSConns, ra, utils.SessionSv1InitiateSession,
 			initArgs, rply)
 		if err = agReq.setCGRReply(rply, err); err != nil {
 			return
@@ -242,7 +282,7 @@ func (ra *RadiusAgent) processRequest(req *radigo.Packet, reqProcessor *config.R
 			reqProcessor.Flags.HasKey(utils.MetaAccounts),
 			cgrEv, cgrArgs.ArgDispatcher)
 		rply := new(sessions.V1UpdateSessionReply)
-		err = ra.connMgr.Call(ra.cgrCfg.RadiusAgentCfg().SessionSConns, nil, utils.SessionSv1UpdateSession,
+		err = ra.connMgr.Call(ra.cgrCfg.RadiusAgentCfg().SessionSConns, ra, utils.SessionSv1UpdateSession,
 			updateArgs, rply)
 		if err = agReq.setCGRReply(rply, err); err != nil {
 			return
@@ -257,11 +297,14 @@ func (ra *RadiusAgent) processRequest(req *radigo.Packet, reqProcessor *config.R
 			reqProcessor.Flags.ParamsSlice(utils.MetaStats),
 			cgrEv, cgrArgs.ArgDispatcher)
 		rply := utils.StringPointer("")
-		err = ra.connMgr.Call(ra.cgrCfg.RadiusAgentCfg().SessionSConns, nil, utils.SessionSv1TerminateSession,
+		err = ra.connMgr.Call(ra.cgrCfg.RadiusAgentCfg().SessionSConns, ra, utils.SessionSv1TerminateSession,
 			terminateArgs, rply)
 		if err = agReq.setCGRReply(nil, err); err != nil {
 			return
 		}
+		if cacheDAPkt { // the session is gone, no Dynamic Authorization request will be sent for it
+			engine.Cache.Remove(utils.CacheRadiusPackets, originID, true, utils.NonTransactional)
+		}
 	case utils.MetaMessage:
 		evArgs := sessions.NewV1ProcessMessageArgs(
 			reqProcessor.Flags.HasKey(utils.MetaAttributes),
@@ -277,7 +320,7 @@ func (ra *RadiusAgent) processRequest(req *radigo.Packet, reqProcessor *config.R
 			reqProcessor.Flags.HasKey(utils.MetaSuppliersEventCost),
 			cgrEv, cgrArgs.ArgDispatcher, *cgrArgs.SupplierPaginator)
 		rply := new(sessions.V1ProcessMessageReply)
-		err = ra.connMgr.Call(ra.cgrCfg.RadiusAgentCfg().SessionSConns, nil, utils.SessionSv1ProcessMessage, evArgs, rply)
+		err = ra.connMgr.Call(ra.cgrCfg.RadiusAgentCfg().SessionSConns, ra, utils.SessionSv1ProcessMessage, evArgs, rply)
 		if utils.ErrHasPrefix(err, utils.RalsErrorPrfx) {
 			cgrEv.Event[utils.Usage] = 0 // avoid further debits
 		} else if evArgs.Debit {
@@ -297,7 +340,7 @@ func (ra *RadiusAgent) processRequest(req *radigo.Packet, reqProcessor *config.R
 			reqProcessor.Flags.HasKey(utils.MetaInit) ||
 			reqProcessor.Flags.HasKey(utils.MetaUpdate)
 		rply := new(sessions.V1ProcessEventReply)
-		err = ra.connMgr.Call(ra.cgrCfg.RadiusAgentCfg().SessionSConns, nil, utils.SessionSv1ProcessEvent,
+		err = ra.connMgr.Call(ra.cgrCfg.RadiusAgentCfg().SessionSConns, ra, utils.SessionSv1ProcessEvent,
 			evArgs, rply)
 		if utils.ErrHasPrefix(err, utils.RalsErrorPrfx) {
 			cgrEv.Event[utils.Usage] = 0 // avoid further debits
@@ -318,7 +361,7 @@ func (ra *RadiusAgent) processRequest(req *radigo.Packet, reqProcessor *config.R
 	// separate request so we can capture the Terminate/Event also here
 	if reqProcessor.Flags.HasKey(utils.MetaCDRs) {
 		rplyCDRs := utils.StringPointer("")
-		if err = ra.connMgr.Call(ra.cgrCfg.RadiusAgentCfg().SessionSConns, nil, utils.SessionSv1ProcessCDR,
+		if err = ra.connMgr.Call(ra.cgrCfg.RadiusAgentCfg().SessionSConns, ra, utils.SessionSv1ProcessCDR,
 			&utils.CGREventWithArgDispatcher{CGREvent: cgrEv,
 				ArgDispatcher: cgrArgs.ArgDispatcher},
 			rplyCDRs); err != nil {
@@ -360,3 +403,181 @@ func (ra *RadiusAgent) ListenAndServe() (err error) {
 	err = <-errListen
 	return
 }
+
+// Call implements rpcclient.ClientConnector interface
+func (ra *RadiusAgent) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	return utils.RPCCall(ra, serviceMethod, args, reply)
+}
+
+// V1DisconnectSession is part of the sessions.BiRPClient
+// sends a Disconnect-Request towards the client which originated the session
+func (ra *RadiusAgent) V1DisconnectSession(args utils.AttrDisconnectSession, reply *string) (err error) {
+	if ra.cgrCfg.RadiusAgentCfg().DMRTemplate == utils.EmptyString {
+		return utils.ErrNotImplemented
+	}
+	originID := utils.IfaceAsString(args.EventStart[utils.OriginID])
+	if originID == utils.EmptyString {
+		utils.Logger.Info(
+			fmt.Sprintf("<%s> cannot disconnect session, missing OriginID in event: %s",
+				utils.RadiusAgent, utils.ToJSON(args.EventStart)))
+		return utils.ErrMandatoryIeMissing
+	}
+	if err = ra.sendRadDaReq(DisconnectRequest, ra.cgrCfg.RadiusAgentCfg().DMRTemplate,
+		originID, map[string]interface{}{utils.DISCONNECT_CAUSE: args.Reason}); err != nil {
+		return
+	}
+	*reply = utils.OK
+	return
+}
+
+// V1GetActiveSessionIDs is part of the sessions.BiRPClient
+func (ra *RadiusAgent) V1GetActiveSessionIDs(ignParam string,
+	sessionIDs *[]*sessions.SessionID) error {
+	return utils.ErrNotImplemented
+}
+
+// V1ReAuthorize is part of the sessions.BiRPClient
+// sends a CoA-Request towards the client which originated the session
+func (ra *RadiusAgent) V1ReAuthorize(originID string, reply *string) (err error) {
+	if ra.cgrCfg.RadiusAgentCfg().CoATemplate == utils.EmptyString {
+		return utils.ErrNotImplemented
+	}
+	if originID == utils.EmptyString {
+		utils.Logger.Info(
+			fmt.Sprintf("<%s> cannot send CoA-Request, missing session ID",
+				utils.RadiusAgent))
+		return utils.ErrMandatoryIeMissing
+	}
+	if err = ra.sendRadDaReq(CoARequest, ra.cgrCfg.RadiusAgentCfg().CoATemplate,
+		originID, nil); err != nil {
+		return
+	}
+	*reply = utils.OK
+	return
+}
+
+// V1DisconnectPeer is part of the sessions.BiRPClient
+func (ra *RadiusAgent) V1DisconnectPeer(args *utils.DPRArgs, reply *string) (err error) {
+	return utils.ErrNotImplemented
+}
+
+// DisconnectWarning is part of the sessions.BiRPClient
+func (ra *RadiusAgent) DisconnectWarning(args map[string]interface{}, reply *string) (err error) {
+	return utils.ErrNotImplemented
+}
+
+// sendRadDaReq builds the Dynamic Authorization request out of the cached packet
+// and sends it towards the client, waiting for the ACK
+func (ra *RadiusAgent) sendRadDaReq(reqCode radigo.PacketCode, tplID, originID string,
+	vars map[string]interface{}) (err error) {
+	pkt, has := engine.Cache.Get(utils.CacheRadiusPackets, originID)
+	if !has {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> cannot retrieve packet from cache with OriginID: <%s>",
+				utils.RadiusAgent, originID))
+		return utils.ErrMandatoryIeMissing
+	}
+	rpd := pkt.(*radPacketData)
+	if vars == nil {
+		vars = make(map[string]interface{})
+	}
+	vars[utils.RemoteHost] = rpd.remoteHost
+	aReq := NewAgentRequest(
+		newRADataProvider(rpd.pkt), vars,
+		config.NewNavigableMap(nil),
+		config.NewNavigableMap(nil),
+		nil,
+		ra.cgrCfg.GeneralCfg().DefaultTenant,
+		ra.cgrCfg.GeneralCfg().DefaultTimezone, ra.filterS, nil, nil)
+	if err = aReq.SetFields(ra.cgrCfg.RadiusAgentCfg().Templates[tplID]); err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> cannot build request with OriginID: <%s>, err: %s",
+				utils.RadiusAgent, originID, err.Error()))
+		return utils.ErrServerError
+	}
+	clntHost, _, err := net.SplitHostPort(rpd.remoteHost)
+	if err != nil {
+		return
+	}
+	daAddr, has := ra.cgrCfg.RadiusAgentCfg().ClientDaAddresses[clntHost]
+	if !has {
+		daAddr = net.JoinHostPort(clntHost, daDefaultPort)
+	}
+	dict := ra.dicts.GetInstance(clntHost)
+	if dict == nil {
+		dict = radigo.RFC2865Dictionary()
+	}
+	secret := ra.secrets.GetSecret(clntHost)
+	daReq := radigo.NewPacket(reqCode, uint8(atomic.AddUint32(&ra.daReqID, 1)),
+		dict, radigo.NewCoder(), secret)
+	if err = radReplyAppendAttributes(daReq, aReq.diamreq); err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> cannot build request with OriginID: <%s>, err: %s",
+				utils.RadiusAgent, originID, err.Error()))
+		return utils.ErrServerError
+	}
+	var daRpl *radigo.Packet
+	if daRpl, err = sendDARequest(daAddr, daReq, dict, secret); err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> cannot send Dynamic Authorization request to <%s>, err: %s",
+				utils.RadiusAgent, daAddr, err.Error()))
+		return
+	}
+	switch daRpl.Code {
+	case DisconnectACK, CoAACK:
+		return
+	case DisconnectNAK, CoANAK:
+		for _, avp := range daRpl.AVPs { // Error-Cause is not part of the RFC 2865 dictionary
+			if avp.Number == errorCauseAttrNr && len(avp.RawValue) == 4 {
+				return fmt.Errorf("NAK received with Error-Cause: <%d>",
+					binary.BigEndian.Uint32(avp.RawValue))
+			}
+		}
+		return fmt.Errorf("NAK received")
+	default:
+		return fmt.Errorf("unexpected reply code: <%d>", daRpl.Code)
+	}
+}
+
+// sendDARequest sends the Dynamic Authorization request over UDP and waits for the authentic reply
+// radigo.Client is not used since it does not compute the authenticators for the RFC 5176 codes
+func sendDARequest(daAddr string, daReq *radigo.Packet, dict *radigo.Dictionary,
+	secret string) (daRpl *radigo.Packet, err error) {
+	var b []byte
+	if b, err = encodeDARequest(daReq, secret); err != nil {
+		return
+	}
+	var conn net.Conn
+	if conn, err = net.Dial(utils.UDP, daAddr); err != nil {
+		return
+	}
+	defer conn.Close()
+	if err = conn.SetDeadline(time.Now().Add(daReplyTimeout)); err != nil {
+		return
+	}
+	if _, err = conn.Write(b); err != nil {
+		return
+	}
+	var buf [4096]byte
+	for {
+		var n int
+		if n, err = conn.Read(buf[:]); err != nil {
+			return
+		}
+		if n < 20 || int(binary.BigEndian.Uint16(buf[2:4])) != n ||
+			buf[1] != daReq.Identifier {
+			continue // not the reply for our request
+		}
+		if !isAuthenticDAReply(buf[:n], daReq.Authenticator, secret) {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> discarding reply from <%s> with invalid Response Authenticator",
+					utils.RadiusAgent, daAddr))
+			continue // silently discarded as per RFC 5176 3.5
+		}
+		daRpl = radigo.NewPacket(0, 0, dict, radigo.NewCoder(), secret)
+		if err = daRpl.Decode(buf[:n]); err != nil {
+			return nil, errors.New("cannot decode reply: " + err.Error())
+		}
+		return
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package agents
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/binary"
+	"net"
+	"testing"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/cgrates/radigo"
+)
+
+func TestRadiusAgentSendRadDaReq(t *testing.T) {
+	pc, err := net.ListenPacket(utils.UDP, "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+	daAddr := pc.LocalAddr().String()
+	secrets := radigo.NewSecrets(map[string]string{utils.MetaDefault: "CGRateS.org"})
+	dicts := radigo.NewDictionaries(map[string]*radigo.Dictionary{
+		utils.MetaDefault: radigo.RFC2865Dictionary()})
+	// the DA server is implemented here, without radigo, so we check the authenticators as per RFC 5176
+	daReply := func(req []byte, code byte, avps []byte, secret string) (rpl []byte) {
+		rpl = append([]byte{code, req[1], 0, 0}, req[4:20]...)
+		rpl = append(rpl, avps...)
+		binary.BigEndian.PutUint16(rpl[2:4], uint16(len(rpl)))
+		acator := md5.Sum(append(append([]byte{}, rpl...), secret...))
+		copy(rpl[4:20], acator[:])
+		return
+	}
+	daReqs := make(chan *radigo.Packet, 1)
+	go func() {
+		var buf [4096]byte
+		for {
+			n, addr, err := pc.ReadFrom(buf[:])
+			if err != nil {
+				return
+			}
+			req := append([]byte{}, buf[:n]...)
+			raw := append([]byte{}, req...)
+			copy(raw[4:20], make([]byte, 16))
+			if acator := md5.Sum(append(raw, "CGRateS.org"...)); !bytes.Equal(acator[:], req[4:20]) {
+				t.Errorf("invalid Request Authenticator: %x", req[4:20])
+				continue
+			}
+			switch radigo.PacketCode(req[0]) {
+			case DisconnectRequest:
+				pkt := radigo.NewPacket(0, 0, radigo.RFC2865Dictionary(), radigo.NewCoder(), "CGRateS.org")
+				if err := pkt.Decode(req); err != nil {
+					t.Error(err)
+				}
+				daReqs <- pkt
+				pc.WriteTo(daReply(req, byte(DisconnectACK), nil, "wrongSecret"), addr) // discarded by the agent
+				pc.WriteTo(daReply(req, byte(DisconnectACK), nil, "CGRateS.org"), addr)
+			case CoARequest:
+				pc.WriteTo(daReply(req, byte(CoANAK),
+					[]byte{errorCauseAttrNr, 6, 0, 0, 1, 247}, "CGRateS.org"), addr) // 503 Session Context Not Found
+			}
+		}
+	}()
+
+	cfg, _ := config.NewDefaultCGRConfig()
+	cfg.RadiusAgentCfg().DMRTemplate = utils.MetaDMR
+	cfg.RadiusAgentCfg().CoATemplate = utils.MetaCoA
+	cfg.RadiusAgentCfg().ClientDaAddresses = map[string]string{"127.0.0.1": daAddr}
+	engine.Cache.Clear(nil)
+	data := engine.NewInternalDB(nil, nil, true, cfg.DataDbCfg().Items)
+	dm := engine.NewDataManager(data, cfg.CacheCfg(), nil)
+	ra := &RadiusAgent{cgrCfg: cfg, filterS: engine.NewFilterS(cfg, nil, dm),
+		dicts: dicts, secrets: secrets}
+	var rply string
+	if err := ra.V1DisconnectSession(utils.AttrDisconnectSession{
+		EventStart: map[string]interface{}{utils.OriginID: "e4921177ab0e3586c37f6a185864b71a@0:0:0:0:0:0:0:0"},
+	}, &rply); err != utils.ErrMandatoryIeMissing {
+		t.Errorf("expecting: %v, received: %v", utils.ErrMandatoryIeMissing, err)
+	}
+
+	req := radigo.NewPacket(radigo.AccountingRequest, 1, dicts.GetInstance(utils.MetaDefault),
+		radigo.NewCoder(), "CGRateS.org")
+	if err := req.AddAVPWithName("User-Name", "1001", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := req.AddAVPWithName("Acct-Session-Id", "e4921177ab0e3586c37f6a185864b71a@0:0:0:0:0:0:0:0", ""); err != nil {
+		t.Fatal(err)
+	}
+	req.SetAVPValues()
+	engine.Cache.Set(utils.CacheRadiusPackets, "e4921177ab0e3586c37f6a185864b71a@0:0:0:0:0:0:0:0",
+		&radPacketData{req, "127.0.0.1:52314"}, nil, true, utils.NonTransactional)
+	if err := ra.V1DisconnectSession(utils.AttrDisconnectSession{
+		EventStart: map[string]interface{}{utils.OriginID: "e4921177ab0e3586c37f6a185864b71a@0:0:0:0:0:0:0:0"},
+		Reason:     "FORCED_DISCONNECT",
+	}, &rply); err != nil {
+		t.Fatal(err)
+	} else if rply != utils.OK {
+		t.Errorf("unexpected reply: %s", rply)
+	}
+	daReq := <-daReqs
+	daReq.SetAVPValues()
+	for attr, exp := range map[string]string{
+		"User-Name":       "1001",
+		"Acct-Session-Id": "e4921177ab0e3586c37f6a185864b71a@0:0:0:0:0:0:0:0",
+		"Reply-Message":   "FORCED_DISCONNECT",
+	} {
+		if avps := daReq.AttributesWithName(attr, ""); len(avps) != 1 {
+			t.Errorf("expecting one %s attribute, received: %d", attr, len(avps))
+		} else if avps[0].StringValue != exp {
+			t.Errorf("expecting %s: %s, received: %s", attr, exp, avps[0].StringValue)
+		}
+	}
+	if err := ra.V1ReAuthorize("e4921177ab0e3586c37f6a185864b71a@0:0:0:0:0:0:0:0", &rply); err == nil ||
+		err.Error() != "NAK received with Error-Cause: <503>" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
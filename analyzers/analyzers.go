@@ -20,30 +20,113 @@ package analyzers
 
 import (
 	"fmt"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/utils"
 )
 
 // NewAnalyzerService initializes a AnalyzerService
-func NewAnalyzerService() (*AnalyzerService, error) {
-	return &AnalyzerService{}, nil
+func NewAnalyzerService(cfg *config.CGRConfig) (*AnalyzerService, error) {
+	return &AnalyzerService{
+		cfg:     cfg,
+		traffic: newTrafficIndex(),
+	}, nil
 }
 
 // AnalyzerService is the service handling analyzer
 type AnalyzerService struct {
+	cfg *config.CGRConfig
+
+	sync.RWMutex // protects the traffic
+	traffic      *trafficIndex
+	lastID       uint64
 }
 
 // ListenAndServe will initialize the service
 func (aS *AnalyzerService) ListenAndServe(exitChan chan bool) error {
 	utils.Logger.Info(fmt.Sprintf("<%s> starting <%s> subsystem", utils.CoreS, utils.AnalyzerS))
-	e := <-exitChan
-	exitChan <- e // put back for the others listening for shutdown request
-	return nil
+	var cleanupChan <-chan time.Time
+	if cleanupInterval := aS.cfg.AnalyzerSCfg().CleanupInterval; cleanupInterval > 0 {
+		ticker := time.NewTicker(cleanupInterval)
+		defer ticker.Stop()
+		cleanupChan = ticker.C
+	}
+	for {
+		select {
+		case e := <-exitChan:
+			exitChan <- e // put back for the others listening for shutdown request
+			return nil
+		case now := <-cleanupChan:
+			aS.removeExpired(now)
+		}
+	}
 }
 
 // Shutdown is called to shutdown the service
 func (aS *AnalyzerService) Shutdown() error {
 	utils.Logger.Info(fmt.Sprintf("<%s> service shutdown initialized", utils.AnalyzerS))
+	aS.Lock()
+	aS.traffic = newTrafficIndex()
+	aS.Unlock()
 	utils.Logger.Info(fmt.Sprintf("<%s> service shutdown complete", utils.AnalyzerS))
 	return nil
 }
+
+// removeExpired removes the traffic with the TTL expired
+func (aS *AnalyzerService) removeExpired(now time.Time) {
+	aS.Lock()
+	aS.traffic.removeExpired(now)
+	aS.Unlock()
+}
+
+// LogTraffic captures the API call
+// implements the utils.RPCAnalyzer interface
+func (aS *AnalyzerService) LogTraffic(method string, params, reply interface{}, err error,
+	enc, from, to string, outgoing bool, sTime, eTime time.Time) {
+	if strings.HasPrefix(method, utils.AnalyzerSv1) { // do not capture our own queries
+		return
+	}
+	inf := &InfoRPC{
+		RequestEncoding:    enc,
+		RequestSource:      from,
+		RequestDestination: to,
+		Outgoing:           outgoing,
+		RequestMethod:      method,
+		RequestParams:      snapshotValue(params),
+		RequestStartTime:   sTime,
+		RequestDuration:    eTime.Sub(sTime),
+	}
+	if err != nil {
+		inf.ReplyError = err.Error()
+	} else {
+		inf.Reply = snapshotValue(reply)
+	}
+	inf.Tenant, inf.Account = eventFields(inf.RequestParams)
+	if ttl := aS.cfg.AnalyzerSCfg().TTL; ttl > 0 {
+		inf.expiryTime = eTime.Add(ttl)
+	}
+	aS.Lock()
+	aS.lastID++
+	inf.RequestID = aS.lastID
+	aS.traffic.add(inf)
+	aS.Unlock()
+}
+
+// V1StringQuery returns the captured traffic matching the query
+func (aS *AnalyzerService) V1StringQuery(args *QueryArgs, reply *[]*InfoRPC) (err error) {
+	var conds []*queryCondition
+	if conds, err = parseQuery(args.HeaderFilters); err != nil {
+		return
+	}
+	aS.RLock()
+	infs := aS.traffic.query(conds, args)
+	aS.RUnlock()
+	if len(infs) == 0 {
+		return utils.ErrNotFound
+	}
+	*reply = infs
+	return
+}
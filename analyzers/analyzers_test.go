@@ -0,0 +1,146 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package analyzers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func testAnalyzerService(t *testing.T) *AnalyzerService {
+	cfg, err := config.NewDefaultCGRConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	aS, err := NewAnalyzerService(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return aS
+}
+
+func TestAnalyzerSLogTraffic(t *testing.T) {
+	aS := testAnalyzerService(t)
+	sTime := time.Date(2020, 4, 1, 10, 0, 0, 0, time.UTC)
+	ev := &utils.CGREvent{
+		Tenant: "cgrates.org",
+		ID:     "ev1",
+		Event: map[string]interface{}{
+			utils.Account: "1001",
+		},
+	}
+	rply := "OK"
+	aS.LogTraffic(utils.SessionSv1ProcessCDR, ev, &rply, nil,
+		utils.MetaJSON, "127.0.0.1:5000", "127.0.0.1:2012", false, sTime, sTime.Add(time.Second))
+	ev.Event[utils.Account] = "1002" // make sure we stored a snapshot
+	aS.LogTraffic(utils.AnalyzerSv1StringQuery, nil, nil, nil,
+		utils.MetaJSON, "127.0.0.1:5000", "127.0.0.1:2012", false, sTime, sTime.Add(time.Second))
+
+	var reply []*InfoRPC
+	if err := aS.V1StringQuery(&QueryArgs{}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	exp := []*InfoRPC{{
+		RequestID:          1,
+		RequestEncoding:    utils.MetaJSON,
+		RequestSource:      "127.0.0.1:5000",
+		RequestDestination: "127.0.0.1:2012",
+		RequestMethod:      utils.SessionSv1ProcessCDR,
+		RequestParams: map[string]interface{}{
+			utils.Tenant: "cgrates.org",
+			utils.ID:     "ev1",
+			utils.Event: map[string]interface{}{
+				utils.Account: "1001",
+			},
+			"Time": nil,
+		},
+		Reply:            "OK",
+		RequestStartTime: sTime,
+		RequestDuration:  time.Second,
+		Tenant:           "cgrates.org",
+		Account:          "1001",
+		expiryTime:       sTime.Add(time.Second + 24*time.Hour),
+	}}
+	if !reflect.DeepEqual(exp, reply) {
+		t.Errorf("Expected: %s, received: %s", utils.ToJSON(exp), utils.ToJSON(reply))
+	}
+	aS.removeExpired(sTime.Add(25 * time.Hour))
+	if err := aS.V1StringQuery(&QueryArgs{}, &reply); err != utils.ErrNotFound {
+		t.Errorf("Expected error: %v, received: %v", utils.ErrNotFound, err)
+	}
+}
+
+func TestAnalyzerSStringQuery(t *testing.T) {
+	aS := testAnalyzerService(t)
+	sTime := time.Date(2020, 4, 1, 10, 0, 0, 0, time.UTC)
+	aS.LogTraffic(utils.SessionSv1AuthorizeEvent,
+		map[string]interface{}{utils.Tenant: "cgrates.org", utils.Event: map[string]interface{}{utils.Account: "1001"}},
+		nil, nil, utils.MetaJSON, "a", "b", false, sTime, sTime)
+	aS.LogTraffic(utils.SessionSv1InitiateSession,
+		map[string]interface{}{utils.Tenant: "cgrates.org", utils.Event: map[string]interface{}{utils.Account: "1002"}},
+		nil, utils.ErrInsufficientCredit, utils.MetaJSON, "a", "b", false, sTime.Add(time.Second), sTime.Add(time.Second))
+	aS.LogTraffic(utils.CDRsV1ProcessEvent,
+		map[string]interface{}{utils.Tenant: "itsyscom.com", utils.Event: map[string]interface{}{utils.Account: "1001"}},
+		nil, nil, utils.MetaGOB, "a", "b", false, sTime.Add(2*time.Second), sTime.Add(2*time.Second))
+
+	for _, tc := range []struct {
+		qry string
+		ids []uint64
+	}{
+		{qry: "Account:1001", ids: []uint64{1, 3}},
+		{qry: "RequestMethod:SessionSv1.*", ids: []uint64{1, 2}},
+		{qry: "Tenant:cgrates.org -ReplyError:*", ids: []uint64{1}},
+		{qry: "ReplyError:*", ids: []uint64{2}},
+		{qry: "-RequestEncoding:*json", ids: []uint64{3}},
+		{qry: "Tenant:cgrates.net"},
+	} {
+		var reply []*InfoRPC
+		err := aS.V1StringQuery(&QueryArgs{HeaderFilters: tc.qry}, &reply)
+		if len(tc.ids) == 0 {
+			if err != utils.ErrNotFound {
+				t.Errorf("query %q, expected error: %v, received: %v", tc.qry, utils.ErrNotFound, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("query %q, error: %v", tc.qry, err)
+		}
+		ids := make([]uint64, len(reply))
+		for i, inf := range reply {
+			ids[i] = inf.RequestID
+		}
+		if !reflect.DeepEqual(tc.ids, ids) {
+			t.Errorf("query %q, expected: %v, received: %v", tc.qry, tc.ids, ids)
+		}
+	}
+
+	var reply []*InfoRPC
+	if err := aS.V1StringQuery(&QueryArgs{StartTime: sTime.Add(time.Second), Limit: 1}, &reply); err != nil {
+		t.Fatal(err)
+	} else if len(reply) != 1 || reply[0].RequestID != 2 {
+		t.Errorf("Unexpected reply: %s", utils.ToJSON(reply))
+	}
+	if err := aS.V1StringQuery(&QueryArgs{HeaderFilters: "Subject:1001"}, &reply); err == nil {
+		t.Error("Expected error for unsupported field")
+	}
+}
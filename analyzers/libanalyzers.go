@@ -0,0 +1,296 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package analyzers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+// the fields of InfoRPC that are indexed and can be used in queries
+var indexedFields = utils.NewStringSet([]string{utils.RequestMethod,
+	utils.RequestEncoding, utils.RequestSource, utils.RequestDestination,
+	utils.Tenant, utils.Account, utils.ReplyError})
+
+// InfoRPC is the information captured for one API call
+type InfoRPC struct {
+	RequestID          uint64
+	RequestEncoding    string
+	RequestSource      string
+	RequestDestination string
+	RequestMethod      string
+	RequestParams      interface{}
+	Reply              interface{}
+	ReplyError         string
+	RequestStartTime   time.Time
+	RequestDuration    time.Duration
+	Tenant             string
+	Account            string
+	Outgoing           bool // sent by the engine (ie: ConnManager towards the subsystems) instead of received
+
+	expiryTime time.Time
+}
+
+// fieldValue returns the value of one of the indexed fields
+func (inf *InfoRPC) fieldValue(fldName string) string {
+	switch fldName {
+	case utils.RequestMethod:
+		return inf.RequestMethod
+	case utils.RequestEncoding:
+		return inf.RequestEncoding
+	case utils.RequestSource:
+		return inf.RequestSource
+	case utils.RequestDestination:
+		return inf.RequestDestination
+	case utils.Tenant:
+		return inf.Tenant
+	case utils.Account:
+		return inf.Account
+	case utils.ReplyError:
+		return inf.ReplyError
+	}
+	return utils.EmptyString
+}
+
+// snapshotValue converts the value to its JSON representation
+// so later changes on the original object are not reflected in the stored traffic
+func snapshotValue(v interface{}) (snp interface{}) {
+	if v == nil {
+		return
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%+v", v)
+	}
+	if err = json.Unmarshal(b, &snp); err != nil {
+		return string(b)
+	}
+	return
+}
+
+// eventFields extracts the Tenant and Account out of the API parameters
+// considering both the top level fields and the ones from Event (eg: CGREvent)
+func eventFields(params interface{}) (tnt, acnt string) {
+	mp, canCast := params.(map[string]interface{})
+	if !canCast {
+		return
+	}
+	tnt = utils.IfaceAsString(mp[utils.Tenant])
+	acnt = utils.IfaceAsString(mp[utils.Account])
+	if ev, canCast := mp[utils.Event].(map[string]interface{}); canCast {
+		if tnt == utils.EmptyString {
+			tnt = utils.IfaceAsString(ev[utils.Tenant])
+		}
+		if acnt == utils.EmptyString {
+			acnt = utils.IfaceAsString(ev[utils.Account])
+		}
+	}
+	return
+}
+
+// newTrafficIndex returns an empty index
+func newTrafficIndex() *trafficIndex {
+	return &trafficIndex{
+		infos:   make(map[uint64]*InfoRPC),
+		indexes: make(map[string]map[string]map[uint64]struct{}),
+	}
+}
+
+// trafficIndex stores the captured traffic indexed by the indexedFields
+// not thread safe, the locking is done by the AnalyzerService
+type trafficIndex struct {
+	infos   map[uint64]*InfoRPC
+	indexes map[string]map[string]map[uint64]struct{} // map[fieldName]map[fieldValue]IDs
+}
+
+// add will store and index the API information
+func (tI *trafficIndex) add(inf *InfoRPC) {
+	tI.infos[inf.RequestID] = inf
+	for fldName := range indexedFields.Data() {
+		fldVal := inf.fieldValue(fldName)
+		if _, has := tI.indexes[fldName]; !has {
+			tI.indexes[fldName] = make(map[string]map[uint64]struct{})
+		}
+		if _, has := tI.indexes[fldName][fldVal]; !has {
+			tI.indexes[fldName][fldVal] = make(map[uint64]struct{})
+		}
+		tI.indexes[fldName][fldVal][inf.RequestID] = struct{}{}
+	}
+}
+
+// remove will remove the API information together with its indexes
+func (tI *trafficIndex) remove(id uint64) {
+	inf, has := tI.infos[id]
+	if !has {
+		return
+	}
+	delete(tI.infos, id)
+	for fldName := range indexedFields.Data() {
+		fldVal := inf.fieldValue(fldName)
+		delete(tI.indexes[fldName][fldVal], id)
+		if len(tI.indexes[fldName][fldVal]) == 0 {
+			delete(tI.indexes[fldName], fldVal)
+		}
+	}
+}
+
+// removeExpired removes the information expired before the given time
+func (tI *trafficIndex) removeExpired(now time.Time) {
+	for id, inf := range tI.infos {
+		if !inf.expiryTime.IsZero() && inf.expiryTime.Before(now) {
+			tI.remove(id)
+		}
+	}
+}
+
+// matchingIDs returns the IDs matching the query condition
+func (tI *trafficIndex) matchingIDs(cond *queryCondition) (ids map[uint64]struct{}) {
+	ids = make(map[uint64]struct{})
+	for fldVal, fldIDs := range tI.indexes[cond.fieldName] {
+		if !cond.matchValue(fldVal) {
+			continue
+		}
+		for id := range fldIDs {
+			ids[id] = struct{}{}
+		}
+	}
+	return
+}
+
+// query returns the information matching all the conditions ordered by RequestStartTime
+func (tI *trafficIndex) query(conds []*queryCondition, args *QueryArgs) (infs []*InfoRPC) {
+	var ids map[uint64]struct{}
+	for _, cond := range conds {
+		if cond.negative {
+			continue
+		}
+		condIDs := tI.matchingIDs(cond)
+		if ids == nil {
+			ids = condIDs
+			continue
+		}
+		for id := range ids {
+			if _, has := condIDs[id]; !has {
+				delete(ids, id)
+			}
+		}
+	}
+	if ids == nil { // no positive condition so start from all
+		ids = make(map[uint64]struct{})
+		for id := range tI.infos {
+			ids[id] = struct{}{}
+		}
+	}
+	for _, cond := range conds {
+		if !cond.negative {
+			continue
+		}
+		for id := range tI.matchingIDs(cond) {
+			delete(ids, id)
+		}
+	}
+	infs = make([]*InfoRPC, 0, len(ids))
+	for id := range ids {
+		inf := tI.infos[id]
+		if !args.StartTime.IsZero() && inf.RequestStartTime.Before(args.StartTime) {
+			continue
+		}
+		if !args.EndTime.IsZero() && !inf.RequestStartTime.Before(args.EndTime) {
+			continue
+		}
+		infs = append(infs, inf)
+	}
+	sort.Slice(infs, func(i, j int) bool {
+		if infs[i].RequestStartTime.Equal(infs[j].RequestStartTime) {
+			return infs[i].RequestID < infs[j].RequestID
+		}
+		return infs[i].RequestStartTime.Before(infs[j].RequestStartTime)
+	})
+	if args.Offset > 0 {
+		if args.Offset >= len(infs) {
+			return []*InfoRPC{}
+		}
+		infs = infs[args.Offset:]
+	}
+	if args.Limit > 0 && args.Limit < len(infs) {
+		infs = infs[:args.Limit]
+	}
+	return
+}
+
+// QueryArgs the arguments for AnalyzerSv1.StringQuery
+type QueryArgs struct {
+	// HeaderFilters is a space separated list of <[-]FieldName:Value> conditions
+	// the Value can end in * to match the prefix, a single * matches any not empty value
+	// and the - in front of the condition negates it
+	// eg: "RequestMethod:SessionSv1.* Tenant:cgrates.org -ReplyError:*"
+	HeaderFilters string
+	StartTime     time.Time // only the traffic started after this time, zero to ignore
+	EndTime       time.Time // only the traffic started before this time, zero to ignore
+	Offset        int
+	Limit         int // maximum number of items returned, 0 for no limit
+}
+
+// queryCondition is one parsed condition out of the HeaderFilters
+type queryCondition struct {
+	fieldName string
+	value     string
+	negative  bool
+	prefix    bool
+}
+
+func (qC *queryCondition) matchValue(fldVal string) bool {
+	if qC.prefix {
+		if qC.value == utils.EmptyString { // single *
+			return fldVal != utils.EmptyString
+		}
+		return strings.HasPrefix(fldVal, qC.value)
+	}
+	return fldVal == qC.value
+}
+
+// parseQuery parses the HeaderFilters into conditions
+func parseQuery(qry string) (conds []*queryCondition, err error) {
+	for _, term := range strings.Fields(qry) {
+		cond := new(queryCondition)
+		if strings.HasPrefix(term, utils.HyphenSep) {
+			cond.negative = true
+			term = term[1:]
+		}
+		idx := strings.Index(term, utils.InInFieldSep)
+		if idx == -1 {
+			return nil, fmt.Errorf("invalid condition: <%s>", term)
+		}
+		cond.fieldName, cond.value = term[:idx], term[idx+1:]
+		if !indexedFields.Has(cond.fieldName) {
+			return nil, fmt.Errorf("unsupported field: <%s>", cond.fieldName)
+		}
+		if strings.HasSuffix(cond.value, utils.Meta) {
+			cond.prefix = true
+			cond.value = strings.TrimSuffix(cond.value, utils.Meta)
+		}
+		conds = append(conds, cond)
+	}
+	return
+}
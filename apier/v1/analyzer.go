@@ -44,3 +44,8 @@ func (alSv1 *AnalyzerSv1) Ping(ign *utils.CGREvent, reply *string) error {
 	*reply = utils.Pong
 	return nil
 }
+
+// StringQuery returns the captured API traffic matching the query
+func (aSv1 *AnalyzerSv1) StringQuery(args *analyzers.QueryArgs, reply *[]*analyzers.InfoRPC) error {
+	return aSv1.aS.V1StringQuery(args, reply)
+}
@@ -122,6 +122,11 @@ func (cdrSv1 *CDRsV1) GetCDRs(args utils.RPCCDRsFilterWithArgDispatcher, reply *
 	return cdrSv1.CDRs.V1GetCDRs(args, reply)
 }
 
+// GetDedupStats returns the number of duplicated events suppressed for each tenant
+func (cdrSv1 *CDRsV1) GetDedupStats(args *engine.ArgsGetDedupStats, reply *map[string]int64) error {
+	return cdrSv1.CDRs.V1GetDedupStats(args, reply)
+}
+
 func (cdrSv1 *CDRsV1) Ping(ign *utils.CGREventWithArgDispatcher, reply *string) error {
 	*reply = utils.Pong
 	return nil
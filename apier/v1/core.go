@@ -42,6 +42,16 @@ func (cS *CoreSv1) Status(arg *utils.TenantWithArgDispatcher, reply *map[string]
 	return cS.cS.Status(arg, reply)
 }
 
+// Metrics returns the runtime metrics of the engine subsystems
+func (cS *CoreSv1) Metrics(args *utils.ArgsGetMetricsWithArgDispatcher, reply *[]*utils.MetricFamily) error {
+	return cS.cS.Metrics(args, reply)
+}
+
+// SetLogLevel changes the log level of the engine or of one subsystem at runtime
+func (cS *CoreSv1) SetLogLevel(args *utils.ArgsSetLogLevelWithArgDispatcher, reply *string) error {
+	return cS.cS.SetLogLevel(args, reply)
+}
+
 // Ping used to detreminate if component is active
 func (cS *CoreSv1) Ping(ign *utils.CGREventWithArgDispatcher, reply *string) error {
 	*reply = utils.Pong
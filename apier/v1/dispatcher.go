@@ -815,6 +815,16 @@ func (dS *DispatcherCoreSv1) Ping(args *utils.CGREventWithArgDispatcher, reply *
 	return dS.dS.CoreSv1Ping(args, reply)
 }
 
+// Metrics returns the runtime metrics of the engine subsystems
+func (dS *DispatcherCoreSv1) Metrics(args *utils.ArgsGetMetricsWithArgDispatcher, reply *[]*utils.MetricFamily) error {
+	return dS.dS.CoreSv1Metrics(args, reply)
+}
+
+// SetLogLevel changes the log level of the engine or of one subsystem at runtime
+func (dS *DispatcherCoreSv1) SetLogLevel(args *utils.ArgsSetLogLevelWithArgDispatcher, reply *string) error {
+	return dS.dS.CoreSv1SetLogLevel(args, reply)
+}
+
 func NewDispatcherRALsV1(dps *dispatchers.DispatcherService) *DispatcherRALsV1 {
 	return &DispatcherRALsV1{dS: dps}
 }
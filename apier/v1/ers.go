@@ -0,0 +1,67 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+package v1
+
+import (
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// ERService is implemented by ers.ERService, not imported since ers tests depend on apier
+type ERService interface {
+	V1GetCheckpoint(args *utils.ArgsERsCheckpoint, reply *utils.ERsCheckpoint) error
+	V1ResetCheckpoint(args *utils.ArgsERsCheckpoint, reply *string) error
+	V1GetDedupStats(args *engine.ArgsGetDedupStats, reply *map[string]int64) error
+}
+
+// NewErSv1 initializes ErSv1
+func NewErSv1(erS ERService) *ErSv1 {
+	return &ErSv1{erS: erS}
+}
+
+// ErSv1 exports RPC from ERs
+type ErSv1 struct {
+	erS ERService
+}
+
+// Call implements rpcclient.ClientConnector interface for internal RPC
+func (erSv1 *ErSv1) Call(serviceMethod string,
+	args interface{}, reply interface{}) error {
+	return utils.APIerRPCCall(erSv1, serviceMethod, args, reply)
+}
+
+// Ping return pong if the service is active
+func (erSv1 *ErSv1) Ping(ign *utils.CGREvent, reply *string) error {
+	*reply = utils.Pong
+	return nil
+}
+
+// GetCheckpoint returns the checkpoint of a reader
+func (erSv1 *ErSv1) GetCheckpoint(args *utils.ArgsERsCheckpoint, reply *utils.ERsCheckpoint) error {
+	return erSv1.erS.V1GetCheckpoint(args, reply)
+}
+
+// ResetCheckpoint removes the checkpoint of a reader or only the one of a file
+func (erSv1 *ErSv1) ResetCheckpoint(args *utils.ArgsERsCheckpoint, reply *string) error {
+	return erSv1.erS.V1ResetCheckpoint(args, reply)
+}
+
+// GetDedupStats returns the number of duplicated events suppressed for each reader
+func (erSv1 *ErSv1) GetDedupStats(args *engine.ArgsGetDedupStats, reply *map[string]int64) error {
+	return erSv1.erS.V1GetDedupStats(args, reply)
+}
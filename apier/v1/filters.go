@@ -35,6 +35,9 @@ func (APIerSv1 *APIerSv1) SetFilter(arg *FilterWithCache, reply *string) error {
 	if missing := utils.MissingStructFields(arg.Filter, []string{"Tenant", "ID"}); len(missing) != 0 {
 		return utils.NewErrMandatoryIeMissing(missing...)
 	}
+	if err := arg.Filter.Compile(); err != nil {
+		return utils.NewErrServerError(err)
+	}
 	if err := APIerSv1.DataManager.SetFilter(arg.Filter); err != nil {
 		return utils.APIErrorHandler(err)
 	}
@@ -111,3 +114,26 @@ func (APIerSv1 *APIerSv1) RemoveFilter(arg utils.TenantIDWithCache, reply *strin
 	*reply = utils.OK
 	return nil
 }
+
+// ExplainFilters evaluates the filters, or the filters of a profile, against the event
+// returning the details of each rule evaluation, without side effects
+func (APIerSv1 *APIerSv1) ExplainFilters(args *engine.ArgsExplainFilters, reply *engine.FiltersExplanation) error {
+	if args.CGREvent == nil {
+		return utils.NewErrMandatoryIeMissing(utils.Event)
+	}
+	if missing := utils.MissingStructFields(args.CGREvent, []string{utils.Tenant}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	if len(args.FilterIDs) == 0 && args.ProfileType == utils.EmptyString {
+		return utils.NewErrMandatoryIeMissing(utils.FilterIDs)
+	}
+	if args.ProfileType != utils.EmptyString && args.ProfileID == utils.EmptyString {
+		return utils.NewErrMandatoryIeMissing("ProfileID")
+	}
+	fltrsExp, err := APIerSv1.FilterS.ExplainFilters(args)
+	if err != nil {
+		return utils.APIErrorHandler(err)
+	}
+	*reply = *fltrsExp
+	return nil
+}
@@ -290,6 +290,10 @@ func testPrecacheGetCacheStatsAfterRestart(t *testing.T) {
 			Items:  0,
 			Groups: 0,
 		},
+		utils.CacheRadiusPackets: {
+			Items:  0,
+			Groups: 0,
+		},
 		utils.CacheClosedSessions: {
 			Items:  0,
 			Groups: 0,
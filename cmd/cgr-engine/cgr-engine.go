@@ -232,6 +232,12 @@ func initLogger(cfg *config.CGRConfig) error {
 	if err != nil {
 		return err
 	}
+	if err = utils.Logger.SetLogFormat(cfg.GeneralCfg().LogFormat); err != nil {
+		return err
+	}
+	for subsys, lvl := range cfg.GeneralCfg().SubsystemLogLevels {
+		utils.Logger.SetSubsystemLogLevel(subsys, lvl)
+	}
 	return nil
 }
 
@@ -448,6 +454,7 @@ func main() {
 	// Done initing DBs
 	engine.SetRoundingDecimals(cfg.GeneralCfg().RoundingDecimals)
 	engine.SetFailedPostCacheTTL(cfg.GeneralCfg().FailedPostsTTL)
+	utils.SetConvertersTimezone(cfg.GeneralCfg().DefaultTimezone)
 
 	// Rpc/http server
 	server := utils.NewServer()
@@ -455,6 +462,9 @@ func main() {
 	if *httpPprofPath != "" {
 		go server.RegisterProfiler(*httpPprofPath)
 	}
+	if cfg.HTTPCfg().HTTPMetricsURL != "" {
+		server.RegisterHttpHandler(cfg.HTTPCfg().HTTPMetricsURL, utils.RuntimeMetrics)
+	}
 	// Async starts here, will follow cgrates.json start order
 
 	// Define internal connections via channels
@@ -502,11 +512,11 @@ func main() {
 
 	ldrs := services.NewLoaderService(cfg, dmService, filterSChan, server, exitChan,
 		internalLoaderSChan, connManager)
-	anz := services.NewAnalyzerService(cfg, server, exitChan, internalAnalyzerSChan)
+	anz := services.NewAnalyzerService(cfg, server, exitChan, internalAnalyzerSChan, connManager)
 
 	srvManager.AddServices(attrS, chrS, tS, stS, reS, supS, schS, rals,
 		rals.GetResponder(), APIerSv1, APIerSv2, cdrS, smg,
-		services.NewEventReaderService(cfg, filterSChan, exitChan, connManager),
+		services.NewEventReaderService(cfg, dmService, filterSChan, exitChan, server, connManager),
 		services.NewDNSAgent(cfg, filterSChan, exitChan, connManager),
 		services.NewFreeswitchAgent(cfg, exitChan, connManager),
 		services.NewKamailioAgent(cfg, exitChan, connManager),
@@ -514,6 +524,8 @@ func main() {
 		services.NewRadiusAgent(cfg, filterSChan, exitChan, connManager),   // partial reload
 		services.NewDiameterAgent(cfg, filterSChan, exitChan, connManager), // partial reload
 		services.NewHTTPAgent(cfg, filterSChan, server, connManager),       // no reload
+		services.NewPrometheusAgent(cfg, filterSChan, server, connManager), // no reload
+		services.NewTracingService(cfg),
 		ldrs, anz, dspS, dmService, storDBService,
 	)
 	srvManager.StartServices()
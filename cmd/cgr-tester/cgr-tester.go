@@ -67,6 +67,14 @@ var (
 	usage          = cgrTesterFlags.String("usage", "1m", "The duration to use in call simulation.")
 	fPath          = cgrTesterFlags.String("file_path", "", "read requests from file with path")
 	reqSep         = cgrTesterFlags.String("req_separator", "\n\n", "separator for requests in file")
+	replayFile     = cgrTesterFlags.String("replay_file", "", "replay the traffic captured in this JSON-lines file towards rater_address")
+	replayAnalyzer = cgrTesterFlags.String("replay_analyzer", "", "replay the traffic captured by the AnalyzerS at this address towards rater_address")
+	replayQuery    = cgrTesterFlags.String("replay_query", "", "HeaderFilters used when querying the AnalyzerS for traffic")
+	replayMethods  = cgrTesterFlags.String("replay_methods", "SessionSv1.,CDRsV1.", "comma separated prefixes of the replayed methods")
+	replayStart    = cgrTesterFlags.String("replay_start", "", "replay only the traffic started after this time")
+	replayEnd      = cgrTesterFlags.String("replay_end", "", "replay only the traffic started before this time")
+	replaySpeed    = cgrTesterFlags.Float64("replay_speed", 0, "multiplier of the original timing (eg: 1 for real time, 2 for double speed), 0 to replay without delays")
+	replayReport   = cgrTesterFlags.String("replay_report", "", "write the replay report into this file instead of stdout")
 
 	err error
 )
@@ -206,6 +214,12 @@ func main() {
 		pprof.StartCPUProfile(f)
 		defer pprof.StopCPUProfile()
 	}
+	if *replayFile != "" || *replayAnalyzer != "" {
+		if err := replayTraffic(); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 	if *fPath != "" {
 		frt, err := NewFileReaderTester(*fPath, *raterAddress,
 			*parallel, *runs, []byte(*reqSep))
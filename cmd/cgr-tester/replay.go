@@ -0,0 +1,315 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package main
+
+import (
+	"bufio"
+	jsn "encoding/json"
+	"fmt"
+	"io"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cgrates/cgrates/analyzers"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// the fields compared in the replies, together with the error
+var replayReportFields = []string{utils.Cost, utils.CapMaxUsage}
+
+// NewReplayer returns a Replayer sending the traffic towards the engine at cgrAddr
+func NewReplayer(cgrAddr string, methods []string, speed float64) (rpl *Replayer, err error) {
+	rpl = &Replayer{
+		methods: methods,
+		speed:   speed,
+	}
+	if rpl.client, err = jsonrpc.Dial(utils.TCP, cgrAddr); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// Replayer sends the captured traffic to another engine and compares the replies
+type Replayer struct {
+	methods []string // method prefixes to replay
+	speed   float64  // 0 to send the requests one after the other, 1 to keep the original timing
+	client  *rpc.Client
+}
+
+// ReplayDiff is the comparison between the recorded and replayed reply of one request
+type ReplayDiff struct {
+	RequestID        uint64
+	RequestMethod    string
+	RequestStartTime time.Time
+	RecordedError    string
+	ReplayError      string
+	RecordedFields   map[string]interface{} // the replayReportFields out of the recorded reply
+	ReplayFields     map[string]interface{} // the replayReportFields out of the replayed reply
+	Equal            bool                   // true if both the replayReportFields and the errors are the same
+}
+
+// ReplayReport is the result of one replay
+type ReplayReport struct {
+	Total      int
+	Equal      int
+	Different  int
+	Diffs      []*ReplayDiff // only the requests with different replies
+	ReplayTime time.Duration
+}
+
+// ReadTrafficFromFile reads the captured traffic from a JSON-lines file
+func ReadTrafficFromFile(fPath string) (infs []*analyzers.InfoRPC, err error) {
+	var f *os.File
+	if f, err = os.Open(fPath); err != nil {
+		return
+	}
+	defer f.Close()
+	return readTraffic(f)
+}
+
+func readTraffic(rdr io.Reader) (infs []*analyzers.InfoRPC, err error) {
+	scnr := bufio.NewScanner(rdr)
+	scnr.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for lnNr := 1; scnr.Scan(); lnNr++ {
+		ln := strings.TrimSpace(scnr.Text())
+		if ln == utils.EmptyString {
+			continue
+		}
+		inf := new(analyzers.InfoRPC)
+		if err = jsn.Unmarshal([]byte(ln), inf); err != nil {
+			return nil, fmt.Errorf("line %d: %s", lnNr, err.Error())
+		}
+		infs = append(infs, inf)
+	}
+	err = scnr.Err()
+	return
+}
+
+// ReadTrafficFromAnalyzer queries the AnalyzerS of the engine at anzAddr for the captured traffic
+func ReadTrafficFromAnalyzer(anzAddr string, args *analyzers.QueryArgs) (infs []*analyzers.InfoRPC, err error) {
+	var client *rpc.Client
+	if client, err = jsonrpc.Dial(utils.TCP, anzAddr); err != nil {
+		return
+	}
+	defer client.Close()
+	err = client.Call(utils.AnalyzerSv1StringQuery, args, &infs)
+	return
+}
+
+// replayMethod checks if the method should be replayed
+func (rpl *Replayer) replayMethod(method string) bool {
+	if len(rpl.methods) == 0 {
+		return true
+	}
+	for _, mthdPrfx := range rpl.methods {
+		if strings.HasPrefix(method, mthdPrfx) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterTraffic keeps only the requests for the replayed methods in the given interval
+// the requests sent by the engine itself (ie: SessionS towards CDRs) are ignored
+// since the replayed engine will send them again while processing the received ones
+func (rpl *Replayer) filterTraffic(infs []*analyzers.InfoRPC, sTime, eTime time.Time) (fltrd []*analyzers.InfoRPC) {
+	for _, inf := range infs {
+		if inf.Outgoing {
+			continue
+		}
+		if !sTime.IsZero() && inf.RequestStartTime.Before(sTime) {
+			continue
+		}
+		if !eTime.IsZero() && !inf.RequestStartTime.Before(eTime) {
+			continue
+		}
+		if !rpl.replayMethod(inf.RequestMethod) {
+			continue
+		}
+		fltrd = append(fltrd, inf)
+	}
+	sort.SliceStable(fltrd, func(i, j int) bool {
+		return fltrd[i].RequestStartTime.Before(fltrd[j].RequestStartTime)
+	})
+	return
+}
+
+// Replay sends the traffic in the interval and returns the report
+func (rpl *Replayer) Replay(infs []*analyzers.InfoRPC, sTime, eTime time.Time) (rprt *ReplayReport) {
+	infs = rpl.filterTraffic(infs, sTime, eTime)
+	rprt = &ReplayReport{Total: len(infs)}
+	diffs := make([]*ReplayDiff, len(infs))
+	start := time.Now()
+	if rpl.speed <= 0 {
+		for i, inf := range infs {
+			diffs[i] = rpl.replayRequest(inf)
+		}
+	} else {
+		var wg sync.WaitGroup
+		for i, inf := range infs {
+			if wait := time.Duration(float64(inf.RequestStartTime.Sub(infs[0].RequestStartTime))/rpl.speed) -
+				time.Since(start); wait > 0 {
+				time.Sleep(wait)
+			}
+			wg.Add(1)
+			go func(i int, inf *analyzers.InfoRPC) {
+				diffs[i] = rpl.replayRequest(inf)
+				wg.Done()
+			}(i, inf)
+		}
+		wg.Wait()
+	}
+	rprt.ReplayTime = time.Since(start)
+	for _, diff := range diffs {
+		if diff.Equal {
+			rprt.Equal++
+			continue
+		}
+		rprt.Different++
+		rprt.Diffs = append(rprt.Diffs, diff)
+	}
+	return
+}
+
+// replayRequest sends one request and compares the replies
+func (rpl *Replayer) replayRequest(inf *analyzers.InfoRPC) (diff *ReplayDiff) {
+	var reply interface{}
+	err := rpl.client.Call(inf.RequestMethod, inf.RequestParams, &reply)
+	return compareReplies(inf, reply, err)
+}
+
+// compareReplies builds the diff between the recorded reply and the replayed one
+func compareReplies(inf *analyzers.InfoRPC, reply interface{}, err error) (diff *ReplayDiff) {
+	diff = &ReplayDiff{
+		RequestID:        inf.RequestID,
+		RequestMethod:    inf.RequestMethod,
+		RequestStartTime: inf.RequestStartTime,
+		RecordedError:    inf.ReplyError,
+		RecordedFields:   make(map[string]interface{}),
+		ReplayFields:     make(map[string]interface{}),
+	}
+	if err != nil {
+		diff.ReplayError = err.Error()
+	}
+	for _, fldName := range replayReportFields {
+		extractReplyFields(inf.Reply, fldName, utils.EmptyString, diff.RecordedFields)
+		extractReplyFields(reply, fldName, utils.EmptyString, diff.ReplayFields)
+	}
+	diff.Equal = diff.RecordedError == diff.ReplayError &&
+		reflect.DeepEqual(diff.RecordedFields, diff.ReplayFields)
+	return
+}
+
+// extractReplyFields walks the reply and populates the values of the fields
+// with the given name indexed by their path
+func extractReplyFields(reply interface{}, fldName, path string, flds map[string]interface{}) {
+	switch rply := reply.(type) {
+	case map[string]interface{}:
+		for key, val := range rply {
+			fldPath := key
+			if path != utils.EmptyString {
+				fldPath = path + utils.NestingSep + key
+			}
+			if key == fldName {
+				flds[fldPath] = val
+				continue
+			}
+			extractReplyFields(val, fldName, fldPath, flds)
+		}
+	case []interface{}:
+		for i, val := range rply {
+			extractReplyFields(val, fldName, fmt.Sprintf("%s[%d]", path, i), flds)
+		}
+	}
+}
+
+// WriteReport writes the report as JSON-lines, first the diffs and then the summary
+func (rprt *ReplayReport) WriteReport(w io.Writer) (err error) {
+	enc := jsn.NewEncoder(w)
+	for _, diff := range rprt.Diffs {
+		if err = enc.Encode(diff); err != nil {
+			return
+		}
+	}
+	return enc.Encode(map[string]interface{}{
+		"Total":      rprt.Total,
+		"Equal":      rprt.Equal,
+		"Different":  rprt.Different,
+		"ReplayTime": rprt.ReplayTime.String(),
+	})
+}
+
+// replayTraffic replays the traffic based on the command line flags
+func replayTraffic() (err error) {
+	if *raterAddress == utils.EmptyString {
+		return fmt.Errorf("missing rater_address for replay")
+	}
+	var sTime, eTime time.Time
+	if *replayStart != utils.EmptyString {
+		if sTime, err = utils.ParseTimeDetectLayout(*replayStart,
+			tstCfg.GeneralCfg().DefaultTimezone); err != nil {
+			return
+		}
+	}
+	if *replayEnd != utils.EmptyString {
+		if eTime, err = utils.ParseTimeDetectLayout(*replayEnd,
+			tstCfg.GeneralCfg().DefaultTimezone); err != nil {
+			return
+		}
+	}
+	var infs []*analyzers.InfoRPC
+	if *replayFile != utils.EmptyString {
+		infs, err = ReadTrafficFromFile(*replayFile)
+	} else {
+		infs, err = ReadTrafficFromAnalyzer(*replayAnalyzer, &analyzers.QueryArgs{
+			HeaderFilters: *replayQuery,
+			StartTime:     sTime,
+			EndTime:       eTime,
+		})
+	}
+	if err != nil {
+		return
+	}
+	var methods []string
+	if *replayMethods != utils.EmptyString {
+		methods = strings.Split(*replayMethods, utils.FIELDS_SEP)
+	}
+	var rpl *Replayer
+	if rpl, err = NewReplayer(*raterAddress, methods, *replaySpeed); err != nil {
+		return
+	}
+	defer rpl.client.Close()
+	rprt := rpl.Replay(infs, sTime, eTime)
+	out := io.Writer(os.Stdout)
+	if *replayReport != utils.EmptyString {
+		var f *os.File
+		if f, err = os.Create(*replayReport); err != nil {
+			return
+		}
+		defer f.Close()
+		out = f
+	}
+	return rprt.WriteReport(out)
+}
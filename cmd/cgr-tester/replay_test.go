@@ -0,0 +1,120 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/analyzers"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestReplayReadTraffic(t *testing.T) {
+	rdr := strings.NewReader(`{"RequestID":1,"RequestMethod":"SessionSv1.AuthorizeEvent","RequestStartTime":"2020-04-01T10:00:00Z","Reply":{"MaxUsage":60000000000}}
+
+{"RequestID":2,"RequestMethod":"CDRsV1.ProcessEvent","RequestStartTime":"2020-04-01T10:00:01Z","Reply":"OK"}
+`)
+	infs, err := readTraffic(rdr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infs) != 2 {
+		t.Fatalf("Expected 2 requests, received: %s", utils.ToJSON(infs))
+	}
+	if infs[1].RequestMethod != utils.CDRsV1ProcessEvent ||
+		!infs[1].RequestStartTime.Equal(time.Date(2020, 4, 1, 10, 0, 1, 0, time.UTC)) {
+		t.Errorf("Unexpected request: %s", utils.ToJSON(infs[1]))
+	}
+	if _, err := readTraffic(strings.NewReader("{")); err == nil {
+		t.Error("Expected error for invalid line")
+	}
+}
+
+func TestReplayFilterTraffic(t *testing.T) {
+	sTime := time.Date(2020, 4, 1, 10, 0, 0, 0, time.UTC)
+	infs := []*analyzers.InfoRPC{
+		{RequestID: 1, RequestMethod: utils.CDRsV1ProcessEvent, RequestStartTime: sTime.Add(2 * time.Second)},
+		{RequestID: 2, RequestMethod: utils.SessionSv1AuthorizeEvent, RequestStartTime: sTime.Add(time.Second)},
+		{RequestID: 3, RequestMethod: utils.CoreSv1Status, RequestStartTime: sTime.Add(time.Second)},
+		{RequestID: 4, RequestMethod: utils.SessionSv1AuthorizeEvent, RequestStartTime: sTime.Add(time.Hour)},
+		{RequestID: 5, RequestMethod: utils.CDRsV1ProcessEvent, RequestStartTime: sTime.Add(3 * time.Second), Outgoing: true},
+	}
+	rpl := &Replayer{methods: []string{"SessionSv1.", "CDRsV1."}}
+	fltrd := rpl.filterTraffic(infs, sTime, sTime.Add(time.Minute))
+	exp := []*analyzers.InfoRPC{infs[1], infs[0]}
+	if !reflect.DeepEqual(exp, fltrd) {
+		t.Errorf("Expected: %s, received: %s", utils.ToJSON(exp), utils.ToJSON(fltrd))
+	}
+}
+
+func TestReplayCompareReplies(t *testing.T) {
+	inf := &analyzers.InfoRPC{
+		RequestID:     1,
+		RequestMethod: utils.SessionSv1InitiateSession,
+		Reply: map[string]interface{}{
+			utils.CapMaxUsage: 60000000000.,
+			"Attributes": map[string]interface{}{
+				"Cost": 1.2,
+			},
+		},
+	}
+	if diff := compareReplies(inf, map[string]interface{}{
+		utils.CapMaxUsage: 60000000000.,
+		"Attributes": map[string]interface{}{
+			"Cost": 1.2,
+		},
+	}, nil); !diff.Equal {
+		t.Errorf("Expected equal replies: %s", utils.ToJSON(diff))
+	}
+	if diff := compareReplies(inf, map[string]interface{}{ // only the reported fields are compared
+		utils.CapMaxUsage: 60000000000.,
+		"Attributes": map[string]interface{}{
+			"Cost":      1.2,
+			"SetupTime": "2020-04-01T10:00:00Z",
+			utils.CGRID: "8f2c9b2ca7e5ad7f0a3ac3ef9e7bd4e3c29a4c1b",
+		},
+	}, nil); !diff.Equal {
+		t.Errorf("Expected equal replies: %s", utils.ToJSON(diff))
+	}
+	if diff := compareReplies(inf, map[string]interface{}{
+		utils.CapMaxUsage: 30000000000.,
+		"Attributes": map[string]interface{}{
+			"Cost": 1.2,
+		},
+	}, nil); diff.Equal {
+		t.Errorf("Expected different replies: %s", utils.ToJSON(diff))
+	}
+	exp := &ReplayDiff{
+		RequestID:     1,
+		RequestMethod: utils.SessionSv1InitiateSession,
+		ReplayError:   utils.ErrInsufficientCredit.Error(),
+		RecordedFields: map[string]interface{}{
+			utils.CapMaxUsage: 60000000000.,
+			"Attributes.Cost": 1.2,
+		},
+		ReplayFields: map[string]interface{}{},
+	}
+	if diff := compareReplies(inf, nil, errors.New(utils.ErrInsufficientCredit.Error())); !reflect.DeepEqual(exp, diff) {
+		t.Errorf("Expected: %s, received: %s", utils.ToJSON(exp), utils.ToJSON(diff))
+	}
+}
@@ -18,9 +18,17 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 
 package config
 
-// AttributeSCfg is the configuration of attribute service
+import (
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+// AnalyzerSCfg is the configuration of analyzer service
 type AnalyzerSCfg struct {
-	Enabled bool
+	Enabled         bool
+	TTL             time.Duration // how long the captured traffic is kept
+	CleanupInterval time.Duration // how often the expired traffic is removed
 }
 
 func (alS *AnalyzerSCfg) loadFromJsonCfg(jsnCfg *AnalyzerSJsonCfg) (err error) {
@@ -30,5 +38,15 @@ func (alS *AnalyzerSCfg) loadFromJsonCfg(jsnCfg *AnalyzerSJsonCfg) (err error) {
 	if jsnCfg.Enabled != nil {
 		alS.Enabled = *jsnCfg.Enabled
 	}
+	if jsnCfg.Ttl != nil {
+		if alS.TTL, err = utils.ParseDurationWithNanosecs(*jsnCfg.Ttl); err != nil {
+			return
+		}
+	}
+	if jsnCfg.Cleanup_interval != nil {
+		if alS.CleanupInterval, err = utils.ParseDurationWithNanosecs(*jsnCfg.Cleanup_interval); err != nil {
+			return
+		}
+	}
 	return nil
 }
@@ -140,6 +140,7 @@ func NewDefaultCGRConfig() (cfg *CGRConfig, err error) {
 	cfg.rpcConns = make(map[string]*RPCConn)
 	cfg.generalCfg = new(GeneralCfg)
 	cfg.generalCfg.NodeID = utils.UUIDSha1Prefix()
+	cfg.dfltNodeID = cfg.generalCfg.NodeID
 	cfg.dataDbCfg = new(DataDbCfg)
 	cfg.dataDbCfg.Items = make(map[string]*ItemOpt)
 	cfg.storDbCfg = new(StorDbCfg)
@@ -156,6 +157,8 @@ func NewDefaultCGRConfig() (cfg *CGRConfig, err error) {
 	cfg.cdrsCfg = new(CdrsCfg)
 	cfg.CdreProfiles = make(map[string]*CdreCfg)
 	cfg.analyzerSCfg = new(AnalyzerSCfg)
+	cfg.tracingCfg = new(TracingCfg)
+	cfg.dedupCfg = new(DedupCfg)
 	cfg.sessionSCfg = new(SessionSCfg)
 	cfg.fsAgentCfg = new(FsAgentCfg)
 	cfg.kamAgentCfg = new(KamAgentCfg)
@@ -163,6 +166,7 @@ func NewDefaultCGRConfig() (cfg *CGRConfig, err error) {
 	cfg.diameterAgentCfg = new(DiameterAgentCfg)
 	cfg.radiusAgentCfg = new(RadiusAgentCfg)
 	cfg.dnsAgentCfg = new(DNSAgentCfg)
+	cfg.prometheusAgentCfg = new(PrometheusAgentCfg)
 	cfg.attributeSCfg = new(AttributeSCfg)
 	cfg.chargerSCfg = new(ChargerSCfg)
 	cfg.resourceSCfg = new(ResourceSConfig)
@@ -248,6 +252,7 @@ type CGRConfig struct {
 	// Cache defaults loaded from json and needing clones
 	dfltCdreProfile *CdreCfg        // Default cdreConfig profile
 	dfltEvRdr       *EventReaderCfg // default event reader
+	dfltNodeID      string          // node_id generated when not configured, different on each start
 
 	CdreProfiles map[string]*CdreCfg // Cdre config profiles
 	loaderCfg    LoaderSCfgs         // LoaderS configs
@@ -258,38 +263,41 @@ type CGRConfig struct {
 
 	rpcConns map[string]*RPCConn
 
-	generalCfg       *GeneralCfg       // General config
-	dataDbCfg        *DataDbCfg        // Database config
-	storDbCfg        *StorDbCfg        // StroreDb config
-	tlsCfg           *TlsCfg           // TLS config
-	cacheCfg         CacheCfg          // Cache config
-	listenCfg        *ListenCfg        // Listen config
-	httpCfg          *HTTPCfg          // HTTP config
-	filterSCfg       *FilterSCfg       // FilterS config
-	ralsCfg          *RalsCfg          // Rals config
-	schedulerCfg     *SchedulerCfg     // Scheduler config
-	cdrsCfg          *CdrsCfg          // Cdrs config
-	sessionSCfg      *SessionSCfg      // SessionS config
-	fsAgentCfg       *FsAgentCfg       // FreeSWITCHAgent config
-	kamAgentCfg      *KamAgentCfg      // KamailioAgent config
-	asteriskAgentCfg *AsteriskAgentCfg // AsteriskAgent config
-	diameterAgentCfg *DiameterAgentCfg // DiameterAgent config
-	radiusAgentCfg   *RadiusAgentCfg   // RadiusAgent config
-	dnsAgentCfg      *DNSAgentCfg      // DNSAgent config
-	attributeSCfg    *AttributeSCfg    // AttributeS config
-	chargerSCfg      *ChargerSCfg      // ChargerS config
-	resourceSCfg     *ResourceSConfig  // ResourceS config
-	statsCfg         *StatSCfg         // StatS config
-	thresholdSCfg    *ThresholdSCfg    // ThresholdS config
-	supplierSCfg     *SupplierSCfg     // SupplierS config
-	sureTaxCfg       *SureTaxCfg       // SureTax config
-	dispatcherSCfg   *DispatcherSCfg   // DispatcherS config
-	loaderCgrCfg     *LoaderCgrCfg     // LoaderCgr config
-	migratorCgrCfg   *MigratorCgrCfg   // MigratorCgr config
-	mailerCfg        *MailerCfg        // Mailer config
-	analyzerSCfg     *AnalyzerSCfg     // AnalyzerS config
-	apier            *ApierCfg
-	ersCfg           *ERsCfg
+	generalCfg         *GeneralCfg         // General config
+	dataDbCfg          *DataDbCfg          // Database config
+	storDbCfg          *StorDbCfg          // StroreDb config
+	tlsCfg             *TlsCfg             // TLS config
+	cacheCfg           CacheCfg            // Cache config
+	listenCfg          *ListenCfg          // Listen config
+	httpCfg            *HTTPCfg            // HTTP config
+	filterSCfg         *FilterSCfg         // FilterS config
+	ralsCfg            *RalsCfg            // Rals config
+	schedulerCfg       *SchedulerCfg       // Scheduler config
+	cdrsCfg            *CdrsCfg            // Cdrs config
+	sessionSCfg        *SessionSCfg        // SessionS config
+	fsAgentCfg         *FsAgentCfg         // FreeSWITCHAgent config
+	kamAgentCfg        *KamAgentCfg        // KamailioAgent config
+	asteriskAgentCfg   *AsteriskAgentCfg   // AsteriskAgent config
+	diameterAgentCfg   *DiameterAgentCfg   // DiameterAgent config
+	radiusAgentCfg     *RadiusAgentCfg     // RadiusAgent config
+	dnsAgentCfg        *DNSAgentCfg        // DNSAgent config
+	prometheusAgentCfg *PrometheusAgentCfg // PrometheusAgent config
+	attributeSCfg      *AttributeSCfg      // AttributeS config
+	chargerSCfg        *ChargerSCfg        // ChargerS config
+	resourceSCfg       *ResourceSConfig    // ResourceS config
+	statsCfg           *StatSCfg           // StatS config
+	thresholdSCfg      *ThresholdSCfg      // ThresholdS config
+	supplierSCfg       *SupplierSCfg       // SupplierS config
+	sureTaxCfg         *SureTaxCfg         // SureTax config
+	dispatcherSCfg     *DispatcherSCfg     // DispatcherS config
+	loaderCgrCfg       *LoaderCgrCfg       // LoaderCgr config
+	migratorCgrCfg     *MigratorCgrCfg     // MigratorCgr config
+	mailerCfg          *MailerCfg          // Mailer config
+	analyzerSCfg       *AnalyzerSCfg       // AnalyzerS config
+	tracingCfg         *TracingCfg         // Tracing config
+	dedupCfg           *DedupCfg           // Dedup config
+	apier              *ApierCfg
+	ersCfg             *ERsCfg
 }
 
 var posibleLoaderTypes = utils.NewStringSet([]string{utils.MetaAttributes,
@@ -299,7 +307,8 @@ var posibleLoaderTypes = utils.NewStringSet([]string{utils.MetaAttributes,
 
 var possibleReaderTypes = utils.NewStringSet([]string{utils.MetaFileCSV,
 	utils.MetaKafkajsonMap, utils.MetaFileXML, utils.MetaSQL, utils.MetaFileFWV,
-	utils.MetaPartialCSV, utils.MetaFlatstore, utils.MetaJSON})
+	utils.MetaPartialCSV, utils.MetaFlatstore, utils.MetaJSON, utils.MetaAMQPjsonMap,
+	utils.MetaSQSjsonMap, utils.MetaS3jsonMap, utils.MetaNatsjsonMap, utils.MetaFileASN1, utils.MetaHTTPjson, utils.MetaHTTPform})
 
 func (cfg *CGRConfig) LazySanityCheck() {
 	for _, cdrePrfl := range cfg.cdrsCfg.OnlineCDRExports {
@@ -329,12 +338,13 @@ func (cfg *CGRConfig) loadFromJsonCfg(jsnCfg *CgrJsonCfg) (err error) {
 		cfg.loadCdrsCfg, cfg.loadCdreCfg, cfg.loadSessionSCfg,
 		cfg.loadFreeswitchAgentCfg, cfg.loadKamAgentCfg,
 		cfg.loadAsteriskAgentCfg, cfg.loadDiameterAgentCfg, cfg.loadRadiusAgentCfg,
-		cfg.loadDNSAgentCfg, cfg.loadHttpAgentCfg, cfg.loadAttributeSCfg,
+		cfg.loadDNSAgentCfg, cfg.loadPrometheusAgentCfg, cfg.loadHttpAgentCfg, cfg.loadAttributeSCfg,
 		cfg.loadChargerSCfg, cfg.loadResourceSCfg, cfg.loadStatSCfg,
 		cfg.loadThresholdSCfg, cfg.loadSupplierSCfg, cfg.loadLoaderSCfg,
 		cfg.loadMailerCfg, cfg.loadSureTaxCfg, cfg.loadDispatcherSCfg,
 		cfg.loadLoaderCgrCfg, cfg.loadMigratorCgrCfg, cfg.loadTlsCgrCfg,
-		cfg.loadAnalyzerCgrCfg, cfg.loadApierCfg, cfg.loadErsCfg} {
+		cfg.loadAnalyzerCgrCfg, cfg.loadTracingCfg, cfg.loadDedupCfg,
+		cfg.loadApierCfg, cfg.loadErsCfg} {
 		if err = loadFunc(jsnCfg); err != nil {
 			return
 		}
@@ -453,6 +463,8 @@ func (cfg *CGRConfig) loadDataDBCfg(jsnCfg *CgrJsonCfg) (err error) {
 			utils.CacheLoadIDs:                 zeroLimit,
 			utils.CacheDiameterMessages: &CacheParamCfg{Limit: -1,
 				TTL: time.Duration(3 * time.Hour), StaticTTL: false},
+			utils.CacheRadiusPackets: &CacheParamCfg{Limit: -1,
+				TTL: time.Duration(3 * time.Hour), StaticTTL: false},
 			utils.CacheClosedSessions: &CacheParamCfg{Limit: -1,
 				TTL: time.Duration(10 * time.Second), StaticTTL: false},
 			utils.CacheRPCConnections: &CacheParamCfg{Limit: -1,
@@ -585,6 +597,15 @@ func (cfg *CGRConfig) loadRadiusAgentCfg(jsnCfg *CgrJsonCfg) (err error) {
 	return cfg.radiusAgentCfg.loadFromJsonCfg(jsnRACfg, cfg.generalCfg.RSRSep)
 }
 
+// loadPrometheusAgentCfg loads the PrometheusAgent section of the configuration
+func (cfg *CGRConfig) loadPrometheusAgentCfg(jsnCfg *CgrJsonCfg) (err error) {
+	var jsnPrmCfg *PrometheusAgentJsonCfg
+	if jsnPrmCfg, err = jsnCfg.PrometheusAgentJsonCfg(); err != nil {
+		return
+	}
+	return cfg.prometheusAgentCfg.loadFromJsonCfg(jsnPrmCfg)
+}
+
 // loadDNSAgentCfg loads the DNSAgent section of the configuration
 func (cfg *CGRConfig) loadDNSAgentCfg(jsnCfg *CgrJsonCfg) (err error) {
 	var jsnDNSCfg *DNSAgentJsonCfg
@@ -737,6 +758,24 @@ func (cfg *CGRConfig) loadAnalyzerCgrCfg(jsnCfg *CgrJsonCfg) (err error) {
 	return cfg.analyzerSCfg.loadFromJsonCfg(jsnAnalyzerCgrCfg)
 }
 
+// loadTracingCfg loads the Tracing section of the configuration
+func (cfg *CGRConfig) loadTracingCfg(jsnCfg *CgrJsonCfg) (err error) {
+	var jsnTracingCfg *TracingJsonCfg
+	if jsnTracingCfg, err = jsnCfg.TracingJsonCfg(); err != nil {
+		return
+	}
+	return cfg.tracingCfg.loadFromJsonCfg(jsnTracingCfg)
+}
+
+// loadDedupCfg loads the Dedup section of the configuration
+func (cfg *CGRConfig) loadDedupCfg(jsnCfg *CgrJsonCfg) (err error) {
+	var jsnDedupCfg *DedupJsonCfg
+	if jsnDedupCfg, err = jsnCfg.DedupJsonCfg(); err != nil {
+		return
+	}
+	return cfg.dedupCfg.loadFromJsonCfg(jsnDedupCfg, cfg.generalCfg.RSRSep)
+}
+
 // loadApierCfg loads the Apier section of the configuration
 func (cfg *CGRConfig) loadApierCfg(jsnCfg *CgrJsonCfg) (err error) {
 	var jsnApierCfg *ApierJsonCfg
@@ -776,6 +815,13 @@ func (cfg *CGRConfig) RadiusAgentCfg() *RadiusAgentCfg {
 	return cfg.radiusAgentCfg
 }
 
+// PrometheusAgentCfg returns the config for Prometheus Agent
+func (cfg *CGRConfig) PrometheusAgentCfg() *PrometheusAgentCfg {
+	cfg.lks[PrometheusAgentJson].Lock()
+	defer cfg.lks[PrometheusAgentJson].Unlock()
+	return cfg.prometheusAgentCfg
+}
+
 // DNSAgentCfg returns the config for DNS Agent
 func (cfg *CGRConfig) DNSAgentCfg() *DNSAgentCfg {
 	cfg.lks[DNSAgentJson].Lock()
@@ -930,6 +976,11 @@ func (cfg *CGRConfig) GeneralCfg() *GeneralCfg {
 	return cfg.generalCfg
 }
 
+// NodeIDGenerated returns true if the node_id was not configured, being generated on start
+func (cfg *CGRConfig) NodeIDGenerated() bool {
+	return cfg.GeneralCfg().NodeID == cfg.dfltNodeID
+}
+
 // TlsCfg returns the config for Tls
 func (cfg *CGRConfig) TlsCfg() *TlsCfg {
 	cfg.lks[TlsCfgJson].Lock()
@@ -979,6 +1030,20 @@ func (cfg *CGRConfig) AnalyzerSCfg() *AnalyzerSCfg {
 	return cfg.analyzerSCfg
 }
 
+// TracingCfg returns the config for the distributed tracing
+func (cfg *CGRConfig) TracingCfg() *TracingCfg {
+	cfg.lks[TracingJson].Lock()
+	defer cfg.lks[TracingJson].Unlock()
+	return cfg.tracingCfg
+}
+
+// DedupCfg returns the config for the event deduplication
+func (cfg *CGRConfig) DedupCfg() *DedupCfg {
+	cfg.lks[DedupJson].Lock()
+	defer cfg.lks[DedupJson].Unlock()
+	return cfg.dedupCfg
+}
+
 // ApierCfg reads the Apier configuration
 func (cfg *CGRConfig) ApierCfg() *ApierCfg {
 	cfg.lks[ApierS].Lock()
@@ -1058,6 +1123,8 @@ func (cfg *CGRConfig) V1GetConfigSection(args *StringWithArgDispatcher, reply *m
 		jsonString = utils.ToJSON(cfg.RadiusAgentCfg())
 	case DNSAgentJson:
 		jsonString = utils.ToJSON(cfg.DNSAgentCfg())
+	case PrometheusAgentJson:
+		jsonString = utils.ToJSON(cfg.PrometheusAgentCfg())
 	case ATTRIBUTE_JSN:
 		jsonString = utils.ToJSON(cfg.AttributeSCfg())
 	case ChargerSCfgJson:
@@ -1080,6 +1147,10 @@ func (cfg *CGRConfig) V1GetConfigSection(args *StringWithArgDispatcher, reply *m
 		jsonString = utils.ToJSON(cfg.LoaderCgrCfg())
 	case CgrMigratorCfgJson:
 		jsonString = utils.ToJSON(cfg.MigratorCgrCfg())
+	case TracingJson:
+		jsonString = utils.ToJSON(cfg.TracingCfg())
+	case DedupJson:
+		jsonString = utils.ToJSON(cfg.DedupCfg())
 	case ApierS:
 		jsonString = utils.ToJSON(cfg.ApierCfg())
 	case CDRE_JSN:
@@ -1159,42 +1230,45 @@ func (cfg *CGRConfig) V1ReloadConfigFromPath(args *ConfigReloadWithArgDispatcher
 
 func (cfg *CGRConfig) getLoadFunctions() map[string]func(*CgrJsonCfg) error {
 	return map[string]func(*CgrJsonCfg) error{
-		GENERAL_JSN:        cfg.loadGeneralCfg,
-		DATADB_JSN:         cfg.loadDataDBCfg,
-		STORDB_JSN:         cfg.loadStorDBCfg,
-		LISTEN_JSN:         cfg.loadListenCfg,
-		TlsCfgJson:         cfg.loadTlsCgrCfg,
-		HTTP_JSN:           cfg.loadHTTPCfg,
-		SCHEDULER_JSN:      cfg.loadSchedulerCfg,
-		CACHE_JSN:          cfg.loadCacheCfg,
-		FilterSjsn:         cfg.loadFilterSCfg,
-		RALS_JSN:           cfg.loadRalSCfg,
-		CDRS_JSN:           cfg.loadCdrsCfg,
-		CDRE_JSN:           cfg.loadCdreCfg,
-		ERsJson:            cfg.loadErsCfg,
-		SessionSJson:       cfg.loadSessionSCfg,
-		AsteriskAgentJSN:   cfg.loadAsteriskAgentCfg,
-		FreeSWITCHAgentJSN: cfg.loadFreeswitchAgentCfg,
-		KamailioAgentJSN:   cfg.loadKamAgentCfg,
-		DA_JSN:             cfg.loadDiameterAgentCfg,
-		RA_JSN:             cfg.loadRadiusAgentCfg,
-		HttpAgentJson:      cfg.loadHttpAgentCfg,
-		DNSAgentJson:       cfg.loadDNSAgentCfg,
-		ATTRIBUTE_JSN:      cfg.loadAttributeSCfg,
-		ChargerSCfgJson:    cfg.loadChargerSCfg,
-		RESOURCES_JSON:     cfg.loadResourceSCfg,
-		STATS_JSON:         cfg.loadStatSCfg,
-		THRESHOLDS_JSON:    cfg.loadThresholdSCfg,
-		SupplierSJson:      cfg.loadSupplierSCfg,
-		LoaderJson:         cfg.loadLoaderSCfg,
-		MAILER_JSN:         cfg.loadMailerCfg,
-		SURETAX_JSON:       cfg.loadSureTaxCfg,
-		CgrLoaderCfgJson:   cfg.loadLoaderCgrCfg,
-		CgrMigratorCfgJson: cfg.loadMigratorCgrCfg,
-		DispatcherSJson:    cfg.loadDispatcherSCfg,
-		AnalyzerCfgJson:    cfg.loadAnalyzerCgrCfg,
-		ApierS:             cfg.loadApierCfg,
-		RPCConnsJsonName:   cfg.loadRPCConns,
+		GENERAL_JSN:         cfg.loadGeneralCfg,
+		DATADB_JSN:          cfg.loadDataDBCfg,
+		STORDB_JSN:          cfg.loadStorDBCfg,
+		LISTEN_JSN:          cfg.loadListenCfg,
+		TlsCfgJson:          cfg.loadTlsCgrCfg,
+		HTTP_JSN:            cfg.loadHTTPCfg,
+		SCHEDULER_JSN:       cfg.loadSchedulerCfg,
+		CACHE_JSN:           cfg.loadCacheCfg,
+		FilterSjsn:          cfg.loadFilterSCfg,
+		RALS_JSN:            cfg.loadRalSCfg,
+		CDRS_JSN:            cfg.loadCdrsCfg,
+		CDRE_JSN:            cfg.loadCdreCfg,
+		ERsJson:             cfg.loadErsCfg,
+		SessionSJson:        cfg.loadSessionSCfg,
+		AsteriskAgentJSN:    cfg.loadAsteriskAgentCfg,
+		FreeSWITCHAgentJSN:  cfg.loadFreeswitchAgentCfg,
+		KamailioAgentJSN:    cfg.loadKamAgentCfg,
+		DA_JSN:              cfg.loadDiameterAgentCfg,
+		RA_JSN:              cfg.loadRadiusAgentCfg,
+		HttpAgentJson:       cfg.loadHttpAgentCfg,
+		DNSAgentJson:        cfg.loadDNSAgentCfg,
+		PrometheusAgentJson: cfg.loadPrometheusAgentCfg,
+		ATTRIBUTE_JSN:       cfg.loadAttributeSCfg,
+		ChargerSCfgJson:     cfg.loadChargerSCfg,
+		RESOURCES_JSON:      cfg.loadResourceSCfg,
+		STATS_JSON:          cfg.loadStatSCfg,
+		THRESHOLDS_JSON:     cfg.loadThresholdSCfg,
+		SupplierSJson:       cfg.loadSupplierSCfg,
+		LoaderJson:          cfg.loadLoaderSCfg,
+		MAILER_JSN:          cfg.loadMailerCfg,
+		SURETAX_JSON:        cfg.loadSureTaxCfg,
+		CgrLoaderCfgJson:    cfg.loadLoaderCgrCfg,
+		CgrMigratorCfgJson:  cfg.loadMigratorCgrCfg,
+		DispatcherSJson:     cfg.loadDispatcherSCfg,
+		AnalyzerCfgJson:     cfg.loadAnalyzerCgrCfg,
+		TracingJson:         cfg.loadTracingCfg,
+		DedupJson:           cfg.loadDedupCfg,
+		ApierS:              cfg.loadApierCfg,
+		RPCConnsJsonName:    cfg.loadRPCConns,
 	}
 }
 
@@ -1441,6 +1515,8 @@ func (cfg *CGRConfig) reloadSections(sections ...string) (err error) {
 			cfg.rldChans[HttpAgentJson] <- struct{}{}
 		case DNSAgentJson:
 			cfg.rldChans[DNSAgentJson] <- struct{}{}
+		case PrometheusAgentJson:
+			cfg.rldChans[PrometheusAgentJson] <- struct{}{}
 		case ATTRIBUTE_JSN:
 			cfg.rldChans[ATTRIBUTE_JSN] <- struct{}{}
 		case ChargerSCfgJson:
@@ -1458,6 +1534,9 @@ func (cfg *CGRConfig) reloadSections(sections ...string) (err error) {
 		case DispatcherSJson:
 			cfg.rldChans[DispatcherSJson] <- struct{}{}
 		case AnalyzerCfgJson:
+		case TracingJson:
+			cfg.rldChans[TracingJson] <- struct{}{}
+		case DedupJson:
 		case ApierS:
 			cfg.rldChans[ApierS] <- struct{}{}
 		}
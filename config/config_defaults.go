@@ -31,6 +31,8 @@ const CGRATES_CFG_JSON = `
 	"node_id": "",											// identifier of this instance in the cluster, if empty it will be autogenerated
 	"logger":"*syslog",										// controls the destination of logs <*syslog|*stdout>
 	"log_level": 6,											// control the level of messages logged (0-emerg to 7-debug)
+	"log_format": "*text",									// format of the logged messages <*text|*json>
+	"subsystem_log_levels": {},								// log level per subsystem overwriting the log_level, ie: {"SessionS": 7, "DiameterAgent": 7}
 	"http_skip_tls_verify": false,							// if enabled HttpClient will accept any TLS certificate
 	"rounding_decimals": 5,									// system level precision for floats
 	"dbdata_encoding": "*msgpack",							// encoding used to store object data in strings: <*msgpack|*json>
@@ -171,6 +173,7 @@ const CGRATES_CFG_JSON = `
 	"ws_url": "/ws",							// WebSockets relative URL ("" to disable)
 	"freeswitch_cdrs_url": "/freeswitch_json",	// Freeswitch CDRS relative URL ("" to disable)
 	"http_cdrs": "/cdr_http",					// CDRS relative URL ("" to disable)
+	"metrics_url": "",							// runtime metrics in Prometheus text format relative URL ("" to disable)
 	"use_basic_auth": false,					// use basic authentication
 	"auth_users": {},							// basic authentication usernames and base64-encoded passwords (eg: { "username1": "cGFzc3dvcmQ=", "username2": "cGFzc3dvcmQy "})
 },
@@ -216,6 +219,7 @@ const CGRATES_CFG_JSON = `
 	"*dispatcher_filter_indexes" : {"limit": -1, "ttl": "", "static_ttl": false}, 				// control dispatcher filter indexes caching
 	"*dispatcher_routes": {"limit": -1, "ttl": "", "static_ttl": false}, 						// control dispatcher routes caching
 	"*diameter_messages": {"limit": -1, "ttl": "3h", "static_ttl": false},						// diameter messages caching
+	"*radius_packets": {"limit": -1, "ttl": "3h", "static_ttl": false},							// radius packets caching, used for Disconnect-Request/CoA-Request
 	"*rpc_responses": {"limit": 0, "ttl": "2s", "static_ttl": false},							// RPC responses caching
 	"*closed_sessions": {"limit": -1, "ttl": "10s", "static_ttl": false},						// closed sessions cached for CDRs
 	"*cdr_ids": {"limit": -1, "ttl": "10m", "static_ttl": false},								// protects CDRs against double-charging
@@ -302,6 +306,8 @@ const CGRATES_CFG_JSON = `
 "ers": {									// EventReaderService
 	"enabled": false,						// starts the EventReader service: <true|false>
 	"sessions_conns":["*internal"],			// RPC Connections IDs
+	"checkpoint_path": "",					// folder where the readers persist their progress in order to resume after restarts, disabled if empty
+	"max_decompressed_size": 1073741824,	// maximum size in bytes of the files extracted out of one compressed file, 0 for unlimited
 	"readers": [
 		{
 			"id": "*default",									// identifier of the EventReader profile
@@ -313,6 +319,7 @@ const CGRATES_CFG_JSON = `
 			"source_path": "/var/spool/cgrates/cdrc/in",		// read data from this path
 			"processed_path": "/var/spool/cgrates/cdrc/out",	// move processed data here
 			"xml_root_path": "",								// path towards one event in case of XML CDRs
+			"asn1_schema_path": "",								// path towards the ASN.1 schema used by *file_asn1 reader, empty for the built-in 3GPP TS 32.298 one
 			"tenant": "",										// tenant used by import
 			"timezone": "",										// timezone for timestamps where not specified <""|UTC|Local|$IANA_TZ_DB>
 			"filters": [],										// limit parsing based on the filters
@@ -362,6 +369,7 @@ const CGRATES_CFG_JSON = `
 	"terminate_attempts": 5,				// attempts to get the session before terminating it
 	"alterable_fields": [],					// the session fields that can be updated
 	//"min_dur_low_balance": "5s",			// threshold which will trigger low balance warnings for prepaid calls (needs to be lower than debit_interval)
+	"backup_interval": "0",					// backup active sessions to DataDB at this interval and on shutdown, restoring them on start, requires channel_sync_interval (0 to disable)
 },
 
 
@@ -490,6 +498,29 @@ const CGRATES_CFG_JSON = `
 		"*default": "/usr/share/cgrates/radius/dict/",			// key represents the client IP or catch-all <*default|$client_ip>
 	},
 	"sessions_conns": ["*internal"],
+	"client_da_addresses": {},									// address of the Dynamic Authorization server per client, defaults to $client_ip:3799 <$client_ip:$da_address>
+	"dmr_template": "",											// enable Disconnect-Request being sent to client on DisconnectSession
+	"coa_template": "",											// enable CoA-Request being sent to client on ReAuthorize
+	"templates":{												// default message templates
+		"*dmr": [
+			{"tag": "UserName", "path": "*radDAReq.User-Name", "type": "*variable",
+				"filters": ["*notempty:~*req.User-Name:"], "value": "~*req.User-Name"},
+			{"tag": "AcctSessionId", "path": "*radDAReq.Acct-Session-Id", "type": "*variable",
+				"value": "~*req.Acct-Session-Id", "mandatory": true},
+			{"tag": "NASIPAddress", "path": "*radDAReq.NAS-IP-Address", "type": "*variable",
+				"filters": ["*notempty:~*req.NAS-IP-Address:"], "value": "~*req.NAS-IP-Address"},
+			{"tag": "ReplyMessage", "path": "*radDAReq.Reply-Message", "type": "*variable",
+				"filters": ["*notempty:~*vars.DisconnectCause:"], "value": "~*vars.DisconnectCause"},
+		],
+		"*coa": [
+			{"tag": "UserName", "path": "*radDAReq.User-Name", "type": "*variable",
+				"filters": ["*notempty:~*req.User-Name:"], "value": "~*req.User-Name"},
+			{"tag": "AcctSessionId", "path": "*radDAReq.Acct-Session-Id", "type": "*variable",
+				"value": "~*req.Acct-Session-Id", "mandatory": true},
+			{"tag": "NASIPAddress", "path": "*radDAReq.NAS-IP-Address", "type": "*variable",
+				"filters": ["*notempty:~*req.NAS-IP-Address:"], "value": "~*req.NAS-IP-Address"},
+		],
+	},
 	"request_processors": [										// request processors to be applied to Radius messages
 	],
 },
@@ -510,6 +541,15 @@ const CGRATES_CFG_JSON = `
 },
 
 
+"prometheus_agent": {
+	"enabled": false,											// enables the Prometheus metrics endpoint: <true|false>
+	"path": "/metrics",											// HTTP path where the metrics are exposed
+	"stats_conns": [],											// connections to StatS for the StatQueue metrics: <""|*internal|$rpc_conns_id>
+	"stats_tenants": [],										// export the StatQueues of these tenants, empty for the default_tenant
+	"stats_filters": [],										// export only the StatQueues passing these filters (eg: *string:~*req.ID:SQ_1)
+},
+
+
 "attributes": {								// AttributeS config
 	"enabled": false,						// starts attribute service: <true|false>.
 	"indexed_selects":true,					// enable profile matching exclusively on indexes
@@ -821,7 +861,25 @@ const CGRATES_CFG_JSON = `
 
 
 "analyzers":{								// AnalyzerS config
-	"enabled":false							// starts AnalyzerS service: <true|false>.
+	"enabled": false,						// starts AnalyzerS service: <true|false>.
+	"ttl": "24h",							// time to keep the captured API traffic, 0 to keep it until shutdown
+	"cleanup_interval": "1h",				// interval to remove the expired API traffic
+},
+
+
+"tracing": {							// distributed tracing of the calls between subsystems
+	"enabled": false,					// starts tracing the calls sent through the connections and dispatchers: <true|false>
+	"exporter": "*file_json",				// where the finished spans are sent: <*file_json|*otlp_http>
+	"export_path": "/var/spool/cgrates/traces.json",	// file path for *file_json, collector URL for *otlp_http (ie: http://127.0.0.1:4318/v1/traces)
+	"buffer_size": 1024,					// maximum finished spans waiting for export, new spans are dropped when full
+	"flush_interval": "1s",					// how often the finished spans are exported
+},
+
+
+"dedup": {								// suppresses the duplicated events before charging, applied by ERs readers and CDRs on *dedup flag
+	"key": "~*req.OriginID;~*req.OriginHost",	// template building the deduplication key out of the event
+	"ttl": "1h",							// time window in which an event with the same key is considered duplicate
+	"store": "*internal",					// where the keys are kept: <*internal|*datadb>
 },
 
 
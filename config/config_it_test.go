@@ -443,8 +443,9 @@ func testCGRConfigReloadERs(t *testing.T) {
 		&FCTemplate{Tag: utils.Usage, Path: utils.MetaCgreq + utils.NestingSep + utils.Usage, Type: utils.MetaVariable, Value: NewRSRParsersMustCompile("~*req.13", true, utils.INFIELD_SEP), Mandatory: true, Layout: time.RFC3339},
 	}
 	expAttr := &ERsCfg{
-		Enabled:       true,
-		SessionSConns: []string{utils.MetaLocalHost},
+		Enabled:             true,
+		SessionSConns:       []string{utils.MetaLocalHost},
+		MaxDecompressedSize: 1073741824,
 		Readers: []*EventReaderCfg{
 			&EventReaderCfg{
 				ID:              utils.MetaDefault,
@@ -852,6 +853,7 @@ func testCgrCfgV1ReloadConfigSection(t *testing.T) {
 				"Timezone":                 "",
 				"Type":                     "*file_csv",
 				"XmlRootPath":              []interface{}{utils.EmptyString},
+				"ASN1SchemaPath":           "",
 			},
 			map[string]interface{}{
 				"CacheDumpFields": []interface{}{},
@@ -873,12 +875,15 @@ func testCgrCfgV1ReloadConfigSection(t *testing.T) {
 				"Timezone":                 "",
 				"Type":                     "*file_csv",
 				"XmlRootPath":              []interface{}{utils.EmptyString},
+				"ASN1SchemaPath":           "",
 				"Fields":                   content,
 			},
 		},
 		"SessionSConns": []string{
 			utils.MetaLocalHost,
 		},
+		"CheckpointPath":      "",
+		"MaxDecompressedSize": 1073741824.,
 	}
 
 	cfg, _ := NewDefaultCGRConfig()
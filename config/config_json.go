@@ -23,49 +23,52 @@ import (
 )
 
 const (
-	GENERAL_JSN        = "general"
-	CACHE_JSN          = "caches"
-	LISTEN_JSN         = "listen"
-	HTTP_JSN           = "http"
-	DATADB_JSN         = "data_db"
-	STORDB_JSN         = "stor_db"
-	FilterSjsn         = "filters"
-	RALS_JSN           = "rals"
-	SCHEDULER_JSN      = "schedulers"
-	CDRS_JSN           = "cdrs"
-	CDRE_JSN           = "cdre"
-	SessionSJson       = "sessions"
-	FreeSWITCHAgentJSN = "freeswitch_agent"
-	KamailioAgentJSN   = "kamailio_agent"
-	AsteriskAgentJSN   = "asterisk_agent"
-	DA_JSN             = "diameter_agent"
-	RA_JSN             = "radius_agent"
-	HttpAgentJson      = "http_agent"
-	ATTRIBUTE_JSN      = "attributes"
-	RESOURCES_JSON     = "resources"
-	STATS_JSON         = "stats"
-	THRESHOLDS_JSON    = "thresholds"
-	SupplierSJson      = "suppliers"
-	LoaderJson         = "loaders"
-	MAILER_JSN         = "mailer"
-	SURETAX_JSON       = "suretax"
-	DispatcherSJson    = "dispatchers"
-	CgrLoaderCfgJson   = "loader"
-	CgrMigratorCfgJson = "migrator"
-	ChargerSCfgJson    = "chargers"
-	TlsCfgJson         = "tls"
-	AnalyzerCfgJson    = "analyzers"
-	ApierS             = "apiers"
-	DNSAgentJson       = "dns_agent"
-	ERsJson            = "ers"
-	RPCConnsJsonName   = "rpc_conns"
+	GENERAL_JSN         = "general"
+	CACHE_JSN           = "caches"
+	LISTEN_JSN          = "listen"
+	HTTP_JSN            = "http"
+	DATADB_JSN          = "data_db"
+	STORDB_JSN          = "stor_db"
+	FilterSjsn          = "filters"
+	RALS_JSN            = "rals"
+	SCHEDULER_JSN       = "schedulers"
+	CDRS_JSN            = "cdrs"
+	CDRE_JSN            = "cdre"
+	SessionSJson        = "sessions"
+	FreeSWITCHAgentJSN  = "freeswitch_agent"
+	KamailioAgentJSN    = "kamailio_agent"
+	AsteriskAgentJSN    = "asterisk_agent"
+	DA_JSN              = "diameter_agent"
+	RA_JSN              = "radius_agent"
+	HttpAgentJson       = "http_agent"
+	ATTRIBUTE_JSN       = "attributes"
+	RESOURCES_JSON      = "resources"
+	STATS_JSON          = "stats"
+	THRESHOLDS_JSON     = "thresholds"
+	SupplierSJson       = "suppliers"
+	LoaderJson          = "loaders"
+	MAILER_JSN          = "mailer"
+	SURETAX_JSON        = "suretax"
+	DispatcherSJson     = "dispatchers"
+	CgrLoaderCfgJson    = "loader"
+	CgrMigratorCfgJson  = "migrator"
+	ChargerSCfgJson     = "chargers"
+	TlsCfgJson          = "tls"
+	AnalyzerCfgJson     = "analyzers"
+	TracingJson         = "tracing"
+	DedupJson           = "dedup"
+	ApierS              = "apiers"
+	DNSAgentJson        = "dns_agent"
+	PrometheusAgentJson = "prometheus_agent"
+	ERsJson             = "ers"
+	RPCConnsJsonName    = "rpc_conns"
 )
 
 var (
 	sortedCfgSections = []string{GENERAL_JSN, RPCConnsJsonName, DATADB_JSN, STORDB_JSN, LISTEN_JSN, TlsCfgJson, HTTP_JSN, SCHEDULER_JSN, CACHE_JSN, FilterSjsn, RALS_JSN,
 		CDRS_JSN, CDRE_JSN, ERsJson, SessionSJson, AsteriskAgentJSN, FreeSWITCHAgentJSN, KamailioAgentJSN,
-		DA_JSN, RA_JSN, HttpAgentJson, DNSAgentJson, ATTRIBUTE_JSN, ChargerSCfgJson, RESOURCES_JSON, STATS_JSON, THRESHOLDS_JSON,
-		SupplierSJson, LoaderJson, MAILER_JSN, SURETAX_JSON, CgrLoaderCfgJson, CgrMigratorCfgJson, DispatcherSJson, AnalyzerCfgJson, ApierS}
+		DA_JSN, RA_JSN, HttpAgentJson, DNSAgentJson, PrometheusAgentJson, ATTRIBUTE_JSN, ChargerSCfgJson, RESOURCES_JSON, STATS_JSON, THRESHOLDS_JSON,
+		SupplierSJson, LoaderJson, MAILER_JSN, SURETAX_JSON, CgrLoaderCfgJson, CgrMigratorCfgJson, DispatcherSJson, AnalyzerCfgJson, TracingJson, DedupJson, ApierS}
 )
 
 // Loads the json config out of io.Reader, eg other sources than file, maybe over http
@@ -482,6 +485,42 @@ func (self CgrJsonCfg) AnalyzerCfgJson() (*AnalyzerSJsonCfg, error) {
 	return cfg, nil
 }
 
+func (self CgrJsonCfg) TracingJsonCfg() (*TracingJsonCfg, error) {
+	rawCfg, hasKey := self[TracingJson]
+	if !hasKey {
+		return nil, nil
+	}
+	cfg := new(TracingJsonCfg)
+	if err := json.Unmarshal(*rawCfg, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (self CgrJsonCfg) DedupJsonCfg() (*DedupJsonCfg, error) {
+	rawCfg, hasKey := self[DedupJson]
+	if !hasKey {
+		return nil, nil
+	}
+	cfg := new(DedupJsonCfg)
+	if err := json.Unmarshal(*rawCfg, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (self CgrJsonCfg) PrometheusAgentJsonCfg() (*PrometheusAgentJsonCfg, error) {
+	rawCfg, hasKey := self[PrometheusAgentJson]
+	if !hasKey {
+		return nil, nil
+	}
+	cfg := new(PrometheusAgentJsonCfg)
+	if err := json.Unmarshal(*rawCfg, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
 func (self CgrJsonCfg) ApierCfgJson() (*ApierJsonCfg, error) {
 	rawCfg, hasKey := self[ApierS]
 	if !hasKey {
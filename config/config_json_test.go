@@ -41,6 +41,8 @@ func TestDfGeneralJsonCfg(t *testing.T) {
 		Node_id:              utils.StringPointer(""),
 		Logger:               utils.StringPointer(utils.MetaSysLog),
 		Log_level:            utils.IntPointer(utils.LOGLEVEL_INFO),
+		Log_format:           utils.StringPointer(utils.MetaText),
+		Subsystem_log_levels: &map[string]int{},
 		Http_skip_tls_verify: utils.BoolPointer(false),
 		Rounding_decimals:    utils.IntPointer(5),
 		Dbdata_encoding:      utils.StringPointer("*msgpack"),
@@ -158,6 +160,8 @@ func TestCacheJsonCfg(t *testing.T) {
 			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false)},
 		utils.CacheDiameterMessages: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer("3h"), Static_ttl: utils.BoolPointer(false)},
+		utils.CacheRadiusPackets: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
+			Ttl: utils.StringPointer("3h"), Static_ttl: utils.BoolPointer(false)},
 		utils.CacheRPCResponses: &CacheParamJsonCfg{Limit: utils.IntPointer(0),
 			Ttl: utils.StringPointer("2s"), Static_ttl: utils.BoolPointer(false)},
 		utils.CacheClosedSessions: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
@@ -667,6 +671,7 @@ func TestSmgJsonCfg(t *testing.T) {
 		Channel_sync_interval: utils.StringPointer("0"),
 		Terminate_attempts:    utils.IntPointer(5),
 		Alterable_fields:      &[]string{},
+		Backup_interval:       utils.StringPointer("0"),
 	}
 	if cfg, err := dfCgrJsonCfg.SessionSJsonCfg(); err != nil {
 		t.Error(err)
@@ -926,14 +931,64 @@ func TestRadiusAgentJsonCfg(t *testing.T) {
 		Client_dictionaries: utils.MapStringStringPointer(map[string]string{
 			utils.MetaDefault: "/usr/share/cgrates/radius/dict/",
 		}),
-		Sessions_conns:     &[]string{utils.MetaInternal},
+		Sessions_conns:      &[]string{utils.MetaInternal},
+		Client_da_addresses: utils.MapStringStringPointer(map[string]string{}),
+		Dmr_template:        utils.StringPointer(""),
+		Coa_template:        utils.StringPointer(""),
+		Templates: map[string][]*FcTemplateJsonCfg{
+			utils.MetaDMR: {
+				{
+					Tag:     utils.StringPointer("UserName"),
+					Path:    utils.StringPointer(fmt.Sprintf("%s.User-Name", utils.MetaRadDAReq)),
+					Type:    utils.StringPointer(utils.MetaVariable),
+					Filters: &[]string{"*notempty:~*req.User-Name:"},
+					Value:   utils.StringPointer("~*req.User-Name")},
+				{
+					Tag:       utils.StringPointer("AcctSessionId"),
+					Path:      utils.StringPointer(fmt.Sprintf("%s.Acct-Session-Id", utils.MetaRadDAReq)),
+					Type:      utils.StringPointer(utils.MetaVariable),
+					Value:     utils.StringPointer("~*req.Acct-Session-Id"),
+					Mandatory: utils.BoolPointer(true)},
+				{
+					Tag:     utils.StringPointer("NASIPAddress"),
+					Path:    utils.StringPointer(fmt.Sprintf("%s.NAS-IP-Address", utils.MetaRadDAReq)),
+					Type:    utils.StringPointer(utils.MetaVariable),
+					Filters: &[]string{"*notempty:~*req.NAS-IP-Address:"},
+					Value:   utils.StringPointer("~*req.NAS-IP-Address")},
+				{
+					Tag:     utils.StringPointer("ReplyMessage"),
+					Path:    utils.StringPointer(fmt.Sprintf("%s.Reply-Message", utils.MetaRadDAReq)),
+					Type:    utils.StringPointer(utils.MetaVariable),
+					Filters: &[]string{"*notempty:~*vars.DisconnectCause:"},
+					Value:   utils.StringPointer("~*vars.DisconnectCause")},
+			},
+			utils.MetaCoA: {
+				{
+					Tag:     utils.StringPointer("UserName"),
+					Path:    utils.StringPointer(fmt.Sprintf("%s.User-Name", utils.MetaRadDAReq)),
+					Type:    utils.StringPointer(utils.MetaVariable),
+					Filters: &[]string{"*notempty:~*req.User-Name:"},
+					Value:   utils.StringPointer("~*req.User-Name")},
+				{
+					Tag:       utils.StringPointer("AcctSessionId"),
+					Path:      utils.StringPointer(fmt.Sprintf("%s.Acct-Session-Id", utils.MetaRadDAReq)),
+					Type:      utils.StringPointer(utils.MetaVariable),
+					Value:     utils.StringPointer("~*req.Acct-Session-Id"),
+					Mandatory: utils.BoolPointer(true)},
+				{
+					Tag:     utils.StringPointer("NASIPAddress"),
+					Path:    utils.StringPointer(fmt.Sprintf("%s.NAS-IP-Address", utils.MetaRadDAReq)),
+					Type:    utils.StringPointer(utils.MetaVariable),
+					Filters: &[]string{"*notempty:~*req.NAS-IP-Address:"},
+					Value:   utils.StringPointer("~*req.NAS-IP-Address")},
+			},
+		},
 		Request_processors: &[]*ReqProcessorJsnCfg{},
 	}
 	if cfg, err := dfCgrJsonCfg.RadiusAgentJsonCfg(); err != nil {
 		t.Error(err)
 	} else if !reflect.DeepEqual(eCfg, cfg) {
-		rcv := *cfg.Request_processors
-		t.Errorf("Received: %+v", rcv)
+		t.Errorf("expecting: %s, \n\nreceived: %s", utils.ToJSON(eCfg), utils.ToJSON(cfg))
 	}
 }
 
@@ -962,6 +1017,49 @@ func TestDNSAgentJsonCfg(t *testing.T) {
 	}
 }
 
+func TestDfPrometheusAgentJsonCfg(t *testing.T) {
+	eCfg := &PrometheusAgentJsonCfg{
+		Enabled:       utils.BoolPointer(false),
+		Path:          utils.StringPointer("/metrics"),
+		Stats_conns:   &[]string{},
+		Stats_tenants: &[]string{},
+		Stats_filters: &[]string{},
+	}
+	if cfg, err := dfCgrJsonCfg.PrometheusAgentJsonCfg(); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eCfg, cfg) {
+		t.Errorf("expecting: %+v, received: %+v", utils.ToJSON(eCfg), utils.ToJSON(cfg))
+	}
+}
+
+func TestDfTracingJsonCfg(t *testing.T) {
+	eCfg := &TracingJsonCfg{
+		Enabled:        utils.BoolPointer(false),
+		Exporter:       utils.StringPointer(utils.MetaFileJSON),
+		Export_path:    utils.StringPointer("/var/spool/cgrates/traces.json"),
+		Buffer_size:    utils.IntPointer(1024),
+		Flush_interval: utils.StringPointer("1s"),
+	}
+	if cfg, err := dfCgrJsonCfg.TracingJsonCfg(); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eCfg, cfg) {
+		t.Errorf("expecting: %+v, received: %+v", utils.ToJSON(eCfg), utils.ToJSON(cfg))
+	}
+}
+
+func TestDfDedupJsonCfg(t *testing.T) {
+	eCfg := &DedupJsonCfg{
+		Key:   utils.StringPointer("~*req.OriginID;~*req.OriginHost"),
+		Ttl:   utils.StringPointer("1h"),
+		Store: utils.StringPointer(utils.MetaInternal),
+	}
+	if cfg, err := dfCgrJsonCfg.DedupJsonCfg(); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eCfg, cfg) {
+		t.Errorf("expecting: %+v, received: %+v", utils.ToJSON(eCfg), utils.ToJSON(cfg))
+	}
+}
+
 func TestDfAttributeServJsonCfg(t *testing.T) {
 	eCfg := &AttributeSJsonCfg{
 		Enabled:               utils.BoolPointer(false),
@@ -1665,6 +1763,7 @@ func TestDfHttpJsonCfg(t *testing.T) {
 		Ws_url:              utils.StringPointer("/ws"),
 		Freeswitch_cdrs_url: utils.StringPointer("/freeswitch_json"),
 		Http_Cdrs:           utils.StringPointer("/cdr_http"),
+		Metrics_url:         utils.StringPointer(""),
 		Use_basic_auth:      utils.BoolPointer(false),
 		Auth_users:          utils.MapStringStringPointer(map[string]string{}),
 	}
@@ -1754,7 +1853,9 @@ func TestDfTlsCfg(t *testing.T) {
 
 func TestDfAnalyzerCfg(t *testing.T) {
 	eCfg := &AnalyzerSJsonCfg{
-		Enabled: utils.BoolPointer(false),
+		Enabled:          utils.BoolPointer(false),
+		Ttl:              utils.StringPointer("24h"),
+		Cleanup_interval: utils.StringPointer("1h"),
 	}
 	if cfg, err := dfCgrJsonCfg.AnalyzerCfgJson(); err != nil {
 		t.Error(err)
@@ -1803,8 +1904,10 @@ func TestDfEventReaderCfg(t *testing.T) {
 			Value: utils.StringPointer("~*req.13"), Mandatory: utils.BoolPointer(true)},
 	}
 	eCfg := &ERsJsonCfg{
-		Enabled:        utils.BoolPointer(false),
-		Sessions_conns: &[]string{utils.MetaInternal},
+		Enabled:               utils.BoolPointer(false),
+		Sessions_conns:        &[]string{utils.MetaInternal},
+		Checkpoint_path:       utils.StringPointer(utils.EmptyString),
+		Max_decompressed_size: utils.Int64Pointer(1073741824),
 		Readers: &[]*EventReaderJsonCfg{
 			&EventReaderJsonCfg{
 				Id:                  utils.StringPointer(utils.MetaDefault),
@@ -1816,6 +1919,7 @@ func TestDfEventReaderCfg(t *testing.T) {
 				Source_path:         utils.StringPointer("/var/spool/cgrates/cdrc/in"),
 				Processed_path:      utils.StringPointer("/var/spool/cgrates/cdrc/out"),
 				Xml_root_path:       utils.StringPointer(utils.EmptyString),
+				Asn1_schema_path:    utils.StringPointer(utils.EmptyString),
 				Tenant:              utils.StringPointer(utils.EmptyString),
 				Timezone:            utils.StringPointer(utils.EmptyString),
 				Filters:             &[]string{},
@@ -283,6 +283,12 @@ func TestCgrCfgJSONDefaultsGeneral(t *testing.T) {
 	if cgrCfg.GeneralCfg().LogLevel != 6 {
 		t.Errorf("Expected: 6, received: %+v", cgrCfg.GeneralCfg().LogLevel)
 	}
+	if cgrCfg.GeneralCfg().LogFormat != utils.MetaText {
+		t.Errorf("Expected: %+v, received: %+v", utils.MetaText, cgrCfg.GeneralCfg().LogFormat)
+	}
+	if len(cgrCfg.GeneralCfg().SubsystemLogLevels) != 0 {
+		t.Errorf("Expected no subsystem log levels, received: %+v", cgrCfg.GeneralCfg().SubsystemLogLevels)
+	}
 	if cgrCfg.GeneralCfg().DigestSeparator != "," {
 		t.Errorf("Expected: utils.CSV_SEP , received: %+v", cgrCfg.GeneralCfg().DigestSeparator)
 	}
@@ -694,6 +700,8 @@ func TestCgrCfgJSONDefaultsCacheCFG(t *testing.T) {
 			TTL: time.Duration(0), StaticTTL: false, Precache: false},
 		utils.CacheDiameterMessages: &CacheParamCfg{Limit: -1,
 			TTL: time.Duration(3 * time.Hour), StaticTTL: false},
+		utils.CacheRadiusPackets: &CacheParamCfg{Limit: -1,
+			TTL: time.Duration(3 * time.Hour), StaticTTL: false},
 		utils.CacheRPCResponses: &CacheParamCfg{Limit: 0,
 			TTL: time.Duration(2 * time.Second), StaticTTL: false},
 		utils.CacheClosedSessions: &CacheParamCfg{Limit: -1,
@@ -965,6 +973,9 @@ func TestCgrCfgJSONDefaultsHTTP(t *testing.T) {
 	if cgrCfg.HTTPCfg().HTTPCDRsURL != "/cdr_http" {
 		t.Errorf("expecting: /cdr_http , received: %+v", cgrCfg.HTTPCfg().HTTPCDRsURL)
 	}
+	if cgrCfg.HTTPCfg().HTTPMetricsURL != "" {
+		t.Errorf("expecting empty metrics url, received: %+v", cgrCfg.HTTPCfg().HTTPMetricsURL)
+	}
 	if cgrCfg.HTTPCfg().HTTPUseBasicAuth != false {
 		t.Errorf("expecting: false , received: %+v", cgrCfg.HTTPCfg().HTTPUseBasicAuth)
 	}
@@ -982,8 +993,23 @@ func TestRadiusAgentCfg(t *testing.T) {
 		ClientSecrets:      map[string]string{utils.MetaDefault: "CGRateS.org"},
 		ClientDictionaries: map[string]string{utils.MetaDefault: "/usr/share/cgrates/radius/dict/"},
 		SessionSConns:      []string{utils.ConcatenatedKey(utils.MetaInternal, utils.MetaSessionS)},
+		ClientDaAddresses:  map[string]string{},
+		Templates:          make(map[string][]*FCTemplate),
 		RequestProcessors:  nil,
 	}
+	dfJsnCfg, err := NewCgrJsonCfgFromBytes([]byte(CGRATES_CFG_JSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsnRACfg, err := dfJsnCfg.RadiusAgentJsonCfg()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for tplID, jsnTpls := range jsnRACfg.Templates {
+		if testRA.Templates[tplID], err = FCTemplatesFromFCTemplatesJsonCfg(jsnTpls, utils.INFIELD_SEP); err != nil {
+			t.Fatal(err)
+		}
+	}
 	if !reflect.DeepEqual(cgrCfg.radiusAgentCfg, testRA) {
 		t.Errorf("expecting: %+v, received: %+v", cgrCfg.radiusAgentCfg, testRA)
 	}
@@ -1718,13 +1744,52 @@ func TestCfgTlsCfg(t *testing.T) {
 
 func TestCgrCfgJSONDefaultAnalyzerSCfg(t *testing.T) {
 	aSCfg := &AnalyzerSCfg{
-		Enabled: false,
+		Enabled:         false,
+		TTL:             24 * time.Hour,
+		CleanupInterval: time.Hour,
 	}
 	if !reflect.DeepEqual(cgrCfg.analyzerSCfg, aSCfg) {
 		t.Errorf("received: %+v, expecting: %+v", cgrCfg.analyzerSCfg, aSCfg)
 	}
 }
 
+func TestCgrCfgJSONDefaultPrometheusAgentCfg(t *testing.T) {
+	pACfg := &PrometheusAgentCfg{
+		Enabled:      false,
+		Path:         "/metrics",
+		StatSConns:   []string{},
+		StatsTenants: []string{},
+		StatsFilters: []string{},
+	}
+	if !reflect.DeepEqual(cgrCfg.PrometheusAgentCfg(), pACfg) {
+		t.Errorf("received: %+v, expecting: %+v", utils.ToJSON(cgrCfg.PrometheusAgentCfg()), utils.ToJSON(pACfg))
+	}
+}
+
+func TestCgrCfgJSONDefaultTracingCfg(t *testing.T) {
+	trCfg := &TracingCfg{
+		Enabled:       false,
+		Exporter:      utils.MetaFileJSON,
+		ExportPath:    "/var/spool/cgrates/traces.json",
+		BufferSize:    1024,
+		FlushInterval: time.Second,
+	}
+	if !reflect.DeepEqual(cgrCfg.TracingCfg(), trCfg) {
+		t.Errorf("received: %+v, expecting: %+v", utils.ToJSON(cgrCfg.TracingCfg()), utils.ToJSON(trCfg))
+	}
+}
+
+func TestCgrCfgJSONDefaultDedupCfg(t *testing.T) {
+	ddCfg := &DedupCfg{
+		Key:   NewRSRParsersMustCompile("~*req.OriginID;~*req.OriginHost", true, utils.INFIELD_SEP),
+		TTL:   time.Hour,
+		Store: utils.MetaInternal,
+	}
+	if !reflect.DeepEqual(cgrCfg.DedupCfg(), ddCfg) {
+		t.Errorf("received: %+v, expecting: %+v", utils.ToJSON(cgrCfg.DedupCfg()), utils.ToJSON(ddCfg))
+	}
+}
+
 func TestNewCGRConfigFromPathNotFound(t *testing.T) {
 	fpath := path.Join("/usr", "share", "cgrates", "conf", "samples", "notValid")
 	_, err := NewCGRConfigFromPath(fpath)
@@ -1788,8 +1853,9 @@ func TestCgrCfgV1GetConfigSection(t *testing.T) {
 
 func TestCgrCdfEventReader(t *testing.T) {
 	eCfg := &ERsCfg{
-		Enabled:       false,
-		SessionSConns: []string{utils.ConcatenatedKey(utils.MetaInternal, utils.MetaSessionS)},
+		Enabled:             false,
+		SessionSConns:       []string{utils.ConcatenatedKey(utils.MetaInternal, utils.MetaSessionS)},
+		MaxDecompressedSize: 1073741824,
 		Readers: []*EventReaderCfg{
 			&EventReaderCfg{
 				ID:             utils.MetaDefault,
@@ -20,7 +20,9 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/cgrates/cgrates/utils"
@@ -32,6 +34,16 @@ func (cfg *CGRConfig) CheckConfigSanity() error {
 }
 
 func (cfg *CGRConfig) checkConfigSanity() error {
+	// General checks
+	if cfg.generalCfg.LogFormat != utils.MetaText &&
+		cfg.generalCfg.LogFormat != utils.MetaJSON {
+		return fmt.Errorf("<%s> unsupported log_format %s", GENERAL_JSN, cfg.generalCfg.LogFormat)
+	}
+	for subsys, lvl := range cfg.generalCfg.SubsystemLogLevels {
+		if lvl < utils.LOGLEVEL_EMERGENCY || lvl > utils.LOGLEVEL_DEBUG {
+			return fmt.Errorf("<%s> invalid log level %d for subsystem %s", GENERAL_JSN, lvl, subsys)
+		}
+	}
 	// Rater checks
 	if cfg.ralsCfg.Enabled {
 		for _, connID := range cfg.ralsCfg.StatSConns {
@@ -126,6 +138,10 @@ func (cfg *CGRConfig) checkConfigSanity() error {
 		if cfg.sessionSCfg.TerminateAttempts < 1 {
 			return fmt.Errorf("<%s> 'terminate_attempts' should be at least 1", utils.SessionS)
 		}
+		// the restored sessions are reconciled with the agents only on channel sync
+		if cfg.sessionSCfg.BackupInterval != 0 && cfg.sessionSCfg.ChannelSyncInterval == 0 {
+			return fmt.Errorf("<%s> 'backup_interval' requires 'channel_sync_interval'", utils.SessionS)
+		}
 		for _, connID := range cfg.sessionSCfg.ChargerSConns {
 			if strings.HasPrefix(connID, utils.MetaInternal) && !cfg.chargerSCfg.Enabled {
 				return fmt.Errorf("<%s> not enabled but requested by <%s> component.", utils.ChargerS, utils.SessionS)
@@ -279,6 +295,11 @@ func (cfg *CGRConfig) checkConfigSanity() error {
 				return fmt.Errorf("<%s> connection with id: <%s> not defined", utils.RadiusAgent, connID)
 			}
 		}
+		for _, tplID := range []string{cfg.radiusAgentCfg.DMRTemplate, cfg.radiusAgentCfg.CoATemplate} {
+			if _, has := cfg.radiusAgentCfg.Templates[tplID]; tplID != utils.EmptyString && !has {
+				return fmt.Errorf("<%s> template with ID <%s> not defined", utils.RadiusAgent, tplID)
+			}
+		}
 	}
 	//DNS Agent
 	if cfg.dnsAgentCfg.Enabled {
@@ -306,13 +327,31 @@ func (cfg *CGRConfig) checkConfigSanity() error {
 				return fmt.Errorf("<%s> template with ID <%s> has connection with id: <%s> not defined", utils.HTTPAgent, httpAgentCfg.ID, connID)
 			}
 		}
-		if !utils.SliceHasMember([]string{utils.MetaUrl, utils.MetaXml}, httpAgentCfg.RequestPayload) {
+		if !utils.SliceHasMember([]string{utils.MetaJSON, utils.MetaSIP, utils.MetaUrl, utils.MetaXml}, httpAgentCfg.RequestPayload) {
 			return fmt.Errorf("<%s> unsupported request payload %s", utils.HTTPAgent, httpAgentCfg.RequestPayload)
 		}
-		if !utils.SliceHasMember([]string{utils.MetaTextPlain, utils.MetaXml}, httpAgentCfg.ReplyPayload) {
+		if !utils.SliceHasMember([]string{utils.MetaTextPlain, utils.MetaXml, utils.MetaJSON}, httpAgentCfg.ReplyPayload) {
 			return fmt.Errorf("<%s> unsupported reply payload %s", utils.HTTPAgent, httpAgentCfg.ReplyPayload)
 		}
 	}
+	// PrometheusAgent checks
+	if cfg.prometheusAgentCfg.Enabled {
+		if cfg.prometheusAgentCfg.Path == utils.EmptyString {
+			return fmt.Errorf("<%s> empty path", utils.PrometheusAgent)
+		}
+		if cfg.prometheusAgentCfg.Path == cfg.httpCfg.HTTPMetricsURL {
+			return fmt.Errorf("<%s> path already used by the runtime metrics: %s",
+				utils.PrometheusAgent, cfg.prometheusAgentCfg.Path)
+		}
+		for _, connID := range cfg.prometheusAgentCfg.StatSConns {
+			if strings.HasPrefix(connID, utils.MetaInternal) && !cfg.statsCfg.Enabled {
+				return fmt.Errorf("<%s> not enabled but requested by <%s> component.", utils.StatService, utils.PrometheusAgent)
+			}
+			if _, has := cfg.rpcConns[connID]; !has && !strings.HasPrefix(connID, utils.MetaInternal) {
+				return fmt.Errorf("<%s> connection with id: <%s> not defined", utils.PrometheusAgent, connID)
+			}
+		}
+	}
 	if cfg.attributeSCfg.Enabled {
 		if cfg.attributeSCfg.ProcessRuns < 1 {
 			return fmt.Errorf("<%s> process_runs needs to be bigger than 0", utils.AttributeS)
@@ -398,14 +437,38 @@ func (cfg *CGRConfig) checkConfigSanity() error {
 				return fmt.Errorf("<%s> connection with id: <%s> not defined", utils.ERs, connID)
 			}
 		}
+		if cfg.ersCfg.CheckpointPath != utils.EmptyString {
+			if _, err := os.Stat(cfg.ersCfg.CheckpointPath); err != nil && os.IsNotExist(err) {
+				return fmt.Errorf("<%s> nonexistent checkpoint folder: %s", utils.ERs, cfg.ersCfg.CheckpointPath)
+			}
+		}
 		for _, rdr := range cfg.ersCfg.Readers {
 			if !possibleReaderTypes.Has(rdr.Type) {
 				return fmt.Errorf("<%s> unsupported data type: %s for reader with ID: %s", utils.ERs, rdr.Type, rdr.ID)
 			}
-
+			localDirs := []string{rdr.ProcessedPath, rdr.SourcePath}
+			if utils.IsRemoteFilePath(rdr.SourcePath) { // polled over SFTP or FTP(S)
+				if rdr.RunDelay < 0 {
+					return fmt.Errorf("<%s> the RunDelay field can not be smaller than zero for reader with ID: %s", utils.ERs, rdr.ID)
+				}
+				if strings.HasPrefix(rdr.SourcePath, utils.SFTPScheme+"://") { // the server key needs to be verified
+					u, err := url.Parse(rdr.SourcePath)
+					if err != nil {
+						return fmt.Errorf("<%s> invalid source path: %s for reader with ID: %s", utils.ERs, err.Error(), rdr.ID)
+					}
+					knownHosts := u.Query().Get(utils.SSHKnownHosts)
+					if knownHosts == utils.EmptyString {
+						return fmt.Errorf("<%s> missing known_hosts for SFTP reader with ID: %s", utils.ERs, rdr.ID)
+					}
+					if _, err := os.Stat(knownHosts); err != nil && os.IsNotExist(err) {
+						return fmt.Errorf("<%s> nonexistent known_hosts: %s for reader with ID: %s", utils.ERs, knownHosts, rdr.ID)
+					}
+				}
+				localDirs = localDirs[:1]
+			}
 			switch rdr.Type {
 			case utils.MetaFileCSV, utils.MetaPartialCSV, utils.MetaFlatstore:
-				for _, dir := range []string{rdr.ProcessedPath, rdr.SourcePath} {
+				for _, dir := range localDirs {
 					if _, err := os.Stat(dir); err != nil && os.IsNotExist(err) {
 						return fmt.Errorf("<%s> nonexistent folder: %s for reader with ID: %s", utils.ERs, dir, rdr.ID)
 					}
@@ -413,16 +476,38 @@ func (cfg *CGRConfig) checkConfigSanity() error {
 				if rdr.FieldSep == utils.EmptyString {
 					return fmt.Errorf("<%s> empty FieldSep for reader with ID: %s", utils.ERs, rdr.ID)
 				}
-			case utils.MetaKafkajsonMap:
+			case utils.MetaKafkajsonMap, utils.MetaAMQPjsonMap, utils.MetaSQSjsonMap, utils.MetaNatsjsonMap:
+				if rdr.RunDelay > 0 {
+					return fmt.Errorf("<%s> the RunDelay field can not be bigger than zero for reader with ID: %s", utils.ERs, rdr.ID)
+				}
+			case utils.MetaHTTPjson, utils.MetaHTTPform:
 				if rdr.RunDelay > 0 {
 					return fmt.Errorf("<%s> the RunDelay field can not be bigger than zero for reader with ID: %s", utils.ERs, rdr.ID)
 				}
+				if !strings.HasPrefix(rdr.SourcePath, utils.Slash) {
+					return fmt.Errorf("<%s> the SourcePath field should be an HTTP path for reader with ID: %s", utils.ERs, rdr.ID)
+				}
+			case utils.MetaS3jsonMap:
+				if rdr.RunDelay < 0 {
+					return fmt.Errorf("<%s> the RunDelay field can not be smaller than zero for reader with ID: %s", utils.ERs, rdr.ID)
+				}
 			case utils.MetaFileXML, utils.MetaFileFWV, utils.MetaJSON:
-				for _, dir := range []string{rdr.ProcessedPath, rdr.SourcePath} {
+				for _, dir := range localDirs {
 					if _, err := os.Stat(dir); err != nil && os.IsNotExist(err) {
 						return fmt.Errorf("<%s> nonexistent folder: %s for reader with ID: %s", utils.ERs, dir, rdr.ID)
 					}
 				}
+			case utils.MetaFileASN1:
+				for _, dir := range localDirs {
+					if _, err := os.Stat(dir); err != nil && os.IsNotExist(err) {
+						return fmt.Errorf("<%s> nonexistent folder: %s for reader with ID: %s", utils.ERs, dir, rdr.ID)
+					}
+				}
+				if rdr.ASN1SchemaPath != utils.EmptyString {
+					if _, err := os.Stat(rdr.ASN1SchemaPath); err != nil && os.IsNotExist(err) {
+						return fmt.Errorf("<%s> nonexistent ASN.1 schema: %s for reader with ID: %s", utils.ERs, rdr.ASN1SchemaPath, rdr.ID)
+					}
+				}
 			}
 		}
 	}
@@ -493,6 +578,41 @@ func (cfg *CGRConfig) checkConfigSanity() error {
 			return fmt.Errorf("<%s> partition <%s> not defined", utils.CacheS, cacheID)
 		}
 	}
+	// Tracing sanity checks
+	if cfg.tracingCfg.Enabled {
+		switch cfg.tracingCfg.Exporter {
+		case utils.MetaFileJSON:
+			dir := filepath.Dir(cfg.tracingCfg.ExportPath)
+			if _, err := os.Stat(dir); err != nil && os.IsNotExist(err) {
+				return fmt.Errorf("<%s> nonexistent folder: %s", utils.Tracing, dir)
+			}
+		case utils.MetaOTLPHTTP:
+			if cfg.tracingCfg.ExportPath == utils.EmptyString {
+				return fmt.Errorf("<%s> empty export_path for exporter %s", utils.Tracing, utils.MetaOTLPHTTP)
+			}
+		default:
+			return fmt.Errorf("<%s> unsupported exporter %s", utils.Tracing, cfg.tracingCfg.Exporter)
+		}
+		if cfg.tracingCfg.BufferSize <= 0 {
+			return fmt.Errorf("<%s> buffer_size should be greater than 0", utils.Tracing)
+		}
+		if cfg.tracingCfg.FlushInterval <= 0 {
+			return fmt.Errorf("<%s> flush_interval should be greater than 0", utils.Tracing)
+		}
+	}
+	// Dedup sanity checks
+	if cfg.ersCfg.Enabled || cfg.cdrsCfg.Enabled {
+		if cfg.dedupCfg.Store != utils.MetaInternal &&
+			cfg.dedupCfg.Store != utils.MetaDataDB {
+			return fmt.Errorf("<%s> unsupported store %s", utils.Dedup, cfg.dedupCfg.Store)
+		}
+		if len(cfg.dedupCfg.Key) == 0 {
+			return fmt.Errorf("<%s> empty key", utils.Dedup)
+		}
+		if cfg.dedupCfg.TTL <= 0 {
+			return fmt.Errorf("<%s> ttl should be greater than 0", utils.Dedup)
+		}
+	}
 	// FilterS sanity check
 	for _, connID := range cfg.filterSCfg.StatSConns {
 		if strings.HasPrefix(connID, utils.MetaInternal) && !cfg.statsCfg.Enabled {
@@ -518,6 +638,86 @@ func (cfg *CGRConfig) checkConfigSanity() error {
 			return fmt.Errorf("<%s> connection with id: <%s> not defined", utils.FilterS, connID)
 		}
 	}
+	// Field templates converters checks
+	for expID, cdreCfg := range cfg.CdreProfiles {
+		if err := checkFieldsConverters(cdreCfg.Fields); err != nil {
+			return fmt.Errorf("<%s> %s for export profile with ID: %s", utils.CDRE, err.Error(), expID)
+		}
+	}
+	for _, ldrSCfg := range cfg.loaderCfg {
+		if !ldrSCfg.Enabled {
+			continue
+		}
+		for _, data := range ldrSCfg.Data {
+			if err := checkFieldsConverters(data.Fields); err != nil {
+				return fmt.Errorf("<%s> %s for loader with ID: %s", utils.LoaderS, err.Error(), ldrSCfg.Id)
+			}
+		}
+	}
+	if cfg.ersCfg.Enabled {
+		for _, rdr := range cfg.ersCfg.Readers {
+			for _, flds := range [][]*FCTemplate{rdr.Fields, rdr.CacheDumpFields} {
+				if err := checkFieldsConverters(flds); err != nil {
+					return fmt.Errorf("<%s> %s for reader with ID: %s", utils.ERs, err.Error(), rdr.ID)
+				}
+			}
+		}
+	}
+	agntsRPs := make(map[string][]*RequestProcessor)
+	if cfg.diameterAgentCfg.Enabled {
+		for _, tpl := range cfg.diameterAgentCfg.Templates {
+			if err := checkFieldsConverters(tpl); err != nil {
+				return fmt.Errorf("<%s> %s", utils.DiameterAgent, err.Error())
+			}
+		}
+		agntsRPs[utils.DiameterAgent] = cfg.diameterAgentCfg.RequestProcessors
+	}
+	if cfg.radiusAgentCfg.Enabled {
+		for _, tpl := range cfg.radiusAgentCfg.Templates {
+			if err := checkFieldsConverters(tpl); err != nil {
+				return fmt.Errorf("<%s> %s", utils.RadiusAgent, err.Error())
+			}
+		}
+		agntsRPs[utils.RadiusAgent] = cfg.radiusAgentCfg.RequestProcessors
+	}
+	if cfg.dnsAgentCfg.Enabled {
+		agntsRPs[utils.DNSAgent] = cfg.dnsAgentCfg.RequestProcessors
+	}
+	for _, httpAgentCfg := range cfg.httpAgentCfg {
+		agntsRPs[utils.HTTPAgent] = append(agntsRPs[utils.HTTPAgent], httpAgentCfg.RequestProcessors...)
+	}
+	for agnt, rps := range agntsRPs {
+		for _, rp := range rps {
+			for _, flds := range [][]*FCTemplate{rp.RequestFields, rp.ReplyFields} {
+				if err := checkFieldsConverters(flds); err != nil {
+					return fmt.Errorf("<%s> %s for request processor with ID: %s", agnt, err.Error(), rp.ID)
+				}
+			}
+		}
+	}
+	return nil
+}
 
+// checkFieldsConverters validates the converters used within the field templates:
+// the *pad length can not exceed the field width and the static values need to be convertible
+func checkFieldsConverters(fields []*FCTemplate) error {
+	for _, fld := range fields {
+		for _, prsr := range fld.Value {
+			for _, conv := range prsr.converters {
+				if padConv, isPad := conv.(*utils.PadConverter); isPad &&
+					fld.Width != 0 && padConv.Length > fld.Width {
+					return fmt.Errorf("%s converter length %d exceeds the width %d of field with tag: %s",
+						utils.MetaPad, padConv.Length, fld.Width, fld.Tag)
+				}
+			}
+			if prsr.attrValue == utils.EmptyString || len(prsr.converters) == 0 {
+				continue
+			}
+			if _, err := prsr.converters.ConvertString(prsr.attrValue); err != nil {
+				return fmt.Errorf("cannot convert the value of field with tag: %s, err: %s",
+					fld.Tag, err.Error())
+			}
+		}
+	}
 	return nil
 }
@@ -0,0 +1,52 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package config
+
+import (
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+// DedupCfg is the configuration of the event deduplication applied by ERs and CDRs
+type DedupCfg struct {
+	Key   RSRParsers    // template building the deduplication key out of the event
+	TTL   time.Duration // time window in which an event with the same key is considered duplicate
+	Store string        // where the keys are kept <*internal|*datadb>
+}
+
+func (dd *DedupCfg) loadFromJsonCfg(jsnCfg *DedupJsonCfg, separator string) (err error) {
+	if jsnCfg == nil {
+		return
+	}
+	if jsnCfg.Key != nil {
+		if dd.Key, err = NewRSRParsers(*jsnCfg.Key, true, separator); err != nil {
+			return
+		}
+	}
+	if jsnCfg.Ttl != nil {
+		if dd.TTL, err = utils.ParseDurationWithNanosecs(*jsnCfg.Ttl); err != nil {
+			return
+		}
+	}
+	if jsnCfg.Store != nil {
+		dd.Store = *jsnCfg.Store
+	}
+	return
+}
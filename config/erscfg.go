@@ -25,9 +25,11 @@ import (
 )
 
 type ERsCfg struct {
-	Enabled       bool
-	SessionSConns []string
-	Readers       []*EventReaderCfg
+	Enabled             bool
+	SessionSConns       []string
+	CheckpointPath      string // folder where the readers persist their progress, disabled if empty
+	MaxDecompressedSize int64  // maximum size of the files extracted out of one compressed file, 0 for unlimited
+	Readers             []*EventReaderCfg
 }
 
 func (erS *ERsCfg) loadFromJsonCfg(jsnCfg *ERsJsonCfg, sep string, dfltRdrCfg *EventReaderCfg) (err error) {
@@ -48,6 +50,12 @@ func (erS *ERsCfg) loadFromJsonCfg(jsnCfg *ERsJsonCfg, sep string, dfltRdrCfg *E
 			}
 		}
 	}
+	if jsnCfg.Checkpoint_path != nil {
+		erS.CheckpointPath = *jsnCfg.Checkpoint_path
+	}
+	if jsnCfg.Max_decompressed_size != nil {
+		erS.MaxDecompressedSize = *jsnCfg.Max_decompressed_size
+	}
 	return erS.appendERsReaders(jsnCfg.Readers, sep, dfltRdrCfg)
 }
 
@@ -91,6 +99,8 @@ func (erS *ERsCfg) Clone() (cln *ERsCfg) {
 	for idx, sConn := range erS.SessionSConns {
 		cln.SessionSConns[idx] = sConn
 	}
+	cln.CheckpointPath = erS.CheckpointPath
+	cln.MaxDecompressedSize = erS.MaxDecompressedSize
 	cln.Readers = make([]*EventReaderCfg, len(erS.Readers))
 	for idx, rdr := range erS.Readers {
 		cln.Readers[idx] = rdr.Clone()
@@ -108,6 +118,7 @@ type EventReaderCfg struct {
 	SourcePath               string
 	ProcessedPath            string
 	XmlRootPath              utils.HierarchyPath
+	ASN1SchemaPath           string // path towards the ASN.1 schema used by *file_asn1, built-in 3GPP one if empty
 	Tenant                   RSRParsers
 	Timezone                 string
 	Filters                  []string
@@ -152,6 +163,9 @@ func (er *EventReaderCfg) loadFromJsonCfg(jsnCfg *EventReaderJsonCfg, sep string
 	if jsnCfg.Xml_root_path != nil {
 		er.XmlRootPath = utils.ParseHierarchyPath(*jsnCfg.Xml_root_path, utils.EmptyString)
 	}
+	if jsnCfg.Asn1_schema_path != nil {
+		er.ASN1SchemaPath = *jsnCfg.Asn1_schema_path
+	}
 	if jsnCfg.Tenant != nil {
 		if er.Tenant, err = NewRSRParsers(*jsnCfg.Tenant, true, sep); err != nil {
 			return err
@@ -206,6 +220,7 @@ func (er *EventReaderCfg) Clone() (cln *EventReaderCfg) {
 	cln.SourcePath = er.SourcePath
 	cln.ProcessedPath = er.ProcessedPath
 	cln.XmlRootPath = er.XmlRootPath
+	cln.ASN1SchemaPath = er.ASN1SchemaPath
 	if len(er.Tenant) != 0 {
 		cln.Tenant = make(RSRParsers, len(er.Tenant))
 		for idx, val := range er.Tenant {
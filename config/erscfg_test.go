@@ -95,8 +95,9 @@ func TestEventRedearClone(t *testing.T) {
 
 func TestEventReaderLoadFromJSON(t *testing.T) {
 	expectedERsCfg := &ERsCfg{
-		Enabled:       true,
-		SessionSConns: []string{"conn1", "conn3"},
+		Enabled:             true,
+		SessionSConns:       []string{"conn1", "conn3"},
+		MaxDecompressedSize: 1073741824,
 		Readers: []*EventReaderCfg{
 			&EventReaderCfg{
 				ID:             utils.MetaDefault,
@@ -227,8 +228,9 @@ func TestEventReaderSanitization(t *testing.T) {
 
 func TestEventReaderSameID(t *testing.T) {
 	expectedERsCfg := &ERsCfg{
-		Enabled:       true,
-		SessionSConns: []string{"conn1"},
+		Enabled:             true,
+		SessionSConns:       []string{"conn1"},
+		MaxDecompressedSize: 1073741824,
 		Readers: []*EventReaderCfg{
 			&EventReaderCfg{
 				ID:             utils.MetaDefault,
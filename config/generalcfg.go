@@ -27,30 +27,32 @@ import (
 
 // General config section
 type GeneralCfg struct {
-	NodeID            string        // Identifier for this engine instance
-	Logger            string        // dictates the way logs are displayed/stored
-	LogLevel          int           // system wide log level, nothing higher than this will be logged
-	HttpSkipTlsVerify bool          // If enabled Http Client will accept any TLS certificate
-	RoundingDecimals  int           // Number of decimals to round end prices at
-	DBDataEncoding    string        // The encoding used to store object data in strings: <msgpack|json>
-	TpExportPath      string        // Path towards export folder for offline Tariff Plans
-	PosterAttempts    int           // Time to wait before writing the failed posts in a single file
-	FailedPostsDir    string        // Directory path where we store failed http requests
-	FailedPostsTTL    time.Duration // Directory path where we store failed http requests
-	DefaultReqType    string        // Use this request type if not defined on top
-	DefaultCategory   string        // set default type of record
-	DefaultTenant     string        // set default tenant
-	DefaultTimezone   string        // default timezone for timestamps where not specified <""|UTC|Local|$IANA_TZ_DB>
-	DefaultCaching    string
-	ConnectAttempts   int           // number of initial connection attempts before giving up
-	Reconnects        int           // number of recconect attempts in case of connection lost <-1 for infinite | nb>
-	ConnectTimeout    time.Duration // timeout for RPC connection attempts
-	ReplyTimeout      time.Duration // timeout replies if not reaching back
-	LockingTimeout    time.Duration // locking mechanism timeout to avoid deadlocks
-	DigestSeparator   string        //
-	DigestEqual       string        //
-	RSRSep            string        // separator used to split RSRParser (by degault is used ";")
-	MaxParralelConns  int           // the maximum number of connection used by the *parallel strategy
+	NodeID             string         // Identifier for this engine instance
+	Logger             string         // dictates the way logs are displayed/stored
+	LogLevel           int            // system wide log level, nothing higher than this will be logged
+	LogFormat          string         // format of the log entries <*text|*json>
+	SubsystemLogLevels map[string]int // log level per subsystem, overwriting the LogLevel
+	HttpSkipTlsVerify  bool           // If enabled Http Client will accept any TLS certificate
+	RoundingDecimals   int            // Number of decimals to round end prices at
+	DBDataEncoding     string         // The encoding used to store object data in strings: <msgpack|json>
+	TpExportPath       string         // Path towards export folder for offline Tariff Plans
+	PosterAttempts     int            // Time to wait before writing the failed posts in a single file
+	FailedPostsDir     string         // Directory path where we store failed http requests
+	FailedPostsTTL     time.Duration  // Directory path where we store failed http requests
+	DefaultReqType     string         // Use this request type if not defined on top
+	DefaultCategory    string         // set default type of record
+	DefaultTenant      string         // set default tenant
+	DefaultTimezone    string         // default timezone for timestamps where not specified <""|UTC|Local|$IANA_TZ_DB>
+	DefaultCaching     string
+	ConnectAttempts    int           // number of initial connection attempts before giving up
+	Reconnects         int           // number of recconect attempts in case of connection lost <-1 for infinite | nb>
+	ConnectTimeout     time.Duration // timeout for RPC connection attempts
+	ReplyTimeout       time.Duration // timeout replies if not reaching back
+	LockingTimeout     time.Duration // locking mechanism timeout to avoid deadlocks
+	DigestSeparator    string        //
+	DigestEqual        string        //
+	RSRSep             string        // separator used to split RSRParser (by degault is used ";")
+	MaxParralelConns   int           // the maximum number of connection used by the *parallel strategy
 }
 
 //loadFromJsonCfg loads General config from JsonCfg
@@ -67,6 +69,15 @@ func (gencfg *GeneralCfg) loadFromJsonCfg(jsnGeneralCfg *GeneralJsonCfg) (err er
 	if jsnGeneralCfg.Log_level != nil {
 		gencfg.LogLevel = *jsnGeneralCfg.Log_level
 	}
+	if jsnGeneralCfg.Log_format != nil {
+		gencfg.LogFormat = *jsnGeneralCfg.Log_format
+	}
+	if jsnGeneralCfg.Subsystem_log_levels != nil {
+		gencfg.SubsystemLogLevels = make(map[string]int)
+		for subsys, lvl := range *jsnGeneralCfg.Subsystem_log_levels {
+			gencfg.SubsystemLogLevels[subsys] = lvl
+		}
+	}
 
 	if jsnGeneralCfg.Dbdata_encoding != nil {
 		gencfg.DBDataEncoding = strings.TrimPrefix(*jsnGeneralCfg.Dbdata_encoding, "*")
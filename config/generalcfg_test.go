@@ -21,6 +21,8 @@ import (
 	"reflect"
 	"testing"
 	"time"
+
+	"github.com/cgrates/cgrates/utils"
 )
 
 func TestGeneralCfgloadFromJsonCfg(t *testing.T) {
@@ -40,6 +42,8 @@ func TestGeneralCfgloadFromJsonCfg(t *testing.T) {
 	"node_id": "",										// identifier of this instance in the cluster, if empty it will be autogenerated
 	"logger":"*syslog",										// controls the destination of logs <*syslog|*stdout>
 	"log_level": 6,											// control the level of messages logged (0-emerg to 7-debug)
+	"log_format": "*json",
+	"subsystem_log_levels": {"SessionS": 7},
 	"http_skip_tls_verify": false,							// if enabled Http Client will accept any TLS certificate
 	"rounding_decimals": 5,									// system level precision for floats
 	"dbdata_encoding": "msgpack",							// encoding used to store object data in strings: <msgpack|json>
@@ -61,25 +65,27 @@ func TestGeneralCfgloadFromJsonCfg(t *testing.T) {
 }
 }`
 	expected = GeneralCfg{
-		NodeID:            "",
-		Logger:            "*syslog",
-		LogLevel:          6,
-		HttpSkipTlsVerify: false,
-		RoundingDecimals:  5,
-		DBDataEncoding:    "msgpack",
-		TpExportPath:      "/var/spool/cgrates/tpe",
-		PosterAttempts:    3,
-		FailedPostsDir:    "/var/spool/cgrates/failed_posts",
-		DefaultReqType:    "*rated",
-		DefaultCategory:   "call",
-		DefaultTenant:     "cgrates.org",
-		DefaultTimezone:   "Local",
-		ConnectAttempts:   3,
-		Reconnects:        -1,
-		ConnectTimeout:    time.Duration(1 * time.Second),
-		ReplyTimeout:      time.Duration(2 * time.Second),
-		DigestSeparator:   ",",
-		DigestEqual:       ":",
+		NodeID:             "",
+		Logger:             "*syslog",
+		LogLevel:           6,
+		LogFormat:          utils.MetaJSON,
+		SubsystemLogLevels: map[string]int{utils.SessionS: utils.LOGLEVEL_DEBUG},
+		HttpSkipTlsVerify:  false,
+		RoundingDecimals:   5,
+		DBDataEncoding:     "msgpack",
+		TpExportPath:       "/var/spool/cgrates/tpe",
+		PosterAttempts:     3,
+		FailedPostsDir:     "/var/spool/cgrates/failed_posts",
+		DefaultReqType:     "*rated",
+		DefaultCategory:    "call",
+		DefaultTenant:      "cgrates.org",
+		DefaultTimezone:    "Local",
+		ConnectAttempts:    3,
+		Reconnects:         -1,
+		ConnectTimeout:     time.Duration(1 * time.Second),
+		ReplyTimeout:       time.Duration(2 * time.Second),
+		DigestSeparator:    ",",
+		DigestEqual:        ":",
 	}
 	if jsnCfg, err := NewCgrJsonCfgFromBytes([]byte(cfgJSONStr)); err != nil {
 		t.Error(err)
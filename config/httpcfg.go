@@ -24,6 +24,7 @@ type HTTPCfg struct {
 	HTTPWSURL             string            // WebSocket relative URL ("" to disable)
 	HTTPFreeswitchCDRsURL string            // Freeswitch CDRS relative URL ("" to disable)
 	HTTPCDRsURL           string            // CDRS relative URL ("" to disable)
+	HTTPMetricsURL        string            // runtime metrics relative URL ("" to disable)
 	HTTPUseBasicAuth      bool              // Use basic auth for HTTP API
 	HTTPAuthUsers         map[string]string // Basic auth user:password map (base64 passwords)
 }
@@ -45,6 +46,9 @@ func (httpcfg *HTTPCfg) loadFromJsonCfg(jsnHttpCfg *HTTPJsonCfg) (err error) {
 	if jsnHttpCfg.Http_Cdrs != nil {
 		httpcfg.HTTPCDRsURL = *jsnHttpCfg.Http_Cdrs
 	}
+	if jsnHttpCfg.Metrics_url != nil {
+		httpcfg.HTTPMetricsURL = *jsnHttpCfg.Metrics_url
+	}
 	if jsnHttpCfg.Use_basic_auth != nil {
 		httpcfg.HTTPUseBasicAuth = *jsnHttpCfg.Use_basic_auth
 	}
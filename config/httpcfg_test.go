@@ -40,6 +40,7 @@ func TestHTTPCfgloadFromJsonCfg(t *testing.T) {
 	"ws_url": "/ws",							// WebSockets relative URL ("" to disable)
 	"freeswitch_cdrs_url": "/freeswitch_json",	// Freeswitch CDRS relative URL ("" to disable)
 	"http_cdrs": "/cdr_http",					// CDRS relative URL ("" to disable)
+	"metrics_url": "/metrics",
 	"use_basic_auth": false,					// use basic authentication
 	"auth_users": {},							// basic authentication usernames and base64-encoded passwords (eg: { "username1": "cGFzc3dvcmQ=", "username2": "cGFzc3dvcmQy "})
 	},
@@ -49,6 +50,7 @@ func TestHTTPCfgloadFromJsonCfg(t *testing.T) {
 		HTTPWSURL:             "/ws",
 		HTTPFreeswitchCDRsURL: "/freeswitch_json",
 		HTTPCDRsURL:           "/cdr_http",
+		HTTPMetricsURL:        "/metrics",
 		HTTPUseBasicAuth:      false,
 		HTTPAuthUsers:         map[string]string{},
 	}
@@ -25,6 +25,8 @@ type GeneralJsonCfg struct {
 	Node_id              *string
 	Logger               *string
 	Log_level            *int
+	Log_format           *string
+	Subsystem_log_levels *map[string]int
 	Http_skip_tls_verify *bool
 	Rounding_decimals    *int
 	Dbdata_encoding      *string
@@ -64,6 +66,7 @@ type HTTPJsonCfg struct {
 	Ws_url              *string
 	Freeswitch_cdrs_url *string
 	Http_Cdrs           *string
+	Metrics_url         *string
 	Use_basic_auth      *bool
 	Auth_users          *map[string]string
 }
@@ -166,9 +169,11 @@ type CdreJsonCfg struct {
 
 // EventReaderSJsonCfg contains the configuration of EventReaderService
 type ERsJsonCfg struct {
-	Enabled        *bool
-	Sessions_conns *[]string
-	Readers        *[]*EventReaderJsonCfg
+	Enabled               *bool
+	Sessions_conns        *[]string
+	Checkpoint_path       *string
+	Max_decompressed_size *int64
+	Readers               *[]*EventReaderJsonCfg
 }
 
 // EventReaderSJsonCfg is the configuration of a single EventReader
@@ -182,6 +187,7 @@ type EventReaderJsonCfg struct {
 	Source_path                 *string
 	Processed_path              *string
 	Xml_root_path               *string
+	Asn1_schema_path            *string
 	Tenant                      *string
 	Timezone                    *string
 	Filters                     *[]string
@@ -220,6 +226,7 @@ type SessionSJsonCfg struct {
 	Terminate_attempts    *int
 	Alterable_fields      *[]string
 	Min_dur_low_balance   *string
+	Backup_interval       *string
 }
 
 // FreeSWITCHAgent config section
@@ -334,6 +341,10 @@ type RadiusAgentJsonCfg struct {
 	Client_dictionaries *map[string]string
 	Sessions_conns      *[]string
 	Timezone            *string
+	Client_da_addresses *map[string]string
+	Dmr_template        *string
+	Coa_template        *string
+	Templates           map[string][]*FcTemplateJsonCfg
 	Request_processors  *[]*ReqProcessorJsnCfg
 }
 
@@ -555,7 +566,34 @@ type FcTemplateJsonCfg struct {
 
 // Analyzer service json config section
 type AnalyzerSJsonCfg struct {
-	Enabled *bool
+	Enabled          *bool
+	Ttl              *string
+	Cleanup_interval *string
+}
+
+// DedupJsonCfg the config section for the event deduplication
+type DedupJsonCfg struct {
+	Key   *string
+	Ttl   *string
+	Store *string
+}
+
+// TracingJsonCfg the config section for the distributed tracing
+type TracingJsonCfg struct {
+	Enabled        *bool
+	Exporter       *string
+	Export_path    *string
+	Buffer_size    *int
+	Flush_interval *string
+}
+
+// PrometheusAgentJsonCfg the config section for the Prometheus metrics endpoint
+type PrometheusAgentJsonCfg struct {
+	Enabled       *bool
+	Path          *string
+	Stats_conns   *[]string
+	Stats_tenants *[]string
+	Stats_filters *[]string
 }
 
 type ApierJsonCfg struct {
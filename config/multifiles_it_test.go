@@ -81,30 +81,32 @@ func TestMfCdreExport1Instance(t *testing.T) {
 
 func TestMfEnvReaderITRead(t *testing.T) {
 	expected := GeneralCfg{
-		NodeID:            "d80fac5",
-		Logger:            "*syslog",
-		LogLevel:          6,
-		HttpSkipTlsVerify: false,
-		RoundingDecimals:  5,
-		DBDataEncoding:    "msgpack",
-		TpExportPath:      "/var/spool/cgrates/tpe",
-		PosterAttempts:    3,
-		FailedPostsDir:    "/var/spool/cgrates/failed_posts",
-		DefaultReqType:    utils.META_PSEUDOPREPAID,
-		DefaultCategory:   "call",
-		DefaultTenant:     "cgrates.org",
-		DefaultCaching:    utils.MetaReload,
-		DefaultTimezone:   "Local",
-		ConnectAttempts:   3,
-		Reconnects:        -1,
-		ConnectTimeout:    time.Duration(1 * time.Second),
-		ReplyTimeout:      time.Duration(2 * time.Second),
-		LockingTimeout:    time.Duration(0),
-		DigestSeparator:   ",",
-		DigestEqual:       ":",
-		RSRSep:            ";",
-		MaxParralelConns:  100,
-		FailedPostsTTL:    5 * time.Second,
+		NodeID:             "d80fac5",
+		Logger:             "*syslog",
+		LogLevel:           6,
+		LogFormat:          utils.MetaText,
+		SubsystemLogLevels: map[string]int{},
+		HttpSkipTlsVerify:  false,
+		RoundingDecimals:   5,
+		DBDataEncoding:     "msgpack",
+		TpExportPath:       "/var/spool/cgrates/tpe",
+		PosterAttempts:     3,
+		FailedPostsDir:     "/var/spool/cgrates/failed_posts",
+		DefaultReqType:     utils.META_PSEUDOPREPAID,
+		DefaultCategory:    "call",
+		DefaultTenant:      "cgrates.org",
+		DefaultCaching:     utils.MetaReload,
+		DefaultTimezone:    "Local",
+		ConnectAttempts:    3,
+		Reconnects:         -1,
+		ConnectTimeout:     time.Duration(1 * time.Second),
+		ReplyTimeout:       time.Duration(2 * time.Second),
+		LockingTimeout:     time.Duration(0),
+		DigestSeparator:    ",",
+		DigestEqual:        ":",
+		RSRSep:             ";",
+		MaxParralelConns:   100,
+		FailedPostsTTL:     5 * time.Second,
 	}
 	if !reflect.DeepEqual(expected, *mfCgrCfg.generalCfg) {
 		t.Errorf("Expected: %+v\n, recived: %+v", utils.ToJSON(expected), utils.ToJSON(*mfCgrCfg.generalCfg))
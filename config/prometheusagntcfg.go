@@ -0,0 +1,62 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package config
+
+import "github.com/cgrates/cgrates/utils"
+
+// PrometheusAgentCfg the config section for the Prometheus metrics endpoint
+type PrometheusAgentCfg struct {
+	Enabled      bool
+	Path         string   // HTTP path where the metrics are exposed
+	StatSConns   []string // connections to StatS queried for the StatQueue metrics
+	StatsTenants []string // export only the StatQueues of these tenants, defaults to the default_tenant
+	StatsFilters []string // export only the StatQueues passing these filters
+}
+
+func (pa *PrometheusAgentCfg) loadFromJsonCfg(jsnCfg *PrometheusAgentJsonCfg) (err error) {
+	if jsnCfg == nil {
+		return
+	}
+	if jsnCfg.Enabled != nil {
+		pa.Enabled = *jsnCfg.Enabled
+	}
+	if jsnCfg.Path != nil {
+		pa.Path = *jsnCfg.Path
+	}
+	if jsnCfg.Stats_conns != nil {
+		pa.StatSConns = make([]string, len(*jsnCfg.Stats_conns))
+		for idx, connID := range *jsnCfg.Stats_conns {
+			// if we have the connection internal we change the name so we can have internal rpc for each subsystem
+			if connID == utils.MetaInternal {
+				pa.StatSConns[idx] = utils.ConcatenatedKey(utils.MetaInternal, utils.MetaStatS)
+			} else {
+				pa.StatSConns[idx] = connID
+			}
+		}
+	}
+	if jsnCfg.Stats_tenants != nil {
+		pa.StatsTenants = make([]string, len(*jsnCfg.Stats_tenants))
+		copy(pa.StatsTenants, *jsnCfg.Stats_tenants)
+	}
+	if jsnCfg.Stats_filters != nil {
+		pa.StatsFilters = make([]string, len(*jsnCfg.Stats_filters))
+		copy(pa.StatsFilters, *jsnCfg.Stats_filters)
+	}
+	return
+}
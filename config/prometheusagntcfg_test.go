@@ -0,0 +1,60 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestPrometheusAgentCfgloadFromJsonCfg(t *testing.T) {
+	var paCfg, expected PrometheusAgentCfg
+	if err := paCfg.loadFromJsonCfg(nil); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(paCfg, expected) {
+		t.Errorf("Expected: %+v ,recived: %+v", expected, paCfg)
+	}
+	cfgJSONStr := `{
+"prometheus_agent": {
+	"enabled": true,
+	"path": "/prometheus",
+	"stats_conns": ["*internal", "conn1"],
+	"stats_tenants": ["cgrates.org", "itsyscom.com"],
+	"stats_filters": ["*string:~*req.ID:SQ_1"],
+},
+}`
+	expected = PrometheusAgentCfg{
+		Enabled:      true,
+		Path:         "/prometheus",
+		StatSConns:   []string{utils.ConcatenatedKey(utils.MetaInternal, utils.MetaStatS), "conn1"},
+		StatsTenants: []string{"cgrates.org", "itsyscom.com"},
+		StatsFilters: []string{"*string:~*req.ID:SQ_1"},
+	}
+	if jsnCfg, err := NewCgrJsonCfgFromBytes([]byte(cfgJSONStr)); err != nil {
+		t.Error(err)
+	} else if jsnPaCfg, err := jsnCfg.PrometheusAgentJsonCfg(); err != nil {
+		t.Error(err)
+	} else if err = paCfg.loadFromJsonCfg(jsnPaCfg); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(expected, paCfg) {
+		t.Errorf("Expected: %+v , recived: %+v", utils.ToJSON(expected), utils.ToJSON(paCfg))
+	}
+}
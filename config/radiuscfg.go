@@ -28,6 +28,10 @@ type RadiusAgentCfg struct {
 	ClientSecrets      map[string]string
 	ClientDictionaries map[string]string
 	SessionSConns      []string
+	ClientDaAddresses  map[string]string // address of the Dynamic Authorization server per client, defaults to client:3799
+	DMRTemplate        string
+	CoATemplate        string
+	Templates          map[string][]*FCTemplate
 	RequestProcessors  []*RequestProcessor
 }
 
@@ -74,6 +78,30 @@ func (self *RadiusAgentCfg) loadFromJsonCfg(jsnCfg *RadiusAgentJsonCfg, separato
 			}
 		}
 	}
+	if jsnCfg.Client_da_addresses != nil {
+		if self.ClientDaAddresses == nil {
+			self.ClientDaAddresses = make(map[string]string)
+		}
+		for k, v := range *jsnCfg.Client_da_addresses {
+			self.ClientDaAddresses[k] = v
+		}
+	}
+	if jsnCfg.Dmr_template != nil {
+		self.DMRTemplate = *jsnCfg.Dmr_template
+	}
+	if jsnCfg.Coa_template != nil {
+		self.CoATemplate = *jsnCfg.Coa_template
+	}
+	if jsnCfg.Templates != nil {
+		if self.Templates == nil {
+			self.Templates = make(map[string][]*FCTemplate)
+		}
+		for k, jsnTpls := range jsnCfg.Templates {
+			if self.Templates[k], err = FCTemplatesFromFCTemplatesJsonCfg(jsnTpls, separator); err != nil {
+				return
+			}
+		}
+	}
 	if jsnCfg.Request_processors != nil {
 		for _, reqProcJsn := range *jsnCfg.Request_processors {
 			rp := new(RequestProcessor)
@@ -49,6 +49,8 @@ func TestRadiusAgentCfgloadFromJsonCfg(t *testing.T) {
 		"*default": "/usr/share/cgrates/radius/dict/",			// key represents the client IP or catch-all <*default|$client_ip>
 	},
 	"sessions_conns": ["*internal"],
+	"client_da_addresses": {"127.0.0.1": "127.0.0.1:3799"},
+	"dmr_template": "*dmr",
 	"request_processors": [],
 },
 }`
@@ -59,6 +61,8 @@ func TestRadiusAgentCfgloadFromJsonCfg(t *testing.T) {
 		ClientSecrets:      map[string]string{utils.MetaDefault: "CGRateS.org"},
 		ClientDictionaries: map[string]string{utils.MetaDefault: "/usr/share/cgrates/radius/dict/"},
 		SessionSConns:      []string{utils.ConcatenatedKey(utils.MetaInternal, utils.MetaSessionS)},
+		ClientDaAddresses:  map[string]string{"127.0.0.1": "127.0.0.1:3799"},
+		DMRTemplate:        utils.MetaDMR,
 	}
 	if jsnCfg, err := NewCgrJsonCfgFromBytes([]byte(cfgJSONStr)); err != nil {
 		t.Error(err)
@@ -165,3 +165,50 @@ func TestRSRParsersParseInnerBracket(t *testing.T) {
 		t.Errorf("expecting: %s, received: %s", expAttrName, prsr.AttrName())
 	}
 }
+
+func TestRSRParserStringConverters(t *testing.T) {
+	prsr, err := NewRSRParser("~Account{*trim&*upper&*substr:0:3&*pad:5:0:*left}", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out, err := prsr.ParseEvent(map[string]interface{}{"Account": " abcdef "}); err != nil {
+		t.Error(err)
+	} else if out != "00ABC" {
+		t.Errorf("expecting: 00ABC, received: %s", out)
+	}
+	if _, err := NewRSRParser("~Account{*pad:a}", true); err == nil {
+		t.Error("expecting error")
+	}
+}
+
+func TestRSRParserTimeConverters(t *testing.T) {
+	prsr, err := NewRSRParser("~AnswerTime{*time_string:2006-01-02 15:04:05:Europe/Berlin}", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out, err := prsr.ParseEvent(map[string]interface{}{"AnswerTime": "2020-03-29T01:30:15Z"}); err != nil {
+		t.Error(err)
+	} else if out != "2020-03-29 03:30:15" {
+		t.Errorf("expecting: 2020-03-29 03:30:15, received: %s", out)
+	}
+	prsr = NewRSRParserMustCompile("~AnswerTime{*unixtime_ms}", true)
+	if out, err := prsr.ParseEvent(map[string]interface{}{"AnswerTime": "2020-03-29T01:30:15Z"}); err != nil {
+		t.Error(err)
+	} else if out != "1585445415000" {
+		t.Errorf("expecting: 1585445415000, received: %s", out)
+	}
+}
+
+func TestRSRParserSIPURIConverter(t *testing.T) {
+	prsr := NewRSRParserMustCompile("~*req.From{*sipuri:user}", true)
+	nM := NewNavigableMap(map[string]interface{}{
+		utils.MetaReq: map[string]interface{}{
+			"From": `"Alice" <sip:1001@cgrates.org;transport=tcp>;tag=1928301774`,
+		},
+	})
+	if out, err := prsr.ParseDataProvider(nM, utils.NestingSep); err != nil {
+		t.Error(err)
+	} else if out != "1001" {
+		t.Errorf("expecting: 1001, received: %s", out)
+	}
+}
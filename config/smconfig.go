@@ -89,6 +89,7 @@ type SessionSCfg struct {
 	TerminateAttempts   int
 	AlterableFields     *utils.StringSet
 	MinDurLowBalance    time.Duration
+	BackupInterval      time.Duration
 }
 
 func (scfg *SessionSCfg) loadFromJsonCfg(jsnCfg *SessionSJsonCfg) (err error) {
@@ -265,6 +266,11 @@ func (scfg *SessionSCfg) loadFromJsonCfg(jsnCfg *SessionSJsonCfg) (err error) {
 			return err
 		}
 	}
+	if jsnCfg.Backup_interval != nil {
+		if scfg.BackupInterval, err = utils.ParseDurationWithNanosecs(*jsnCfg.Backup_interval); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
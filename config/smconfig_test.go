@@ -95,6 +95,7 @@ func TestSessionSCfgloadFromJsonCfg(t *testing.T) {
 	"session_indexes": [],					// index sessions based on these fields for GetActiveSessions API
 	"client_protocol": 1.0,					// version of protocol to use when acting as JSON-PRC client <"0","1.0">
 	"channel_sync_interval": "0",			// sync channels regularly (0 to disable sync session)
+	"backup_interval": "30s",
 },
 }`
 	expected = SessionSCfg{
@@ -111,6 +112,7 @@ func TestSessionSCfgloadFromJsonCfg(t *testing.T) {
 		MaxCallDuration:  time.Duration(3 * time.Hour),
 		SessionIndexes:   map[string]bool{},
 		ClientProtocol:   1,
+		BackupInterval:   30 * time.Second,
 	}
 	if jsnCfg, err := NewCgrJsonCfgFromBytes([]byte(cfgJSONStr)); err != nil {
 		t.Error(err)
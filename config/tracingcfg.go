@@ -0,0 +1,58 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package config
+
+import (
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+// TracingCfg is the configuration of the distributed tracing
+type TracingCfg struct {
+	Enabled       bool
+	Exporter      string        // where the finished spans are sent <*file_json|*otlp_http>
+	ExportPath    string        // the file path for *file_json, the collector URL for *otlp_http
+	BufferSize    int           // maximum number of finished spans waiting to be exported
+	FlushInterval time.Duration // how often the finished spans are exported
+}
+
+func (tr *TracingCfg) loadFromJsonCfg(jsnCfg *TracingJsonCfg) (err error) {
+	if jsnCfg == nil {
+		return
+	}
+	if jsnCfg.Enabled != nil {
+		tr.Enabled = *jsnCfg.Enabled
+	}
+	if jsnCfg.Exporter != nil {
+		tr.Exporter = *jsnCfg.Exporter
+	}
+	if jsnCfg.Export_path != nil {
+		tr.ExportPath = *jsnCfg.Export_path
+	}
+	if jsnCfg.Buffer_size != nil {
+		tr.BufferSize = *jsnCfg.Buffer_size
+	}
+	if jsnCfg.Flush_interval != nil {
+		if tr.FlushInterval, err = utils.ParseDurationWithNanosecs(*jsnCfg.Flush_interval); err != nil {
+			return
+		}
+	}
+	return
+}
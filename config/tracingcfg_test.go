@@ -0,0 +1,65 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestTracingCfgloadFromJsonCfg(t *testing.T) {
+	var trCfg, expected TracingCfg
+	if err := trCfg.loadFromJsonCfg(nil); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(trCfg, expected) {
+		t.Errorf("Expected: %+v ,recived: %+v", expected, trCfg)
+	}
+	cfgJSONStr := `{
+"tracing": {
+	"enabled": true,
+	"exporter": "*otlp_http",
+	"export_path": "http://127.0.0.1:4318/v1/traces",
+	"buffer_size": 512,
+	"flush_interval": "5s",
+},
+}`
+	expected = TracingCfg{
+		Enabled:       true,
+		Exporter:      utils.MetaOTLPHTTP,
+		ExportPath:    "http://127.0.0.1:4318/v1/traces",
+		BufferSize:    512,
+		FlushInterval: 5 * time.Second,
+	}
+	if jsnCfg, err := NewCgrJsonCfgFromBytes([]byte(cfgJSONStr)); err != nil {
+		t.Error(err)
+	} else if jsnTrCfg, err := jsnCfg.TracingJsonCfg(); err != nil {
+		t.Error(err)
+	} else if err = trCfg.loadFromJsonCfg(jsnTrCfg); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(expected, trCfg) {
+		t.Errorf("Expected: %+v , recived: %+v", utils.ToJSON(expected), utils.ToJSON(trCfg))
+	}
+	jsnTrCfg := &TracingJsonCfg{Flush_interval: utils.StringPointer("1ss")}
+	if err := trCfg.loadFromJsonCfg(jsnTrCfg); err == nil {
+		t.Error("Expected error for invalid flush_interval")
+	}
+}
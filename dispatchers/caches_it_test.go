@@ -179,6 +179,7 @@ func testDspChcPrecacheStatus(t *testing.T) {
 		utils.CacheDispatcherProfiles:      utils.MetaReady,
 		utils.CacheDispatcherHosts:         utils.MetaReady,
 		utils.CacheDiameterMessages:        utils.MetaReady,
+		utils.CacheRadiusPackets:           utils.MetaReady,
 		utils.CacheAttributeFilterIndexes:  utils.MetaReady,
 		utils.CacheResourceFilterIndexes:   utils.MetaReady,
 		utils.CacheStatFilterIndexes:       utils.MetaReady,
@@ -68,3 +68,49 @@ func (dS *DispatcherService) CoreSv1Ping(args *utils.CGREventWithArgDispatcher,
 	return dS.Dispatch(args.CGREvent, utils.MetaCore, routeID,
 		utils.CoreSv1Ping, args, reply)
 }
+
+func (dS *DispatcherService) CoreSv1Metrics(args *utils.ArgsGetMetricsWithArgDispatcher,
+	reply *[]*utils.MetricFamily) (err error) {
+	tnt := dS.cfg.GeneralCfg().DefaultTenant
+	if args.TenantArg.Tenant != utils.EmptyString {
+		tnt = args.TenantArg.Tenant
+	}
+	if len(dS.cfg.DispatcherSCfg().AttributeSConns) != 0 {
+		if args.ArgDispatcher == nil {
+			return utils.NewErrMandatoryIeMissing(utils.ArgDispatcherField)
+		}
+		if err = dS.authorize(utils.CoreSv1Metrics, tnt,
+			args.APIKey, utils.TimePointer(time.Now())); err != nil {
+			return
+		}
+	}
+	var routeID *string
+	if args.ArgDispatcher != nil {
+		routeID = args.ArgDispatcher.RouteID
+	}
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCore,
+		routeID, utils.CoreSv1Metrics, args, reply)
+}
+
+func (dS *DispatcherService) CoreSv1SetLogLevel(args *utils.ArgsSetLogLevelWithArgDispatcher,
+	reply *string) (err error) {
+	tnt := dS.cfg.GeneralCfg().DefaultTenant
+	if args.TenantArg.Tenant != utils.EmptyString {
+		tnt = args.TenantArg.Tenant
+	}
+	if len(dS.cfg.DispatcherSCfg().AttributeSConns) != 0 {
+		if args.ArgDispatcher == nil {
+			return utils.NewErrMandatoryIeMissing(utils.ArgDispatcherField)
+		}
+		if err = dS.authorize(utils.CoreSv1SetLogLevel, tnt,
+			args.APIKey, utils.TimePointer(time.Now())); err != nil {
+			return
+		}
+	}
+	var routeID *string
+	if args.ArgDispatcher != nil {
+		routeID = args.ArgDispatcher.RouteID
+	}
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCore,
+		routeID, utils.CoreSv1SetLogLevel, args, reply)
+}
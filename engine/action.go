@@ -104,6 +104,7 @@ func getActionFunc(typ string) (actionTypeFunc, bool) {
 		utils.MetaSQSjsonMap:            sendSQS,
 		utils.MetaKafkajsonMap:          sendKafka,
 		utils.MetaS3jsonMap:             sendS3,
+		utils.MetaNatsjsonMap:           sendNATS,
 		utils.MetaRemoveSessionCosts:    removeSessionCosts,
 		utils.MetaRemoveExpired:         removeExpired,
 		utils.MetaPostEvent:             postEvent,
@@ -439,6 +440,19 @@ func sendS3(ub *Account, a *Action, acs Actions, extraData interface{}) error {
 	return err
 }
 
+func sendNATS(ub *Account, a *Action, acs Actions, extraData interface{}) error {
+	body, err := getOneData(ub, extraData)
+	if err != nil {
+		return err
+	}
+	err = PostersCache.PostNATS(a.ExtraParameters, config.CgrConfig().GeneralCfg().PosterAttempts, body)
+	if err != nil && config.CgrConfig().GeneralCfg().FailedPostsDir != utils.META_NONE {
+		addFailedPost(a.ExtraParameters, utils.MetaNatsjsonMap, utils.ActionsPoster+utils.HIERARCHY_SEP+a.ActionType, body)
+		err = nil
+	}
+	return err
+}
+
 func callURL(ub *Account, a *Action, acs Actions, extraData interface{}) error {
 	body, err := getOneData(ub, extraData)
 	if err != nil {
@@ -21,6 +21,7 @@ package engine
 import (
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/cgrates/cgrates/config"
@@ -31,8 +32,23 @@ import (
 // Cache is the global cache used
 var Cache *ltcache.TransCache
 
+// cacheLookups counts the hits and misses per partition for the DataDB items
+var cacheLookups sync.Map // map[partition]*cacheLookupCounters
+
+// cacheLookupCounters are updated atomically, without locking
+type cacheLookupCounters struct {
+	hits   uint64
+	misses uint64
+}
+
 func init() {
 	InitCache(nil)
+	utils.RuntimeMetrics.RegisterGaugeFunc(utils.MetricCacheItems,
+		"Items in cache, per partition.", cacheItemsMetrics)
+	utils.RuntimeMetrics.RegisterCounterFunc(utils.MetricCacheHits,
+		"DataDB items found in cache, per partition.", cacheHitsMetrics)
+	utils.RuntimeMetrics.RegisterCounterFunc(utils.MetricCacheMisses,
+		"DataDB items not found in cache, per partition.", cacheMissesMetrics)
 }
 
 // InitCache will instantiate the cache with specific or default configuraiton
@@ -41,7 +57,64 @@ func InitCache(cfg config.CacheCfg) {
 		cfg = config.CgrConfig().CacheCfg()
 	}
 	cfg.AddTmpCaches()
-	Cache = ltcache.NewTransCache(cfg.AsTransCacheConfig())
+	tcCfg := cfg.AsTransCacheConfig()
+	for chID, chCfg := range tcCfg {
+		chCfg.OnEvicted = newCacheEvictionCounter(chID)
+	}
+	Cache = ltcache.NewTransCache(tcCfg)
+}
+
+// cacheGet returns the item from Cache, counting the hit or miss
+// used for the DataDB items so the hit ratio of the data caching can be monitored
+func cacheGet(chID, itmID string) (x interface{}, has bool) {
+	x, has = Cache.Get(chID, itmID)
+	cntrs, ok := cacheLookups.Load(chID)
+	if !ok {
+		cntrs, _ = cacheLookups.LoadOrStore(chID, new(cacheLookupCounters))
+	}
+	if has {
+		atomic.AddUint64(&cntrs.(*cacheLookupCounters).hits, 1)
+	} else {
+		atomic.AddUint64(&cntrs.(*cacheLookupCounters).misses, 1)
+	}
+	return
+}
+
+// cacheLookupsMetrics returns the hits or misses for each partition
+func cacheLookupsMetrics(hits bool) (smpls []*utils.MetricSample) {
+	cacheLookups.Range(func(chID, cntrs interface{}) bool {
+		cnt := atomic.LoadUint64(&cntrs.(*cacheLookupCounters).misses)
+		if hits {
+			cnt = atomic.LoadUint64(&cntrs.(*cacheLookupCounters).hits)
+		}
+		smpls = append(smpls, &utils.MetricSample{
+			Labels: map[string]string{utils.MetricLabelPartition: chID.(string)},
+			Value:  float64(cnt),
+		})
+		return true
+	})
+	return
+}
+
+func cacheHitsMetrics() []*utils.MetricSample   { return cacheLookupsMetrics(true) }
+func cacheMissesMetrics() []*utils.MetricSample { return cacheLookupsMetrics(false) }
+
+// newCacheEvictionCounter returns the OnEvicted function counting the items removed from the partition
+func newCacheEvictionCounter(chID string) func(string, interface{}) {
+	return func(string, interface{}) {
+		utils.RuntimeMetrics.IncCounter(utils.MetricCacheEvictions, chID)
+	}
+}
+
+// cacheItemsMetrics returns the number of items in each cache partition
+func cacheItemsMetrics() (smpls []*utils.MetricSample) {
+	for chID, chStats := range Cache.GetCacheStats(nil) {
+		smpls = append(smpls, &utils.MetricSample{
+			Labels: map[string]string{utils.MetricLabelPartition: chID},
+			Value:  float64(chStats.Items),
+		})
+	}
+	return
 }
 
 // NewCacheS initializes the Cache service and executes the precaching
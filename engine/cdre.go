@@ -247,7 +247,7 @@ func (cdre *CDRExporter) postCdr(cdr *CDR) (err error) {
 		if body, err = json.Marshal(cdr); err != nil {
 			return
 		}
-	case utils.MetaHTTPjsonMap, utils.MetaAMQPjsonMap, utils.MetaAMQPV1jsonMap, utils.MetaSQSjsonMap, utils.MetaKafkajsonMap, utils.MetaS3jsonMap:
+	case utils.MetaHTTPjsonMap, utils.MetaAMQPjsonMap, utils.MetaAMQPV1jsonMap, utils.MetaSQSjsonMap, utils.MetaKafkajsonMap, utils.MetaS3jsonMap, utils.MetaNatsjsonMap:
 		var expMp map[string]string
 		if expMp, err = cdr.AsExportMap(cdre.exportTemplate.Fields, cdre.httpSkipTLSCheck, nil, cdre.filterS); err != nil {
 			return
@@ -288,6 +288,8 @@ func (cdre *CDRExporter) postCdr(cdr *CDR) (err error) {
 		err = PostersCache.PostKafka(cdre.exportPath, cdre.attempts, body.([]byte), utils.ConcatenatedKey(cdr.CGRID, cdr.RunID))
 	case utils.MetaS3jsonMap:
 		err = PostersCache.PostS3(cdre.exportPath, cdre.attempts, body.([]byte), utils.ConcatenatedKey(cdr.CGRID, cdr.RunID))
+	case utils.MetaNatsjsonMap:
+		err = PostersCache.PostNATS(cdre.exportPath, cdre.attempts, body.([]byte))
 	}
 	if err != nil && cdre.fallbackPath != utils.META_NONE {
 		addFailedPost(cdre.exportPath, cdre.exportFormat, utils.CDRPoster, body)
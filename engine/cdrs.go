@@ -79,6 +79,7 @@ func NewCDRServer(cgrCfg *config.CGRConfig, storDBChan chan StorDB, dm *DataMana
 		filterS:    filterS,
 		connMgr:    connMgr,
 		storDBChan: storDBChan,
+		dedupS:     NewDedupService(cgrCfg, dm, utils.CDRs),
 	}
 }
 
@@ -91,6 +92,7 @@ type CDRServer struct {
 	filterS    *FilterS
 	connMgr    *ConnManager
 	storDBChan chan StorDB
+	dedupS     *DedupService
 }
 
 // ListenAndServe listen for storbd reload
@@ -404,8 +406,18 @@ func (cdrS *CDRServer) exportCDRs(cdrs []*CDR) (err error) {
 
 // processEvent processes a CGREvent based on arguments
 // in case of partially executed, both error and evs will be returned
+// with dedup the event failing before being charged is released so it can be sent again
 func (cdrS *CDRServer) processEvent(ev *utils.CGREventWithArgDispatcher,
-	chrgS, attrS, refund, ralS, store, reRate, export, thdS, stS bool) (evs []*utils.EventWithFlags, err error) {
+	chrgS, attrS, refund, ralS, store, reRate, export, thdS, stS, dedup bool) (evs []*utils.EventWithFlags, err error) {
+	var charging bool // once charging started the event is not released anymore
+	if dedup {
+		origEv := ev.CGREvent // AttributeS might replace it
+		defer func() {
+			if err != nil && !charging {
+				cdrS.releaseEvent(origEv)
+			}
+		}()
+	}
 	if attrS {
 		if err = cdrS.attrSProcessEvent(ev); err != nil {
 			utils.Logger.Warning(
@@ -469,6 +481,7 @@ func (cdrS *CDRServer) processEvent(ev *utils.CGREventWithArgDispatcher,
 	for i := range cgrEvs {
 		procFlgs[i] = utils.NewStringSet(nil)
 	}
+	charging = true
 	if refund {
 		for i, cdr := range cdrs {
 			if rfnd, errRfd := cdrS.refundEventCost(cdr.CostDetails,
@@ -668,7 +681,8 @@ func (cdrS *CDRServer) V1ProcessCDR(cdr *CDRWithArgDispatcher, reply *string) (e
 		false, // no rerate
 		len(cdrS.cgrCfg.CdrsCfg().OnlineCDRExports) != 0,
 		len(cdrS.cgrCfg.CdrsCfg().ThresholdSConns) != 0,
-		len(cdrS.cgrCfg.CdrsCfg().StatSConns) != 0); err != nil {
+		len(cdrS.cgrCfg.CdrsCfg().StatSConns) != 0,
+		false); err != nil {
 		return
 	}
 	*reply = utils.OK
@@ -682,6 +696,35 @@ type ArgV1ProcessEvent struct {
 	*utils.ArgDispatcher
 }
 
+// dedupEvent returns utils.ErrExists if the event was processed already within the dedup ttl
+func (cdrS *CDRServer) dedupEvent(cgrEv *utils.CGREvent) (err error) {
+	var dup bool
+	if dup, err = cdrS.dedupS.IsDuplicate(cgrEv, cgrEv.Tenant); err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> error: %s deduplicating event: %s",
+				utils.CDRs, err.Error(), utils.ToJSON(cgrEv)))
+		return utils.NewErrServerError(err)
+	}
+	if dup {
+		return utils.ErrExists
+	}
+	return
+}
+
+// releaseEvent allows the event failed to process to be sent again
+func (cdrS *CDRServer) releaseEvent(cgrEv *utils.CGREvent) {
+	if err := cdrS.dedupS.Release(cgrEv); err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> error: %s releasing dedup key for event: %s",
+				utils.CDRs, err.Error(), utils.ToJSON(cgrEv)))
+	}
+}
+
+// V1GetDedupStats returns the number of duplicated events suppressed for each tenant
+func (cdrS *CDRServer) V1GetDedupStats(args *ArgsGetDedupStats, reply *map[string]int64) error {
+	return cdrS.dedupS.V1GetDedupStats(args, reply)
+}
+
 // V1ProcessEvent will process the CGREvent
 func (cdrS *CDRServer) V1ProcessEvent(arg *ArgV1ProcessEvent, reply *string) (err error) {
 	if arg.CGREvent.ID == "" {
@@ -751,14 +794,20 @@ func (cdrS *CDRServer) V1ProcessEvent(arg *ArgV1ProcessEvent, reply *string) (er
 	if flgs.HasKey(utils.MetaRefund) {
 		refund = flgs.GetBool(utils.MetaRefund)
 	}
+	dedup := flgs.GetBool(utils.MetaDedup)
 	// end of processing options
 
+	if dedup {
+		if err = cdrS.dedupEvent(&arg.CGREvent); err != nil {
+			return
+		}
+	}
 	cgrEv := &utils.CGREventWithArgDispatcher{
 		CGREvent:      &arg.CGREvent,
 		ArgDispatcher: arg.ArgDispatcher,
 	}
 	if _, err = cdrS.processEvent(cgrEv, chrgS, attrS, refund,
-		ralS, store, reRate, export, thdS, stS); err != nil {
+		ralS, store, reRate, export, thdS, stS, dedup); err != nil {
 		return
 	}
 	*reply = utils.OK
@@ -834,15 +883,21 @@ func (cdrS *CDRServer) V2ProcessEvent(arg *ArgV1ProcessEvent, evs *[]*utils.Even
 	if flgs.HasKey(utils.MetaRefund) {
 		refund = flgs.GetBool(utils.MetaRefund)
 	}
+	dedup := flgs.GetBool(utils.MetaDedup)
 	// end of processing options
 
+	if dedup {
+		if err = cdrS.dedupEvent(&arg.CGREvent); err != nil {
+			return
+		}
+	}
 	cgrEv := &utils.CGREventWithArgDispatcher{
 		CGREvent:      &arg.CGREvent,
 		ArgDispatcher: arg.ArgDispatcher,
 	}
 	var procEvs []*utils.EventWithFlags
 	if procEvs, err = cdrS.processEvent(cgrEv, chrgS, attrS, refund,
-		ralS, store, reRate, export, thdS, stS); err != nil {
+		ralS, store, reRate, export, thdS, stS, dedup); err != nil {
 		return
 	} else {
 		*evs = procEvs
@@ -1003,7 +1058,7 @@ func (cdrS *CDRServer) V1RateCDRs(arg *ArgRateCDRs, reply *string) (err error) {
 			ArgDispatcher: arg.ArgDispatcher,
 		}
 		if _, err = cdrS.processEvent(cgrEv, chrgS, attrS, false,
-			true, store, true, export, thdS, statS); err != nil {
+			true, store, true, export, thdS, statS, false); err != nil {
 			return utils.NewErrServerError(err)
 		}
 	}
@@ -20,6 +20,7 @@ package engine
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/utils"
@@ -30,6 +31,9 @@ import (
 func NewConnManager(cfg *config.CGRConfig, rpcInternal map[string]chan rpcclient.ClientConnector) (cM *ConnManager) {
 	cM = &ConnManager{cfg: cfg, rpcInternal: rpcInternal}
 	SetConnManager(cM)
+	utils.RuntimeMetrics.RegisterGaugeFunc(utils.MetricConnPools,
+		"Connections configured in the established pools, per connection ID and strategy.",
+		cM.poolsMetrics)
 	return
 }
 
@@ -37,6 +41,41 @@ func NewConnManager(cfg *config.CGRConfig, rpcInternal map[string]chan rpcclient
 type ConnManager struct {
 	cfg         *config.CGRConfig
 	rpcInternal map[string]chan rpcclient.ClientConnector
+
+	anzLk sync.RWMutex
+	anz   utils.RPCAnalyzer // captures the traffic when AnalyzerS is active
+}
+
+// SetAnalyzer sets the analyzer that will capture the RPC traffic, nil to disable it
+func (cM *ConnManager) SetAnalyzer(anz utils.RPCAnalyzer) {
+	cM.anzLk.Lock()
+	cM.anz = anz
+	cM.anzLk.Unlock()
+}
+
+func (cM *ConnManager) getAnalyzer() (anz utils.RPCAnalyzer) {
+	cM.anzLk.RLock()
+	anz = cM.anz
+	cM.anzLk.RUnlock()
+	return
+}
+
+// connEncoding returns the encoding used to reach the connection
+func (cM *ConnManager) connEncoding(connID string) string {
+	if _, has := cM.rpcInternal[connID]; has {
+		return utils.MetaInternal
+	}
+	connCfg, has := cM.cfg.RPCConns()[connID]
+	if !has || len(connCfg.Conns) == 0 {
+		return utils.EmptyString
+	}
+	if connCfg.Conns[0].Address == utils.MetaInternal {
+		return utils.MetaInternal
+	}
+	if connCfg.Conns[0].Transport == utils.EmptyString {
+		return utils.MetaGOB
+	}
+	return connCfg.Conns[0].Transport
 }
 
 // getConn is used to retrieve a connection from cache
@@ -129,12 +168,23 @@ func (cM *ConnManager) Call(connIDs []string, biRPCClient rpcclient.ClientConnec
 	if len(connIDs) == 0 {
 		return utils.NewErrMandatoryIeMissing("connIDs")
 	}
+	span := utils.StartSpan(method, arg) // nil if tracing is disabled
+	arg = span.Inject(arg)
+	defer func() { span.End(err) }()
 	var conn rpcclient.ClientConnector
 	for _, connID := range connIDs {
+		utils.RuntimeMetrics.IncCounter(utils.MetricConnCalls, connID)
+		span.SetAttribute(utils.SpanAttrConnID, connID)
 		if conn, err = cM.getConn(connID, biRPCClient); err != nil {
+			utils.RuntimeMetrics.IncCounter(utils.MetricConnFailures, connID, utils.MetricReasonConnect)
 			continue
 		}
+		if anz := cM.getAnalyzer(); anz != nil {
+			conn = utils.NewAnalyzerConnector(conn, anz, cM.connEncoding(connID),
+				cM.cfg.GeneralCfg().NodeID, connID)
+		}
 		if err = conn.Call(method, arg, reply); utils.IsNetworkError(err) {
+			utils.RuntimeMetrics.IncCounter(utils.MetricConnFailures, connID, utils.MetricReasonNetwork)
 			continue
 		} else {
 			return
@@ -142,3 +192,28 @@ func (cM *ConnManager) Call(connIDs []string, biRPCClient rpcclient.ClientConnec
 	}
 	return
 }
+
+// poolsMetrics returns the number of connections in each pool established by the ConnManager
+func (cM *ConnManager) poolsMetrics() (smpls []*utils.MetricSample) {
+	for _, connID := range Cache.GetItemIDs(utils.CacheRPCConnections, utils.EmptyString) {
+		if x, has := Cache.Get(utils.CacheRPCConnections, connID); !has || x == nil {
+			continue
+		}
+		strategy := utils.MetaInternal
+		var nrConns int
+		if _, has := cM.rpcInternal[connID]; has {
+			nrConns = 1
+		} else if connCfg, has := cM.cfg.RPCConns()[connID]; has {
+			strategy = connCfg.Strategy
+			nrConns = len(connCfg.Conns)
+		}
+		smpls = append(smpls, &utils.MetricSample{
+			Labels: map[string]string{
+				utils.MetricLabelConnID:   connID,
+				utils.MetricLabelStrategy: strategy,
+			},
+			Value: float64(nrConns),
+		})
+	}
+	return
+}
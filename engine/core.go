@@ -64,3 +64,30 @@ func (cS *CoreService) Status(arg *utils.TenantWithArgDispatcher, reply *map[str
 	*reply = response
 	return
 }
+
+// SetLogLevel changes the log level of the engine or of one subsystem at runtime
+func (cS *CoreService) SetLogLevel(args *utils.ArgsSetLogLevelWithArgDispatcher, reply *string) (err error) {
+	if args.Level > utils.LOGLEVEL_DEBUG {
+		return fmt.Errorf("invalid log level: <%d>", args.Level)
+	}
+	if args.Subsystem == utils.EmptyString || args.Subsystem == utils.MetaAll {
+		if args.Level < utils.LOGLEVEL_EMERGENCY {
+			return fmt.Errorf("invalid log level: <%d>", args.Level)
+		}
+		utils.Logger.SetLogLevel(args.Level)
+	} else {
+		utils.Logger.SetSubsystemLogLevel(args.Subsystem, args.Level)
+	}
+	*reply = utils.OK
+	return
+}
+
+// Metrics returns the runtime metrics of the engine subsystems
+func (cS *CoreService) Metrics(args *utils.ArgsGetMetricsWithArgDispatcher, reply *[]*utils.MetricFamily) (err error) {
+	mfs := utils.RuntimeMetrics.GetMetrics(args.MetricNames)
+	if len(mfs) == 0 {
+		return utils.ErrNotFound
+	}
+	*reply = mfs
+	return
+}
@@ -18,6 +18,7 @@ package engine
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/utils"
@@ -207,7 +208,7 @@ func (dm *DataManager) CacheDataFromDB(prfx string, ids []string, mustBeCached b
 		}
 		for _, keyID := range keyIDs {
 			if mustBeCached { // Only consider loading ids which are already in cache
-				if _, hasIt := Cache.Get(utils.CachePrefixToInstance[prfx], keyID[len(prfx):]); !hasIt {
+				if _, hasIt := cacheGet(utils.CachePrefixToInstance[prfx], keyID[len(prfx):]); !hasIt {
 					continue
 				}
 			}
@@ -223,7 +224,7 @@ func (dm *DataManager) CacheDataFromDB(prfx string, ids []string, mustBeCached b
 	}
 	for _, dataID := range ids {
 		if mustBeCached {
-			if _, hasIt := Cache.Get(utils.CachePrefixToInstance[prfx], dataID); !hasIt { // only cache if previously there
+			if _, hasIt := cacheGet(utils.CachePrefixToInstance[prfx], dataID); !hasIt { // only cache if previously there
 				continue
 			}
 		}
@@ -499,7 +500,7 @@ func (dm *DataManager) GetStatQueue(tenant, id string,
 	cacheRead, cacheWrite bool, transactionID string) (sq *StatQueue, err error) {
 	tntID := utils.ConcatenatedKey(tenant, id)
 	if cacheRead {
-		if x, ok := Cache.Get(utils.CacheStatQueues, tntID); ok {
+		if x, ok := cacheGet(utils.CacheStatQueues, tntID); ok {
 			if x == nil {
 				return nil, utils.ErrNotFound
 			}
@@ -597,7 +598,7 @@ func GetFilter(dm *DataManager, tenant, id string, cacheRead, cacheWrite bool,
 	transactionID string) (fltr *Filter, err error) {
 	tntID := utils.ConcatenatedKey(tenant, id)
 	if cacheRead {
-		if x, ok := Cache.Get(utils.CacheFilters, tntID); ok {
+		if x, ok := cacheGet(utils.CacheFilters, tntID); ok {
 			if x == nil {
 				return nil, utils.ErrNotFound
 			}
@@ -684,7 +685,7 @@ func (dm *DataManager) GetThreshold(tenant, id string,
 	cacheRead, cacheWrite bool, transactionID string) (th *Threshold, err error) {
 	tntID := utils.ConcatenatedKey(tenant, id)
 	if cacheRead {
-		if x, ok := Cache.Get(utils.CacheThresholds, tntID); ok {
+		if x, ok := cacheGet(utils.CacheThresholds, tntID); ok {
 			if x == nil {
 				return nil, utils.ErrNotFound
 			}
@@ -762,7 +763,7 @@ func (dm *DataManager) GetThresholdProfile(tenant, id string, cacheRead, cacheWr
 	transactionID string) (th *ThresholdProfile, err error) {
 	tntID := utils.ConcatenatedKey(tenant, id)
 	if cacheRead {
-		if x, ok := Cache.Get(utils.CacheThresholdProfiles, tntID); ok {
+		if x, ok := cacheGet(utils.CacheThresholdProfiles, tntID); ok {
 			if x == nil {
 				return nil, utils.ErrNotFound
 			}
@@ -880,7 +881,7 @@ func (dm *DataManager) GetStatQueueProfile(tenant, id string, cacheRead, cacheWr
 	transactionID string) (sqp *StatQueueProfile, err error) {
 	tntID := utils.ConcatenatedKey(tenant, id)
 	if cacheRead {
-		if x, ok := Cache.Get(utils.CacheStatQueueProfiles, tntID); ok {
+		if x, ok := cacheGet(utils.CacheStatQueueProfiles, tntID); ok {
 			if x == nil {
 				return nil, utils.ErrNotFound
 			}
@@ -997,7 +998,7 @@ func (dm *DataManager) RemoveStatQueueProfile(tenant, id,
 func (dm *DataManager) GetTiming(id string, skipCache bool,
 	transactionID string) (t *utils.TPTiming, err error) {
 	if !skipCache {
-		if x, ok := Cache.Get(utils.CacheTimings, id); ok {
+		if x, ok := cacheGet(utils.CacheTimings, id); ok {
 			if x == nil {
 				return nil, utils.ErrNotFound
 			}
@@ -1076,7 +1077,7 @@ func (dm *DataManager) GetResource(tenant, id string, cacheRead, cacheWrite bool
 	transactionID string) (rs *Resource, err error) {
 	tntID := utils.ConcatenatedKey(tenant, id)
 	if cacheRead {
-		if x, ok := Cache.Get(utils.CacheResources, tntID); ok {
+		if x, ok := cacheGet(utils.CacheResources, tntID); ok {
 			if x == nil {
 				return nil, utils.ErrNotFound
 			}
@@ -1157,7 +1158,7 @@ func (dm *DataManager) GetResourceProfile(tenant, id string, cacheRead, cacheWri
 	transactionID string) (rp *ResourceProfile, err error) {
 	tntID := utils.ConcatenatedKey(tenant, id)
 	if cacheRead {
-		if x, ok := Cache.Get(utils.CacheResourceProfiles, tntID); ok {
+		if x, ok := cacheGet(utils.CacheResourceProfiles, tntID); ok {
 			if x == nil {
 				return nil, utils.ErrNotFound
 			}
@@ -1272,7 +1273,7 @@ func (dm *DataManager) RemoveResourceProfile(tenant, id, transactionID string, w
 func (dm *DataManager) GetActionTriggers(id string, skipCache bool,
 	transactionID string) (attrs ActionTriggers, err error) {
 	if !skipCache {
-		if x, ok := Cache.Get(utils.CacheActionTriggers, id); ok {
+		if x, ok := cacheGet(utils.CacheActionTriggers, id); ok {
 			if x == nil {
 				return nil, utils.ErrNotFound
 			}
@@ -1365,7 +1366,7 @@ func (dm *DataManager) SetActionTriggers(key string, attr ActionTriggers,
 func (dm *DataManager) GetSharedGroup(key string, skipCache bool,
 	transactionID string) (sg *SharedGroup, err error) {
 	if !skipCache {
-		if x, ok := Cache.Get(utils.CacheSharedGroups, key); ok {
+		if x, ok := cacheGet(utils.CacheSharedGroups, key); ok {
 			if x != nil {
 				return x.(*SharedGroup), nil
 			}
@@ -1721,7 +1722,7 @@ func (dm *DataManager) RemAccountActionPlans(acntID string, apIDs []string) (err
 func (dm *DataManager) GetRatingPlan(key string, skipCache bool,
 	transactionID string) (rp *RatingPlan, err error) {
 	if !skipCache {
-		if x, ok := Cache.Get(utils.CacheRatingPlans, key); ok {
+		if x, ok := cacheGet(utils.CacheRatingPlans, key); ok {
 			if x != nil {
 				return x.(*RatingPlan), nil
 			}
@@ -1809,7 +1810,7 @@ func (dm *DataManager) GetRatingProfile(key string, skipCache bool,
 	transactionID string) (rpf *RatingProfile, err error) {
 	if !skipCache {
 		for _, cacheRP := range []string{utils.CacheRatingProfilesTmp, utils.CacheRatingProfiles} {
-			if x, ok := Cache.Get(cacheRP, key); ok {
+			if x, ok := cacheGet(cacheRP, key); ok {
 				if x != nil {
 					return x.(*RatingProfile), nil
 				}
@@ -1978,7 +1979,7 @@ func (dm *DataManager) MatchFilterIndexFromKey(cacheID, key string) (err error)
 func (dm *DataManager) MatchFilterIndex(cacheID, itemIDPrefix,
 	filterType, fieldName, fieldVal string) (itemIDs utils.StringMap, err error) {
 	fieldValKey := utils.ConcatenatedKey(itemIDPrefix, filterType, fieldName, fieldVal)
-	if x, ok := Cache.Get(cacheID, fieldValKey); ok { // Attempt to find in cache first
+	if x, ok := cacheGet(cacheID, fieldValKey); ok { // Attempt to find in cache first
 		if x == nil {
 			return nil, utils.ErrNotFound
 		}
@@ -2023,7 +2024,7 @@ func (dm *DataManager) GetSupplierProfile(tenant, id string, cacheRead, cacheWri
 	transactionID string) (supp *SupplierProfile, err error) {
 	tntID := utils.ConcatenatedKey(tenant, id)
 	if cacheRead {
-		if x, ok := Cache.Get(utils.CacheSupplierProfiles, tntID); ok {
+		if x, ok := cacheGet(utils.CacheSupplierProfiles, tntID); ok {
 			if x == nil {
 				return nil, utils.ErrNotFound
 			}
@@ -2145,7 +2146,7 @@ func (dm *DataManager) GetAttributeProfile(tenant, id string, cacheRead, cacheWr
 	transactionID string) (attrPrfl *AttributeProfile, err error) {
 	tntID := utils.ConcatenatedKey(tenant, id)
 	if cacheRead {
-		if x, ok := Cache.Get(utils.CacheAttributeProfiles, tntID); ok {
+		if x, ok := cacheGet(utils.CacheAttributeProfiles, tntID); ok {
 			if x == nil {
 				return nil, utils.ErrNotFound
 			}
@@ -2290,7 +2291,7 @@ func (dm *DataManager) GetChargerProfile(tenant, id string, cacheRead, cacheWrit
 	transactionID string) (cpp *ChargerProfile, err error) {
 	tntID := utils.ConcatenatedKey(tenant, id)
 	if cacheRead {
-		if x, ok := Cache.Get(utils.CacheChargerProfiles, tntID); ok {
+		if x, ok := cacheGet(utils.CacheChargerProfiles, tntID); ok {
 			if x == nil {
 				return nil, utils.ErrNotFound
 			}
@@ -2409,7 +2410,7 @@ func (dm *DataManager) GetDispatcherProfile(tenant, id string, cacheRead, cacheW
 	transactionID string) (dpp *DispatcherProfile, err error) {
 	tntID := utils.ConcatenatedKey(tenant, id)
 	if cacheRead {
-		if x, ok := Cache.Get(utils.CacheDispatcherProfiles, tntID); ok {
+		if x, ok := cacheGet(utils.CacheDispatcherProfiles, tntID); ok {
 			if x == nil {
 				return nil, utils.ErrNotFound
 			}
@@ -2537,7 +2538,7 @@ func (dm *DataManager) GetDispatcherHost(tenant, id string, cacheRead, cacheWrit
 	transactionID string) (dH *DispatcherHost, err error) {
 	tntID := utils.ConcatenatedKey(tenant, id)
 	if cacheRead {
-		if x, ok := Cache.Get(utils.CacheDispatcherHosts, tntID); ok {
+		if x, ok := cacheGet(utils.CacheDispatcherHosts, tntID); ok {
 			if x == nil {
 				return nil, utils.ErrNotFound
 			}
@@ -2704,3 +2705,43 @@ func (dm *DataManager) Reconnect(marshaller string, newcfg *config.DataDbCfg) (e
 	dm.dataDB = d
 	return
 }
+
+// GetSessionsBackup returns the active sessions backed up by the node with nodeID
+func (dm *DataManager) GetSessionsBackup(nodeID string) (ss []*StoredSession, err error) {
+	if dm == nil {
+		return nil, utils.ErrNoDatabaseConn
+	}
+	return dm.dataDB.GetSessionsBackupDrv(nodeID)
+}
+
+// SetSessionsBackup overwrites the backup of active sessions for the node with nodeID
+func (dm *DataManager) SetSessionsBackup(nodeID string, ss []*StoredSession) (err error) {
+	if dm == nil {
+		return utils.ErrNoDatabaseConn
+	}
+	return dm.dataDB.SetSessionsBackupDrv(nodeID, ss)
+}
+
+// SetDedupKey stores the deduplication key for ttl, returning false if the key is already stored
+func (dm *DataManager) SetDedupKey(key string, ttl time.Duration) (stored bool, err error) {
+	if dm == nil {
+		return false, utils.ErrNoDatabaseConn
+	}
+	return dm.dataDB.SetDedupKeyDrv(key, ttl)
+}
+
+// RemoveDedupKey removes the deduplication key
+func (dm *DataManager) RemoveDedupKey(key string) (err error) {
+	if dm == nil {
+		return utils.ErrNoDatabaseConn
+	}
+	return dm.dataDB.RemoveDedupKeyDrv(key)
+}
+
+// RemoveSessionsBackup removes the backup of active sessions for the node with nodeID
+func (dm *DataManager) RemoveSessionsBackup(nodeID string) (err error) {
+	if dm == nil {
+		return utils.ErrNoDatabaseConn
+	}
+	return dm.dataDB.RemoveSessionsBackupDrv(nodeID)
+}
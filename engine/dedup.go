@@ -0,0 +1,143 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"sync"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/cgrates/ltcache"
+)
+
+// NewDedupService returns the deduplication stage for the subsystem
+// the subsystem prefixes the keys so ERs and CDRs sharing the DataDB do not suppress each other's events
+func NewDedupService(cfg *config.CGRConfig, dm *DataManager, subsystem string) *DedupService {
+	return &DedupService{
+		cfg:        cfg,
+		dm:         dm,
+		subsystem:  subsystem,
+		suppressed: make(map[string]int64),
+	}
+}
+
+// DedupService suppresses the events already seen within the configured time window
+type DedupService struct {
+	cfg       *config.CGRConfig
+	dm        *DataManager
+	subsystem string
+
+	cchMux   sync.Mutex
+	cache    *ltcache.Cache // used by the *internal store
+	cacheTTL time.Duration  // the cache is rebuilt if the ttl changes on reload
+
+	cntMux     sync.RWMutex
+	suppressed map[string]int64 // suppressed duplicates indexed on source
+}
+
+// eventKey builds the deduplication key out of the event based on the key template
+func (dS *DedupService) eventKey(ev *utils.CGREvent) (key string, err error) {
+	evNm := config.NewNavigableMap(map[string]interface{}{utils.MetaReq: ev.Event})
+	if key, err = dS.cfg.DedupCfg().Key.ParseDataProvider(evNm, utils.NestingSep); err != nil {
+		return
+	}
+	return utils.ConcatenatedKey(dS.subsystem, ev.Tenant, key), nil
+}
+
+// storeKey stores the key, returning false if it was stored already within ttl
+func (dS *DedupService) storeKey(key string) (stored bool, err error) {
+	ddCfg := dS.cfg.DedupCfg()
+	if ddCfg.Store == utils.MetaDataDB {
+		return dS.dm.SetDedupKey(key, ddCfg.TTL)
+	}
+	dS.cchMux.Lock()
+	defer dS.cchMux.Unlock()
+	if dS.cache == nil || dS.cacheTTL != ddCfg.TTL {
+		dS.cache = ltcache.NewCache(ltcache.UnlimitedCaching, ddCfg.TTL, true, nil)
+		dS.cacheTTL = ddCfg.TTL
+	}
+	if dS.cache.HasItem(key) {
+		return
+	}
+	dS.cache.Set(key, nil, nil)
+	return true, nil
+}
+
+// IsDuplicate returns true if an event with the same key was processed within ttl, counting it as suppressed for source
+// the events out of which the key cannot be built are not considered duplicates
+func (dS *DedupService) IsDuplicate(ev *utils.CGREvent, source string) (dup bool, err error) {
+	var key string
+	if key, err = dS.eventKey(ev); err != nil {
+		if err == utils.ErrNotFound {
+			err = nil
+		}
+		return
+	}
+	var stored bool
+	if stored, err = dS.storeKey(key); err != nil || stored {
+		return
+	}
+	dS.cntMux.Lock()
+	dS.suppressed[source]++
+	dS.cntMux.Unlock()
+	return true, nil
+}
+
+// Release removes the key of the event so it can be processed again, used when the processing failed
+func (dS *DedupService) Release(ev *utils.CGREvent) (err error) {
+	var key string
+	if key, err = dS.eventKey(ev); err != nil {
+		if err == utils.ErrNotFound {
+			err = nil
+		}
+		return
+	}
+	if dS.cfg.DedupCfg().Store == utils.MetaDataDB {
+		return dS.dm.RemoveDedupKey(key)
+	}
+	dS.cchMux.Lock()
+	if dS.cache != nil {
+		dS.cache.Remove(key)
+	}
+	dS.cchMux.Unlock()
+	return
+}
+
+// V1GetDedupStats returns the number of suppressed duplicates for each source
+func (dS *DedupService) V1GetDedupStats(args *ArgsGetDedupStats, reply *map[string]int64) (err error) {
+	dS.cntMux.RLock()
+	defer dS.cntMux.RUnlock()
+	stats := make(map[string]int64)
+	if len(args.Sources) == 0 {
+		for src, cnt := range dS.suppressed {
+			stats[src] = cnt
+		}
+	}
+	for _, src := range args.Sources {
+		stats[src] = dS.suppressed[src]
+	}
+	*reply = stats
+	return
+}
+
+// ArgsGetDedupStats selects the sources of the suppressed duplicates, all if empty
+type ArgsGetDedupStats struct {
+	Sources []string
+}
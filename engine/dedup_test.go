@@ -0,0 +1,228 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func testDedupService(t *testing.T, store string) {
+	cfg, _ := config.NewDefaultCGRConfig()
+	cfg.DedupCfg().Store = store
+	data := NewInternalDB(nil, nil, true, cfg.DataDbCfg().Items)
+	dS := NewDedupService(cfg, NewDataManager(data, cfg.CacheCfg(), nil), utils.CDRs)
+	ev := &utils.CGREvent{
+		Tenant: "cgrates.org",
+		ID:     "ev1",
+		Event: map[string]interface{}{
+			utils.OriginID:   "abc",
+			utils.OriginHost: "192.168.1.1",
+		},
+	}
+	if dup, err := dS.IsDuplicate(ev, "cgrates.org"); err != nil {
+		t.Fatal(err)
+	} else if dup {
+		t.Error("Expected first event not duplicated")
+	}
+	if dup, err := dS.IsDuplicate(ev, "cgrates.org"); err != nil {
+		t.Fatal(err)
+	} else if !dup {
+		t.Error("Expected event duplicated")
+	}
+	ev2 := &utils.CGREvent{
+		Tenant: "cgrates.org",
+		ID:     "ev2",
+		Event: map[string]interface{}{
+			utils.OriginID:   "abc",
+			utils.OriginHost: "192.168.1.2",
+		},
+	}
+	if dup, err := dS.IsDuplicate(ev2, "cgrates.org"); err != nil {
+		t.Fatal(err)
+	} else if dup {
+		t.Error("Expected event with different key not duplicated")
+	}
+	// key cannot be built
+	evNoKey := &utils.CGREvent{Tenant: "cgrates.org", ID: "ev3",
+		Event: map[string]interface{}{utils.Account: "1001"}}
+	for i := 0; i < 2; i++ {
+		if dup, err := dS.IsDuplicate(evNoKey, "cgrates.org"); err != nil {
+			t.Fatal(err)
+		} else if dup {
+			t.Error("Expected event without key not duplicated")
+		}
+	}
+	// released events can be processed again
+	if err := dS.Release(ev); err != nil {
+		t.Fatal(err)
+	}
+	if dup, err := dS.IsDuplicate(ev, "cgrates.org"); err != nil {
+		t.Fatal(err)
+	} else if dup {
+		t.Error("Expected released event not duplicated")
+	}
+	// the keys expire after ttl
+	cfg.DedupCfg().TTL = 10 * time.Millisecond
+	ev4 := &utils.CGREvent{Tenant: "cgrates.org", ID: "ev4",
+		Event: map[string]interface{}{utils.OriginID: "def", utils.OriginHost: "192.168.1.1"}}
+	for _, expDup := range []bool{false, true} {
+		if dup, err := dS.IsDuplicate(ev4, "cgrates.org"); err != nil {
+			t.Fatal(err)
+		} else if dup != expDup {
+			t.Errorf("Expected duplicated: %v, received: %v", expDup, dup)
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+	if dup, err := dS.IsDuplicate(ev4, "cgrates.org"); err != nil {
+		t.Fatal(err)
+	} else if dup {
+		t.Error("Expected expired key not duplicated")
+	}
+	var stats map[string]int64
+	exp := map[string]int64{"cgrates.org": 2}
+	if err := dS.V1GetDedupStats(new(ArgsGetDedupStats), &stats); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(exp, stats) {
+		t.Errorf("Expected: %v, received: %v", exp, stats)
+	}
+	exp["itsyscom.com"] = 0
+	if err := dS.V1GetDedupStats(&ArgsGetDedupStats{
+		Sources: []string{"cgrates.org", "itsyscom.com"}}, &stats); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(exp, stats) {
+		t.Errorf("Expected: %v, received: %v", exp, stats)
+	}
+}
+
+func TestDedupServiceInternal(t *testing.T) {
+	testDedupService(t, utils.MetaInternal)
+}
+
+func TestDedupServiceDataDB(t *testing.T) {
+	testDedupService(t, utils.MetaDataDB)
+}
+
+func TestDedupServiceSubsystems(t *testing.T) {
+	cfg, _ := config.NewDefaultCGRConfig()
+	cfg.DedupCfg().Store = utils.MetaDataDB
+	dm := NewDataManager(NewInternalDB(nil, nil, true, cfg.DataDbCfg().Items), cfg.CacheCfg(), nil)
+	ev := &utils.CGREvent{Tenant: "cgrates.org", ID: "ev1",
+		Event: map[string]interface{}{utils.OriginID: "abc", utils.OriginHost: "192.168.1.1"}}
+	for _, dS := range []*DedupService{
+		NewDedupService(cfg, dm, utils.ERs),
+		NewDedupService(cfg, dm, utils.CDRs),
+	} {
+		if dup, err := dS.IsDuplicate(ev, "rdr1"); err != nil {
+			t.Fatal(err)
+		} else if dup {
+			t.Error("Expected the subsystems not to share the keys")
+		}
+	}
+	if _, err := NewDedupService(cfg, nil, utils.CDRs).IsDuplicate(ev, "rdr1"); err != utils.ErrNoDatabaseConn {
+		t.Errorf("Expected %v, received: %v", utils.ErrNoDatabaseConn, err)
+	}
+}
+
+func TestCDRServerDedupEvent(t *testing.T) {
+	cfg, _ := config.NewDefaultCGRConfig()
+	cdrS := &CDRServer{cgrCfg: cfg, dedupS: NewDedupService(cfg, nil, utils.CDRs)}
+	ev := &utils.CGREvent{Tenant: "cgrates.org", ID: "ev1",
+		Event: map[string]interface{}{utils.OriginID: "abc", utils.OriginHost: "192.168.1.1"}}
+	if err := cdrS.dedupEvent(ev); err != nil {
+		t.Error(err)
+	}
+	if err := cdrS.dedupEvent(ev); err != utils.ErrExists {
+		t.Errorf("Expected %v, received: %v", utils.ErrExists, err)
+	}
+	cdrS.releaseEvent(ev)
+	if err := cdrS.dedupEvent(ev); err != nil {
+		t.Error(err)
+	}
+	var stats map[string]int64
+	if err := cdrS.V1GetDedupStats(new(ArgsGetDedupStats), &stats); err != nil {
+		t.Error(err)
+	} else if exp := map[string]int64{"cgrates.org": 1}; !reflect.DeepEqual(exp, stats) {
+		t.Errorf("Expected: %v, received: %v", exp, stats)
+	}
+	cfg.DedupCfg().Store = utils.MetaDataDB // no DataDB connection
+	if err := cdrS.dedupEvent(ev); err == nil ||
+		err.Error() != utils.NewErrServerError(utils.ErrNoDatabaseConn).Error() {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestCDRServerProcessEventRelease(t *testing.T) {
+	cfg, _ := config.NewDefaultCGRConfig()
+	cdrS := &CDRServer{cgrCfg: cfg, dedupS: NewDedupService(cfg, nil, utils.CDRs),
+		cdrDb: NewInternalDB(nil, nil, false, cfg.StorDbCfg().Items)}
+	ev := &utils.CGREvent{Tenant: "cgrates.org", ID: "ev1",
+		Event: map[string]interface{}{utils.OriginID: "dedupRelease", utils.OriginHost: "192.168.1.1",
+			utils.Account: "1001", utils.Destination: "1002"}}
+	if err := cdrS.dedupEvent(ev); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cdrS.processEvent(&utils.CGREventWithArgDispatcher{CGREvent: ev},
+		false, false, false, false, true, false, false, false, false, true); err != nil {
+		t.Fatal(err)
+	}
+	// failing after the CDR was stored keeps the key
+	Cache.Clear([]string{utils.CacheCDRIDs})
+	if _, err := cdrS.processEvent(&utils.CGREventWithArgDispatcher{CGREvent: ev},
+		false, false, false, false, true, false, false, false, false, true); err != utils.ErrExists {
+		t.Errorf("Expected %v, received: %v", utils.ErrExists, err)
+	}
+	if err := cdrS.dedupEvent(ev); err != utils.ErrExists {
+		t.Errorf("Expected %v, received: %v", utils.ErrExists, err)
+	}
+	// failing before charging releases the key
+	if _, err := cdrS.processEvent(&utils.CGREventWithArgDispatcher{CGREvent: ev},
+		false, false, false, false, true, false, false, false, false, true); err != utils.ErrExists {
+		t.Errorf("Expected %v, received: %v", utils.ErrExists, err)
+	}
+	if err := cdrS.dedupEvent(ev); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestInternalDBDedupKeyTTL(t *testing.T) {
+	ddCfg := config.CgrConfig().DedupCfg()
+	ttl := ddCfg.TTL
+	ddCfg.TTL = 10 * time.Millisecond
+	defer func() { ddCfg.TTL = ttl }()
+	iDB := NewInternalDB(nil, nil, true, config.CgrConfig().DataDbCfg().Items)
+	if stored, err := iDB.SetDedupKeyDrv("key1", ddCfg.TTL); err != nil {
+		t.Fatal(err)
+	} else if !stored {
+		t.Error("Expected key stored")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if iDB.db.HasItem(utils.CacheDedupKeys, "key1") {
+		t.Error("Expected expired key removed")
+	}
+	if stored, err := iDB.SetDedupKeyDrv("key1", ddCfg.TTL); err != nil {
+		t.Fatal(err)
+	} else if !stored {
+		t.Error("Expected expired key stored again")
+	}
+}
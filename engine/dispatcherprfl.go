@@ -150,9 +150,13 @@ func (dH *DispatcherHost) TenantID() string {
 }
 
 // GetRPCConnection builds or returns the cached connection
-func (dH *DispatcherHost) Call(serviceMethod string, args interface{}, reply interface{}) error {
+func (dH *DispatcherHost) Call(serviceMethod string, args interface{}, reply interface{}) (err error) {
 	if dH.rpcConn == nil {
 		return utils.ErrNotConnected
 	}
-	return dH.rpcConn.Call(serviceMethod, args, reply)
+	span := utils.StartSpan(serviceMethod, args) // nil if tracing is disabled
+	span.SetAttribute(utils.SpanAttrHostID, dH.TenantID())
+	err = dH.rpcConn.Call(serviceMethod, span.Inject(args), reply)
+	span.End(err)
+	return
 }
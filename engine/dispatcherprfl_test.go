@@ -20,6 +20,7 @@ package engine
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/cgrates/cgrates/utils"
 )
@@ -255,3 +256,62 @@ func TestDispatcherHostCall(t *testing.T) {
 		t.Errorf("Expected: %s , received: %s", utils.ToJSON(etRPC), utils.ToJSON(tRPC))
 	}
 }
+
+type testSpanExporter struct {
+	spans []*utils.Span
+}
+
+func (exp *testSpanExporter) ExportSpans(spans []*utils.Span) error {
+	exp.spans = append(exp.spans, spans...)
+	return nil
+}
+
+func (exp *testSpanExporter) Close() error { return nil }
+
+type testTraceRPCHost struct {
+	argD *utils.ArgDispatcher
+}
+
+func (v *testTraceRPCHost) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	v.argD = args.(*utils.CGREventWithArgDispatcher).ArgDispatcher
+	return nil
+}
+
+func TestDispatcherHostCallTracing(t *testing.T) {
+	exp := new(testSpanExporter)
+	tr := utils.NewTracer("node1", exp, 10, time.Hour)
+	utils.SetTracer(tr)
+	tRPC := new(testTraceRPCHost)
+	dspHost := &DispatcherHost{Tenant: "cgrates.org", ID: "HOST1", rpcConn: tRPC}
+	argD := &utils.ArgDispatcher{
+		APIKey:  utils.StringPointer("sup12345"),
+		TraceID: utils.StringPointer("4bf92f3577b34da6a3ce929d0e0e4736"),
+		SpanID:  utils.StringPointer("00f067aa0ba902b7"),
+	}
+	args := &utils.CGREventWithArgDispatcher{CGREvent: &utils.CGREvent{}, ArgDispatcher: argD}
+	var reply string
+	if err := dspHost.Call(utils.AttributeSv1Ping, args, &reply); err != nil {
+		t.Error(err)
+	}
+	utils.SetTracer(nil)
+	tr.Shutdown()
+	if len(exp.spans) != 1 {
+		t.Fatalf("Expected one span, received: %s", utils.ToJSON(exp.spans))
+	}
+	sp := exp.spans[0]
+	if sp.TraceID != *argD.TraceID || sp.ParentSpanID != *argD.SpanID ||
+		sp.Name != utils.AttributeSv1Ping || sp.Attributes[utils.SpanAttrHostID] != "cgrates.org:HOST1" {
+		t.Errorf("Unexpected span: %s", utils.ToJSON(sp))
+	}
+	eArgD := &utils.ArgDispatcher{
+		APIKey:  argD.APIKey,
+		TraceID: argD.TraceID,
+		SpanID:  utils.StringPointer(sp.SpanID),
+	}
+	if !reflect.DeepEqual(eArgD, tRPC.argD) {
+		t.Errorf("Expected: %s , received: %s", utils.ToJSON(eArgD), utils.ToJSON(tRPC.argD))
+	}
+	if args.ArgDispatcher != argD {
+		t.Errorf("Expected the original ArgDispatcher restored, received: %s", utils.ToJSON(args.ArgDispatcher))
+	}
+}
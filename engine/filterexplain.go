@@ -0,0 +1,301 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// ArgsExplainFilters are the arguments passed to ExplainFilters
+// either the FilterIDs or the ProfileType together with the ProfileID need to be populated
+type ArgsExplainFilters struct {
+	*utils.CGREvent
+	FilterIDs   []string
+	ProfileType string  // one of *attributes, *chargers, *suppliers, *thresholds, *stats, *resources or *dispatchers
+	ProfileID   string  // the profile having its filters explained
+	Context     *string // attributes context or dispatchers subsystem used for the index lookup
+}
+
+// FiltersExplanation is the result of evaluating the filters against an event
+type FiltersExplanation struct {
+	ProfileType   string
+	ProfileID     string
+	ProfileActive bool // profile ActivationInterval is active at event Time, only for profiles
+	IndexHit      bool // profile is selected out of indexes for the event, only for profiles
+	Pass          bool
+	Filters       []*FilterExplanation
+}
+
+// FilterExplanation details the evaluation of one filter
+type FilterExplanation struct {
+	ID     string
+	Active bool // ActivationInterval is active at the time of the call
+	Pass   bool
+	Error  string
+	Rules  []*FilterRuleExplanation
+}
+
+// FilterRuleExplanation details the evaluation of one filter rule
+type FilterRuleExplanation struct {
+	Type          string
+	Element       string
+	Values        []string
+	ElementValue  *string  // Element resolved out of the event, nil if not found
+	CompareValues []string // Values resolved out of the event, the unresolvable ones are omitted
+	Pass          bool
+	Error         string
+	Operands      []*FilterExplanation // operands of the *expr rules
+}
+
+// ExplainFilters evaluates every filter and rule against the event, without stopping at the first failure
+// the event is evaluated as *req, same as within the subsystems
+func (fS *FilterS) ExplainFilters(args *ArgsExplainFilters) (fltrsExp *FiltersExplanation, err error) {
+	if args.CGREvent == nil {
+		return nil, utils.NewErrMandatoryIeMissing(utils.Event)
+	}
+	fltrsExp = &FiltersExplanation{
+		ProfileType: args.ProfileType,
+		ProfileID:   args.ProfileID,
+	}
+	fltrIDs := args.FilterIDs
+	if args.ProfileType != utils.EmptyString {
+		if fltrIDs, err = fS.explainProfile(args, fltrsExp); err != nil {
+			return nil, err
+		}
+	}
+	evNm := config.NewNavigableMap(map[string]interface{}{utils.MetaReq: args.Event})
+	fltrsExp.Pass = len(fltrIDs) == 0
+	var failed bool
+	for _, fltrID := range fltrIDs {
+		dDP := newDynamicDP(fS.cfg, fS.connMgr, args.Tenant, evNm)
+		fltrExp := fS.explainFilter(args.Tenant, fltrID, dDP, 0)
+		fltrsExp.Filters = append(fltrsExp.Filters, fltrExp)
+		if !fltrExp.Active && fltrExp.Error == utils.EmptyString {
+			continue
+		}
+		if !fltrExp.Pass {
+			failed = true
+			continue
+		}
+		fltrsExp.Pass = true
+	}
+	if failed {
+		fltrsExp.Pass = false
+	}
+	return
+}
+
+// explainProfile returns the FilterIDs of the profile, populating the profile activation and index hit
+func (fS *FilterS) explainProfile(args *ArgsExplainFilters,
+	fltrsExp *FiltersExplanation) (fltrIDs []string, err error) {
+	var aI *utils.ActivationInterval
+	var cacheID string
+	var strFlds, prfxFlds *[]string
+	var idxSelects, nestedFlds bool
+	idxKeys := []string{args.Tenant}
+	ctx := utils.META_ANY
+	if args.Context != nil && *args.Context != utils.EmptyString {
+		ctx = *args.Context
+	}
+	switch args.ProfileType {
+	case utils.MetaAttributes:
+		var prfl *AttributeProfile
+		if prfl, err = fS.dm.GetAttributeProfile(args.Tenant, args.ProfileID,
+			true, true, utils.NonTransactional); err != nil {
+			return
+		}
+		fltrIDs, aI, cacheID = prfl.FilterIDs, prfl.ActivationInterval, utils.CacheAttributeFilterIndexes
+		idxKeys = []string{utils.ConcatenatedKey(args.Tenant, ctx),
+			utils.ConcatenatedKey(args.Tenant, utils.META_ANY)}
+		strFlds, prfxFlds = fS.cfg.AttributeSCfg().StringIndexedFields, fS.cfg.AttributeSCfg().PrefixIndexedFields
+		idxSelects, nestedFlds = fS.cfg.AttributeSCfg().IndexedSelects, fS.cfg.AttributeSCfg().NestedFields
+	case utils.MetaChargers:
+		var prfl *ChargerProfile
+		if prfl, err = fS.dm.GetChargerProfile(args.Tenant, args.ProfileID,
+			true, true, utils.NonTransactional); err != nil {
+			return
+		}
+		fltrIDs, aI, cacheID = prfl.FilterIDs, prfl.ActivationInterval, utils.CacheChargerFilterIndexes
+		strFlds, prfxFlds = fS.cfg.ChargerSCfg().StringIndexedFields, fS.cfg.ChargerSCfg().PrefixIndexedFields
+		idxSelects, nestedFlds = fS.cfg.ChargerSCfg().IndexedSelects, fS.cfg.ChargerSCfg().NestedFields
+	case utils.MetaSuppliers:
+		var prfl *SupplierProfile
+		if prfl, err = fS.dm.GetSupplierProfile(args.Tenant, args.ProfileID,
+			true, true, utils.NonTransactional); err != nil {
+			return
+		}
+		fltrIDs, aI, cacheID = prfl.FilterIDs, prfl.ActivationInterval, utils.CacheSupplierFilterIndexes
+		strFlds, prfxFlds = fS.cfg.SupplierSCfg().StringIndexedFields, fS.cfg.SupplierSCfg().PrefixIndexedFields
+		idxSelects, nestedFlds = fS.cfg.SupplierSCfg().IndexedSelects, fS.cfg.SupplierSCfg().NestedFields
+	case utils.MetaThresholds:
+		var prfl *ThresholdProfile
+		if prfl, err = fS.dm.GetThresholdProfile(args.Tenant, args.ProfileID,
+			true, true, utils.NonTransactional); err != nil {
+			return
+		}
+		fltrIDs, aI, cacheID = prfl.FilterIDs, prfl.ActivationInterval, utils.CacheThresholdFilterIndexes
+		strFlds, prfxFlds = fS.cfg.ThresholdSCfg().StringIndexedFields, fS.cfg.ThresholdSCfg().PrefixIndexedFields
+		idxSelects, nestedFlds = fS.cfg.ThresholdSCfg().IndexedSelects, fS.cfg.ThresholdSCfg().NestedFields
+	case utils.MetaStats:
+		var prfl *StatQueueProfile
+		if prfl, err = fS.dm.GetStatQueueProfile(args.Tenant, args.ProfileID,
+			true, true, utils.NonTransactional); err != nil {
+			return
+		}
+		fltrIDs, aI, cacheID = prfl.FilterIDs, prfl.ActivationInterval, utils.CacheStatFilterIndexes
+		strFlds, prfxFlds = fS.cfg.StatSCfg().StringIndexedFields, fS.cfg.StatSCfg().PrefixIndexedFields
+		idxSelects, nestedFlds = fS.cfg.StatSCfg().IndexedSelects, fS.cfg.StatSCfg().NestedFields
+	case utils.MetaResources:
+		var prfl *ResourceProfile
+		if prfl, err = fS.dm.GetResourceProfile(args.Tenant, args.ProfileID,
+			true, true, utils.NonTransactional); err != nil {
+			return
+		}
+		fltrIDs, aI, cacheID = prfl.FilterIDs, prfl.ActivationInterval, utils.CacheResourceFilterIndexes
+		strFlds, prfxFlds = fS.cfg.ResourceSCfg().StringIndexedFields, fS.cfg.ResourceSCfg().PrefixIndexedFields
+		idxSelects, nestedFlds = fS.cfg.ResourceSCfg().IndexedSelects, fS.cfg.ResourceSCfg().NestedFields
+	case utils.MetaDispatchers:
+		var prfl *DispatcherProfile
+		if prfl, err = fS.dm.GetDispatcherProfile(args.Tenant, args.ProfileID,
+			true, true, utils.NonTransactional); err != nil {
+			return
+		}
+		fltrIDs, aI, cacheID = prfl.FilterIDs, prfl.ActivationInterval, utils.CacheDispatcherFilterIndexes
+		idxKeys = []string{utils.ConcatenatedKey(args.Tenant, ctx),
+			utils.ConcatenatedKey(args.Tenant, utils.META_ANY)}
+		strFlds, prfxFlds = fS.cfg.DispatcherSCfg().StringIndexedFields, fS.cfg.DispatcherSCfg().PrefixIndexedFields
+		idxSelects, nestedFlds = fS.cfg.DispatcherSCfg().IndexedSelects, fS.cfg.DispatcherSCfg().NestedFields
+	default:
+		return nil, fmt.Errorf("unsupported profile type <%s>", args.ProfileType)
+	}
+	fltrsExp.ProfileActive = aI == nil || args.Time == nil ||
+		aI.IsActiveAtTime(*args.Time)
+	for _, idxKey := range idxKeys {
+		var itemIDs utils.StringMap
+		if itemIDs, err = MatchingItemIDsForEvent(args.Event, strFlds, prfxFlds,
+			fS.dm, cacheID, idxKey, idxSelects, nestedFlds); err != nil {
+			if err != utils.ErrNotFound {
+				return
+			}
+			err = nil
+			continue
+		}
+		if itemIDs.HasKey(args.ProfileID) {
+			fltrsExp.IndexHit = true
+			break
+		}
+	}
+	return
+}
+
+// explainFilter evaluates all the rules of one filter
+func (fS *FilterS) explainFilter(tenant, fltrID string,
+	dDP config.DataProvider, depth int) (fltrExp *FilterExplanation) {
+	fltrExp = &FilterExplanation{ID: fltrID}
+	f, err := GetFilter(fS.dm, tenant, fltrID,
+		true, true, utils.NonTransactional)
+	if err != nil {
+		if err == utils.ErrNotFound {
+			err = utils.ErrPrefixNotFound(fltrID)
+		}
+		fltrExp.Error = err.Error()
+		return
+	}
+	if fltrExp.Active = f.ActivationInterval == nil ||
+		f.ActivationInterval.IsActiveAtTime(time.Now()); !fltrExp.Active {
+		return
+	}
+	fltrExp.Pass = true
+	fltrExp.Rules = make([]*FilterRuleExplanation, len(f.Rules))
+	for i, rule := range f.Rules {
+		fltrExp.Rules[i] = fS.explainRule(tenant, rule, dDP, depth)
+		if fltrExp.Rules[i].Error != utils.EmptyString {
+			fltrExp.Pass = false
+			fltrExp.Error = fltrExp.Rules[i].Error
+		} else if !fltrExp.Rules[i].Pass {
+			fltrExp.Pass = false
+		}
+	}
+	return
+}
+
+// explainRule evaluates one rule, resolving its Element and Values out of the event
+func (fS *FilterS) explainRule(tenant string, rule *FilterRule,
+	dDP config.DataProvider, depth int) (ruleExp *FilterRuleExplanation) {
+	ruleExp = &FilterRuleExplanation{
+		Type:    rule.Type,
+		Element: rule.Element,
+		Values:  rule.Values,
+	}
+	var err error
+	switch rule.Type {
+	case utils.MetaExpr:
+		ruleExp.Pass, err = fS.explainExpr(tenant, rule, dDP, depth, ruleExp)
+	case utils.MetaRSR, utils.MetaNotRSR:
+		ruleExp.Pass, err = rule.Pass(dDP)
+	default:
+		if rule.Element != utils.EmptyString {
+			if elmVal, err := config.DPDynamicString(rule.Element, dDP); err == nil {
+				ruleExp.ElementValue = utils.StringPointer(elmVal)
+			}
+		}
+		for _, val := range rule.Values {
+			if cmpVal, err := config.DPDynamicString(val, dDP); err == nil {
+				ruleExp.CompareValues = append(ruleExp.CompareValues, cmpVal)
+			}
+		}
+		ruleExp.Pass, err = rule.Pass(dDP)
+	}
+	if err != nil {
+		ruleExp.Pass = false
+		ruleExp.Error = err.Error()
+	}
+	return
+}
+
+// explainExpr explains every operand of the *expr rule and evaluates the expression out of their results
+func (fS *FilterS) explainExpr(tenant string, rule *FilterRule, dDP config.DataProvider,
+	depth int, ruleExp *FilterRuleExplanation) (pass bool, err error) {
+	if depth >= maxExprDepth {
+		return false, fmt.Errorf("filter expressions nested deeper than %d", maxExprDepth)
+	}
+	if rule.expr == nil { // rule was not compiled
+		if err = rule.CompileValues(); err != nil {
+			return
+		}
+	}
+	oprndExps := make(map[string]*FilterExplanation)
+	for _, oprnd := range rule.expr.operands() {
+		if _, has := oprndExps[oprnd]; has {
+			continue
+		}
+		oprndExps[oprnd] = fS.explainFilter(tenant, oprnd, dDP, depth+1)
+		ruleExp.Operands = append(ruleExp.Operands, oprndExps[oprnd])
+	}
+	return rule.expr.eval(func(oprnd string) (bool, error) {
+		if oprndExps[oprnd].Error != utils.EmptyString {
+			return false, errors.New(oprndExps[oprnd].Error)
+		}
+		return oprndExps[oprnd].Pass, nil
+	})
+}
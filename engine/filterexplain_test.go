@@ -0,0 +1,177 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+package engine
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestFilterSExplainFilters(t *testing.T) {
+	cfg, _ := config.NewDefaultCGRConfig()
+	dm := NewDataManager(NewInternalDB(nil, nil, true, cfg.DataDbCfg().Items),
+		config.CgrConfig().CacheCfg(), nil)
+	fS := NewFilterS(cfg, nil, dm)
+	if err := dm.SetFilter(&Filter{
+		Tenant: "cgrates.org",
+		ID:     "FLTR_EXP_1",
+		Rules: []*FilterRule{
+			{Type: utils.MetaString, Element: "~*req.Account", Values: []string{"1001", "~*req.Subject"}},
+			{Type: utils.MetaPrefix, Element: "~*req.Destination", Values: []string{"+49"}},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := dm.SetFilter(&Filter{
+		Tenant: "cgrates.org",
+		ID:     "FLTR_EXP_INACTIVE",
+		Rules: []*FilterRule{
+			{Type: utils.MetaString, Element: "~*req.Account", Values: []string{"1002"}},
+		},
+		ActivationInterval: &utils.ActivationInterval{
+			ActivationTime: time.Date(2014, 7, 14, 14, 25, 0, 0, time.UTC),
+			ExpiryTime:     time.Date(2014, 7, 15, 14, 25, 0, 0, time.UTC),
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	ev := &utils.CGREvent{
+		Tenant: "cgrates.org",
+		Event: map[string]interface{}{
+			utils.Account:     "1002",
+			utils.Subject:     "1003",
+			utils.Destination: "+4986517174963",
+		},
+	}
+	eExp := &FiltersExplanation{
+		Filters: []*FilterExplanation{
+			{
+				ID:     "FLTR_EXP_1",
+				Active: true,
+				Rules: []*FilterRuleExplanation{
+					{
+						Type:          utils.MetaString,
+						Element:       "~*req.Account",
+						Values:        []string{"1001", "~*req.Subject"},
+						ElementValue:  utils.StringPointer("1002"),
+						CompareValues: []string{"1001", "1003"},
+					},
+					{
+						Type:          utils.MetaPrefix,
+						Element:       "~*req.Destination",
+						Values:        []string{"+49"},
+						ElementValue:  utils.StringPointer("+4986517174963"),
+						CompareValues: []string{"+49"},
+						Pass:          true,
+					},
+				},
+			},
+			{ID: "FLTR_EXP_INACTIVE"},
+			{ID: "FLTR_EXP_MISSING", Error: "NOT_FOUND:FLTR_EXP_MISSING"},
+			{
+				ID:     "*expr::FLTR_EXP_1||*string:~*req.Subject:1003",
+				Active: true,
+				Pass:   true,
+				Rules: []*FilterRuleExplanation{
+					{
+						Type:   utils.MetaExpr,
+						Values: []string{"FLTR_EXP_1||*string:~*req.Subject:1003"},
+						Pass:   true,
+						Operands: []*FilterExplanation{
+							nil, // checked below
+							{
+								ID:     "*string:~*req.Subject:1003",
+								Active: true,
+								Pass:   true,
+								Rules: []*FilterRuleExplanation{{
+									Type:          utils.MetaString,
+									Element:       "~*req.Subject",
+									Values:        []string{"1003"},
+									ElementValue:  utils.StringPointer("1003"),
+									CompareValues: []string{"1003"},
+									Pass:          true,
+								}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	eExp.Filters[3].Rules[0].Operands[0] = eExp.Filters[0]
+	if rcv, err := fS.ExplainFilters(&ArgsExplainFilters{
+		CGREvent: ev,
+		FilterIDs: []string{"FLTR_EXP_1", "FLTR_EXP_INACTIVE", "FLTR_EXP_MISSING",
+			"*expr::FLTR_EXP_1||*string:~*req.Subject:1003"},
+	}); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(eExp, rcv) {
+		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(eExp), utils.ToJSON(rcv))
+	}
+
+	ev.Event[utils.Account] = "1001"
+	if err := dm.SetChargerProfile(&ChargerProfile{
+		Tenant:    "cgrates.org",
+		ID:        "CHRG_EXP",
+		FilterIDs: []string{"*string:~*req.Account:1001", "FLTR_EXP_1"},
+		ActivationInterval: &utils.ActivationInterval{
+			ActivationTime: time.Date(2014, 7, 14, 14, 25, 0, 0, time.UTC),
+		},
+		RunID: utils.MetaDefault,
+	}, true); err != nil {
+		t.Fatal(err)
+	}
+	if rcv, err := fS.ExplainFilters(&ArgsExplainFilters{
+		CGREvent:    ev,
+		ProfileType: utils.MetaChargers,
+		ProfileID:   "CHRG_EXP",
+	}); err != nil {
+		t.Fatal(err)
+	} else if !rcv.ProfileActive || !rcv.IndexHit || !rcv.Pass || len(rcv.Filters) != 2 {
+		t.Errorf("received: %s", utils.ToJSON(rcv))
+	}
+	ev.Event[utils.Account] = "1002"
+	ev.Time = utils.TimePointer(time.Date(2013, 7, 14, 14, 25, 0, 0, time.UTC))
+	if rcv, err := fS.ExplainFilters(&ArgsExplainFilters{
+		CGREvent:    ev,
+		ProfileType: utils.MetaChargers,
+		ProfileID:   "CHRG_EXP",
+	}); err != nil {
+		t.Fatal(err)
+	} else if rcv.ProfileActive || rcv.IndexHit || rcv.Pass {
+		t.Errorf("received: %s", utils.ToJSON(rcv))
+	}
+	if _, err := fS.ExplainFilters(&ArgsExplainFilters{
+		CGREvent:    ev,
+		ProfileType: utils.MetaChargers,
+		ProfileID:   "CHRG_MISSING",
+	}); err != utils.ErrNotFound {
+		t.Errorf("Expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	if _, err := fS.ExplainFilters(&ArgsExplainFilters{
+		CGREvent:    ev,
+		ProfileType: "*unsupported",
+		ProfileID:   "CHRG_EXP",
+	}); err == nil {
+		t.Error("expecting error")
+	}
+}
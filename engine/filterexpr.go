@@ -0,0 +1,196 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+package engine
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	exprAnd    = "&&"
+	exprOr     = "||"
+	exprNot    = "!"
+	exprOpen   = "("
+	exprClose  = ")"
+	exprOprand = ""
+
+	// maxExprDepth limits the *expr filters referencing other *expr filters
+	maxExprDepth = 10
+)
+
+// filterExpr is a node of the boolean expression compiled out of a *expr filter rule
+type filterExpr struct {
+	op       string        // one of exprAnd, exprOr, exprNot or exprOprand for leafs
+	operand  string        // filter ID or inline filter, only for leafs
+	children []*filterExpr // operands of op
+}
+
+// eval evaluates the expression, short-circuiting the AND and OR operations
+func (fe *filterExpr) eval(passOperand func(string) (bool, error)) (pass bool, err error) {
+	switch fe.op {
+	case exprOprand:
+		return passOperand(fe.operand)
+	case exprNot:
+		if pass, err = fe.children[0].eval(passOperand); err != nil {
+			return
+		}
+		return !pass, nil
+	case exprAnd:
+		for _, child := range fe.children {
+			if pass, err = child.eval(passOperand); err != nil || !pass {
+				return
+			}
+		}
+		return
+	default: // exprOr
+		for _, child := range fe.children {
+			if pass, err = child.eval(passOperand); err != nil || pass {
+				return
+			}
+		}
+		return
+	}
+}
+
+// operands returns the filter IDs or inline filters used within the expression
+func (fe *filterExpr) operands() (ops []string) {
+	if fe.op == exprOprand {
+		return []string{fe.operand}
+	}
+	for _, child := range fe.children {
+		ops = append(ops, child.operands()...)
+	}
+	return
+}
+
+// tokenizeFilterExpr splits the expression into operators, parentheses and operands
+// operands cannot contain whitespaces or parentheses
+func tokenizeFilterExpr(expr string) (tkns []string) {
+	var operand strings.Builder
+	flushOperand := func() {
+		if operand.Len() != 0 {
+			tkns = append(tkns, operand.String())
+			operand.Reset()
+		}
+	}
+	for i := 0; i < len(expr); i++ {
+		switch {
+		case expr[i] == ' ' || expr[i] == '\t' || expr[i] == '\n':
+			flushOperand()
+		case expr[i] == '(' || expr[i] == ')':
+			flushOperand()
+			tkns = append(tkns, expr[i:i+1])
+		case strings.HasPrefix(expr[i:], exprAnd) || strings.HasPrefix(expr[i:], exprOr):
+			flushOperand()
+			tkns = append(tkns, expr[i:i+2])
+			i++
+		case expr[i] == '!' && operand.Len() == 0:
+			tkns = append(tkns, exprNot)
+		default:
+			operand.WriteByte(expr[i])
+		}
+	}
+	flushOperand()
+	return
+}
+
+// newFilterExpr compiles the expression, with NOT having precedence over AND and AND over OR
+func newFilterExpr(expr string) (fe *filterExpr, err error) {
+	p := &filterExprParser{tkns: tokenizeFilterExpr(expr)}
+	if len(p.tkns) == 0 {
+		return nil, fmt.Errorf("empty filter expression")
+	}
+	if fe, err = p.parseOr(); err != nil {
+		return nil, fmt.Errorf("invalid filter expression <%s>: %s", expr, err.Error())
+	}
+	if p.pos != len(p.tkns) {
+		return nil, fmt.Errorf("invalid filter expression <%s>: unexpected <%s>", expr, p.tkns[p.pos])
+	}
+	return
+}
+
+// filterExprParser is a recursive descent parser over the expression tokens
+type filterExprParser struct {
+	tkns []string
+	pos  int
+}
+
+func (p *filterExprParser) next() (tkn string) {
+	if p.pos < len(p.tkns) {
+		tkn = p.tkns[p.pos]
+	}
+	return
+}
+
+func (p *filterExprParser) parseOr() (fe *filterExpr, err error) {
+	return p.parseBinary(exprOr, p.parseAnd)
+}
+
+func (p *filterExprParser) parseAnd() (fe *filterExpr, err error) {
+	return p.parseBinary(exprAnd, p.parseUnary)
+}
+
+func (p *filterExprParser) parseBinary(op string,
+	parseOperand func() (*filterExpr, error)) (fe *filterExpr, err error) {
+	if fe, err = parseOperand(); err != nil {
+		return
+	}
+	for p.next() == op {
+		p.pos++
+		var rhs *filterExpr
+		if rhs, err = parseOperand(); err != nil {
+			return
+		}
+		if fe.op != op {
+			fe = &filterExpr{op: op, children: []*filterExpr{fe}}
+		}
+		fe.children = append(fe.children, rhs)
+	}
+	return
+}
+
+func (p *filterExprParser) parseUnary() (fe *filterExpr, err error) {
+	switch tkn := p.next(); tkn {
+	case exprNot:
+		p.pos++
+		var child *filterExpr
+		if child, err = p.parseUnary(); err != nil {
+			return
+		}
+		return &filterExpr{op: exprNot, children: []*filterExpr{child}}, nil
+	case exprOpen:
+		p.pos++
+		if fe, err = p.parseOr(); err != nil {
+			return
+		}
+		if p.next() != exprClose {
+			return nil, fmt.Errorf("missing <%s>", exprClose)
+		}
+		p.pos++
+		return
+	case exprClose, exprAnd, exprOr, "":
+		if tkn == "" {
+			return nil, fmt.Errorf("unexpected end")
+		}
+		return nil, fmt.Errorf("unexpected <%s>", tkn)
+	default:
+		p.pos++
+		return &filterExpr{op: exprOprand, operand: tkn}, nil
+	}
+}
@@ -0,0 +1,125 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+package engine
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestTokenizeFilterExpr(t *testing.T) {
+	exp := []string{"FLTR_A", "||", "(", "*string:~*req.Account:1001", "&&", "!", "FLTR_C", ")"}
+	if rcv := tokenizeFilterExpr("FLTR_A || (*string:~*req.Account:1001&&!FLTR_C)"); !reflect.DeepEqual(exp, rcv) {
+		t.Errorf("Expecting: %q, received: %q", exp, rcv)
+	}
+}
+
+func TestNewFilterExpr(t *testing.T) {
+	exp := &filterExpr{op: exprOr, children: []*filterExpr{
+		{operand: "A"},
+		{op: exprAnd, children: []*filterExpr{
+			{operand: "B"},
+			{op: exprNot, children: []*filterExpr{{operand: "C"}}},
+		}},
+		{operand: "D"},
+	}}
+	if rcv, err := newFilterExpr("A || B && !C || D"); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(exp, rcv) {
+		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(exp), utils.ToJSON(rcv))
+	}
+	if ops := exp.operands(); !reflect.DeepEqual([]string{"A", "B", "C", "D"}, ops) {
+		t.Errorf("received: %+v", ops)
+	}
+	for _, expr := range []string{"", "A ||", "(A && B", "A B", "A && || B", ")"} {
+		if _, err := newFilterExpr(expr); err == nil {
+			t.Errorf("expecting error for expression: <%s>", expr)
+		}
+	}
+}
+
+func TestFilterSPassExpr(t *testing.T) {
+	cfg, _ := config.NewDefaultCGRConfig()
+	dm := NewDataManager(NewInternalDB(nil, nil, true, cfg.DataDbCfg().Items),
+		config.CgrConfig().CacheCfg(), nil)
+	fS := NewFilterS(cfg, nil, dm)
+	if err := dm.SetFilter(&Filter{
+		Tenant: "cgrates.org",
+		ID:     "FLTR_PREMIUM",
+		Rules: []*FilterRule{{
+			Type:    utils.MetaPrefix,
+			Element: "~*req.Destination",
+			Values:  []string{"+49900", "+49137"},
+		}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := dm.SetFilter(&Filter{
+		Tenant: "cgrates.org",
+		ID:     "FLTR_EXPR",
+		Rules: []*FilterRule{{
+			Type:   utils.MetaExpr,
+			Values: []string{"*string:~*req.Account:1001", "1002 || (FLTR_PREMIUM && !*string:~*req.Category:free)"},
+		}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		ev   map[string]interface{}
+		pass bool
+	}{
+		{ev: map[string]interface{}{"Account": "1002"}, pass: true},
+		{ev: map[string]interface{}{"Account": "1003", "Destination": "+4990012"}, pass: true},
+		{ev: map[string]interface{}{"Account": "1003", "Destination": "+4990012", "Category": "free"}, pass: false},
+		{ev: map[string]interface{}{"Account": "1003", "Destination": "+4930012"}, pass: false},
+	} {
+		ev := config.NewNavigableMap(map[string]interface{}{utils.MetaReq: tc.ev})
+		if pass, err := fS.Pass("cgrates.org", []string{"FLTR_EXPR"}, ev); err != nil {
+			t.Error(err)
+		} else if pass != tc.pass {
+			t.Errorf("Expecting: %v, received: %v for event: %s", tc.pass, pass, utils.ToJSON(tc.ev))
+		}
+	}
+	// inline expression referencing itself
+	if _, err := fS.Pass("cgrates.org", []string{"*expr::FLTR_LOOP"},
+		config.NewNavigableMap(nil)); err == nil {
+		t.Error("expecting error")
+	}
+}
+
+func TestNewFilterRuleNotExpr(t *testing.T) {
+	if _, err := NewFilterRule("*notexpr", utils.EmptyString,
+		[]string{"FLTR_1 || FLTR_2"}); err == nil || err.Error() != "Unsupported filter Type: *notexpr" {
+		t.Errorf("received error: %v", err)
+	}
+	if _, err := NewFilterRule(utils.MetaExpr, utils.EmptyString,
+		[]string{"!(FLTR_1 || FLTR_2)"}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestFilterIndexValuesExpr(t *testing.T) {
+	if fldType, fldName, fldVals := filterIndexValues(utils.MetaExpr, utils.EmptyString,
+		[]string{"FLTR_1 || FLTR_2"}); fldType != utils.META_NONE || fldName != utils.META_ANY ||
+		!reflect.DeepEqual([]string{utils.META_ANY}, fldVals) {
+		t.Errorf("received: %s %s %+v", fldType, fldName, fldVals)
+	}
+}
@@ -20,6 +20,7 @@ package engine
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/guardian"
@@ -65,6 +66,16 @@ func (rfi *FilterIndexer) IndexTPFilter(tpFltr *utils.TPFilterProfile, itemID st
 				rfi.indexes[concatKey][itemID] = true
 				rfi.chngdIndxKeys[concatKey] = true
 			}
+		case utils.MetaRegex, utils.MetaExpr:
+			fldType, fldName, fldVals := filterIndexValues(fltr.Type, fltr.Element, fltr.Values)
+			for _, fldVal := range fldVals {
+				concatKey := utils.ConcatenatedKey(fldType, fldName, fldVal)
+				if _, hasIt := rfi.indexes[concatKey]; !hasIt {
+					rfi.indexes[concatKey] = make(utils.StringMap)
+				}
+				rfi.indexes[concatKey][itemID] = true
+				rfi.chngdIndxKeys[concatKey] = true
+			}
 		case utils.META_NONE:
 			concatKey := utils.ConcatenatedKey(utils.META_NONE, utils.ANY, utils.ANY)
 			if _, hasIt := rfi.indexes[concatKey]; !hasIt {
@@ -247,12 +258,7 @@ func (rfi *FilterIndexer) RemoveItemFromIndex(tenant, itemID string, oldFilters
 			return err
 		}
 		for _, flt := range fltr.Rules {
-			var fldType, fldName string
-			var fldVals []string
-			if utils.SliceHasMember([]string{utils.META_NONE, utils.MetaPrefix, utils.MetaString}, flt.Type) {
-				fldType, fldName = flt.Type, flt.Element
-				fldVals = flt.Values
-			}
+			fldType, fldName, fldVals := filterIndexValues(flt.Type, flt.Element, flt.Values)
 			for _, fldVal := range fldVals {
 				if err = rfi.loadFldNameFldValIndex(fldType,
 					fldName, fldVal); err != nil && err != utils.ErrNotFound {
@@ -308,12 +314,7 @@ func createAndIndex(itemPrefix, tenant, context, itemID string, filterIDs []stri
 			return
 		}
 		for _, flt := range fltr.Rules {
-			var fldType, fldName string
-			var fldVals []string
-			if utils.SliceHasMember([]string{utils.META_NONE, utils.MetaPrefix, utils.MetaString}, flt.Type) {
-				fldType, fldName = flt.Type, flt.Element
-				fldVals = flt.Values
-			}
+			fldType, fldName, fldVals := filterIndexValues(flt.Type, flt.Element, flt.Values)
 			for _, fldVal := range fldVals {
 				if err = indexer.loadFldNameFldValIndex(fldType,
 					fldName, fldVal); err != nil && err != utils.ErrNotFound {
@@ -325,3 +326,36 @@ func createAndIndex(itemPrefix, tenant, context, itemID string, filterIDs []stri
 	}
 	return indexer.StoreIndexes(true, utils.NonTransactional)
 }
+
+// filterIndexValues returns the index type, field name and values of a filter rule,
+// empty if the rule cannot be indexed
+func filterIndexValues(fltrType, fldName string, vals []string) (idxType, idxFldName string, idxVals []string) {
+	switch fltrType {
+	case utils.META_NONE, utils.MetaPrefix, utils.MetaString:
+		return fltrType, fldName, vals
+	case utils.MetaRegex: // anchored patterns are indexed as *prefix, only if all of them have a literal prefix
+		idxVals = make([]string, len(vals))
+		for i, val := range vals {
+			if idxVals[i] = regexIndexPrefix(val); idxVals[i] == "" { // no literal prefix, make sure the item is always checked
+				return utils.META_NONE, utils.META_ANY, []string{utils.META_ANY}
+			}
+		}
+		return utils.MetaPrefix, fldName, idxVals
+	case utils.MetaExpr: // expressions cannot be indexed, make sure the item is always checked
+		return utils.META_NONE, utils.META_ANY, []string{utils.META_ANY}
+	}
+	return
+}
+
+// regexIndexPrefix returns the literal prefix which needs to start any value matching the pattern
+func regexIndexPrefix(pattern string) (prfx string) {
+	if !strings.HasPrefix(pattern, "^") {
+		return
+	}
+	rgx, err := compileRegexp(pattern)
+	if err != nil {
+		return
+	}
+	prfx, _ = rgx.LiteralPrefix()
+	return
+}
@@ -19,11 +19,13 @@ import (
 	"fmt"
 	"net"
 	"reflect"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/utils"
+	"github.com/cgrates/ltcache"
 )
 
 // NewFilterS initializtes the filter service
@@ -68,7 +70,7 @@ func (fS *FilterS) Pass(tenant string, filterIDs []string,
 		}
 		dDP := newDynamicDP(fS.cfg, fS.connMgr, tenant, ev)
 		for _, fltr := range f.Rules {
-			if pass, err = fltr.Pass(dDP); err != nil || !pass {
+			if pass, err = fS.passRule(tenant, fltr, dDP, 0); err != nil || !pass {
 				return pass, err
 			}
 		}
@@ -77,6 +79,55 @@ func (fS *FilterS) Pass(tenant string, filterIDs []string,
 	return
 }
 
+// PassRule checks one rule against the dataProvider, to be used instead of FilterRule.Pass
+// for the rules which might be of *expr type
+func (fS *FilterS) PassRule(tenant string, rule *FilterRule,
+	dDP config.DataProvider) (pass bool, err error) {
+	return fS.passRule(tenant, rule, dDP, 0)
+}
+
+// passRule checks the rule against the dataProvider, resolving the operands of *expr rules
+func (fS *FilterS) passRule(tenant string, rule *FilterRule,
+	dDP config.DataProvider, depth int) (pass bool, err error) {
+	if rule.Type != utils.MetaExpr {
+		return rule.Pass(dDP)
+	}
+	if depth >= maxExprDepth {
+		return false, fmt.Errorf("filter expressions nested deeper than %d", maxExprDepth)
+	}
+	if rule.expr == nil { // rule was not compiled
+		if err = rule.CompileValues(); err != nil {
+			return
+		}
+	}
+	return rule.expr.eval(func(fltrID string) (bool, error) {
+		return fS.passFilter(tenant, fltrID, dDP, depth+1)
+	})
+}
+
+// passFilter checks all the rules of one filter, used as operand of *expr rules
+func (fS *FilterS) passFilter(tenant, fltrID string,
+	dDP config.DataProvider, depth int) (pass bool, err error) {
+	var f *Filter
+	if f, err = GetFilter(fS.dm, tenant, fltrID,
+		true, true, utils.NonTransactional); err != nil {
+		if err == utils.ErrNotFound {
+			err = utils.ErrPrefixNotFound(fltrID)
+		}
+		return
+	}
+	if f.ActivationInterval != nil &&
+		!f.ActivationInterval.IsActiveAtTime(time.Now()) { // not active
+		return
+	}
+	for _, rule := range f.Rules {
+		if pass, err = fS.passRule(tenant, rule, dDP, depth); err != nil || !pass {
+			return
+		}
+	}
+	return true, nil
+}
+
 //checkPrefix verify if the value has as prefix one of the prefixes
 func checkPrefix(value string, prefixes []string) (hasPrefix bool) {
 	for _, prefix := range prefixes {
@@ -138,7 +189,7 @@ func (fS *FilterS) LazyPass(tenant string, filterIDs []string,
 				continue
 			}
 			dDP := newDynamicDP(fS.cfg, fS.connMgr, tenant, ev)
-			if pass, err = rule.Pass(dDP); err != nil || !pass {
+			if pass, err = fS.passRule(tenant, rule, dDP, 0); err != nil || !pass {
 				return
 			}
 		}
@@ -203,15 +254,34 @@ var supportedFiltersType *utils.StringSet = utils.NewStringSet([]string{utils.Me
 	utils.MetaTimings, utils.MetaRSR, utils.MetaDestinations,
 	utils.MetaEmpty, utils.MetaExists, utils.MetaLessThan, utils.MetaLessOrEqual,
 	utils.MetaGreaterThan, utils.MetaGreaterOrEqual, utils.MetaEqual,
-	utils.MetaNotEqual})
+	utils.MetaNotEqual, utils.MetaRegex, utils.MetaIPNet, utils.MetaExpr})
 var needsFieldName *utils.StringSet = utils.NewStringSet([]string{utils.MetaString, utils.MetaPrefix,
 	utils.MetaSuffix, utils.MetaTimings, utils.MetaDestinations, utils.MetaLessThan,
 	utils.MetaEmpty, utils.MetaExists, utils.MetaLessOrEqual, utils.MetaGreaterThan,
-	utils.MetaGreaterOrEqual, utils.MetaEqual, utils.MetaNotEqual})
+	utils.MetaGreaterOrEqual, utils.MetaEqual, utils.MetaNotEqual, utils.MetaRegex,
+	utils.MetaIPNet})
 var needsValues *utils.StringSet = utils.NewStringSet([]string{utils.MetaString, utils.MetaPrefix,
 	utils.MetaSuffix, utils.MetaTimings, utils.MetaRSR, utils.MetaDestinations,
 	utils.MetaLessThan, utils.MetaLessOrEqual, utils.MetaGreaterThan, utils.MetaGreaterOrEqual,
-	utils.MetaEqual, utils.MetaNotEqual})
+	utils.MetaEqual, utils.MetaNotEqual, utils.MetaRegex, utils.MetaIPNet, utils.MetaExpr})
+
+// regexpCacheLimit is the maximum number of compiled patterns kept in regexpCache
+const regexpCacheLimit = 10000
+
+// regexpCache shares the compiled patterns between filter rules, the least recently used ones being evicted
+var regexpCache = ltcache.NewCache(regexpCacheLimit, 0, false, nil)
+
+// compileRegexp returns the compiled pattern, compiling it only once
+func compileRegexp(pattern string) (rgx *regexp.Regexp, err error) {
+	if x, has := regexpCache.Get(pattern); has {
+		return x.(*regexp.Regexp), nil
+	}
+	if rgx, err = regexp.Compile(pattern); err != nil {
+		return
+	}
+	regexpCache.Set(pattern, rgx, nil)
+	return
+}
 
 // NewFilterRule returns a new filter
 func NewFilterRule(rfType, fieldName string, vals []string) (*FilterRule, error) {
@@ -221,7 +291,8 @@ func NewFilterRule(rfType, fieldName string, vals []string) (*FilterRule, error)
 		rType = "*" + strings.TrimPrefix(rfType, utils.MetaNot)
 		negative = true
 	}
-	if !supportedFiltersType.Has(rType) {
+	if !supportedFiltersType.Has(rType) ||
+		(negative && rType == utils.MetaExpr) { // expressions are negated with the NOT operator
 		return nil, fmt.Errorf("Unsupported filter Type: %s", rfType)
 	}
 	if fieldName == "" && needsFieldName.Has(rType) {
@@ -249,6 +320,10 @@ type FilterRule struct {
 	Element   string            // Name of the field providing us the Values to check (used in case of some )
 	Values    []string          // Filter definition
 	rsrFields config.RSRParsers // Cache here the RSRFilter Values
+	regexps   []*regexp.Regexp  // Cache here the compiled *regex Values
+	ipNets    []*net.IPNet      // Cache here the static *ipnet Values
+	ipNetIDs  []string          // *ipnet Values resolved on each pass, dynamic or destination IDs
+	expr      *filterExpr       // Cache here the compiled *expr Values
 	negative  *bool
 }
 
@@ -265,11 +340,35 @@ func (fltr *FilterRule) CompileValues() (err error) {
 				return
 			}
 		}
+	case utils.MetaRegex, utils.MetaNotRegex:
+		rgxs := make([]*regexp.Regexp, len(fltr.Values))
+		for i, val := range fltr.Values {
+			if rgxs[i], err = compileRegexp(val); err != nil {
+				return
+			}
+		}
+		fltr.regexps = rgxs
+	case utils.MetaIPNet, utils.MetaNotIPNet:
+		ipNets := make([]*net.IPNet, 0, len(fltr.Values))
+		ipNetIDs := make([]string, 0)
+		for _, val := range fltr.Values {
+			if ipNet := parseIPNet(val); ipNet != nil {
+				ipNets = append(ipNets, ipNet)
+			} else {
+				ipNetIDs = append(ipNetIDs, val)
+			}
+		}
+		fltr.ipNets, fltr.ipNetIDs = ipNets, ipNetIDs
+	case utils.MetaExpr: // the Values were split on the separator which might be part of inline operands
+		if fltr.expr, err = newFilterExpr(strings.Join(fltr.Values, utils.INFIELD_SEP)); err != nil {
+			return
+		}
 	}
 	return
 }
 
 // Pass is the method which should be used from outside.
+// The *expr rules need FilterS for resolving their operands, see FilterS.PassRule
 func (fltr *FilterRule) Pass(dDP config.DataProvider) (result bool, err error) {
 	if fltr.negative == nil {
 		fltr.negative = utils.BoolPointer(strings.HasPrefix(fltr.Type, utils.MetaNot))
@@ -296,6 +395,10 @@ func (fltr *FilterRule) Pass(dDP config.DataProvider) (result bool, err error) {
 		result, err = fltr.passGreaterThan(dDP)
 	case utils.MetaEqual, utils.MetaNotEqual:
 		result, err = fltr.passEqualTo(dDP)
+	case utils.MetaRegex, utils.MetaNotRegex:
+		result, err = fltr.passRegex(dDP)
+	case utils.MetaIPNet, utils.MetaNotIPNet:
+		result, err = fltr.passIPNet(dDP)
 	default:
 		err = utils.ErrPrefixNotErrNotImplemented(fltr.Type)
 	}
@@ -514,6 +617,96 @@ func (fltr *FilterRule) passEqualTo(dDP config.DataProvider) (bool, error) {
 	return false, nil
 }
 
+func (fltr *FilterRule) passRegex(dDP config.DataProvider) (bool, error) {
+	strVal, err := config.DPDynamicString(fltr.Element, dDP)
+	if err != nil {
+		if err == utils.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	if fltr.regexps == nil { // rule was not compiled
+		if err = fltr.CompileValues(); err != nil {
+			return false, err
+		}
+	}
+	for _, rgx := range fltr.regexps {
+		if rgx.MatchString(strVal) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// parseIPNet parses a CIDR or a single IP address into a network, nil if not possible
+func parseIPNet(val string) (ipNet *net.IPNet) {
+	if _, ipNet, _ = net.ParseCIDR(val); ipNet != nil {
+		return
+	}
+	ip := net.ParseIP(val)
+	if ip == nil {
+		return
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}
+}
+
+// passIPNet checks the IP address in Element against the networks in Values,
+// the ones not being CIDRs are considered destination IDs holding them as prefixes
+func (fltr *FilterRule) passIPNet(dDP config.DataProvider) (bool, error) {
+	strVal, err := config.DPDynamicString(fltr.Element, dDP)
+	if err != nil {
+		if err == utils.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	ip := net.ParseIP(strVal)
+	if ip == nil { // might include the port
+		if host, _, err := net.SplitHostPort(strVal); err == nil {
+			ip = net.ParseIP(host)
+		}
+	}
+	if ip == nil {
+		return false, nil
+	}
+	if fltr.ipNets == nil { // rule was not compiled
+		if err = fltr.CompileValues(); err != nil {
+			return false, err
+		}
+	}
+	for _, ipNet := range fltr.ipNets {
+		if ipNet.Contains(ip) {
+			return true, nil
+		}
+	}
+	for _, val := range fltr.ipNetIDs {
+		val, err := config.DPDynamicString(val, dDP)
+		if err != nil {
+			continue
+		}
+		if ipNet := parseIPNet(val); ipNet != nil {
+			if ipNet.Contains(ip) {
+				return true, nil
+			}
+			continue
+		}
+		var dst Destination
+		if err = connMgr.Call(config.CgrConfig().FilterSCfg().ApierSConns, nil,
+			utils.APIerSv1GetDestination, val, &dst); err != nil {
+			continue
+		}
+		for _, prfx := range dst.Prefixes {
+			if ipNet := parseIPNet(prfx); ipNet != nil && ipNet.Contains(ip) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
 func newDynamicDP(cfg *config.CGRConfig, connMgr *ConnManager,
 	tenant string, initialDP config.DataProvider) *dynamicDP {
 	return &dynamicDP{
@@ -1212,3 +1212,129 @@ func TestPassPartial(t *testing.T) {
 		t.Errorf("Expecting: %+v, received: %+v", 0, len(ruleList))
 	}
 }
+
+func TestFilterPassRegex(t *testing.T) {
+	ev := config.NewNavigableMap(map[string]interface{}{
+		utils.MetaReq: map[string]interface{}{
+			"UserAgent": "Linphone/3.6.1 (eXosip2/4.1.0)",
+			"Number":    "+4917012345",
+		},
+	})
+	rf, err := NewFilterRule(utils.MetaRegex, "~*req.UserAgent", []string{`^Zoiper`, `^Linphone/3\.[0-9]+`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if passes, err := rf.Pass(ev); err != nil {
+		t.Error(err)
+	} else if !passes {
+		t.Error("Not passing")
+	}
+	rf, err = NewFilterRule(utils.MetaNotRegex, "~*req.Number", []string{`^\+4917[0-9]{6}$`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if passes, err := rf.Pass(ev); err != nil {
+		t.Error(err)
+	} else if passes {
+		t.Error("Passing")
+	}
+	// uncompiled rule
+	rf = &FilterRule{Type: utils.MetaRegex, Element: "~*req.Missing", Values: []string{"^1"}}
+	if passes, err := rf.Pass(ev); err != nil {
+		t.Error(err)
+	} else if passes {
+		t.Error("Passing")
+	}
+	rf = &FilterRule{Type: utils.MetaRegex, Element: "~*req.Number", Values: []string{"17012"}}
+	if passes, err := rf.Pass(ev); err != nil {
+		t.Error(err)
+	} else if !passes {
+		t.Error("Not passing")
+	}
+	if _, err := NewFilterRule(utils.MetaRegex, "~*req.Number", []string{`^(1`}); err == nil {
+		t.Error("Expecting error for invalid pattern")
+	}
+	if _, err := NewFilterFromInline("cgrates.org", `*regex:~*req.Number:^\+49(17|15)`); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestFilterIndexValuesRegex(t *testing.T) {
+	if fldType, _, fldVals := filterIndexValues(utils.MetaRegex, "~*req.Destination",
+		[]string{`^\+4917[0-9]+$`, `^1001`}); fldType != utils.MetaPrefix ||
+		!reflect.DeepEqual([]string{"+4917", "1001"}, fldVals) {
+		t.Errorf("received: %s %+v", fldType, fldVals)
+	}
+	if fldType, _, fldVals := filterIndexValues(utils.MetaRegex, "~*req.Destination",
+		[]string{`^1001`, `1002`}); fldType != utils.META_NONE ||
+		!reflect.DeepEqual([]string{utils.META_ANY}, fldVals) {
+		t.Errorf("received: %s %+v", fldType, fldVals)
+	}
+	for _, rgx := range []string{`^ab*`, `^a?b`, `^abc|^abd`, `^(?i)abc`} {
+		if fldType, fldName, fldVals := filterIndexValues(utils.MetaRegex, "~*req.Account",
+			[]string{rgx}); fldType != utils.META_NONE || fldName != utils.META_ANY ||
+			!reflect.DeepEqual([]string{utils.META_ANY}, fldVals) {
+			t.Errorf("pattern: %s received: %s %s %+v", rgx, fldType, fldName, fldVals)
+		}
+	}
+	if fldType, _, fldVals := filterIndexValues(utils.MetaNotRegex, "~*req.Destination",
+		[]string{`^1001`}); fldType != utils.EmptyString || fldVals != nil {
+		t.Errorf("received: %s %+v", fldType, fldVals)
+	}
+}
+
+func TestFilterPassIPNet(t *testing.T) {
+	ev := config.NewNavigableMap(map[string]interface{}{
+		utils.MetaReq: map[string]interface{}{
+			"SourceIP":   "10.10.2.17",
+			"RemoteHost": "[2001:db8::1]:5060",
+			"Subnet":     "10.10.0.0/16",
+			"Invalid":    "not_an_ip",
+		},
+	})
+	rf, err := NewFilterRule(utils.MetaIPNet, "~*req.SourceIP", []string{"192.168.0.0/24", "10.10.0.0/16"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if passes, err := rf.Pass(ev); err != nil {
+		t.Error(err)
+	} else if !passes {
+		t.Error("Not passing")
+	}
+	rf, err = NewFilterRule(utils.MetaIPNet, "~*req.RemoteHost", []string{"2001:db8::/32"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if passes, err := rf.Pass(ev); err != nil {
+		t.Error(err)
+	} else if !passes {
+		t.Error("Not passing")
+	}
+	rf, err = NewFilterRule(utils.MetaNotIPNet, "~*req.SourceIP", []string{"10.10.2.17"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if passes, err := rf.Pass(ev); err != nil {
+		t.Error(err)
+	} else if passes {
+		t.Error("Passing")
+	}
+	rf, err = NewFilterRule(utils.MetaIPNet, "~*req.SourceIP", []string{"~*req.Subnet"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if passes, err := rf.Pass(ev); err != nil {
+		t.Error(err)
+	} else if !passes {
+		t.Error("Not passing")
+	}
+	rf, err = NewFilterRule(utils.MetaIPNet, "~*req.Invalid", []string{"10.10.0.0/16"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if passes, err := rf.Pass(ev); err != nil {
+		t.Error(err)
+	} else if passes {
+		t.Error("Passing")
+	}
+}
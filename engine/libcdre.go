@@ -195,6 +195,13 @@ func (expEv *ExportEvents) ReplayFailedPosts(attempts int) (failedEvents *Export
 				failedEvents.AddEvent(ev)
 			}
 		}
+	case utils.MetaNatsjsonMap:
+		for _, ev := range expEv.Events {
+			err = PostersCache.PostNATS(expEv.Path, attempts, ev.([]byte))
+			if err != nil {
+				failedEvents.AddEvent(ev)
+			}
+		}
 	}
 	if len(failedEvents.Events) > 0 {
 		err = utils.ErrPartiallyExecuted
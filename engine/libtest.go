@@ -601,6 +601,10 @@ func GetDefaultEmptyCacheStats() map[string]*ltcache.CacheStats {
 			Items:  0,
 			Groups: 0,
 		},
+		utils.CacheRadiusPackets: {
+			Items:  0,
+			Groups: 0,
+		},
 		utils.CacheClosedSessions: {
 			Items:  0,
 			Groups: 0,
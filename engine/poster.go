@@ -43,6 +43,7 @@ func init() {
 		sqsCache:    make(map[string]Poster),
 		kafkaCache:  make(map[string]Poster),
 		s3Cache:     make(map[string]Poster),
+		natsCache:   make(map[string]Poster),
 	} // Initialize the cache for amqpPosters
 }
 
@@ -55,6 +56,7 @@ type PosterCache struct {
 	sqsCache    map[string]Poster
 	kafkaCache  map[string]Poster
 	s3Cache     map[string]Poster
+	natsCache   map[string]Poster
 }
 
 type Poster interface {
@@ -90,6 +92,9 @@ func (pc *PosterCache) Close() {
 	for _, v := range pc.kafkaCache {
 		v.Close()
 	}
+	for _, v := range pc.natsCache {
+		v.Close()
+	}
 }
 
 // GetAMQPPoster creates a new poster only if not already cached
@@ -158,6 +163,19 @@ func (pc *PosterCache) GetS3Poster(dialURL string, attempts int) (pstr Poster, e
 	return pc.s3Cache[dialURL], nil
 }
 
+// GetNATSPoster creates a new poster only if not already cached
+func (pc *PosterCache) GetNATSPoster(dialURL string, attempts int) (pstr Poster, err error) {
+	pc.Lock()
+	defer pc.Unlock()
+	if _, hasIt := pc.natsCache[dialURL]; !hasIt {
+		if pstr, err = NewNATSPoster(dialURL, attempts); err != nil {
+			return nil, err
+		}
+		pc.natsCache[dialURL] = pstr
+	}
+	return pc.natsCache[dialURL], nil
+}
+
 func (pc *PosterCache) PostAMQP(dialURL string, attempts int,
 	content []byte) error {
 	amqpPoster, err := pc.GetAMQPPoster(dialURL, attempts)
@@ -202,3 +220,12 @@ func (pc *PosterCache) PostS3(dialURL string, attempts int,
 	}
 	return sqsPoster.Post(content, key)
 }
+
+func (pc *PosterCache) PostNATS(dialURL string, attempts int,
+	content []byte) error {
+	natsPoster, err := pc.GetNATSPoster(dialURL, attempts)
+	if err != nil {
+		return err
+	}
+	return natsPoster.Post(content, "")
+}
@@ -41,3 +41,27 @@ func TestAMQPPosterParseURL(t *testing.T) {
 		t.Errorf("Expected: %s ,recived: %s", utils.ToJSON(expected), utils.ToJSON(amqp))
 	}
 }
+
+func TestNATSPosterParseURL(t *testing.T) {
+	pstr := &NATSPoster{}
+	expected := &NATSPoster{
+		dialURL: "nats://localhost:4222",
+		subject: "cgrates.cdrs",
+		stream:  "CDRS",
+	}
+	if err := pstr.parseURL("nats://localhost:4222?subject=cgrates.cdrs&stream=CDRS"); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(expected, pstr) {
+		t.Errorf("Expected: %+v ,recived: %+v", expected, pstr)
+	}
+	pstr = &NATSPoster{}
+	expected = &NATSPoster{
+		dialURL: "nats://localhost:4222",
+		subject: defaultQueueID,
+	}
+	if err := pstr.parseURL("nats://localhost:4222"); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(expected, pstr) {
+		t.Errorf("Expected: %+v ,recived: %+v", expected, pstr)
+	}
+}
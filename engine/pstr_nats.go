@@ -0,0 +1,137 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+	"github.com/nats-io/nats.go"
+)
+
+// NewNATSPoster creates a NATS JetStream poster
+func NewNATSPoster(dialURL string, attempts int) (*NATSPoster, error) {
+	pstr := &NATSPoster{
+		attempts: attempts,
+	}
+	if err := pstr.parseURL(dialURL); err != nil {
+		return nil, err
+	}
+	return pstr, nil
+}
+
+// NATSPoster is a NATS JetStream poster
+type NATSPoster struct {
+	dialURL    string
+	subject    string // subject where we publish
+	stream     string // optional stream created for the subject if missing
+	attempts   int
+	sync.Mutex // protect connection
+	conn       *nats.Conn
+	js         nats.JetStreamContext
+}
+
+func (pstr *NATSPoster) parseURL(dialURL string) error {
+	u, err := url.Parse(dialURL)
+	if err != nil {
+		return err
+	}
+	qry := u.Query()
+
+	pstr.dialURL = strings.Split(dialURL, "?")[0]
+	pstr.subject = defaultQueueID
+	if vals, has := qry[utils.NATSSubject]; has && len(vals) != 0 {
+		pstr.subject = vals[0]
+	}
+	if vals, has := qry[utils.NATSStream]; has && len(vals) != 0 {
+		pstr.stream = vals[0]
+	}
+	return nil
+}
+
+// Post is the method being called when we need to post anything in the queue
+// the message is considered posted only after JetStream acknowledged it
+func (pstr *NATSPoster) Post(content []byte, _ string) (err error) {
+	var js nats.JetStreamContext
+	fib := utils.Fib()
+	for i := 0; i < pstr.attempts; i++ {
+		if js, err = pstr.newPostWriter(); err == nil {
+			if _, err = js.Publish(pstr.subject, content); err == nil {
+				return
+			}
+		}
+		if i+1 < pstr.attempts {
+			time.Sleep(time.Duration(fib()) * time.Second)
+		}
+	}
+	return
+}
+
+// Close closes the connection
+func (pstr *NATSPoster) Close() {
+	pstr.Lock()
+	if pstr.conn != nil {
+		pstr.conn.Close()
+	}
+	pstr.conn = nil
+	pstr.js = nil
+	pstr.Unlock()
+}
+
+func (pstr *NATSPoster) newPostWriter() (js nats.JetStreamContext, err error) {
+	pstr.Lock()
+	defer pstr.Unlock()
+	if pstr.conn != nil && !pstr.conn.IsClosed() {
+		return pstr.js, nil
+	}
+	var conn *nats.Conn
+	if conn, err = nats.Connect(pstr.dialURL); err != nil {
+		return
+	}
+	if js, err = conn.JetStream(); err != nil {
+		conn.Close()
+		return
+	}
+	if err = EnsureNATSStream(js, pstr.stream, pstr.subject); err != nil {
+		conn.Close()
+		return
+	}
+	pstr.conn = conn
+	pstr.js = js
+	return
+}
+
+// EnsureNATSStream creates the stream for the subject if it does not exist
+// no stream name means the stream is managed outside CGRateS
+func EnsureNATSStream(js nats.JetStreamContext, stream, subject string) (err error) {
+	if stream == utils.EmptyString {
+		return
+	}
+	if _, err = js.StreamInfo(stream); err == nil {
+		return
+	}
+	_, err = js.AddStream(&nats.StreamConfig{
+		Name:     stream,
+		Subjects: []string{subject},
+	})
+	return
+}
@@ -24,6 +24,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/cgrates/cgrates/utils"
 	"github.com/ugorji/go/codec"
@@ -133,6 +134,11 @@ type DataDB interface {
 	GetDispatcherHostDrv(string, string) (*DispatcherHost, error)
 	SetDispatcherHostDrv(*DispatcherHost) error
 	RemoveDispatcherHostDrv(string, string) error
+	GetSessionsBackupDrv(nodeID string) ([]*StoredSession, error)
+	SetSessionsBackupDrv(nodeID string, sessions []*StoredSession) error
+	RemoveSessionsBackupDrv(nodeID string) error
+	SetDedupKeyDrv(key string, ttl time.Duration) (stored bool, err error)
+	RemoveDedupKeyDrv(key string) error
 }
 
 type StorDB interface {
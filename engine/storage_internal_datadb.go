@@ -190,6 +190,14 @@ func newInternalDBCfg(itemsCacheCfg map[string]*config.ItemOpt, isDataDB bool) m
 				TTL:       itemsCacheCfg[utils.CacheLoadIDs].TTL,
 				StaticTTL: itemsCacheCfg[utils.CacheLoadIDs].StaticTTL,
 			},
+			utils.CacheSessionsBackup: &ltcache.CacheConfig{
+				MaxItems: -1,
+			},
+			utils.CacheDedupKeys: &ltcache.CacheConfig{ // expire the keys with the deduplication window
+				MaxItems:  -1,
+				TTL:       config.CgrConfig().DedupCfg().TTL,
+				StaticTTL: true,
+			},
 		}
 	} else {
 		return map[string]*ltcache.CacheConfig{
@@ -567,7 +575,7 @@ func (iDB *InternalDB) GetDestinationDrv(key string, skipCache bool, transaction
 	cCommit := cacheCommit(transactionID)
 
 	if !skipCache {
-		if x, ok := Cache.Get(utils.CacheDestinations, key); ok {
+		if x, ok := cacheGet(utils.CacheDestinations, key); ok {
 			if x != nil {
 				return x.(*Destination), nil
 			}
@@ -634,7 +642,7 @@ func (iDB *InternalDB) SetReverseDestinationDrv(dest *Destination, transactionID
 func (iDB *InternalDB) GetReverseDestinationDrv(prefix string,
 	skipCache bool, transactionID string) (ids []string, err error) {
 	if !skipCache {
-		if x, ok := Cache.Get(utils.CacheReverseDestinations, prefix); ok {
+		if x, ok := cacheGet(utils.CacheReverseDestinations, prefix); ok {
 			if x != nil {
 				return x.([]string), nil
 			}
@@ -792,7 +800,7 @@ func (iDB *InternalDB) RemoveActionTriggersDrv(id string) (err error) {
 func (iDB *InternalDB) GetActionPlanDrv(key string, skipCache bool,
 	transactionID string) (ats *ActionPlan, err error) {
 	if !skipCache {
-		if x, ok := Cache.Get(utils.CacheActionPlans, key); ok {
+		if x, ok := cacheGet(utils.CacheActionPlans, key); ok {
 			if x != nil {
 				return x.(*ActionPlan), nil
 			}
@@ -866,7 +874,7 @@ func (iDB *InternalDB) GetAllActionPlansDrv() (ats map[string]*ActionPlan, err e
 func (iDB *InternalDB) GetAccountActionPlansDrv(acntID string,
 	skipCache bool, transactionID string) (apIDs []string, err error) {
 	if !skipCache {
-		if x, ok := Cache.Get(utils.CacheAccountActionPlans, acntID); ok {
+		if x, ok := cacheGet(utils.CacheAccountActionPlans, acntID); ok {
 			if x == nil {
 				return nil, utils.ErrNotFound
 			}
@@ -1365,3 +1373,46 @@ func (iDB *InternalDB) RemoveDispatcherHostDrv(tenant, id string) (err error) {
 func (iDB *InternalDB) RemoveLoadIDsDrv() (err error) {
 	return utils.ErrNotImplemented
 }
+
+func (iDB *InternalDB) GetSessionsBackupDrv(nodeID string) (ss []*StoredSession, err error) {
+	x, ok := iDB.db.Get(utils.CacheSessionsBackup, nodeID)
+	if !ok || x == nil {
+		return nil, utils.ErrNotFound
+	}
+	return x.([]*StoredSession), nil
+}
+
+func (iDB *InternalDB) SetSessionsBackupDrv(nodeID string, ss []*StoredSession) (err error) {
+	iDB.db.Set(utils.CacheSessionsBackup, nodeID, ss, nil,
+		cacheCommit(utils.NonTransactional), utils.NonTransactional)
+	return
+}
+
+func (iDB *InternalDB) RemoveSessionsBackupDrv(nodeID string) (err error) {
+	iDB.db.Remove(utils.CacheSessionsBackup, nodeID,
+		cacheCommit(utils.NonTransactional), utils.NonTransactional)
+	return
+}
+
+func (iDB *InternalDB) SetDedupKeyDrv(key string, ttl time.Duration) (stored bool, err error) {
+	iDB.mu.Lock()
+	defer iDB.mu.Unlock()
+	now := time.Now()
+	if x, ok := iDB.db.Get(utils.CacheDedupKeys, key); ok && x != nil {
+		if x.(time.Time).After(now) {
+			return
+		}
+		// remove the expired key so its cache TTL starts again
+		iDB.db.Remove(utils.CacheDedupKeys, key,
+			cacheCommit(utils.NonTransactional), utils.NonTransactional)
+	}
+	iDB.db.Set(utils.CacheDedupKeys, key, now.Add(ttl), nil,
+		cacheCommit(utils.NonTransactional), utils.NonTransactional)
+	return true, nil
+}
+
+func (iDB *InternalDB) RemoveDedupKeyDrv(key string) (err error) {
+	iDB.db.Remove(utils.CacheDedupKeys, key,
+		cacheCommit(utils.NonTransactional), utils.NonTransactional)
+	return
+}
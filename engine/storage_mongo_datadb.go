@@ -75,6 +75,8 @@ const (
 	ColDpp  = "dispatcher_profiles"
 	ColDph  = "dispatcher_hosts"
 	ColLID  = "load_ids"
+	ColSbk  = "sessions_backup"
+	ColDdp  = "dedup_keys"
 )
 
 var (
@@ -251,6 +253,17 @@ func (ms *MongoStorage) enusureIndex(colName string, uniq bool, keys ...string)
 	})
 }
 
+// ensureTTLIndex makes Mongo remove the documents once the time in key passed
+func (ms *MongoStorage) ensureTTLIndex(colName, key string) error {
+	return ms.query(func(sctx mongo.SessionContext) error {
+		_, err := ms.getCol(colName).Indexes().CreateOne(sctx, mongo.IndexModel{
+			Keys:    bson.M{key: 1},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		})
+		return err
+	})
+}
+
 func (ms *MongoStorage) dropAllIndexesForCol(colName string) error {
 	return ms.query(func(sctx mongo.SessionContext) error {
 		col := ms.getCol(colName)
@@ -282,10 +295,17 @@ func (ms *MongoStorage) ensureIndexesForCol(col string) (err error) { // exporte
 	}
 	err = nil
 	switch col {
-	case ColAct, ColApl, ColAAp, ColAtr, ColRpl, ColDst, ColRds, ColLht, ColRFI:
+	case ColAct, ColApl, ColAAp, ColAtr, ColRpl, ColDst, ColRds, ColLht, ColRFI, ColSbk:
+		if err = ms.enusureIndex(col, true, "key"); err != nil {
+			return
+		}
+	case ColDdp:
 		if err = ms.enusureIndex(col, true, "key"); err != nil {
 			return
 		}
+		if err = ms.ensureTTLIndex(col, "expiresat"); err != nil { // the expired keys are removed by Mongo
+			return
+		}
 	case ColRsP, ColRes, ColSqs, ColSqp, ColTps, ColThs, ColSpp, ColAttr, ColFlt, ColCpp, ColDpp, ColDph:
 		if err = ms.enusureIndex(col, true, "tenant", "id"); err != nil {
 			return
@@ -351,7 +371,7 @@ func (ms *MongoStorage) EnsureIndexes(cols ...string) (err error) {
 		for _, col := range []string{ColAct, ColApl, ColAAp, ColAtr,
 			ColRpl, ColDst, ColRds, ColLht, ColRFI, ColRsP, ColRes, ColSqs, ColSqp,
 			ColTps, ColThs, ColSpp, ColAttr, ColFlt, ColCpp, ColDpp,
-			ColRpf, ColShg, ColAcc} {
+			ColRpf, ColShg, ColAcc, ColSbk, ColDdp} {
 			if err = ms.ensureIndexesForCol(col); err != nil {
 				return
 			}
@@ -858,7 +878,7 @@ func (ms *MongoStorage) RemoveRatingProfileDrv(key string) error {
 func (ms *MongoStorage) GetDestinationDrv(key string, skipCache bool,
 	transactionID string) (result *Destination, err error) {
 	if !skipCache {
-		if x, ok := Cache.Get(utils.CacheDestinations, key); ok {
+		if x, ok := cacheGet(utils.CacheDestinations, key); ok {
 			if x == nil {
 				return nil, utils.ErrNotFound
 			}
@@ -958,7 +978,7 @@ func (ms *MongoStorage) RemoveDestinationDrv(destID string,
 func (ms *MongoStorage) GetReverseDestinationDrv(prefix string, skipCache bool,
 	transactionID string) (ids []string, err error) {
 	if !skipCache {
-		if x, ok := Cache.Get(utils.CacheReverseDestinations, prefix); ok {
+		if x, ok := cacheGet(utils.CacheReverseDestinations, prefix); ok {
 			if x == nil {
 				return nil, utils.ErrNotFound
 			}
@@ -1474,7 +1494,7 @@ func (ms *MongoStorage) GetAllActionPlansDrv() (ats map[string]*ActionPlan, err
 
 func (ms *MongoStorage) GetAccountActionPlansDrv(acntID string, skipCache bool, transactionID string) (aPlIDs []string, err error) {
 	if !skipCache {
-		if x, ok := Cache.Get(utils.CacheAccountActionPlans, acntID); ok {
+		if x, ok := cacheGet(utils.CacheAccountActionPlans, acntID); ok {
 			if x == nil {
 				return nil, utils.ErrNotFound
 			}
@@ -2296,3 +2316,82 @@ func (ms *MongoStorage) RemoveLoadIDsDrv() (err error) {
 		return err
 	})
 }
+
+func (ms *MongoStorage) GetSessionsBackupDrv(nodeID string) (ss []*StoredSession, err error) {
+	var kv struct {
+		Key   string
+		Value []byte
+	}
+	if err = ms.query(func(sctx mongo.SessionContext) (err error) {
+		cur := ms.getCol(ColSbk).FindOne(sctx, bson.M{"key": nodeID})
+		if err := cur.Decode(&kv); err != nil {
+			if err == mongo.ErrNoDocuments {
+				return utils.ErrNotFound
+			}
+			return err
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	err = ms.ms.Unmarshal(kv.Value, &ss)
+	return
+}
+
+func (ms *MongoStorage) SetSessionsBackupDrv(nodeID string, ss []*StoredSession) (err error) {
+	var result []byte
+	if result, err = ms.ms.Marshal(ss); err != nil {
+		return
+	}
+	return ms.query(func(sctx mongo.SessionContext) (err error) {
+		_, err = ms.getCol(ColSbk).UpdateOne(sctx, bson.M{"key": nodeID},
+			bson.M{"$set": struct {
+				Key   string
+				Value []byte
+			}{Key: nodeID, Value: result}},
+			options.Update().SetUpsert(true),
+		)
+		return err
+	})
+}
+
+func (ms *MongoStorage) RemoveSessionsBackupDrv(nodeID string) (err error) {
+	return ms.query(func(sctx mongo.SessionContext) (err error) {
+		_, err = ms.getCol(ColSbk).DeleteOne(sctx, bson.M{"key": nodeID})
+		return err
+	})
+}
+
+func (ms *MongoStorage) SetDedupKeyDrv(key string, ttl time.Duration) (stored bool, err error) {
+	now := time.Now()
+	err = ms.query(func(sctx mongo.SessionContext) (err error) {
+		// refresh the key if expired
+		res, err := ms.getCol(ColDdp).UpdateOne(sctx,
+			bson.M{"key": key, "expiresat": bson.M{"$lte": now}},
+			bson.M{"$set": bson.M{"expiresat": now.Add(ttl)}})
+		if err != nil {
+			return
+		}
+		if res.MatchedCount != 0 {
+			stored = true
+			return
+		}
+		if _, err = ms.getCol(ColDdp).InsertOne(sctx,
+			bson.M{"key": key, "expiresat": now.Add(ttl)}); err != nil {
+			if strings.Contains(err.Error(), "E11000") { // Mongo returns E11000 when key is duplicated
+				err = nil
+			}
+			return
+		}
+		stored = true
+		return
+	})
+	return
+}
+
+func (ms *MongoStorage) RemoveDedupKeyDrv(key string) (err error) {
+	return ms.query(func(sctx mongo.SessionContext) (err error) {
+		_, err = ms.getCol(ColDdp).DeleteOne(sctx, bson.M{"key": key})
+		return err
+	})
+}
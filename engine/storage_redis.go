@@ -514,7 +514,7 @@ func (rs *RedisStorage) RemoveRatingProfileDrv(key string) error {
 func (rs *RedisStorage) GetDestinationDrv(key string, skipCache bool,
 	transactionID string) (dest *Destination, err error) {
 	if !skipCache {
-		if x, ok := Cache.Get(utils.CacheDestinations, key); ok {
+		if x, ok := cacheGet(utils.CacheDestinations, key); ok {
 			if x == nil {
 				return nil, utils.ErrNotFound
 			}
@@ -568,7 +568,7 @@ func (rs *RedisStorage) SetDestinationDrv(dest *Destination, transactionID strin
 func (rs *RedisStorage) GetReverseDestinationDrv(key string,
 	skipCache bool, transactionID string) (ids []string, err error) {
 	if !skipCache {
-		if x, ok := Cache.Get(utils.CacheReverseDestinations, key); ok {
+		if x, ok := cacheGet(utils.CacheReverseDestinations, key); ok {
 			if x == nil {
 				return nil, utils.ErrNotFound
 			}
@@ -1000,7 +1000,7 @@ func (rs *RedisStorage) GetAllActionPlansDrv() (ats map[string]*ActionPlan, err
 func (rs *RedisStorage) GetAccountActionPlansDrv(acntID string, skipCache bool,
 	transactionID string) (aPlIDs []string, err error) {
 	if !skipCache {
-		if x, ok := Cache.Get(utils.CacheAccountActionPlans, acntID); ok {
+		if x, ok := cacheGet(utils.CacheAccountActionPlans, acntID); ok {
 			if x == nil {
 				return nil, utils.ErrNotFound
 			}
@@ -1684,3 +1684,40 @@ func (rs *RedisStorage) SetLoadIDsDrv(loadIDs map[string]int64) error {
 func (rs *RedisStorage) RemoveLoadIDsDrv() (err error) {
 	return rs.Cmd(redis_DEL, utils.LoadIDs).Err
 }
+
+func (rs *RedisStorage) GetSessionsBackupDrv(nodeID string) (ss []*StoredSession, err error) {
+	var values []byte
+	if values, err = rs.Cmd(redis_GET, utils.SessionsBackupPrefix+nodeID).Bytes(); err != nil {
+		if err == redis.ErrRespNil {
+			err = utils.ErrNotFound
+		}
+		return
+	}
+	err = rs.ms.Unmarshal(values, &ss)
+	return
+}
+
+func (rs *RedisStorage) SetSessionsBackupDrv(nodeID string, ss []*StoredSession) (err error) {
+	var result []byte
+	if result, err = rs.ms.Marshal(ss); err != nil {
+		return
+	}
+	return rs.Cmd(redis_SET, utils.SessionsBackupPrefix+nodeID, result).Err
+}
+
+func (rs *RedisStorage) RemoveSessionsBackupDrv(nodeID string) (err error) {
+	return rs.Cmd(redis_DEL, utils.SessionsBackupPrefix+nodeID).Err
+}
+
+func (rs *RedisStorage) SetDedupKeyDrv(key string, ttl time.Duration) (stored bool, err error) {
+	rpl := rs.Cmd(redis_SET, utils.DedupPrefix+key, time.Now().Unix(),
+		"PX", ttl.Nanoseconds()/int64(time.Millisecond), "NX")
+	if err = rpl.Err; err != nil {
+		return
+	}
+	return !rpl.IsType(redis.Nil), nil // NX replies nil if the key exists
+}
+
+func (rs *RedisStorage) RemoveDedupKeyDrv(key string) (err error) {
+	return rs.Cmd(redis_DEL, utils.DedupPrefix+key).Err
+}
@@ -466,7 +466,8 @@ func (spS *SupplierService) populateSortingData(ev *utils.CGREvent, spl *Supplie
 		nM.Set([]string{utils.MetaVars}, sortedSpl.SortingData, false, false)
 
 		for _, rule := range spl.lazyCheckRules { // verify the rules remaining from PartialPass
-			if pass, err = rule.Pass(newDynamicDP(spS.cgrcfg, spS.connMgr, ev.Tenant, nM)); err != nil {
+			if pass, err = spS.filterS.PassRule(ev.Tenant, rule,
+				newDynamicDP(spS.cgrcfg, spS.connMgr, ev.Tenant, nM)); err != nil {
 				return nil, false, err
 			} else if !pass {
 				return nil, false, nil
@@ -0,0 +1,319 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+package ers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/streadway/amqp"
+)
+
+const (
+	defaultAMQPQueueID      = "cgrates_cdrs"
+	defaultAMQPExchangeType = "direct"
+)
+
+// errReaderStopped is returned when the reader is stopped before the message processing finished
+var errReaderStopped = errors.New("reader stopped")
+
+// NewAMQPER return a new AMQP event reader
+func NewAMQPER(cfg *config.CGRConfig, cfgIdx int,
+	rdrEvents chan *erEvent, rdrErr chan error,
+	fltrS *engine.FilterS, rdrExit chan struct{}) (er EventReader, err error) {
+	rdr := &AMQPER{
+		cgrCfg:    cfg,
+		cfgIdx:    cfgIdx,
+		fltrS:     fltrS,
+		rdrEvents: rdrEvents,
+		rdrExit:   rdrExit,
+		rdrErr:    rdrErr,
+	}
+	if concReq := rdr.Config().ConcurrentReqs; concReq != -1 {
+		rdr.cap = make(chan struct{}, concReq)
+		for i := 0; i < concReq; i++ {
+			rdr.cap <- struct{}{}
+		}
+		rdr.prefetchCount = concReq
+	}
+	er = rdr
+	err = rdr.setURL(rdr.Config().SourcePath)
+	return
+}
+
+// AMQPER implements EventReader interface for AMQP 0.9.1 messages
+// the messages are acknowledged only after they were successfully processed
+type AMQPER struct {
+	cgrCfg *config.CGRConfig
+	cfgIdx int // index of config instance within ERsCfg.Readers
+	fltrS  *engine.FilterS
+
+	dialURL         string
+	queueID         string
+	exchange        string
+	exchangeType    string
+	routingKey      string
+	prefetchCount   int
+	deadLetterQueue string // failed messages are published here
+
+	rdrEvents chan *erEvent // channel to dispatch the events created to
+	rdrExit   chan struct{}
+	rdrErr    chan error
+	cap       chan struct{}
+}
+
+// Config returns the curent configuration
+func (rdr *AMQPER) Config() *config.EventReaderCfg {
+	return rdr.cgrCfg.ERsCfg().Readers[rdr.cfgIdx]
+}
+
+// Serve will start the gorutines needed to consume the AMQP queue
+func (rdr *AMQPER) Serve() (err error) {
+	if rdr.Config().RunDelay == time.Duration(0) { // 0 disables the automatic read, maybe done per API
+		return
+	}
+	var conn *amqp.Connection
+	if conn, err = amqp.Dial(rdr.dialURL); err != nil {
+		return
+	}
+	var chn *amqp.Channel
+	var msgs <-chan amqp.Delivery
+	if chn, err = conn.Channel(); err != nil {
+		conn.Close()
+		return
+	}
+	if msgs, err = rdr.consume(chn); err != nil {
+		conn.Close()
+		return
+	}
+	go func() { // close the connection when the reader is stopped
+		select {
+		case <-rdr.rdrExit:
+			utils.Logger.Info(
+				fmt.Sprintf("<%s> stop monitoring amqp queue <%s>",
+					utils.ERs, rdr.queueID))
+			conn.Close()
+			return
+		}
+	}()
+	go rdr.readLoop(chn, msgs) // read until the connection is closed
+	return
+}
+
+// consume declares the queues and starts consuming
+func (rdr *AMQPER) consume(chn *amqp.Channel) (msgs <-chan amqp.Delivery, err error) {
+	if err = chn.Qos(rdr.prefetchCount, 0, false); err != nil {
+		return
+	}
+	if rdr.exchange != utils.EmptyString {
+		if err = chn.ExchangeDeclare(
+			rdr.exchange,     // name
+			rdr.exchangeType, // type
+			true,             // durable
+			false,            // audo-delete
+			false,            // internal
+			false,            // no-wait
+			nil,              // args
+		); err != nil {
+			return
+		}
+	}
+	if _, err = chn.QueueDeclare(
+		rdr.queueID, // name
+		true,        // durable
+		false,       // auto-delete
+		false,       // exclusive
+		false,       // no-wait
+		nil,         // args
+	); err != nil {
+		return
+	}
+	if rdr.exchange != utils.EmptyString {
+		if err = chn.QueueBind(
+			rdr.queueID,    // queue
+			rdr.routingKey, // key
+			rdr.exchange,   // exchange
+			false,          // no-wait
+			nil,            // args
+		); err != nil {
+			return
+		}
+	}
+	if rdr.deadLetterQueue != utils.EmptyString {
+		if _, err = chn.QueueDeclare(
+			rdr.deadLetterQueue, // name
+			true,                // durable
+			false,               // auto-delete
+			false,               // exclusive
+			false,               // no-wait
+			nil,                 // args
+		); err != nil {
+			return
+		}
+	}
+	return chn.Consume(
+		rdr.queueID,       // queue
+		utils.EmptyString, // consumer
+		false,             // auto-ack
+		false,             // exclusive
+		false,             // no-local
+		false,             // no-wait
+		nil,               // args
+	)
+}
+
+func (rdr *AMQPER) readLoop(chn *amqp.Channel, msgs <-chan amqp.Delivery) {
+	for {
+		if rdr.Config().ConcurrentReqs != -1 {
+			<-rdr.cap // do not try to read if the limit is reached
+		}
+		msg, ok := <-msgs
+		if !ok {
+			select {
+			case <-rdr.rdrExit: // closed by us
+			default:
+				rdr.rdrErr <- fmt.Errorf("amqp consumer for queue <%s> closed", rdr.queueID)
+			}
+			return
+		}
+		go func(msg amqp.Delivery) {
+			if rdr.Config().ConcurrentReqs != -1 {
+				defer func() { rdr.cap <- struct{}{} }()
+			}
+			if err := rdr.processMessage(msg.Body); err != nil {
+				if err == errReaderStopped { // unacknowledged message will be redelivered
+					return
+				}
+				utils.Logger.Warning(
+					fmt.Sprintf("<%s> processing message %s error: %s",
+						utils.ERs, msg.MessageId, err.Error()))
+				rdr.deadLetter(chn, msg, err)
+				return
+			}
+			if err := msg.Ack(false); err != nil {
+				utils.Logger.Warning(
+					fmt.Sprintf("<%s> acknowledging message %s error: %s",
+						utils.ERs, msg.MessageId, err.Error()))
+			}
+			if rdr.Config().ProcessedPath != utils.EmptyString { // post it
+				if err := engine.PostersCache.PostAMQP(rdr.Config().ProcessedPath,
+					rdr.cgrCfg.GeneralCfg().PosterAttempts, msg.Body); err != nil {
+					utils.Logger.Warning(
+						fmt.Sprintf("<%s> writing message %s error: %s",
+							utils.ERs, msg.MessageId, err.Error()))
+				}
+			}
+		}(msg)
+	}
+}
+
+// deadLetter publishes the failed message into the dead letter queue
+// without one, the message is rejected so the broker can apply its own dead letter policy
+func (rdr *AMQPER) deadLetter(chn *amqp.Channel, msg amqp.Delivery, procErr error) {
+	if rdr.deadLetterQueue == utils.EmptyString {
+		if err := msg.Nack(false, false); err != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> rejecting message %s error: %s",
+					utils.ERs, msg.MessageId, err.Error()))
+		}
+		return
+	}
+	if err := chn.Publish(
+		utils.EmptyString,   // exchange
+		rdr.deadLetterQueue, // routing key
+		false,               // mandatory
+		false,               // immediate
+		amqp.Publishing{
+			Headers:      amqp.Table{utils.Error: procErr.Error()},
+			DeliveryMode: amqp.Persistent,
+			ContentType:  msg.ContentType,
+			MessageId:    msg.MessageId,
+			Body:         msg.Body,
+		}); err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> publishing message %s to dead letter queue <%s> error: %s",
+				utils.ERs, msg.MessageId, rdr.deadLetterQueue, err.Error()))
+		msg.Nack(false, true) // requeue so we do not lose it
+		return
+	}
+	msg.Ack(false)
+}
+
+func (rdr *AMQPER) processMessage(msg []byte) (err error) {
+	var decodedMessage map[string]interface{}
+	if err = json.Unmarshal(msg, &decodedMessage); err != nil {
+		return
+	}
+	_, err = dispatchEvent(decodedMessage, rdr.Config(), rdr.cgrCfg,
+		rdr.fltrS, rdr.rdrEvents, rdr.rdrExit, context.Background(), true)
+	return
+}
+
+func (rdr *AMQPER) setURL(dialURL string) (err error) {
+	var u *url.URL
+	if u, err = url.Parse(dialURL); err != nil {
+		return
+	}
+	qry := u.Query()
+	q := url.Values{} // keep only the connection params
+	for key, vals := range qry {
+		switch key {
+		case utils.AMQPQueueID, utils.AMQPExchange, utils.AMQPExchangeType, utils.AMQPRoutingKey,
+			utils.AMQPPrefetchCount, utils.AMQPDeadLetterQueue:
+		default:
+			q[key] = vals
+		}
+	}
+	rdr.dialURL = strings.Split(dialURL, "?")[0]
+	if len(q) != 0 {
+		rdr.dialURL += "?" + q.Encode()
+	}
+	rdr.queueID = defaultAMQPQueueID
+	if vals, has := qry[utils.AMQPQueueID]; has && len(vals) != 0 {
+		rdr.queueID = vals[0]
+	}
+	rdr.routingKey = rdr.queueID
+	if vals, has := qry[utils.AMQPRoutingKey]; has && len(vals) != 0 {
+		rdr.routingKey = vals[0]
+	}
+	if vals, has := qry[utils.AMQPExchange]; has && len(vals) != 0 {
+		rdr.exchange = vals[0]
+		rdr.exchangeType = defaultAMQPExchangeType
+	}
+	if vals, has := qry[utils.AMQPExchangeType]; has && len(vals) != 0 {
+		rdr.exchangeType = vals[0]
+	}
+	if vals, has := qry[utils.AMQPDeadLetterQueue]; has && len(vals) != 0 {
+		rdr.deadLetterQueue = vals[0]
+	}
+	if vals, has := qry[utils.AMQPPrefetchCount]; has && len(vals) != 0 {
+		if rdr.prefetchCount, err = strconv.Atoi(vals[0]); err != nil {
+			return fmt.Errorf("invalid %s: <%s>", utils.AMQPPrefetchCount, vals[0])
+		}
+	}
+	return
+}
@@ -0,0 +1,513 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package ers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+const (
+	checkpointMaxChanges      = 100         // changes kept in memory before persisting the checkpoint
+	checkpointPersistInterval = time.Second // interval to persist the changes kept in memory
+	maxInFlightRecords        = 100         // records of a file sent to ERs without waiting for them to be processed
+	fileFinished              = -1          // offset of the files processed out of an archive not finished yet
+	archivedFileSep           = "/"         // separates the archive key from the file name in the checkpoint keys
+)
+
+// erCheckpoints keeps the checkpoints of all readers
+var erCheckpoints = newCheckpointStore()
+
+// newCheckpointStore returns an empty checkpointStore
+func newCheckpointStore() *checkpointStore {
+	return &checkpointStore{
+		cps:      make(map[string]*utils.ERsCheckpoint),
+		pending:  make(map[string]*pendingChanges),
+		archives: make(map[string]*archiveCheckpoint),
+	}
+}
+
+// checkpointStore caches the checkpoints, persisting them as <checkpoint_path>/<ReaderID>.json
+// the file offsets are persisted every checkpointMaxChanges, every checkpointPersistInterval (see flush)
+// and once a file was finished or the checkpoint reset, the SQL keys on each change
+// the file is replaced atomically so a crash leaves either the old or the new version
+type checkpointStore struct {
+	sync.RWMutex
+	cps      map[string]*utils.ERsCheckpoint // indexed on ReaderID
+	pending  map[string]*pendingChanges      // changes not persisted yet, indexed on ReaderID
+	archives map[string]*archiveCheckpoint   // archives being processed, indexed on the folder they were extracted to
+}
+
+// pendingChanges counts the changes of a checkpoint not persisted yet
+type pendingChanges struct {
+	dir   string
+	count int
+}
+
+// archiveCheckpoint identifies the archive the files being processed were extracted out of
+type archiveCheckpoint struct {
+	key   string // checkpoint key of the archive
+	dir   string // checkpoint_path, known once the first file out of the archive is checkpointed
+	rdrID string
+}
+
+// checkpointFile returns the path of the file persisting the checkpoint
+func checkpointFile(dir, rdrID string) string {
+	return path.Join(dir, url.PathEscape(rdrID)+utils.JSNSuffix)
+}
+
+// fileCheckpointKey identifies the file by name, size and modification time
+// so a different file reusing the name is read from start
+func fileCheckpointKey(fName string, info os.FileInfo) string {
+	return utils.ConcatenatedKey(fName,
+		strconv.FormatInt(info.Size(), 10),
+		strconv.FormatInt(info.ModTime().UnixNano(), 10))
+}
+
+// checkpointSource returns the key and name of the file out of the source folder the checkpoint key belongs to
+// the files extracted out of archives are checkpointed as <archive key>/<file name>
+func checkpointSource(key string) (srcKey, fName string) {
+	srcKey = key
+	if idx := strings.Index(key, archivedFileSep); idx != -1 {
+		srcKey = key[:idx]
+	}
+	fName = srcKey
+	for i := 0; i < 2; i++ { // remove the size and modification time
+		if idx := strings.LastIndex(fName, utils.CONCATENATED_KEY_SEP); idx != -1 {
+			fName = fName[:idx]
+		}
+	}
+	return
+}
+
+// get returns the checkpoint out of cache, loading it from file first time
+// should be called under lock
+func (cs *checkpointStore) get(dir, rdrID string) (cp *utils.ERsCheckpoint, err error) {
+	var has bool
+	if cp, has = cs.cps[rdrID]; has {
+		return
+	}
+	cp = &utils.ERsCheckpoint{ReaderID: rdrID}
+	var content []byte
+	if content, err = ioutil.ReadFile(checkpointFile(dir, rdrID)); err != nil {
+		if os.IsNotExist(err) {
+			err = nil
+			cs.cps[rdrID] = cp
+		}
+		return
+	}
+	if err = json.Unmarshal(content, cp); err != nil {
+		return nil, fmt.Errorf("decoding checkpoint for reader <%s>: %s", rdrID, err.Error())
+	}
+	cs.cps[rdrID] = cp
+	return
+}
+
+// persist writes the checkpoint to file, removing the file for empty checkpoints
+// should be called under lock
+func (cs *checkpointStore) persist(dir string, cp *utils.ERsCheckpoint) (err error) {
+	delete(cs.pending, cp.ReaderID)
+	fPath := checkpointFile(dir, cp.ReaderID)
+	if len(cp.Files) == 0 && cp.LastKey == utils.EmptyString {
+		if err = os.Remove(fPath); os.IsNotExist(err) {
+			err = nil
+		}
+		return
+	}
+	cp.UpdatedAt = time.Now()
+	var content []byte
+	if content, err = json.Marshal(cp); err != nil {
+		return
+	}
+	tmpPath := path.Join(dir, "."+path.Base(fPath))
+	if err = ioutil.WriteFile(tmpPath, content, 0644); err != nil {
+		return
+	}
+	return os.Rename(tmpPath, fPath)
+}
+
+// update counts one change of the checkpoint, persisting it once checkpointMaxChanges were counted
+// should be called under lock
+func (cs *checkpointStore) update(dir string, cp *utils.ERsCheckpoint) (err error) {
+	pc, has := cs.pending[cp.ReaderID]
+	if !has {
+		pc = &pendingChanges{dir: dir}
+		cs.pending[cp.ReaderID] = pc
+	}
+	if pc.count++; pc.count < checkpointMaxChanges {
+		return
+	}
+	return cs.persist(dir, cp)
+}
+
+// flush persists the changes not persisted yet, called periodically and on shutdown by ERService
+func (cs *checkpointStore) flush() {
+	cs.Lock()
+	defer cs.Unlock()
+	for rdrID, pc := range cs.pending {
+		if err := cs.persist(pc.dir, cs.cps[rdrID]); err != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> reader <%s> saving checkpoint, error: %s",
+					utils.ERs, rdrID, err.Error()))
+		}
+	}
+}
+
+// fileOffset returns the number of records already processed out of the file
+func (cs *checkpointStore) fileOffset(dir, rdrID, key string) (offset int64, err error) {
+	cs.Lock()
+	defer cs.Unlock()
+	var cp *utils.ERsCheckpoint
+	if cp, err = cs.get(dir, rdrID); err != nil {
+		return
+	}
+	return cp.Files[key], nil
+}
+
+// setFileOffset stores the number of records processed out of the file
+func (cs *checkpointStore) setFileOffset(dir, rdrID, key string, offset int64) (err error) {
+	cs.Lock()
+	defer cs.Unlock()
+	var cp *utils.ERsCheckpoint
+	if cp, err = cs.get(dir, rdrID); err != nil {
+		return
+	}
+	if cp.Files == nil {
+		cp.Files = make(map[string]int64)
+	}
+	cp.Files[key] = offset
+	return cs.update(dir, cp)
+}
+
+// finishFile removes the file out of checkpoint once it was fully processed
+// the files extracted out of archives are kept as finished until the archive is processed (see removeArchive)
+func (cs *checkpointStore) finishFile(dir, rdrID, key string, archived bool) (err error) {
+	cs.Lock()
+	defer cs.Unlock()
+	var cp *utils.ERsCheckpoint
+	if cp, err = cs.get(dir, rdrID); err != nil {
+		return
+	}
+	if archived {
+		if cp.Files == nil {
+			cp.Files = make(map[string]int64)
+		}
+		cp.Files[key] = fileFinished
+	} else if _, has := cp.Files[key]; !has {
+		return
+	} else {
+		delete(cp.Files, key)
+	}
+	return cs.persist(dir, cp)
+}
+
+// lastKey returns the key of the last row processed
+func (cs *checkpointStore) lastKey(dir, rdrID string) (key string, err error) {
+	cs.Lock()
+	defer cs.Unlock()
+	var cp *utils.ERsCheckpoint
+	if cp, err = cs.get(dir, rdrID); err != nil {
+		return
+	}
+	return cp.LastKey, nil
+}
+
+// setLastKey stores the key of the last row processed
+// persisted right away since the row is removed afterwards
+func (cs *checkpointStore) setLastKey(dir, rdrID, key string) (err error) {
+	cs.Lock()
+	defer cs.Unlock()
+	var cp *utils.ERsCheckpoint
+	if cp, err = cs.get(dir, rdrID); err != nil {
+		return
+	}
+	cp.LastKey = key
+	return cs.persist(dir, cp)
+}
+
+// checkpoint returns a copy of the reader checkpoint
+func (cs *checkpointStore) checkpoint(dir, rdrID string) (cp *utils.ERsCheckpoint, err error) {
+	cs.Lock()
+	defer cs.Unlock()
+	if cp, err = cs.get(dir, rdrID); err != nil {
+		return
+	}
+	if len(cp.Files) == 0 && cp.LastKey == utils.EmptyString {
+		return nil, utils.ErrNotFound
+	}
+	return cp.Clone(), nil
+}
+
+// reset removes the reader checkpoint or only the one of a file if fName is not empty
+func (cs *checkpointStore) reset(dir, rdrID, fName string) (err error) {
+	cs.Lock()
+	defer cs.Unlock()
+	var cp *utils.ERsCheckpoint
+	if cp, err = cs.get(dir, rdrID); err != nil {
+		return
+	}
+	if fName != utils.EmptyString {
+		var has bool
+		for key := range cp.Files {
+			if _, srcName := checkpointSource(key); srcName == fName {
+				delete(cp.Files, key)
+				has = true
+			}
+		}
+		if !has {
+			return utils.ErrNotFound
+		}
+	} else {
+		if len(cp.Files) == 0 && cp.LastKey == utils.EmptyString {
+			return utils.ErrNotFound
+		}
+		cp.Files = nil
+		cp.LastKey = utils.EmptyString
+	}
+	return cs.persist(dir, cp)
+}
+
+// setArchive registers the folder the archive with the checkpoint key was extracted to
+func (cs *checkpointStore) setArchive(extractDir, key string) {
+	cs.Lock()
+	cs.archives[extractDir] = &archiveCheckpoint{key: key}
+	cs.Unlock()
+}
+
+// archiveKey returns the checkpoint key of the archive extracted to fPath, empty if fPath is not such folder
+func (cs *checkpointStore) archiveKey(fPath, dir, rdrID string) (key string) {
+	cs.Lock()
+	defer cs.Unlock()
+	arch, has := cs.archives[fPath]
+	if !has {
+		return
+	}
+	arch.dir, arch.rdrID = dir, rdrID
+	return arch.key
+}
+
+// removeArchive unregisters the archive, removing the checkpoints of its files if it was fully processed
+func (cs *checkpointStore) removeArchive(extractDir string, processed bool) {
+	cs.Lock()
+	defer cs.Unlock()
+	arch, has := cs.archives[extractDir]
+	if !has {
+		return
+	}
+	delete(cs.archives, extractDir)
+	if !processed || arch.dir == utils.EmptyString {
+		return
+	}
+	cp, err := cs.get(arch.dir, arch.rdrID)
+	if err != nil {
+		return
+	}
+	var changed bool
+	for key := range cp.Files {
+		if strings.HasPrefix(key, arch.key+archivedFileSep) {
+			delete(cp.Files, key)
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+	if err = cs.persist(arch.dir, cp); err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> reader <%s> saving checkpoint, error: %s",
+				utils.ERs, arch.rdrID, err.Error()))
+	}
+}
+
+// fileCheckpoint tracks the progress of one file, nil when checkpoints are disabled
+// the records are sent to ERs without waiting for them to be processed, the offset
+// moving after a record only once it and all the ones before it were processed
+type fileCheckpoint struct {
+	dir      string
+	rdrID    string
+	key      string
+	archived bool                // extracted out of an archive
+	offset   int64               // records already processed
+	read     int64               // records dispatched or skipped
+	inFlight []*dispatchedRecord // records sent to ERs, in order
+}
+
+// dispatchedRecord is a record sent to ERs, waiting to be processed
+type dispatchedRecord struct {
+	recNr   int64
+	procErr chan error
+}
+
+// newFileCheckpoint returns the checkpoint of the file, nil if checkpoint_path is not configured
+func newFileCheckpoint(dir, rdrID, fPath, fName string) (fc *fileCheckpoint) {
+	if dir == utils.EmptyString {
+		return
+	}
+	fc = &fileCheckpoint{dir: dir, rdrID: rdrID, key: fName}
+	if archKey := erCheckpoints.archiveKey(fPath, dir, rdrID); archKey != utils.EmptyString {
+		fc.key = archKey + archivedFileSep + fName // the names are unique inside the archive
+		fc.archived = true
+	} else if info, err := os.Stat(path.Join(fPath, fName)); err == nil {
+		fc.key = fileCheckpointKey(fName, info)
+	}
+	var err error
+	if fc.offset, err = erCheckpoints.fileOffset(dir, rdrID, fc.key); err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> reader <%s> reading checkpoint for file <%s>, error: %s",
+				utils.ERs, rdrID, fName, err.Error()))
+	} else if fc.offset == fileFinished {
+		utils.Logger.Info(
+			fmt.Sprintf("<%s> reader <%s> skipping file <%s> processed before restart",
+				utils.ERs, rdrID, fName))
+	} else if fc.offset != 0 {
+		utils.Logger.Info(
+			fmt.Sprintf("<%s> reader <%s> resuming file <%s> after record %d",
+				utils.ERs, rdrID, fName, fc.offset))
+	}
+	return
+}
+
+// processed returns true if the record was processed before restart
+func (fc *fileCheckpoint) processed(recNr int) bool {
+	return fc != nil &&
+		(fc.offset == fileFinished || int64(recNr) <= fc.offset)
+}
+
+// set marks the records up to recNr as read, the ones not dispatched being considered processed
+func (fc *fileCheckpoint) set(recNr int) {
+	if fc == nil {
+		return
+	}
+	if int64(recNr) > fc.read {
+		fc.read = int64(recNr)
+	}
+	fc.collect(maxInFlightRecords)
+}
+
+// dispatch sends the event of record recNr to ERs, waiting for the processing
+// only when maxInFlightRecords are already waiting
+func (fc *fileCheckpoint) dispatch(rdrEvents chan *erEvent, ev *erEvent, recNr int) {
+	if fc == nil {
+		rdrEvents <- ev
+		return
+	}
+	ev.procErr = make(chan error, 1)
+	rdrEvents <- ev
+	fc.inFlight = append(fc.inFlight, &dispatchedRecord{recNr: int64(recNr), procErr: ev.procErr})
+	fc.set(recNr)
+}
+
+// collect drops the processed records out of inFlight, waiting while more than maxInFlight remain,
+// and moves the offset after the records processed in order
+func (fc *fileCheckpoint) collect(maxInFlight int) {
+	for len(fc.inFlight) != 0 {
+		if len(fc.inFlight) > maxInFlight {
+			<-fc.inFlight[0].procErr
+		} else if len(fc.inFlight[0].procErr) == 0 { // not processed yet
+			break
+		} else {
+			<-fc.inFlight[0].procErr
+		}
+		fc.inFlight = fc.inFlight[1:]
+	}
+	done := fc.read
+	if len(fc.inFlight) != 0 {
+		done = fc.inFlight[0].recNr - 1
+	}
+	if fc.offset == fileFinished || done <= fc.offset {
+		return
+	}
+	fc.offset = done
+	if err := erCheckpoints.setFileOffset(fc.dir, fc.rdrID, fc.key, fc.offset); err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> reader <%s> saving checkpoint for file <%s>, error: %s",
+				utils.ERs, fc.rdrID, fc.key, err.Error()))
+	}
+}
+
+// wait waits for all the records dispatched to be processed
+func (fc *fileCheckpoint) wait() {
+	if fc == nil {
+		return
+	}
+	fc.collect(0)
+}
+
+// finish removes the checkpoint once the file was fully processed
+func (fc *fileCheckpoint) finish() {
+	if fc == nil {
+		return
+	}
+	if err := erCheckpoints.finishFile(fc.dir, fc.rdrID, fc.key, fc.archived); err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> reader <%s> removing checkpoint for file <%s>, error: %s",
+				utils.ERs, fc.rdrID, fc.key, err.Error()))
+	}
+}
+
+// resumeFiles processes the files interrupted by a restart, used by the readers relying on inotify
+// the archives are processed again, skipping the files already processed out of them
+func resumeFiles(dir, rdrID, srcDir string, processFile func(fPath, fName string) error) {
+	if dir == utils.EmptyString {
+		return
+	}
+	cp, err := erCheckpoints.checkpoint(dir, rdrID)
+	if err != nil {
+		if err != utils.ErrNotFound {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> reader <%s> reading checkpoint, error: %s",
+					utils.ERs, rdrID, err.Error()))
+		}
+		return
+	}
+	resumed := make(map[string]struct{})
+	for key := range cp.Files {
+		srcKey, fName := checkpointSource(key)
+		info, err := os.Stat(path.Join(srcDir, fName))
+		if err != nil && !os.IsNotExist(err) {
+			continue
+		}
+		if err != nil || // processed meanwhile or removed by the user
+			fileCheckpointKey(fName, info) != srcKey { // replaced by a different file
+			erCheckpoints.finishFile(dir, rdrID, key, false)
+			continue
+		}
+		if _, has := resumed[fName]; has { // archive with more files checkpointed
+			continue
+		}
+		resumed[fName] = struct{}{}
+		go func(fName string) {
+			if err := processFile(srcDir, fName); err != nil {
+				utils.Logger.Warning(
+					fmt.Sprintf("<%s> resuming file %s, error: %s",
+						utils.ERs, fName, err.Error()))
+			}
+		}(fName)
+	}
+}
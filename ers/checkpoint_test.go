@@ -0,0 +1,490 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+package ers
+
+import (
+	"archive/zip"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestCheckpointStore(t *testing.T) {
+	cpDir, err := ioutil.TempDir(utils.EmptyString, "ers_checkpoints")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(cpDir)
+	erCheckpoints = newCheckpointStore()
+	if _, err = erCheckpoints.checkpoint(cpDir, "rdr/1"); err != utils.ErrNotFound {
+		t.Errorf("Expected %v, received: %v", utils.ErrNotFound, err)
+	}
+	if err = erCheckpoints.setFileOffset(cpDir, "rdr/1", "file1.csv", 10); err != nil {
+		t.Fatal(err)
+	}
+	fPath := path.Join(cpDir, "rdr%2F1.json")
+	if _, err = os.Stat(fPath); !os.IsNotExist(err) { // persisted only after checkpointMaxChanges
+		t.Errorf("Expected checkpoint not persisted yet, received: %v", err)
+	}
+	if err = erCheckpoints.setLastKey(cpDir, "rdr/1", "25"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(fPath); err != nil { // the key is persisted right away
+		t.Fatal(err)
+	}
+	// simulate a restart
+	erCheckpoints = newCheckpointStore()
+	if offset, err := erCheckpoints.fileOffset(cpDir, "rdr/1", "file1.csv"); err != nil {
+		t.Error(err)
+	} else if offset != 10 {
+		t.Errorf("Expected 10, received: %d", offset)
+	}
+	if key, err := erCheckpoints.lastKey(cpDir, "rdr/1"); err != nil {
+		t.Error(err)
+	} else if key != "25" {
+		t.Errorf("Expected 25, received: %s", key)
+	}
+	cp, err := erCheckpoints.checkpoint(cpDir, "rdr/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := map[string]int64{"file1.csv": 10}; cp.ReaderID != "rdr/1" ||
+		!reflect.DeepEqual(exp, cp.Files) || cp.LastKey != "25" {
+		t.Errorf("Unexpected checkpoint: %s", utils.ToJSON(cp))
+	}
+	if err = erCheckpoints.reset(cpDir, "rdr/1", "file2.csv"); err != utils.ErrNotFound {
+		t.Errorf("Expected %v, received: %v", utils.ErrNotFound, err)
+	}
+	if err = erCheckpoints.reset(cpDir, "rdr/1", "file1.csv"); err != nil {
+		t.Error(err)
+	}
+	if err = erCheckpoints.reset(cpDir, "rdr/1", utils.EmptyString); err != nil {
+		t.Error(err)
+	}
+	if _, err = os.Stat(fPath); !os.IsNotExist(err) {
+		t.Errorf("Expected checkpoint file removed, received: %v", err)
+	}
+	if err = erCheckpoints.reset(cpDir, "rdr/1", utils.EmptyString); err != utils.ErrNotFound {
+		t.Errorf("Expected %v, received: %v", utils.ErrNotFound, err)
+	}
+	if err = ioutil.WriteFile(path.Join(cpDir, "rdr2.json"), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = erCheckpoints.fileOffset(cpDir, "rdr2", "file1.csv"); err == nil {
+		t.Error("Expected error for corrupted checkpoint")
+	}
+}
+
+func TestFileCheckpointDisabled(t *testing.T) {
+	var fc *fileCheckpoint
+	if fc = newFileCheckpoint(utils.EmptyString, "rdr1", "/tmp", "file1.csv"); fc != nil {
+		t.Errorf("Expected nil, received: %+v", fc)
+	}
+	if fc.processed(1) {
+		t.Error("Expected record not processed")
+	}
+	fc.set(1)
+	fc.wait()
+	fc.finish()
+	rdrEvents := make(chan *erEvent, 1)
+	fc.dispatch(rdrEvents, &erEvent{}, 1)
+	if ev := <-rdrEvents; ev.procErr != nil {
+		t.Error("Expected no procErr when checkpoints are disabled")
+	}
+}
+
+func TestCSVFileERResumeCheckpoint(t *testing.T) {
+	srcDir, err := ioutil.TempDir(utils.EmptyString, "ers_csv_in")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(srcDir)
+	cpDir, err := ioutil.TempDir(utils.EmptyString, "ers_checkpoints")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(cpDir)
+	procDir, err := ioutil.TempDir(utils.EmptyString, "ers_csv_out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(procDir)
+	erCheckpoints = newCheckpointStore()
+	if err = ioutil.WriteFile(path.Join(srcDir, "file1.csv"),
+		[]byte("1001\n1002\n1003\n1004\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path.Join(srcDir, "file1.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := fileCheckpointKey("file1.csv", info)
+	cfg, _ := config.NewDefaultCGRConfig()
+	cfg.ERsCfg().CheckpointPath = cpDir
+	cfg.ERsCfg().Readers[0].ID = "csv1"
+	cfg.ERsCfg().Readers[0].SourcePath = srcDir
+	cfg.ERsCfg().Readers[0].ProcessedPath = procDir
+	cfg.ERsCfg().Readers[0].Fields = []*config.FCTemplate{
+		{Tag: utils.Account, Path: utils.MetaCgreq + utils.NestingSep + utils.Account, Type: utils.META_COMPOSED,
+			Value: config.NewRSRParsersMustCompile("~*req.0", true, utils.INFIELD_SEP)},
+	}
+	// first two rows were processed before the restart
+	if err = erCheckpoints.setFileOffset(cpDir, "csv1", key, 2); err != nil {
+		t.Fatal(err)
+	}
+	erCheckpoints.flush()
+	erCheckpoints = newCheckpointStore()
+	rdrEvents := make(chan *erEvent)
+	rdr, err := NewCSVFileER(cfg, 0, rdrEvents, make(chan error, 1), new(engine.FilterS), make(chan struct{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	errChan := make(chan error, 1)
+	go func() { errChan <- rdr.(*CSVFileER).processFile(srcDir, "file1.csv") }()
+	for _, exp := range []string{"1003", "1004"} {
+		ev := <-rdrEvents
+		if rcv := ev.cgrEvent.Event[utils.Account]; rcv != exp {
+			t.Errorf("Expected: %s, received: %v", exp, rcv)
+		}
+		// the row is not marked as processed until ERs replies
+		if offset, err := erCheckpoints.fileOffset(cpDir, "csv1", key); err != nil {
+			t.Error(err)
+		} else if offset != 2 && exp == "1003" {
+			t.Errorf("Expected 2, received: %d", offset)
+		}
+		ev.procErr <- nil
+	}
+	if err = <-errChan; err != nil {
+		t.Fatal(err)
+	}
+	if _, err = erCheckpoints.checkpoint(cpDir, "csv1"); err != utils.ErrNotFound {
+		t.Errorf("Expected checkpoint removed, received: %v", err)
+	}
+}
+
+func TestFlatstoreERResumeCheckpoint(t *testing.T) {
+	srcDir, err := ioutil.TempDir(utils.EmptyString, "ers_flatstore_in")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(srcDir)
+	cpDir, err := ioutil.TempDir(utils.EmptyString, "ers_checkpoints")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(cpDir)
+	procDir, err := ioutil.TempDir(utils.EmptyString, "ers_flatstore_out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(procDir)
+	erCheckpoints = newCheckpointStore()
+	if err = ioutil.WriteFile(path.Join(srcDir, "acc_1.log"),
+		[]byte(`INVITE,a,b,1001,200,OK,1436280728
+INVITE,a,b,1002,200,OK,1436280728
+BYE,a,b,1001,200,OK,1436280788
+BYE,a,b,1002,200,OK,1436280758
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path.Join(srcDir, "acc_1.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := fileCheckpointKey("acc_1.log", info)
+	cfg, _ := config.NewDefaultCGRConfig()
+	cfg.ERsCfg().CheckpointPath = cpDir
+	cfg.ERsCfg().Readers[0].ID = "flatstore1"
+	cfg.ERsCfg().Readers[0].SourcePath = srcDir
+	cfg.ERsCfg().Readers[0].ProcessedPath = procDir
+	cfg.ERsCfg().Readers[0].FailedCallsPrefix = "missed_calls"
+	cfg.ERsCfg().Readers[0].Fields = []*config.FCTemplate{
+		{Tag: utils.Account, Path: utils.MetaCgreq + utils.NestingSep + utils.Account, Type: utils.META_COMPOSED,
+			Value: config.NewRSRParsersMustCompile("~*req.3", true, utils.INFIELD_SEP)},
+		{Tag: utils.Usage, Path: utils.MetaCgreq + utils.NestingSep + utils.Usage, Type: utils.META_COMPOSED,
+			Value: config.NewRSRParsersMustCompile("~*req.7", true, utils.INFIELD_SEP)},
+	}
+	// the first call was posted before the restart, its INVITE being read again for pairing the second one
+	if err = erCheckpoints.setFileOffset(cpDir, "flatstore1", key, 3); err != nil {
+		t.Fatal(err)
+	}
+	erCheckpoints.flush()
+	erCheckpoints = newCheckpointStore()
+	rdrEvents := make(chan *erEvent)
+	rdr, err := NewFlatstoreER(cfg, 0, rdrEvents, make(chan error, 1), new(engine.FilterS), make(chan struct{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	errChan := make(chan error, 1)
+	go func() { errChan <- rdr.(*FlatstoreER).processFile(srcDir, "acc_1.log") }()
+	ev := <-rdrEvents
+	if rcv := ev.cgrEvent.Event[utils.Account]; rcv != "1002" {
+		t.Errorf("Expected: 1002, received: %v", rcv)
+	}
+	if rcv := ev.cgrEvent.Event[utils.Usage]; rcv != "30" {
+		t.Errorf("Expected: 30, received: %v", rcv)
+	}
+	ev.procErr <- nil
+	if err = <-errChan; err != nil {
+		t.Fatal(err)
+	}
+	if _, err = erCheckpoints.checkpoint(cpDir, "flatstore1"); err != utils.ErrNotFound {
+		t.Errorf("Expected checkpoint removed, received: %v", err)
+	}
+}
+
+func TestERServiceV1Checkpoint(t *testing.T) {
+	cpDir, err := ioutil.TempDir(utils.EmptyString, "ers_checkpoints")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(cpDir)
+	erCheckpoints = newCheckpointStore()
+	cfg, _ := config.NewDefaultCGRConfig()
+	erS := NewERService(cfg, nil, nil, nil, nil, nil)
+	var cp utils.ERsCheckpoint
+	var reply string
+	if err = erS.V1GetCheckpoint(new(utils.ArgsERsCheckpoint), &cp); err == nil ||
+		err.Error() != utils.NewErrMandatoryIeMissing("ReaderID").Error() {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	args := &utils.ArgsERsCheckpoint{ReaderID: "csv1"}
+	if err = erS.V1GetCheckpoint(args, &cp); err != utils.ErrNotFound {
+		t.Errorf("Expected %v, received: %v", utils.ErrNotFound, err)
+	}
+	cfg.ERsCfg().CheckpointPath = cpDir
+	if err = erCheckpoints.setFileOffset(cpDir, "csv1", "file1.csv", 3); err != nil {
+		t.Fatal(err)
+	}
+	if err = erS.V1GetCheckpoint(args, &cp); err != nil {
+		t.Error(err)
+	} else if exp := map[string]int64{"file1.csv": 3}; !reflect.DeepEqual(exp, cp.Files) {
+		t.Errorf("Expected: %v, received: %v", exp, cp.Files)
+	}
+	if err = erS.V1ResetCheckpoint(args, &reply); err != nil {
+		t.Error(err)
+	} else if reply != utils.OK {
+		t.Errorf("Expected OK, received: %s", reply)
+	}
+	if err = erS.V1GetCheckpoint(args, &cp); err != utils.ErrNotFound {
+		t.Errorf("Expected %v, received: %v", utils.ErrNotFound, err)
+	}
+}
+
+func TestCheckpointSource(t *testing.T) {
+	for _, tc := range []struct {
+		key, srcKey, fName string
+	}{
+		{"file1.csv", "file1.csv", "file1.csv"},
+		{"file1.csv:24:1600000000", "file1.csv:24:1600000000", "file1.csv"},
+		{"cdrs:1.csv:24:1600000000", "cdrs:1.csv:24:1600000000", "cdrs:1.csv"},
+		{"cdrs.zip:24:1600000000/switch1_cdrs.csv", "cdrs.zip:24:1600000000", "cdrs.zip"},
+	} {
+		if srcKey, fName := checkpointSource(tc.key); srcKey != tc.srcKey || fName != tc.fName {
+			t.Errorf("For %q expected: %q %q, received: %q %q", tc.key, tc.srcKey, tc.fName, srcKey, fName)
+		}
+	}
+}
+
+func TestFileCheckpointInFlight(t *testing.T) {
+	cpDir, err := ioutil.TempDir(utils.EmptyString, "ers_checkpoints")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(cpDir)
+	erCheckpoints = newCheckpointStore()
+	fc := newFileCheckpoint(cpDir, "rdr1", cpDir, "file1.csv")
+	rdrEvents := make(chan *erEvent, 3)
+	fc.dispatch(rdrEvents, &erEvent{}, 1)
+	fc.set(2) // filtered out
+	fc.dispatch(rdrEvents, &erEvent{}, 3)
+	ev1, ev3 := <-rdrEvents, <-rdrEvents
+	ev3.procErr <- nil
+	fc.set(3)
+	if fc.offset != 0 { // record 1 not processed yet
+		t.Errorf("Expected 0, received: %d", fc.offset)
+	}
+	ev1.procErr <- nil
+	fc.wait()
+	if offset, err := erCheckpoints.fileOffset(cpDir, "rdr1", "file1.csv"); err != nil {
+		t.Error(err)
+	} else if offset != 3 {
+		t.Errorf("Expected 3, received: %d", offset)
+	}
+	if _, err = os.Stat(checkpointFile(cpDir, "rdr1")); !os.IsNotExist(err) {
+		t.Errorf("Expected checkpoint not persisted yet, received: %v", err)
+	}
+	fc.finish()
+	if _, err = erCheckpoints.checkpoint(cpDir, "rdr1"); err != utils.ErrNotFound {
+		t.Errorf("Expected %v, received: %v", utils.ErrNotFound, err)
+	}
+}
+
+func TestCSVFileERResumeArchive(t *testing.T) {
+	srcDir, err := ioutil.TempDir(utils.EmptyString, "ers_csv_in")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(srcDir)
+	cpDir, err := ioutil.TempDir(utils.EmptyString, "ers_checkpoints")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(cpDir)
+	procDir, err := ioutil.TempDir(utils.EmptyString, "ers_csv_out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(procDir)
+	zipFile, err := os.Create(path.Join(srcDir, "cdrs.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(zipFile)
+	for name, content := range map[string]string{
+		"cdrs1.csv": "1001\n1002\n",
+		"cdrs2.csv": "1003\n1004\n",
+	} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	zw.Close()
+	zipFile.Close()
+	info, err := os.Stat(path.Join(srcDir, "cdrs.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	archKey := fileCheckpointKey("cdrs.zip", info)
+	cfg, _ := config.NewDefaultCGRConfig()
+	cfg.ERsCfg().CheckpointPath = cpDir
+	cfg.ERsCfg().Readers[0].ID = "csv1"
+	cfg.ERsCfg().Readers[0].SourcePath = srcDir
+	cfg.ERsCfg().Readers[0].ProcessedPath = procDir
+	cfg.ERsCfg().Readers[0].Fields = []*config.FCTemplate{
+		{Tag: utils.Account, Path: utils.MetaCgreq + utils.NestingSep + utils.Account, Type: utils.META_COMPOSED,
+			Value: config.NewRSRParsersMustCompile("~*req.0", true, utils.INFIELD_SEP)},
+	}
+	// cdrs1.csv and the first row of cdrs2.csv were processed before the restart
+	erCheckpoints = newCheckpointStore()
+	if err = erCheckpoints.finishFile(cpDir, "csv1", archKey+"/cdrs1.csv", true); err != nil {
+		t.Fatal(err)
+	}
+	if err = erCheckpoints.setFileOffset(cpDir, "csv1", archKey+"/cdrs2.csv", 1); err != nil {
+		t.Fatal(err)
+	}
+	erCheckpoints.flush()
+	erCheckpoints = newCheckpointStore()
+	rdrEvents := make(chan *erEvent)
+	rdr, err := NewCSVFileER(cfg, 0, rdrEvents, make(chan error, 1), new(engine.FilterS), make(chan struct{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- decompressingProcessor(rdr.(*CSVFileER).processFile,
+			utils.CSVSuffix, procDir, 0)(srcDir, "cdrs.zip")
+	}()
+	select {
+	case ev := <-rdrEvents:
+		if rcv := ev.cgrEvent.Event[utils.Account]; rcv != "1004" {
+			t.Errorf("Expected: 1004, received: %v", rcv)
+		}
+		ev.procErr <- nil
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+	if err = <-errChan; err != nil {
+		t.Fatal(err)
+	}
+	if _, err = erCheckpoints.checkpoint(cpDir, "csv1"); err != utils.ErrNotFound {
+		t.Errorf("Expected checkpoint removed, received: %v", err)
+	}
+	if _, err = os.Stat(path.Join(srcDir, "cdrs.zip")); !os.IsNotExist(err) {
+		t.Errorf("Expected archive removed, received: %v", err)
+	}
+}
+
+func TestResumeFiles(t *testing.T) {
+	srcDir, err := ioutil.TempDir(utils.EmptyString, "ers_csv_in")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(srcDir)
+	cpDir, err := ioutil.TempDir(utils.EmptyString, "ers_checkpoints")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(cpDir)
+	erCheckpoints = newCheckpointStore()
+	for _, fName := range []string{"file1.csv", "file2.csv", "cdrs.zip"} {
+		if err = ioutil.WriteFile(path.Join(srcDir, fName), []byte("1001\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		info, err := os.Stat(path.Join(srcDir, fName))
+		if err != nil {
+			t.Fatal(err)
+		}
+		key := fileCheckpointKey(fName, info)
+		if fName == "file2.csv" { // replaced after the checkpoint
+			key = utils.ConcatenatedKey(fName, "5", "1")
+		}
+		if fName == "cdrs.zip" {
+			key += "/cdrs.csv"
+		}
+		if err = erCheckpoints.setFileOffset(cpDir, "csv1", key, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = erCheckpoints.setFileOffset(cpDir, "csv1", "file3.csv:5:1", 1); err != nil { // removed meanwhile
+		t.Fatal(err)
+	}
+	resumed := make(chan string, 4)
+	resumeFiles(cpDir, "csv1", srcDir, func(fPath, fName string) error {
+		resumed <- fName
+		return nil
+	})
+	var fNames []string
+	for i := 0; i < 2; i++ {
+		select {
+		case fName := <-resumed:
+			fNames = append(fNames, fName)
+		case <-time.After(time.Second):
+			t.Fatal("timeout")
+		}
+	}
+	sort.Strings(fNames)
+	if exp := []string{"cdrs.zip", "file1.csv"}; !reflect.DeepEqual(exp, fNames) {
+		t.Errorf("Expected: %v, received: %v", exp, fNames)
+	}
+	if cp, err := erCheckpoints.checkpoint(cpDir, "csv1"); err != nil {
+		t.Error(err)
+	} else if len(cp.Files) != 2 {
+		t.Errorf("Expected the stale checkpoints removed, received: %s", utils.ToJSON(cp.Files))
+	}
+}
@@ -21,6 +21,7 @@ package ers
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/engine"
@@ -32,10 +33,12 @@ import (
 type erEvent struct {
 	cgrEvent *utils.CGREvent
 	rdrCfg   *config.EventReaderCfg
+	procErr  chan error // optional, receives the processing result so the reader can acknowledge the event
 }
 
 // NewERService instantiates the ERService
-func NewERService(cfg *config.CGRConfig, filterS *engine.FilterS, stopChan chan struct{}, connMgr *engine.ConnManager) *ERService {
+func NewERService(cfg *config.CGRConfig, dm *engine.DataManager, filterS *engine.FilterS,
+	stopChan chan struct{}, server *utils.Server, connMgr *engine.ConnManager) *ERService {
 	return &ERService{
 		cfg:       cfg,
 		rdrs:      make(map[string]EventReader),
@@ -45,7 +48,9 @@ func NewERService(cfg *config.CGRConfig, filterS *engine.FilterS, stopChan chan
 		rdrErr:    make(chan error),
 		filterS:   filterS,
 		stopChan:  stopChan,
+		server:    server,
 		connMgr:   connMgr,
+		dedupS:    engine.NewDedupService(cfg, dm, utils.ERs),
 	}
 }
 
@@ -61,7 +66,9 @@ type ERService struct {
 
 	filterS  *engine.FilterS
 	stopChan chan struct{}
+	server   *utils.Server // used by the HTTP readers
 	connMgr  *engine.ConnManager
+	dedupS   *engine.DedupService
 }
 
 // ListenAndServe keeps the service alive
@@ -74,8 +81,13 @@ func (erS *ERService) ListenAndServe(cfgRldChan chan struct{}) (err error) {
 			return
 		}
 	}
+	cpTicker := time.NewTicker(checkpointPersistInterval)
+	defer cpTicker.Stop()
+	defer erCheckpoints.flush() // persist the checkpoints on shutdown
 	for {
 		select {
+		case <-cpTicker.C:
+			erCheckpoints.flush()
 		case err = <-erS.rdrErr: // got application error
 			utils.Logger.Crit(
 				fmt.Sprintf("<%s> running reader got error: <%s>",
@@ -84,10 +96,18 @@ func (erS *ERService) ListenAndServe(cfgRldChan chan struct{}) (err error) {
 		case <-erS.stopChan:
 			return
 		case erEv := <-erS.rdrEvents:
-			if err := erS.processEvent(erEv.cgrEvent, erEv.rdrCfg); err != nil {
+			utils.RuntimeMetrics.IncCounter(utils.MetricERsRead, erEv.rdrCfg.ID)
+			procErr := erS.processEvent(erEv.cgrEvent, erEv.rdrCfg)
+			if procErr != nil {
+				utils.RuntimeMetrics.IncCounter(utils.MetricERsFailed, erEv.rdrCfg.ID)
 				utils.Logger.Warning(
 					fmt.Sprintf("<%s> reading event: <%s> got error: <%s>",
-						utils.ERs, utils.ToIJSON(erEv.cgrEvent), err.Error()))
+						utils.ERs, utils.ToIJSON(erEv.cgrEvent), procErr.Error()))
+			} else {
+				utils.RuntimeMetrics.IncCounter(utils.MetricERsProcessed, erEv.rdrCfg.ID)
+			}
+			if erEv.procErr != nil {
+				erEv.procErr <- procErr
 			}
 		case <-cfgRldChan: // handle reload
 			cfgIDs := make(map[string]int)
@@ -140,6 +160,9 @@ func (erS *ERService) addReader(rdrID string, cfgIdx int) (err error) {
 		return
 	}
 	erS.rdrs[rdrID] = rdr
+	if httpRdr, isHTTP := rdr.(*HTTPER); isHTTP && erS.server != nil {
+		httpRdrs.listen(erS.server, httpRdr.path)
+	}
 	return rdr.Serve()
 }
 
@@ -151,6 +174,28 @@ func (erS *ERService) processEvent(cgrEv *utils.CGREvent, rdrCfg *config.EventRe
 			fmt.Sprintf("<%s> LOG, reader: <%s>, message: %s",
 				utils.ERs, rdrCfg.ID, utils.ToIJSON(cgrEv)))
 	}
+	var sent bool // the event reached SessionS so it might have been processed despite the error
+	if rdrCfg.Flags.HasKey(utils.MetaDedup) {
+		var dup bool
+		if dup, err = erS.dedupS.IsDuplicate(cgrEv, rdrCfg.ID); err != nil {
+			return
+		} else if dup {
+			utils.Logger.Debug(
+				fmt.Sprintf("<%s> reader: <%s>, suppressed duplicated event: %s",
+					utils.ERs, rdrCfg.ID, utils.ToJSON(cgrEv)))
+			return
+		}
+		defer func() {
+			if err == nil || sent { // the key of a sent event expires with its TTL
+				return
+			}
+			if errRls := erS.dedupS.Release(cgrEv); errRls != nil { // allow the event to be read again
+				utils.Logger.Warning(
+					fmt.Sprintf("<%s> reader: <%s>, releasing dedup key, error: %s",
+						utils.ERs, rdrCfg.ID, errRls.Error()))
+			}
+		}()
+	}
 	// find out reqType
 	var reqType string
 	for _, typ := range []string{
@@ -169,6 +214,7 @@ func (erS *ERService) processEvent(cgrEv *utils.CGREvent, rdrCfg *config.EventRe
 		reqType == utils.MetaAuthorize ||
 			reqType == utils.MetaMessage ||
 			reqType == utils.MetaEvent)
+	sent = reqType != utils.EmptyString // only the unsupported reqType fails before sending
 	switch reqType {
 	default:
 		return fmt.Errorf("unsupported reqType: <%s>", reqType)
@@ -278,3 +324,41 @@ func (erS *ERService) processEvent(cgrEv *utils.CGREvent, rdrCfg *config.EventRe
 
 	return
 }
+
+// V1GetDedupStats returns the number of duplicated events suppressed for each reader
+func (erS *ERService) V1GetDedupStats(args *engine.ArgsGetDedupStats, reply *map[string]int64) error {
+	return erS.dedupS.V1GetDedupStats(args, reply)
+}
+
+// V1GetCheckpoint returns the checkpoint of a reader
+func (erS *ERService) V1GetCheckpoint(args *utils.ArgsERsCheckpoint, reply *utils.ERsCheckpoint) (err error) {
+	if missing := utils.MissingStructFields(args, []string{"ReaderID"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	cpDir := erS.cfg.ERsCfg().CheckpointPath
+	if cpDir == utils.EmptyString {
+		return utils.ErrNotFound
+	}
+	var cp *utils.ERsCheckpoint
+	if cp, err = erCheckpoints.checkpoint(cpDir, args.ReaderID); err != nil {
+		return
+	}
+	*reply = *cp
+	return
+}
+
+// V1ResetCheckpoint removes the checkpoint of a reader or only the one of a file so it is read from start
+func (erS *ERService) V1ResetCheckpoint(args *utils.ArgsERsCheckpoint, reply *string) (err error) {
+	if missing := utils.MissingStructFields(args, []string{"ReaderID"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	cpDir := erS.cfg.ERsCfg().CheckpointPath
+	if cpDir == utils.EmptyString {
+		return utils.ErrNotFound
+	}
+	if err = erCheckpoints.reset(cpDir, args.ReaderID, args.FileName); err != nil {
+		return
+	}
+	*reply = utils.OK
+	return
+}
@@ -39,7 +39,7 @@ func TestERsNewERService(t *testing.T) {
 		rdrEvents: make(chan *erEvent),
 		rdrErr:    make(chan error),
 		stopChan:  nil}
-	rcv := NewERService(cfg, fltrS, nil, nil)
+	rcv := NewERService(cfg, nil, fltrS, nil, nil, nil)
 
 	if !reflect.DeepEqual(expected.cfg, rcv.cfg) {
 		t.Errorf("Expecting: <%+v>, received: <%+v>", expected.cfg, rcv.cfg)
@@ -51,7 +51,7 @@ func TestERsNewERService(t *testing.T) {
 func TestERsAddReader(t *testing.T) {
 	cfg, _ := config.NewDefaultCGRConfig()
 	fltrS := &engine.FilterS{}
-	erS := NewERService(cfg, fltrS, nil, nil)
+	erS := NewERService(cfg, nil, fltrS, nil, nil, nil)
 	reader := cfg.ERsCfg().Readers[0]
 	reader.Type = utils.MetaFileCSV
 	reader.ID = "file_reader"
@@ -68,3 +68,50 @@ func TestERsAddReader(t *testing.T) {
 		t.Errorf("Expecting: <%+v>, received: <%+v>", reader, erS.rdrs["file_reader"].Config())
 	}
 }
+
+func TestERsProcessEventDedup(t *testing.T) {
+	cfg, _ := config.NewDefaultCGRConfig()
+	erS := NewERService(cfg, nil, nil, nil, nil, nil)
+	rdrCfg := cfg.ERsCfg().Readers[0]
+	rdrCfg.Flags = utils.FlagsWithParams{utils.META_NONE: {}, utils.MetaDedup: {}}
+	ev := &utils.CGREvent{Tenant: "cgrates.org", ID: "ev1",
+		Event: map[string]interface{}{utils.OriginID: "abc", utils.OriginHost: "192.168.1.1"}}
+	for i := 0; i < 2; i++ {
+		if err := erS.processEvent(ev, rdrCfg); err != nil {
+			t.Error(err)
+		}
+	}
+	var stats map[string]int64
+	if err := erS.V1GetDedupStats(new(engine.ArgsGetDedupStats), &stats); err != nil {
+		t.Error(err)
+	} else if exp := map[string]int64{rdrCfg.ID: 1}; !reflect.DeepEqual(exp, stats) {
+		t.Errorf("Expected: %v, received: %v", exp, stats)
+	}
+	// failed events are not kept as processed
+	rdrCfg.Flags = utils.FlagsWithParams{utils.MetaDedup: {}}
+	ev.ID = "ev2"
+	ev.Event[utils.OriginID] = "def"
+	for i := 0; i < 2; i++ {
+		if err := erS.processEvent(ev, rdrCfg); err == nil ||
+			err.Error() != "unsupported reqType: <>" {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+	// the events sent to SessionS keep the key even on error since they might have been processed
+	cfg.ERsCfg().SessionSConns = nil
+	rdrCfg.Flags = utils.FlagsWithParams{utils.MetaMessage: {}, utils.MetaDedup: {}}
+	ev.ID = "ev3"
+	ev.Event[utils.OriginID] = "ghi"
+	if err := erS.processEvent(ev, rdrCfg); err == nil ||
+		err.Error() != utils.NewErrMandatoryIeMissing("connIDs").Error() {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := erS.processEvent(ev, rdrCfg); err != nil {
+		t.Error(err)
+	}
+	if err := erS.V1GetDedupStats(new(engine.ArgsGetDedupStats), &stats); err != nil {
+		t.Error(err)
+	} else if exp := map[string]int64{rdrCfg.ID: 2}; !reflect.DeepEqual(exp, stats) {
+		t.Errorf("Expected: %v, received: %v", exp, stats)
+	}
+}
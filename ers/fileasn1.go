@@ -0,0 +1,249 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package ers
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cgrates/cgrates/agents"
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// NewASN1FileER return a new EventReader for 3GPP ASN.1 BER encoded CDR files
+func NewASN1FileER(cfg *config.CGRConfig, cfgIdx int,
+	rdrEvents chan *erEvent, rdrErr chan error,
+	fltrS *engine.FilterS, rdrExit chan struct{}) (er EventReader, err error) {
+	srcPath := cfg.ERsCfg().Readers[cfgIdx].SourcePath
+	if strings.HasSuffix(srcPath, utils.Slash) {
+		srcPath = srcPath[:len(srcPath)-1]
+	}
+	var schema *ASN1Schema
+	if schema, err = NewASN1SchemaFromFile(cfg.ERsCfg().Readers[cfgIdx].ASN1SchemaPath); err != nil {
+		return nil, fmt.Errorf("loading ASN.1 schema: %s", err.Error())
+	}
+	asn1Er := &ASN1FileER{
+		cgrCfg:    cfg,
+		cfgIdx:    cfgIdx,
+		fltrS:     fltrS,
+		rdrDir:    srcPath,
+		schema:    schema,
+		rdrEvents: rdrEvents,
+		rdrError:  rdrErr,
+		rdrExit:   rdrExit,
+		conReqs:   make(chan struct{}, cfg.ERsCfg().Readers[cfgIdx].ConcurrentReqs)}
+	var processFile struct{}
+	for i := 0; i < cfg.ERsCfg().Readers[cfgIdx].ConcurrentReqs; i++ {
+		asn1Er.conReqs <- processFile // Empty initiate so we do not need to wait later when we pop
+	}
+	return asn1Er, nil
+}
+
+// ASN1FileER implements EventReader interface for ASN.1 BER encoded CDR files
+type ASN1FileER struct {
+	sync.RWMutex
+	cgrCfg    *config.CGRConfig
+	cfgIdx    int // index of config instance within ERsCfg.Readers
+	fltrS     *engine.FilterS
+	rdrDir    string
+	schema    *ASN1Schema
+	rdrEvents chan *erEvent // channel to dispatch the events created to
+	rdrError  chan error
+	rdrExit   chan struct{}
+	conReqs   chan struct{} // limit number of opened files
+}
+
+func (rdr *ASN1FileER) Config() *config.EventReaderCfg {
+	return rdr.cgrCfg.ERsCfg().Readers[rdr.cfgIdx]
+}
+
+func (rdr *ASN1FileER) Serve() (err error) {
+	processFile := decompressingProcessor(rdr.processFile,
+		utils.EmptyString, rdr.Config().ProcessedPath,
+		rdr.cgrCfg.ERsCfg().MaxDecompressedSize) // handle the compressed files transparently
+	if utils.IsRemoteFilePath(rdr.Config().SourcePath) { // SFTP or FTP(S) folder, polled each RunDelay
+		return serveRemote(rdr.Config(), utils.EmptyString, processFile,
+			rdr.cgrCfg.GeneralCfg().ConnectTimeout, rdr.cgrCfg.ERsCfg().CheckpointPath, rdr.rdrExit)
+	}
+	switch rdr.Config().RunDelay {
+	case time.Duration(0): // 0 disables the automatic read, maybe done per API
+		return
+	case time.Duration(-1):
+		resumeFiles(rdr.cgrCfg.ERsCfg().CheckpointPath, rdr.Config().ID,
+			rdr.rdrDir, processFile) // files interrupted by a restart
+		return watchDir(rdr.rdrDir, processFile,
+			utils.ERs, rdr.rdrExit)
+	default:
+		go func() {
+			for {
+				// Not automated, process and sleep approach
+				select {
+				case <-rdr.rdrExit:
+					utils.Logger.Info(
+						fmt.Sprintf("<%s> stop monitoring path <%s>",
+							utils.ERs, rdr.rdrDir))
+					return
+				default:
+				}
+				filesInDir, _ := ioutil.ReadDir(rdr.rdrDir)
+				for _, file := range filesInDir {
+					if file.IsDir() || strings.HasPrefix(file.Name(), ".") { // no extension to filter on, skip folders and hidden files
+						continue
+					}
+					go func(fileName string) {
+						if err := processFile(rdr.rdrDir, fileName); err != nil {
+							utils.Logger.Warning(
+								fmt.Sprintf("<%s> processing file %s, error: %s",
+									utils.ERs, fileName, err.Error()))
+						}
+					}(file.Name())
+				}
+				time.Sleep(rdr.Config().RunDelay)
+			}
+		}()
+	}
+	return
+}
+
+// processFile is called for each file in a directory and dispatches erEvents from it
+func (rdr *ASN1FileER) processFile(fPath, fName string) (err error) {
+	if cap(rdr.conReqs) != 0 { // 0 goes for no limit
+		processFile := <-rdr.conReqs // Queue here for maxOpenFiles
+		defer func() { rdr.conReqs <- processFile }()
+	}
+	absPath := path.Join(fPath, fName)
+	utils.Logger.Info(
+		fmt.Sprintf("<%s> parsing <%s>", utils.ERs, absPath))
+	timeStart := time.Now()
+	var content []byte
+	if content, err = ioutil.ReadFile(absPath); err != nil {
+		return
+	}
+	var hdrDP config.DataProvider
+	records := []*asn1CDR{{format: 1, data: content}}
+	if rdr.schema.Framing == asn1FramingTS32297 {
+		var hdr map[string]interface{}
+		var hdrLen int
+		if hdr, hdrLen, err = decodeTS32297Header(content); err != nil {
+			return fmt.Errorf("decoding file header: %s", err.Error())
+		}
+		hdrDP = config.NewNavigableMap(hdr)
+		if records, err = splitTS32297CDRs(content[hdrLen:]); err != nil {
+			return fmt.Errorf("decoding CDR headers: %s", err.Error())
+		}
+	}
+	evsPosted := 0
+	rowNr := 0 // This counts the records in the file
+	cp := newFileCheckpoint(rdr.cgrCfg.ERsCfg().CheckpointPath, rdr.Config().ID, fPath, fName)
+	for _, cdr := range records {
+		if cdr.format != 1 { // only BER is supported
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> reading file: <%s> record: %d, ignoring unsupported data record format: %d",
+					utils.ERs, absPath, rowNr+1, cdr.format))
+			rowNr++
+			continue
+		}
+		for data := cdr.data; len(data) != 0; { // framing *none holds all the records in one block
+			cp.set(rowNr) // previous records were processed
+			rowNr++
+			var tlv *berTLV
+			var errDecode error
+			if tlv, data, errDecode = decodeBER(data); errDecode != nil {
+				utils.Logger.Warning(
+					fmt.Sprintf("<%s> reading file: <%s> record: %d, ignoring due to error: <%s>",
+						utils.ERs, absPath, rowNr, errDecode.Error()))
+				break // cannot find the start of the next record
+			}
+			if cp.processed(rowNr) {
+				continue // already processed before restart
+			}
+			var posted bool
+			if posted, err = rdr.processRecord(tlv, fName, hdrDP, cp, rowNr); err != nil {
+				utils.Logger.Warning(
+					fmt.Sprintf("<%s> reading file: <%s> record: %d, ignoring due to error: <%s>",
+						utils.ERs, absPath, rowNr, err.Error()))
+				err = nil
+				continue
+			}
+			if posted {
+				evsPosted++
+			}
+		}
+	}
+	cp.wait()
+	if rdr.Config().ProcessedPath != "" {
+		// Finished with file, move it to processed folder
+		outPath := path.Join(rdr.Config().ProcessedPath, fName)
+		if err = os.Rename(absPath, outPath); err != nil {
+			return
+		}
+	}
+	cp.finish()
+
+	utils.Logger.Info(
+		fmt.Sprintf("%s finished processing file <%s>. Events posted: %d, run duration: %s",
+			utils.ERs, absPath, evsPosted, time.Now().Sub(timeStart)))
+	return
+}
+
+// processRecord decodes one record based on schema and dispatches it as erEvent
+func (rdr *ASN1FileER) processRecord(tlv *berTLV, fName string,
+	hdrDP config.DataProvider, cp *fileCheckpoint, recNr int) (posted bool, err error) {
+	recFld, has := rdr.schema.Records[tlv.tag]
+	if !has {
+		return false, fmt.Errorf("unknown record with tag: %d", tlv.tag)
+	}
+	var val interface{}
+	if val, err = recFld.decode(tlv); err != nil {
+		return
+	}
+	data, canCast := val.(map[string]interface{})
+	if !canCast {
+		data = map[string]interface{}{recFld.Name: val}
+	}
+	reqVars := map[string]interface{}{
+		utils.FileName:   fName,
+		utils.ASN1Record: recFld.Name,
+	}
+	agReq := agents.NewAgentRequest(
+		config.NewNavigableMap(data), reqVars, nil, nil, rdr.Config().Tenant,
+		rdr.cgrCfg.GeneralCfg().DefaultTenant,
+		utils.FirstNonEmpty(rdr.Config().Timezone,
+			rdr.cgrCfg.GeneralCfg().DefaultTimezone),
+		rdr.fltrS, hdrDP, nil) // create an AgentRequest
+	var pass bool
+	if pass, err = rdr.fltrS.Pass(agReq.Tenant, rdr.Config().Filters,
+		agReq); err != nil || !pass {
+		return
+	}
+	if err = agReq.SetFields(rdr.Config().Fields); err != nil {
+		return
+	}
+	cp.dispatch(rdr.rdrEvents, &erEvent{cgrEvent: agReq.CGRRequest.AsCGREvent(
+		agReq.Tenant, utils.NestingSep),
+		rdrCfg: rdr.Config()}, recNr)
+	return true, nil
+}
@@ -0,0 +1,120 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package ers
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// testTS32297File frames the records as in 3GPP TS 32.297
+func testTS32297File(records ...[]byte) (file []byte) {
+	hdr := make([]byte, 54)
+	hdr[7] = 54   // headerLength
+	hdr[8] = 0xc7 // highReleaseIdentifier
+	hdr[21] = byte(len(records))
+	hdr[25] = 7 // fileSequenceNumber
+	for _, rec := range records {
+		format := byte(0x20) // BER
+		if rec == nil {      // unaligned PER, not supported
+			format = byte(0x40)
+			rec = []byte{0x00}
+		}
+		file = append(file, byte(len(rec)>>8), byte(len(rec)), 0xc0, format|0x06)
+		file = append(file, rec...)
+	}
+	fileLen := len(hdr) + len(file)
+	hdr[2], hdr[3] = byte(fileLen>>8), byte(fileLen)
+	return append(hdr, file...)
+}
+
+func TestASN1FileERProcessFile(t *testing.T) {
+	srcDir, err := ioutil.TempDir(utils.EmptyString, "ers_asn1_in")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(srcDir)
+	procDir, err := ioutil.TempDir(utils.EmptyString, "ers_asn1_out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(procDir)
+	if err = ioutil.WriteFile(path.Join(srcDir, "pgw.dat"), testTS32297File(
+		testPGWRecord(),
+		nil,
+		berBytes([]byte{0xbf, 0x50}, berBytes([]byte{0x80}, 0x01)...), // unknown record
+		[]byte{0xbf, 0x4f, 0x05, 0x80},                                // truncated
+	), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, _ := config.NewDefaultCGRConfig()
+	cfg.ERsCfg().Readers[0].Type = utils.MetaFileASN1
+	cfg.ERsCfg().Readers[0].SourcePath = srcDir
+	cfg.ERsCfg().Readers[0].ProcessedPath = procDir
+	cfg.ERsCfg().Readers[0].Fields = []*config.FCTemplate{
+		{Tag: utils.OriginID, Path: utils.MetaCgreq + utils.NestingSep + utils.OriginID, Type: utils.META_COMPOSED,
+			Value: config.NewRSRParsersMustCompile("~*req.chargingID;-;~*hdr.fileSequenceNumber", true, utils.INFIELD_SEP)},
+		{Tag: utils.Account, Path: utils.MetaCgreq + utils.NestingSep + utils.Account, Type: utils.META_COMPOSED,
+			Value: config.NewRSRParsersMustCompile("~*req.servedMSISDN", true, utils.INFIELD_SEP)},
+		{Tag: utils.Usage, Path: utils.MetaCgreq + utils.NestingSep + utils.Usage, Type: utils.META_COMPOSED,
+			Value: config.NewRSRParsersMustCompile("~*req.listOfServiceData[0].datavolumeFBCDownlink", true, utils.INFIELD_SEP)},
+		{Tag: utils.ToR, Path: utils.MetaCgreq + utils.NestingSep + utils.ToR, Type: utils.META_COMPOSED,
+			Value: config.NewRSRParsersMustCompile("~*vars.ASN1Record", true, utils.INFIELD_SEP)},
+	}
+	rdrEvents := make(chan *erEvent, 2)
+	rdr, err := NewASN1FileER(cfg, 0, rdrEvents, make(chan error, 1), new(engine.FilterS), make(chan struct{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = rdr.(*ASN1FileER).processFile(srcDir, "pgw.dat"); err != nil {
+		t.Fatal(err)
+	}
+	if len(rdrEvents) != 1 {
+		t.Fatalf("Expected 1 event, received: %d", len(rdrEvents))
+	}
+	exp := map[string]interface{}{
+		utils.OriginID: "12345-7",
+		utils.Account:  "40712345678",
+		utils.Usage:    "2000",
+		utils.ToR:      "pGWRecord",
+	}
+	if ev := <-rdrEvents; !reflect.DeepEqual(exp, ev.cgrEvent.Event) {
+		t.Errorf("Expected: %s, received: %s", utils.ToJSON(exp), utils.ToJSON(ev.cgrEvent.Event))
+	}
+	if _, err = os.Stat(path.Join(procDir, "pgw.dat")); err != nil {
+		t.Error(err)
+	}
+	if err = ioutil.WriteFile(path.Join(srcDir, "short.dat"), []byte{0x00, 0x01}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = rdr.(*ASN1FileER).processFile(srcDir, "short.dat"); err == nil {
+		t.Error("Expected error")
+	}
+	cfg.ERsCfg().Readers[0].ASN1SchemaPath = path.Join(srcDir, "missing.json")
+	if _, err = NewASN1FileER(cfg, 0, rdrEvents, make(chan error, 1), new(engine.FilterS), make(chan struct{})); err == nil {
+		t.Error("Expected error")
+	}
+}
@@ -83,11 +83,20 @@ func (rdr *FWVFileER) Config() *config.EventReaderCfg {
 }
 
 func (rdr *FWVFileER) Serve() (err error) {
+	processFile := decompressingProcessor(rdr.processFile,
+		utils.FWVSuffix, rdr.Config().ProcessedPath,
+		rdr.cgrCfg.ERsCfg().MaxDecompressedSize) // handle the compressed files transparently
+	if utils.IsRemoteFilePath(rdr.Config().SourcePath) { // SFTP or FTP(S) folder, polled each RunDelay
+		return serveRemote(rdr.Config(), utils.FWVSuffix, processFile,
+			rdr.cgrCfg.GeneralCfg().ConnectTimeout, rdr.cgrCfg.ERsCfg().CheckpointPath, rdr.rdrExit)
+	}
 	switch rdr.Config().RunDelay {
 	case time.Duration(0): // 0 disables the automatic read, maybe done per API
 		return
 	case time.Duration(-1):
-		return watchDir(rdr.rdrDir, rdr.processFile,
+		resumeFiles(rdr.cgrCfg.ERsCfg().CheckpointPath, rdr.Config().ID,
+			rdr.rdrDir, processFile) // files interrupted by a restart
+		return watchDir(rdr.rdrDir, processFile,
 			utils.ERs, rdr.rdrExit)
 	default:
 		go func() {
@@ -103,11 +112,11 @@ func (rdr *FWVFileER) Serve() (err error) {
 				}
 				filesInDir, _ := ioutil.ReadDir(rdr.rdrDir)
 				for _, file := range filesInDir {
-					if !strings.HasSuffix(file.Name(), utils.FWVSuffix) { // hardcoded file extension for xml event reader
+					if !hasFileSuffix(file.Name(), utils.FWVSuffix) { // hardcoded file extension for xml event reader
 						continue // used in order to filter the files from directory
 					}
 					go func(fileName string) {
-						if err := rdr.processFile(rdr.rdrDir, fileName); err != nil {
+						if err := processFile(rdr.rdrDir, fileName); err != nil {
 							utils.Logger.Warning(
 								fmt.Sprintf("<%s> processing file %s, error: %s",
 									utils.ERs, fileName, err.Error()))
@@ -137,10 +146,11 @@ func (rdr *FWVFileER) processFile(fPath, fName string) (err error) {
 	defer file.Close()
 
 	rowNr := 0 // This counts the rows in the file, not really number of CDRs
+	recNr := 0 // counts the trailer, header and rows in the order they are dispatched, used by the checkpoint
 	evsPosted := 0
 	timeStart := time.Now()
 	reqVars := map[string]interface{}{utils.FileName: fName}
-
+	cp := newFileCheckpoint(rdr.cgrCfg.ERsCfg().CheckpointPath, rdr.Config().ID, fPath, fName)
 	for {
 		var hasHeader, hasTrailer bool
 		var headerFields, trailerFields []*config.FCTemplate
@@ -162,15 +172,18 @@ func (rdr *FWVFileER) processFile(fPath, fName string) (err error) {
 				break
 			}
 			if hasTrailer {
-
+				cp.set(recNr) // previous records were processed
+				recNr++
 				// process trailer here
-				if err = rdr.processTrailer(file, rowNr, evsPosted, absPath, trailerFields); err != nil {
+				if err = rdr.processTrailer(file, cp, recNr, absPath, trailerFields); err != nil {
 					utils.Logger.Err(fmt.Sprintf("<%s> Read trailer error: %s ", utils.ERs, err.Error()))
 					return
 				}
 			}
 			if hasHeader {
-				if err = rdr.processHeader(file, rowNr, evsPosted, absPath, headerFields); err != nil {
+				cp.set(recNr)
+				recNr++
+				if err = rdr.processHeader(file, cp, recNr, absPath, headerFields); err != nil {
 					utils.Logger.Err(fmt.Sprintf("<%s> Row 0, error reading header: %s", utils.ERs, err.Error()))
 					return
 				}
@@ -190,6 +203,12 @@ func (rdr *FWVFileER) processFile(fPath, fName string) (err error) {
 			continue
 		}
 		rowNr++ // increment the rowNr after checking if it's not the end of file
+		cp.set(recNr)
+		recNr++
+		if cp.processed(recNr) {
+			rdr.offset += rdr.lineLen
+			continue // already processed before restart
+		}
 		record := string(buf)
 		agReq := agents.NewAgentRequest(
 			config.NewFWVProvider(record), reqVars,
@@ -210,13 +229,13 @@ func (rdr *FWVFileER) processFile(fPath, fName string) (err error) {
 			continue
 		}
 		rdr.offset += rdr.lineLen // increase the offset
-		rdr.rdrEvents <- &erEvent{cgrEvent: agReq.CGRRequest.AsCGREvent(
+		cp.dispatch(rdr.rdrEvents, &erEvent{cgrEvent: agReq.CGRRequest.AsCGREvent(
 			agReq.Tenant, utils.NestingSep),
-			rdrCfg: rdr.Config()}
+			rdrCfg: rdr.Config()}, recNr)
 		evsPosted++
 
 	}
-
+	cp.wait()
 	if rdr.Config().ProcessedPath != "" {
 		// Finished with file, move it to processed folder
 		outPath := path.Join(rdr.Config().ProcessedPath, fName)
@@ -224,6 +243,7 @@ func (rdr *FWVFileER) processFile(fPath, fName string) (err error) {
 			return
 		}
 	}
+	cp.finish()
 
 	utils.Logger.Info(
 		fmt.Sprintf("%s finished processing file <%s>. Total records processed: %d, events posted: %d, run duration: %s",
@@ -270,7 +290,7 @@ func (rdr *FWVFileER) setLineLen(file *os.File, hasHeader, hasTrailer bool) erro
 	return nil
 }
 
-func (rdr *FWVFileER) processTrailer(file *os.File, rowNr, evsPosted int, absPath string, trailerFields []*config.FCTemplate) (err error) {
+func (rdr *FWVFileER) processTrailer(file *os.File, cp *fileCheckpoint, rowNr int, absPath string, trailerFields []*config.FCTemplate) (err error) {
 	buf := make([]byte, rdr.trailerLenght)
 	if nRead, err := file.ReadAt(buf, rdr.trailerOffset); err != nil && err != io.EOF {
 		return err
@@ -280,6 +300,9 @@ func (rdr *FWVFileER) processTrailer(file *os.File, rowNr, evsPosted int, absPat
 	record := string(buf)
 	reqVars := make(map[string]interface{})
 	rdr.trailerDP = config.NewFWVProvider(record)
+	if cp.processed(rowNr) {
+		return // already processed before restart
+	}
 	agReq := agents.NewAgentRequest(
 		nil, reqVars,
 		nil, nil, rdr.Config().Tenant,
@@ -297,28 +320,31 @@ func (rdr *FWVFileER) processTrailer(file *os.File, rowNr, evsPosted int, absPat
 				utils.ERs, absPath, rowNr, err.Error()))
 		return err
 	}
-	rdr.rdrEvents <- &erEvent{cgrEvent: agReq.CGRRequest.AsCGREvent(
+	cp.dispatch(rdr.rdrEvents, &erEvent{cgrEvent: agReq.CGRRequest.AsCGREvent(
 		agReq.Tenant, utils.NestingSep),
-		rdrCfg: rdr.Config()}
-	evsPosted++
+		rdrCfg: rdr.Config()}, rowNr)
 	// reset the cursor after process the trailer
 	_, err = file.Seek(0, 0)
 	return
 }
 
-func (rdr *FWVFileER) processHeader(file *os.File, rowNr, evsPosted int, absPath string, hdrFields []*config.FCTemplate) error {
+func (rdr *FWVFileER) processHeader(file *os.File, cp *fileCheckpoint, rowNr int, absPath string, hdrFields []*config.FCTemplate) error {
 	buf := make([]byte, rdr.headerOffset)
 	if nRead, err := file.Read(buf); err != nil {
 		return err
 	} else if nRead != len(buf) {
 		return fmt.Errorf("In header, line len: %d, have read: %d", rdr.headerOffset, nRead)
 	}
-	return rdr.createHeaderMap(string(buf), rowNr, evsPosted, absPath, hdrFields)
+	return rdr.createHeaderMap(string(buf), cp, rowNr, absPath, hdrFields)
 }
 
-func (rdr *FWVFileER) createHeaderMap(record string, rowNr, evsPosted int, absPath string, hdrFields []*config.FCTemplate) (err error) {
+func (rdr *FWVFileER) createHeaderMap(record string, cp *fileCheckpoint, rowNr int, absPath string, hdrFields []*config.FCTemplate) (err error) {
 	reqVars := make(map[string]interface{})
 	rdr.headerDP = config.NewFWVProvider(record)
+	if cp.processed(rowNr) {
+		rdr.offset += rdr.headerOffset
+		return // already processed before restart
+	}
 	agReq := agents.NewAgentRequest(
 		nil, reqVars,
 		nil, nil, rdr.Config().Tenant,
@@ -338,9 +364,8 @@ func (rdr *FWVFileER) createHeaderMap(record string, rowNr, evsPosted int, absPa
 		return err
 	}
 	rdr.offset += rdr.headerOffset // increase the offset
-	rdr.rdrEvents <- &erEvent{cgrEvent: agReq.CGRRequest.AsCGREvent(
+	cp.dispatch(rdr.rdrEvents, &erEvent{cgrEvent: agReq.CGRRequest.AsCGREvent(
 		agReq.Tenant, utils.NestingSep),
-		rdrCfg: rdr.Config()}
-	evsPosted++
+		rdrCfg: rdr.Config()}, rowNr)
 	return
 }
@@ -76,11 +76,20 @@ func (rdr *JSONFileER) Config() *config.EventReaderCfg {
 }
 
 func (rdr *JSONFileER) Serve() (err error) {
+	processFile := decompressingProcessor(rdr.processFile,
+		utils.JSNSuffix, rdr.Config().ProcessedPath,
+		rdr.cgrCfg.ERsCfg().MaxDecompressedSize) // handle the compressed files transparently
+	if utils.IsRemoteFilePath(rdr.Config().SourcePath) { // SFTP or FTP(S) folder, polled each RunDelay
+		return serveRemote(rdr.Config(), utils.JSNSuffix, processFile,
+			rdr.cgrCfg.GeneralCfg().ConnectTimeout, rdr.cgrCfg.ERsCfg().CheckpointPath, rdr.rdrExit)
+	}
 	switch rdr.Config().RunDelay {
 	case time.Duration(0): // 0 disables the automatic read, maybe done per API
 		return
 	case time.Duration(-1):
-		return watchDir(rdr.rdrDir, rdr.processFile,
+		resumeFiles(rdr.cgrCfg.ERsCfg().CheckpointPath, rdr.Config().ID,
+			rdr.rdrDir, processFile) // files interrupted by a restart
+		return watchDir(rdr.rdrDir, processFile,
 			utils.ERs, rdr.rdrExit)
 	default:
 		go func() {
@@ -96,11 +105,11 @@ func (rdr *JSONFileER) Serve() (err error) {
 				}
 				filesInDir, _ := ioutil.ReadDir(rdr.rdrDir)
 				for _, file := range filesInDir {
-					if !strings.HasSuffix(file.Name(), utils.JSNSuffix) { // hardcoded file extension for json event reader
+					if !hasFileSuffix(file.Name(), utils.JSNSuffix) { // hardcoded file extension for json event reader
 						continue // used in order to filter the files from directory
 					}
 					go func(fileName string) {
-						if err := rdr.processFile(rdr.rdrDir, fileName); err != nil {
+						if err := processFile(rdr.rdrDir, fileName); err != nil {
 							utils.Logger.Warning(
 								fmt.Sprintf("<%s> processing file %s, error: %s",
 									utils.ERs, fileName, err.Error()))
@@ -158,10 +167,14 @@ func (rdr *JSONFileER) processFile(fPath, fName string) (err error) {
 				utils.ERs, absPath, err.Error()))
 		return
 	}
-	rdr.rdrEvents <- &erEvent{cgrEvent: agReq.CGRRequest.AsCGREvent(
-		agReq.Tenant, utils.NestingSep),
-		rdrCfg: rdr.Config()}
-	evsPosted++
+	cp := newFileCheckpoint(rdr.cgrCfg.ERsCfg().CheckpointPath, rdr.Config().ID, fPath, fName)
+	if !cp.processed(1) { // the event was not sent before restart
+		cp.dispatch(rdr.rdrEvents, &erEvent{cgrEvent: agReq.CGRRequest.AsCGREvent(
+			agReq.Tenant, utils.NestingSep),
+			rdrCfg: rdr.Config()}, 1)
+		evsPosted++
+	}
+	cp.wait()
 
 	if rdr.Config().ProcessedPath != "" {
 		// Finished with file, move it to processed folder
@@ -170,6 +183,7 @@ func (rdr *JSONFileER) processFile(fPath, fName string) (err error) {
 			return
 		}
 	}
+	cp.finish()
 
 	utils.Logger.Info(
 		fmt.Sprintf("%s finished processing file <%s>. Events posted: %d, run duration: %s",
@@ -77,11 +77,20 @@ func (rdr *XMLFileER) Config() *config.EventReaderCfg {
 }
 
 func (rdr *XMLFileER) Serve() (err error) {
+	processFile := decompressingProcessor(rdr.processFile,
+		utils.XMLSuffix, rdr.Config().ProcessedPath,
+		rdr.cgrCfg.ERsCfg().MaxDecompressedSize) // handle the compressed files transparently
+	if utils.IsRemoteFilePath(rdr.Config().SourcePath) { // SFTP or FTP(S) folder, polled each RunDelay
+		return serveRemote(rdr.Config(), utils.XMLSuffix, processFile,
+			rdr.cgrCfg.GeneralCfg().ConnectTimeout, rdr.cgrCfg.ERsCfg().CheckpointPath, rdr.rdrExit)
+	}
 	switch rdr.Config().RunDelay {
 	case time.Duration(0): // 0 disables the automatic read, maybe done per API
 		return
 	case time.Duration(-1):
-		return watchDir(rdr.rdrDir, rdr.processFile,
+		resumeFiles(rdr.cgrCfg.ERsCfg().CheckpointPath, rdr.Config().ID,
+			rdr.rdrDir, processFile) // files interrupted by a restart
+		return watchDir(rdr.rdrDir, processFile,
 			utils.ERs, rdr.rdrExit)
 	default:
 		go func() {
@@ -97,11 +106,11 @@ func (rdr *XMLFileER) Serve() (err error) {
 				}
 				filesInDir, _ := ioutil.ReadDir(rdr.rdrDir)
 				for _, file := range filesInDir {
-					if !strings.HasSuffix(file.Name(), utils.XMLSuffix) { // hardcoded file extension for xml event reader
+					if !hasFileSuffix(file.Name(), utils.XMLSuffix) { // hardcoded file extension for xml event reader
 						continue // used in order to filter the files from directory
 					}
 					go func(fileName string) {
-						if err := rdr.processFile(rdr.rdrDir, fileName); err != nil {
+						if err := processFile(rdr.rdrDir, fileName); err != nil {
 							utils.Logger.Warning(
 								fmt.Sprintf("<%s> processing file %s, error: %s",
 									utils.ERs, fileName, err.Error()))
@@ -138,8 +147,13 @@ func (rdr *XMLFileER) processFile(fPath, fName string) (err error) {
 	evsPosted := 0
 	timeStart := time.Now()
 	reqVars := map[string]interface{}{utils.FileName: fName}
+	cp := newFileCheckpoint(rdr.cgrCfg.ERsCfg().CheckpointPath, rdr.Config().ID, fPath, fName)
 	for _, xmlElmt := range xmlElmts {
-		rowNr++ // increment the rowNr after checking if it's not the end of file
+		cp.set(rowNr) // previous rows were processed
+		rowNr++       // increment the rowNr after checking if it's not the end of file
+		if cp.processed(rowNr) {
+			continue // already processed before restart
+		}
 		agReq := agents.NewAgentRequest(
 			config.NewXmlProvider(xmlElmt, rdr.Config().XmlRootPath), reqVars,
 			nil, nil, rdr.Config().Tenant,
@@ -157,11 +171,12 @@ func (rdr *XMLFileER) processFile(fPath, fName string) (err error) {
 					utils.ERs, absPath, rowNr, err.Error()))
 			continue
 		}
-		rdr.rdrEvents <- &erEvent{cgrEvent: agReq.CGRRequest.AsCGREvent(
+		cp.dispatch(rdr.rdrEvents, &erEvent{cgrEvent: agReq.CGRRequest.AsCGREvent(
 			agReq.Tenant, utils.NestingSep),
-			rdrCfg: rdr.Config()}
+			rdrCfg: rdr.Config()}, rowNr)
 		evsPosted++
 	}
+	cp.wait()
 
 	if rdr.Config().ProcessedPath != "" {
 		// Finished with file, move it to processed folder
@@ -170,6 +185,7 @@ func (rdr *XMLFileER) processFile(fPath, fName string) (err error) {
 			return
 		}
 	}
+	cp.finish()
 
 	utils.Logger.Info(
 		fmt.Sprintf("%s finished processing file <%s>. Total records processed: %d, events posted: %d, run duration: %s",
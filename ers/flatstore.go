@@ -84,11 +84,20 @@ func (rdr *FlatstoreER) Config() *config.EventReaderCfg {
 }
 
 func (rdr *FlatstoreER) Serve() (err error) {
+	processFile := decompressingProcessor(rdr.processFile,
+		utils.CSVSuffix, rdr.Config().ProcessedPath,
+		rdr.cgrCfg.ERsCfg().MaxDecompressedSize) // handle the compressed files transparently
+	if utils.IsRemoteFilePath(rdr.Config().SourcePath) { // SFTP or FTP(S) folder, polled each RunDelay
+		return serveRemote(rdr.Config(), utils.CSVSuffix, processFile,
+			rdr.cgrCfg.GeneralCfg().ConnectTimeout, rdr.cgrCfg.ERsCfg().CheckpointPath, rdr.rdrExit)
+	}
 	switch rdr.Config().RunDelay {
 	case time.Duration(0): // 0 disables the automatic read, maybe done per API
 		return
 	case time.Duration(-1):
-		return watchDir(rdr.rdrDir, rdr.processFile,
+		resumeFiles(rdr.cgrCfg.ERsCfg().CheckpointPath, rdr.Config().ID,
+			rdr.rdrDir, processFile) // files interrupted by a restart
+		return watchDir(rdr.rdrDir, processFile,
 			utils.ERs, rdr.rdrExit)
 	default:
 		go func() {
@@ -104,11 +113,11 @@ func (rdr *FlatstoreER) Serve() (err error) {
 				}
 				filesInDir, _ := ioutil.ReadDir(rdr.rdrDir)
 				for _, file := range filesInDir {
-					if !strings.HasSuffix(file.Name(), utils.CSVSuffix) { // hardcoded file extension for csv event reader
+					if !hasFileSuffix(file.Name(), utils.CSVSuffix) { // hardcoded file extension for csv event reader
 						continue // used in order to filter the files from directory
 					}
 					go func(fileName string) {
-						if err := rdr.processFile(rdr.rdrDir, fileName); err != nil {
+						if err := processFile(rdr.rdrDir, fileName); err != nil {
 							utils.Logger.Warning(
 								fmt.Sprintf("<%s> processing file %s, error: %s",
 									utils.ERs, fileName, err.Error()))
@@ -147,7 +156,9 @@ func (rdr *FlatstoreER) processFile(fPath, fName string) (err error) {
 	evsPosted := 0
 	timeStart := time.Now()
 	reqVars := map[string]interface{}{utils.FileName: fName}
+	cp := newFileCheckpoint(rdr.cgrCfg.ERsCfg().CheckpointPath, rdr.Config().ID, fPath, fName)
 	for {
+		cp.set(rowNr) // previous rows were processed
 		var record []string
 		if record, err = csvReader.Read(); err != nil {
 			if err == io.EOF {
@@ -155,6 +166,8 @@ func (rdr *FlatstoreER) processFile(fPath, fName string) (err error) {
 			}
 			return
 		}
+		rowNr++ // increment the rowNr after checking if it's not the end of file
+
 		if strings.HasPrefix(fName, rdr.Config().FailedCallsPrefix) { // Use the first index since they should be the same in all configs
 			record = append(record, "0") // Append duration 0 for failed calls flatstore CDR
 		} else {
@@ -180,6 +193,9 @@ func (rdr *FlatstoreER) processFile(fPath, fName string) (err error) {
 				rdr.cache.Remove(pr.OriginID)
 			}
 		}
+		if cp.processed(rowNr) {
+			continue // already processed before restart, read again only for pairing
+		}
 
 		// build Usage from Fields based on record lenght
 		for i, cntFld := range rdr.Config().Fields {
@@ -187,7 +203,6 @@ func (rdr *FlatstoreER) processFile(fPath, fName string) (err error) {
 				rdr.Config().Fields[i].Value = config.NewRSRParsersMustCompile("~*req."+strconv.Itoa(len(record)-1), true, utils.INFIELD_SEP) // in case of flatstore, last element will be the duration computed by us
 			}
 		}
-		rowNr++ // increment the rowNr after checking if it's not the end of file
 		agReq := agents.NewAgentRequest(
 			config.NewSliceDP(record), reqVars,
 			nil, nil, rdr.Config().Tenant,
@@ -206,11 +221,12 @@ func (rdr *FlatstoreER) processFile(fPath, fName string) (err error) {
 			continue
 		}
 
-		rdr.rdrEvents <- &erEvent{cgrEvent: agReq.CGRRequest.AsCGREvent(
+		cp.dispatch(rdr.rdrEvents, &erEvent{cgrEvent: agReq.CGRRequest.AsCGREvent(
 			agReq.Tenant, utils.NestingSep),
-			rdrCfg: rdr.Config()}
+			rdrCfg: rdr.Config()}, rowNr)
 		evsPosted++
 	}
+	cp.wait()
 	if rdr.Config().ProcessedPath != "" {
 		// Finished with file, move it to processed folder
 		outPath := path.Join(rdr.Config().ProcessedPath, fName)
@@ -218,6 +234,7 @@ func (rdr *FlatstoreER) processFile(fPath, fName string) (err error) {
 			return
 		}
 	}
+	cp.finish()
 
 	utils.Logger.Info(
 		fmt.Sprintf("%s finished processing file <%s>. Total records processed: %d, events posted: %d, run duration: %s",
@@ -0,0 +1,315 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package ers
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+const (
+	defaultHMACHeader  = "X-Signature"
+	hmacSHA256Prefix   = "sha256="
+	defaultMaxBodySize = 10 << 20 // 10MiB
+
+	httpEventOK       = "OK"
+	httpEventFiltered = "FILTERED"
+	httpEventError    = "ERROR"
+)
+
+var errHTTPUnauthorized = errors.New("unauthorized")
+
+// httpRdrs routes the HTTP requests towards the readers based on path
+// the server does not allow removing or registering twice the same path
+// so on reloads only the reader behind the path is replaced
+var httpRdrs = &httpReaders{
+	rdrs:       make(map[string]*HTTPER),
+	registered: make(map[string]struct{}),
+}
+
+type httpReaders struct {
+	sync.RWMutex
+	rdrs       map[string]*HTTPER  // active readers indexed on path
+	registered map[string]struct{} // paths already registered within the HTTP server
+}
+
+// listen registers the path within the server, once
+func (hr *httpReaders) listen(srv *utils.Server, path string) {
+	hr.Lock()
+	defer hr.Unlock()
+	if _, has := hr.registered[path]; has {
+		return
+	}
+	srv.RegisterHttpFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		hr.RLock()
+		rdr, has := hr.rdrs[path]
+		hr.RUnlock()
+		if !has { // reader stopped or disabled
+			http.NotFound(w, r)
+			return
+		}
+		rdr.ServeHTTP(w, r)
+	})
+	hr.registered[path] = struct{}{}
+}
+
+func (hr *httpReaders) add(rdr *HTTPER) {
+	hr.Lock()
+	hr.rdrs[rdr.path] = rdr
+	hr.Unlock()
+}
+
+// remove deletes the reader unless it was already replaced by a reload
+func (hr *httpReaders) remove(rdr *HTTPER) {
+	hr.Lock()
+	if hr.rdrs[rdr.path] == rdr {
+		delete(hr.rdrs, rdr.path)
+	}
+	hr.Unlock()
+}
+
+// NewHTTPER return a new HTTP event reader
+func NewHTTPER(cfg *config.CGRConfig, cfgIdx int,
+	rdrEvents chan *erEvent, rdrErr chan error,
+	fltrS *engine.FilterS, rdrExit chan struct{}) (er EventReader, err error) {
+	rdr := &HTTPER{
+		cgrCfg:    cfg,
+		cfgIdx:    cfgIdx,
+		fltrS:     fltrS,
+		rdrEvents: rdrEvents,
+		rdrExit:   rdrExit,
+		rdrErr:    rdrErr,
+	}
+	if concReq := rdr.Config().ConcurrentReqs; concReq != -1 {
+		rdr.cap = make(chan struct{}, concReq)
+		for i := 0; i < concReq; i++ {
+			rdr.cap <- struct{}{}
+		}
+	}
+	er = rdr
+	err = rdr.setURL(rdr.Config().SourcePath)
+	return
+}
+
+// HTTPER implements EventReader interface for events posted over HTTP
+// the reply contains the processing result for each event received
+type HTTPER struct {
+	cgrCfg *config.CGRConfig
+	cfgIdx int // index of config instance within ERsCfg.Readers
+	fltrS  *engine.FilterS
+
+	path         string // path registered within the HTTP server
+	authUser     string // basic authentication enforced if not empty
+	authPassword string
+	hmacSecret   string // requests signed with HMAC-SHA256 over the body if not empty
+	hmacHeader   string // header carrying the signature
+	maxBodySize  int64  // requests with bigger body are rejected
+
+	rdrEvents chan *erEvent // channel to dispatch the events created to
+	rdrExit   chan struct{}
+	rdrErr    chan error
+	cap       chan struct{}
+}
+
+// httpEventReply is the processing result of one event
+type httpEventReply struct {
+	Status string
+	Error  string `json:",omitempty"`
+}
+
+// Config returns the curent configuration
+func (rdr *HTTPER) Config() *config.EventReaderCfg {
+	return rdr.cgrCfg.ERsCfg().Readers[rdr.cfgIdx]
+}
+
+// Serve makes the reader reachable for requests on its path
+func (rdr *HTTPER) Serve() (err error) {
+	if rdr.Config().RunDelay == time.Duration(0) { // 0 disables the automatic read, maybe done per API
+		return
+	}
+	httpRdrs.add(rdr)
+	utils.Logger.Info(
+		fmt.Sprintf("<%s> listening for HTTP requests on path <%s>",
+			utils.ERs, rdr.path))
+	go func() {
+		<-rdr.rdrExit
+		utils.Logger.Info(
+			fmt.Sprintf("<%s> stop listening for HTTP requests on path <%s>",
+				utils.ERs, rdr.path))
+		httpRdrs.remove(rdr)
+	}()
+	return
+}
+
+// ServeHTTP processes the events out of one request
+func (rdr *HTTPER) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, rdr.maxBodySize))
+	if err != nil {
+		code := http.StatusBadRequest
+		if int64(len(body)) == rdr.maxBodySize { // stopped at the limit
+			code = http.StatusRequestEntityTooLarge
+		}
+		http.Error(w, err.Error(), code)
+		return
+	}
+	if err = rdr.authorize(r, body); err != nil {
+		if rdr.authUser != utils.EmptyString {
+			w.Header().Set("WWW-Authenticate", `Basic realm="`+utils.CGRateS+`"`)
+		}
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if rdr.cap != nil { // only the authorized requests wait for processing
+		token := <-rdr.cap
+		defer func() { rdr.cap <- token }()
+	}
+	evs, isBatch, err := rdr.decodeEvents(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	rplies := make([]*httpEventReply, len(evs))
+	for i, ev := range evs {
+		rplies[i] = rdr.processEvent(ev, r)
+	}
+	var rply interface{} = rplies
+	if !isBatch {
+		rply = rplies[0]
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(rply); err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> writing HTTP reply on path <%s>, error: %s",
+				utils.ERs, rdr.path, err.Error()))
+	}
+}
+
+// authorize checks the basic authentication and the HMAC signature if configured
+func (rdr *HTTPER) authorize(r *http.Request, body []byte) (err error) {
+	if rdr.authUser != utils.EmptyString {
+		user, passwd, has := r.BasicAuth()
+		if !has ||
+			subtle.ConstantTimeCompare([]byte(user), []byte(rdr.authUser)) != 1 ||
+			subtle.ConstantTimeCompare([]byte(passwd), []byte(rdr.authPassword)) != 1 {
+			return errHTTPUnauthorized
+		}
+	}
+	if rdr.hmacSecret != utils.EmptyString {
+		var sig []byte
+		if sig, err = hex.DecodeString(strings.TrimPrefix(
+			r.Header.Get(rdr.hmacHeader), hmacSHA256Prefix)); err != nil || len(sig) == 0 {
+			return errHTTPUnauthorized
+		}
+		mac := hmac.New(sha256.New, []byte(rdr.hmacSecret))
+		mac.Write(body)
+		if !hmac.Equal(sig, mac.Sum(nil)) {
+			return errHTTPUnauthorized
+		}
+	}
+	return
+}
+
+// decodeEvents returns the events out of body
+// *http_json accepts one object or a list of objects while *http_form accepts one event
+func (rdr *HTTPER) decodeEvents(body []byte) (evs []map[string]interface{}, isBatch bool, err error) {
+	if rdr.Config().Type == utils.MetaHTTPform {
+		var vals url.Values
+		if vals, err = url.ParseQuery(string(body)); err != nil {
+			return
+		}
+		ev := make(map[string]interface{})
+		for key := range vals {
+			ev[key] = vals.Get(key)
+		}
+		return []map[string]interface{}{ev}, false, nil
+	}
+	body = bytes.TrimSpace(body)
+	if isBatch = len(body) != 0 && body[0] == '['; isBatch {
+		err = json.Unmarshal(body, &evs)
+	} else {
+		var ev map[string]interface{}
+		err = json.Unmarshal(body, &ev)
+		evs = []map[string]interface{}{ev}
+	}
+	if err == nil && len(evs) == 0 {
+		err = errors.New("no events received")
+	}
+	return
+}
+
+// processEvent dispatches the event to ERs and waits for the processing result
+func (rdr *HTTPER) processEvent(ev map[string]interface{}, r *http.Request) (rply *httpEventReply) {
+	pass, err := dispatchEvent(ev, rdr.Config(), rdr.cgrCfg,
+		rdr.fltrS, rdr.rdrEvents, rdr.rdrExit, r.Context(), true) // stop dispatching if the client went away
+	if err != nil {
+		return &httpEventReply{Status: httpEventError, Error: err.Error()}
+	}
+	if !pass {
+		return &httpEventReply{Status: httpEventFiltered}
+	}
+	return &httpEventReply{Status: httpEventOK}
+}
+
+func (rdr *HTTPER) setURL(srcPath string) (err error) {
+	var u *url.URL
+	if u, err = url.Parse(srcPath); err != nil {
+		return
+	}
+	rdr.path = u.Path
+	qry := u.Query()
+	rdr.authUser = qry.Get(utils.HTTPAuthUser)
+	rdr.authPassword = qry.Get(utils.HTTPAuthPassword)
+	rdr.hmacSecret = qry.Get(utils.HTTPHMACSecret)
+	rdr.hmacHeader = defaultHMACHeader
+	if vals, has := qry[utils.HTTPHMACHeader]; has && len(vals) != 0 {
+		rdr.hmacHeader = vals[0]
+	}
+	rdr.maxBodySize = defaultMaxBodySize
+	if vals, has := qry[utils.HTTPMaxBodySize]; has && len(vals) != 0 {
+		if rdr.maxBodySize, err = strconv.ParseInt(vals[0], 10, 64); err != nil {
+			return
+		}
+		if rdr.maxBodySize <= 0 {
+			return fmt.Errorf("invalid %s: <%s>", utils.HTTPMaxBodySize, vals[0])
+		}
+	}
+	return
+}
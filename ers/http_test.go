@@ -0,0 +1,209 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package ers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestHTTPSetURL(t *testing.T) {
+	rdr := new(HTTPER)
+	expHTTP := &HTTPER{
+		path:         "/ers/usage",
+		authUser:     "cgrates",
+		authPassword: "secret",
+		hmacSecret:   "hmacKey",
+		hmacHeader:   "X-Hub-Signature-256",
+		maxBodySize:  1024,
+	}
+	if err := rdr.setURL("/ers/usage?auth_user=cgrates&auth_password=secret&hmac_secret=hmacKey&hmac_header=X-Hub-Signature-256&max_body_size=1024"); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(expHTTP, rdr) {
+		t.Errorf("Expected: %+v ,received: %+v", expHTTP, rdr)
+	}
+	rdr = new(HTTPER)
+	expHTTP = &HTTPER{
+		path:        "/ers/usage",
+		hmacHeader:  defaultHMACHeader,
+		maxBodySize: defaultMaxBodySize,
+	}
+	if err := rdr.setURL("/ers/usage"); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(expHTTP, rdr) {
+		t.Errorf("Expected: %+v ,received: %+v", expHTTP, rdr)
+	}
+	for _, srcPath := range []string{":", "/ers/usage?max_body_size=a", "/ers/usage?max_body_size=0"} {
+		if err := new(HTTPER).setURL(srcPath); err == nil {
+			t.Errorf("Expected error for %q", srcPath)
+		}
+	}
+}
+
+// newTestHTTPER returns a HTTP reader with events processed by processEv
+func newTestHTTPER(t *testing.T, rdrType, srcPath string,
+	processEv func(ev *utils.CGREvent) error) (rdr *HTTPER, rdrExit chan struct{}) {
+	cfg, _ := config.NewDefaultCGRConfig()
+	cfg.ERsCfg().Readers[0].Type = rdrType
+	cfg.ERsCfg().Readers[0].RunDelay = -1
+	cfg.ERsCfg().Readers[0].SourcePath = srcPath
+	cfg.ERsCfg().Readers[0].Filters = []string{"*prefix:~*req.Account:10"}
+	cfg.ERsCfg().Readers[0].Fields = []*config.FCTemplate{{
+		Tag:   utils.Account,
+		Path:  utils.MetaCgreq + utils.NestingSep + utils.Account,
+		Type:  utils.META_COMPOSED,
+		Value: config.NewRSRParsersMustCompile("~*req.Account", true, utils.INFIELD_SEP),
+	}}
+	dm := engine.NewDataManager(engine.NewInternalDB(nil, nil, true, cfg.DataDbCfg().Items),
+		cfg.CacheCfg(), nil)
+	rdrEvents := make(chan *erEvent)
+	rdrExit = make(chan struct{})
+	er, err := NewHTTPER(cfg, 0, rdrEvents, make(chan error, 1),
+		engine.NewFilterS(cfg, nil, dm), rdrExit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for {
+			select {
+			case ev := <-rdrEvents:
+				ev.procErr <- processEv(ev.cgrEvent)
+			case <-rdrExit:
+				return
+			}
+		}
+	}()
+	return er.(*HTTPER), rdrExit
+}
+
+func TestHTTPERServeHTTP(t *testing.T) {
+	var accounts []string
+	rdr, rdrExit := newTestHTTPER(t, utils.MetaHTTPjson, "/ers/usage?auth_user=cgrates&auth_password=secret&max_body_size=64",
+		func(ev *utils.CGREvent) error {
+			acnt := ev.Event[utils.Account].(string)
+			accounts = append(accounts, acnt)
+			if acnt == "1003" {
+				return utils.ErrAccountNotFound
+			}
+			return nil
+		})
+	defer close(rdrExit)
+	for _, test := range []struct {
+		method string
+		body   string
+		auth   bool
+		code   int
+		rply   string
+	}{
+		{http.MethodGet, ``, true, http.StatusMethodNotAllowed, "Method Not Allowed\n"},
+		{http.MethodPost, `{"Account":"1001"}`, false, http.StatusUnauthorized, "unauthorized\n"},
+		{http.MethodPost, `{"Account":`, true, http.StatusBadRequest, "unexpected end of JSON input\n"},
+		{http.MethodPost, `[]`, true, http.StatusBadRequest, "no events received\n"},
+		{http.MethodPost, `[{"Account":"1001"},{"Account":"1002"},{"Account":"1003"},{"Account":"1004"}]`, true,
+			http.StatusRequestEntityTooLarge, "http: request body too large\n"},
+		{http.MethodPost, `{"Account":"1001"}`, true, http.StatusOK, `{"Status":"OK"}` + "\n"},
+		{http.MethodPost, `[{"Account":"1002"},{"Account":"2001"},{"Account":"1003"}]`, true, http.StatusOK,
+			`[{"Status":"OK"},{"Status":"FILTERED"},{"Status":"ERROR","Error":"ACCOUNT_NOT_FOUND"}]` + "\n"},
+	} {
+		req := httptest.NewRequest(test.method, "/ers/usage", strings.NewReader(test.body))
+		if test.auth {
+			req.SetBasicAuth("cgrates", "secret")
+		}
+		w := httptest.NewRecorder()
+		rdr.ServeHTTP(w, req)
+		if w.Code != test.code {
+			t.Errorf("Expected code %d for %q, received: %d", test.code, test.body, w.Code)
+		}
+		if rcv := w.Body.String(); rcv != test.rply {
+			t.Errorf("Expected reply %q for %q, received: %q", test.rply, test.body, rcv)
+		}
+	}
+	if exp := []string{"1001", "1002", "1003"}; !reflect.DeepEqual(exp, accounts) {
+		t.Errorf("Expected: %v, received: %v", exp, accounts)
+	}
+}
+
+func TestHTTPERServeHTTPFormHMAC(t *testing.T) {
+	rdr, rdrExit := newTestHTTPER(t, utils.MetaHTTPform, "/ers/form?hmac_secret=hmacKey",
+		func(ev *utils.CGREvent) error {
+			if ev.Event[utils.Account] != "1001" {
+				return errors.New("unexpected account")
+			}
+			return nil
+		})
+	defer close(rdrExit)
+	body := "Account=1001&Usage=10s"
+	mac := hmac.New(sha256.New, []byte("hmacKey"))
+	mac.Write([]byte(body))
+	for sig, exp := range map[string]int{
+		hmacSHA256Prefix + hex.EncodeToString(mac.Sum(nil)): http.StatusOK,
+		hex.EncodeToString(mac.Sum(nil)):                    http.StatusOK,
+		"00":                                                http.StatusUnauthorized,
+		"invalid":                                           http.StatusUnauthorized,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/ers/form", strings.NewReader(body))
+		req.Header.Set(defaultHMACHeader, sig)
+		w := httptest.NewRecorder()
+		rdr.ServeHTTP(w, req)
+		if w.Code != exp {
+			t.Errorf("Expected code %d for signature %q, received: %d", exp, sig, w.Code)
+		} else if exp == http.StatusOK && w.Body.String() != `{"Status":"OK"}`+"\n" {
+			t.Errorf("Unexpected reply: %q", w.Body.String())
+		}
+	}
+}
+
+func TestHTTPReaders(t *testing.T) {
+	srv := utils.NewServer()
+	rdr, rdrExit := newTestHTTPER(t, utils.MetaHTTPjson, "/ers/registry",
+		func(ev *utils.CGREvent) error { return nil })
+	httpRdrs.listen(srv, rdr.path)
+	httpRdrs.listen(srv, rdr.path) // registering twice should not panic
+	if err := rdr.Serve(); err != nil {
+		t.Fatal(err)
+	}
+	httpRdrs.RLock()
+	if httpRdrs.rdrs[rdr.path] != rdr {
+		t.Error("Expected the reader to be registered")
+	}
+	httpRdrs.RUnlock()
+	close(rdrExit)
+	for i := 0; i < 100; i++ { // removed asynchronously
+		httpRdrs.RLock()
+		_, has := httpRdrs.rdrs[rdr.path]
+		httpRdrs.RUnlock()
+		if !has {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Error("Expected the reader to be removed")
+}
@@ -0,0 +1,783 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package ers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+// ASN.1 schema tokens
+const (
+	asn1FramingTS32297 = "*ts32297" // CDR files with header and CDR headers as in 3GPP TS 32.297
+	asn1FramingNone    = "*none"    // records are concatenated without any framing
+
+	asn1Integer     = "*integer"      // also used for ENUMERATED
+	asn1Boolean     = "*boolean"      // BOOLEAN
+	asn1String      = "*string"       // IA5String, UTF8String, PrintableString, etc.
+	asn1OctetString = "*octet_string" // hex representation of the content
+	asn1TBCD        = "*tbcd"         // TBCD-STRING (IMSI, IMEI)
+	asn1ISDNAddress = "*isdn_address" // AddressString (MSISDN), first octet is the nature of address
+	asn1PLMNId      = "*plmn_id"      // MCC and MNC
+	asn1IPAddress   = "*ip_address"   // binary or text IP address, also out of CHOICEs
+	asn1Timestamp   = "*timestamp"    // 3GPP TimeStamp, BCD YYMMDDhhmmssShhmm
+	asn1Sequence    = "*sequence"     // SEQUENCE, SET or CHOICE with fields identified by their tags
+	asn1List        = "*list"         // SEQUENCE OF or SET OF
+
+	berClassContext = 2 // context-specific tag class
+
+	// maxBERDepth limits the nesting of the constructed BER elements
+	maxBERDepth = 32
+)
+
+var (
+	errASN1Truncated = errors.New("truncated BER content")
+	tbcdDigits       = "0123456789*#abc"
+)
+
+// berTLV is one decoded BER element
+type berTLV struct {
+	class       byte
+	constructed bool
+	tag         int
+	value       []byte    // content octets
+	children    []*berTLV // populated for constructed elements
+}
+
+// decodeBER decodes the first BER element out of data returning the remaining bytes
+// both definite and indefinite length forms are supported
+func decodeBER(data []byte) (tlv *berTLV, rest []byte, err error) {
+	return decodeBERElement(data, 0)
+}
+
+// decodeBERElement decodes one BER element nested at depth within the decoded one
+func decodeBERElement(data []byte, depth int) (tlv *berTLV, rest []byte, err error) {
+	if depth >= maxBERDepth {
+		return nil, nil, fmt.Errorf("BER elements nested deeper than %d", maxBERDepth)
+	}
+	if len(data) < 2 {
+		return nil, nil, errASN1Truncated
+	}
+	tlv = &berTLV{
+		class:       data[0] >> 6,
+		constructed: data[0]&0x20 != 0,
+		tag:         int(data[0] & 0x1f),
+	}
+	i := 1
+	if tlv.tag == 0x1f { // high tag number form
+		tlv.tag = 0
+		for {
+			if i >= len(data) {
+				return nil, nil, errASN1Truncated
+			}
+			if i > 4 {
+				return nil, nil, errors.New("BER tag number too big")
+			}
+			b := data[i]
+			i++
+			tlv.tag = tlv.tag<<7 | int(b&0x7f)
+			if b&0x80 == 0 {
+				break
+			}
+		}
+	}
+	if i >= len(data) {
+		return nil, nil, errASN1Truncated
+	}
+	length := int(data[i])
+	i++
+	if length == 0x80 { // indefinite length, content ends with the end-of-contents octets
+		if !tlv.constructed {
+			return nil, nil, errors.New("indefinite length for primitive BER element")
+		}
+		rest = data[i:]
+		for {
+			if len(rest) >= 2 && rest[0] == 0 && rest[1] == 0 {
+				tlv.value = data[i : len(data)-len(rest)]
+				return tlv, rest[2:], nil
+			}
+			var child *berTLV
+			if child, rest, err = decodeBERElement(rest, depth+1); err != nil {
+				return nil, nil, err
+			}
+			tlv.children = append(tlv.children, child)
+		}
+	}
+	if length > 0x80 { // long form
+		nOctets := length & 0x7f
+		if nOctets > 4 {
+			return nil, nil, errors.New("BER length too big")
+		}
+		if i+nOctets > len(data) {
+			return nil, nil, errASN1Truncated
+		}
+		length = 0
+		for _, b := range data[i : i+nOctets] {
+			length = length<<8 | int(b)
+		}
+		i += nOctets
+	}
+	if i+length > len(data) {
+		return nil, nil, errASN1Truncated
+	}
+	tlv.value = data[i : i+length]
+	rest = data[i+length:]
+	if tlv.constructed {
+		for content := tlv.value; len(content) != 0; {
+			var child *berTLV
+			if child, content, err = decodeBERElement(content, depth+1); err != nil {
+				return nil, nil, err
+			}
+			tlv.children = append(tlv.children, child)
+		}
+	}
+	return
+}
+
+// ASN1Schema describes how the BER records are decoded into events
+type ASN1Schema struct {
+	Framing string                        `json:"framing"` // file framing <*ts32297|*none>
+	Records map[int]*ASN1Field            `json:"records"` // the record CHOICEs indexed on their tag
+	Types   map[string]map[int]*ASN1Field `json:"types"`   // reusable field definitions referenced by name
+}
+
+// ASN1Field describes one element within the schema
+type ASN1Field struct {
+	Name   string             `json:"name"`
+	Type   string             `json:"type"`
+	Ref    string             `json:"ref"`    // name of the type holding the Fields of a *sequence
+	Fields map[int]*ASN1Field `json:"fields"` // fields of a *sequence indexed on their tag
+	Item   *ASN1Field         `json:"item"`   // definition of the *list items
+}
+
+// NewASN1Schema parses the JSON schema definition
+func NewASN1Schema(content []byte) (sch *ASN1Schema, err error) {
+	sch = new(ASN1Schema)
+	if err = json.Unmarshal(content, sch); err != nil {
+		return nil, err
+	}
+	if sch.Framing == utils.EmptyString {
+		sch.Framing = asn1FramingTS32297
+	}
+	if sch.Framing != asn1FramingTS32297 && sch.Framing != asn1FramingNone {
+		return nil, fmt.Errorf("unsupported framing: <%s>", sch.Framing)
+	}
+	if len(sch.Records) == 0 {
+		return nil, errors.New("no records defined")
+	}
+	for tag, fld := range sch.Records {
+		if err = sch.compile(fld); err != nil {
+			return nil, fmt.Errorf("record with tag %d: %s", tag, err.Error())
+		}
+	}
+	return
+}
+
+// NewASN1SchemaFromFile reads the schema from file
+// the built-in 3GPP TS 32.298 schema is returned for empty path
+func NewASN1SchemaFromFile(fPath string) (sch *ASN1Schema, err error) {
+	content := []byte(asn1TS32298Schema)
+	if fPath != utils.EmptyString {
+		if content, err = ioutil.ReadFile(fPath); err != nil {
+			return
+		}
+	}
+	return NewASN1Schema(content)
+}
+
+// compile checks the field definition and resolves the references to types
+func (sch *ASN1Schema) compile(fld *ASN1Field) (err error) {
+	if fld == nil {
+		return errors.New("empty field definition")
+	}
+	switch fld.Type {
+	case asn1Integer, asn1Boolean, asn1String, asn1OctetString, asn1TBCD,
+		asn1ISDNAddress, asn1PLMNId, asn1IPAddress, asn1Timestamp:
+	case asn1List:
+		if fld.Item == nil { // items will be decoded as hex
+			return
+		}
+		return sch.compile(fld.Item)
+	case asn1Sequence:
+		if fld.Ref != utils.EmptyString {
+			if fld.Fields != nil { // already resolved
+				return
+			}
+			var has bool
+			if fld.Fields, has = sch.Types[fld.Ref]; !has {
+				return fmt.Errorf("unknown type: <%s> for field: <%s>", fld.Ref, fld.Name)
+			}
+		}
+		for _, subFld := range fld.Fields {
+			if subFld.Type == asn1Sequence && subFld.Ref == fld.Ref &&
+				fld.Ref != utils.EmptyString { // recursive type, already being compiled
+				subFld.Fields = fld.Fields
+				continue
+			}
+			if err = sch.compile(subFld); err != nil {
+				return
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported type: <%s> for field: <%s>", fld.Type, fld.Name)
+	}
+	return
+}
+
+// decode converts the BER element into its value based on the field definition
+func (fld *ASN1Field) decode(tlv *berTLV) (val interface{}, err error) {
+	switch fld.Type {
+	case asn1Sequence:
+		mp := make(map[string]interface{})
+		for _, child := range tlv.children {
+			subFld, has := fld.Fields[child.tag]
+			if !has || child.class != berClassContext { // not defined within schema
+				continue
+			}
+			if mp[subFld.Name], err = subFld.decode(child); err != nil {
+				return nil, fmt.Errorf("field <%s>: %s", subFld.Name, err.Error())
+			}
+		}
+		return mp, nil
+	case asn1List:
+		items := make([]interface{}, len(tlv.children))
+		for i, child := range tlv.children {
+			if fld.Item == nil {
+				items[i] = hex.EncodeToString(child.value)
+				continue
+			}
+			if items[i], err = fld.Item.decode(child); err != nil {
+				return nil, fmt.Errorf("item %d: %s", i, err.Error())
+			}
+		}
+		return items, nil
+	case asn1IPAddress:
+		return decodeASN1IPAddress(tlv)
+	}
+	if tlv.constructed {
+		return nil, fmt.Errorf("constructed element for %s type", fld.Type)
+	}
+	switch fld.Type {
+	case asn1Integer:
+		return decodeASN1Integer(tlv.value)
+	case asn1Boolean:
+		if len(tlv.value) != 1 {
+			return nil, errors.New("invalid boolean length")
+		}
+		return tlv.value[0] != 0, nil
+	case asn1String:
+		return string(tlv.value), nil
+	case asn1OctetString:
+		return hex.EncodeToString(tlv.value), nil
+	case asn1TBCD:
+		return decodeTBCD(tlv.value), nil
+	case asn1ISDNAddress:
+		if len(tlv.value) == 0 {
+			return utils.EmptyString, nil
+		}
+		return decodeTBCD(tlv.value[1:]), nil // skip the nature of address octet
+	case asn1PLMNId:
+		return decodePLMNId(tlv.value)
+	case asn1Timestamp:
+		return decodeASN1Timestamp(tlv.value)
+	}
+	return nil, fmt.Errorf("unsupported type: <%s>", fld.Type)
+}
+
+// decodeASN1Integer decodes the two's complement big endian integer
+func decodeASN1Integer(b []byte) (i int64, err error) {
+	if len(b) == 0 || len(b) > 8 {
+		return 0, fmt.Errorf("invalid integer length: %d", len(b))
+	}
+	for _, o := range b {
+		i = i<<8 | int64(o)
+	}
+	if b[0]&0x80 != 0 && len(b) < 8 { // negative
+		i -= 1 << (uint(len(b)) * 8)
+	}
+	return
+}
+
+// decodeTBCD decodes the telephony BCD string, low nibble first
+func decodeTBCD(b []byte) string {
+	digits := make([]byte, 0, len(b)*2)
+	for _, o := range b {
+		for _, n := range []byte{o & 0x0f, o >> 4} {
+			if n == 0x0f { // filler
+				return string(digits)
+			}
+			digits = append(digits, tbcdDigits[n])
+		}
+	}
+	return string(digits)
+}
+
+// decodePLMNId returns the MCC followed by the MNC
+func decodePLMNId(b []byte) (plmn string, err error) {
+	if len(b) != 3 {
+		return utils.EmptyString, fmt.Errorf("invalid PLMN-Id length: %d", len(b))
+	}
+	mcc := decodeTBCD([]byte{b[0], b[1] | 0xf0})
+	mnc := decodeTBCD([]byte{b[2]})
+	if b[1]>>4 != 0x0f { // three digits MNC
+		mnc += decodeTBCD([]byte{b[1]>>4 | 0xf0})
+	}
+	return mcc + mnc, nil
+}
+
+// decodeASN1Timestamp decodes the 3GPP TimeStamp into RFC3339 format
+func decodeASN1Timestamp(b []byte) (ts string, err error) {
+	if len(b) != 6 && len(b) != 9 {
+		return utils.EmptyString, fmt.Errorf("invalid timestamp length: %d", len(b))
+	}
+	var d [6]int
+	for i := range d {
+		if d[i], err = strconv.Atoi(fmt.Sprintf("%02x", b[i])); err != nil {
+			return utils.EmptyString, fmt.Errorf("invalid timestamp: %x", b)
+		}
+	}
+	loc := time.UTC
+	if len(b) == 9 {
+		var hh, mm int
+		if hh, err = strconv.Atoi(fmt.Sprintf("%02x", b[7])); err != nil {
+			return utils.EmptyString, fmt.Errorf("invalid timestamp: %x", b)
+		}
+		if mm, err = strconv.Atoi(fmt.Sprintf("%02x", b[8])); err != nil {
+			return utils.EmptyString, fmt.Errorf("invalid timestamp: %x", b)
+		}
+		offset := hh*3600 + mm*60
+		if b[6] == '-' {
+			offset = -offset
+		}
+		loc = time.FixedZone(utils.EmptyString, offset)
+	}
+	return time.Date(2000+d[0], time.Month(d[1]), d[2], d[3], d[4], d[5], 0, loc).Format(time.RFC3339), nil
+}
+
+// decodeASN1IPAddress decodes the IPAddress CHOICE
+// the first primitive element is used in case of constructed ones
+func decodeASN1IPAddress(tlv *berTLV) (ip string, err error) {
+	for tlv.constructed {
+		if len(tlv.children) == 0 {
+			return utils.EmptyString, errors.New("empty IP address")
+		}
+		tlv = tlv.children[0]
+	}
+	switch {
+	case tlv.tag == 2 || tlv.tag == 3: // iPTextV4Address or iPTextV6Address
+		return string(tlv.value), nil
+	case len(tlv.value) == net.IPv4len || len(tlv.value) == net.IPv6len:
+		return net.IP(tlv.value).String(), nil
+	}
+	return hex.EncodeToString(tlv.value), nil
+}
+
+// decodeTS32297Header decodes the fixed part of the CDR file header
+func decodeTS32297Header(data []byte) (hdr map[string]interface{}, hdrLen int, err error) {
+	if len(data) < 27 {
+		return nil, 0, errASN1Truncated
+	}
+	hdr = map[string]interface{}{
+		"fileLength":               int64(beUint(data[0:4])),
+		"headerLength":             int64(beUint(data[4:8])),
+		"highReleaseIdentifier":    int64(data[8]),
+		"lowReleaseIdentifier":     int64(data[9]),
+		"numberOfCDRs":             int64(beUint(data[18:22])),
+		"fileSequenceNumber":       int64(beUint(data[22:26])),
+		"fileClosureTriggerReason": int64(data[26]),
+	}
+	if len(data) > 47 {
+		hdr["lostCDRIndicator"] = int64(data[47])
+	}
+	hdrLen = int(beUint(data[4:8]))
+	if hdrLen > len(data) {
+		return nil, 0, errASN1Truncated
+	}
+	return
+}
+
+// beUint decodes the big endian unsigned integer
+func beUint(b []byte) (u uint64) {
+	for _, o := range b {
+		u = u<<8 | uint64(o)
+	}
+	return
+}
+
+// asn1CDR is one record out of the file
+type asn1CDR struct {
+	format int // data record format out of CDR header, 1 for BER
+	data   []byte
+}
+
+// splitTS32297CDRs returns the CDRs after the file header
+func splitTS32297CDRs(data []byte) (cdrs []*asn1CDR, err error) {
+	for len(data) != 0 {
+		if len(data) < 4 {
+			return nil, errASN1Truncated
+		}
+		cdrLen := int(beUint(data[0:2]))
+		hdrLen := 4
+		if data[2]>>5 == 7 { // release identifier extension present
+			hdrLen = 5
+		}
+		if hdrLen+cdrLen > len(data) {
+			return nil, errASN1Truncated
+		}
+		cdrs = append(cdrs, &asn1CDR{
+			format: int(data[3] >> 5),
+			data:   data[hdrLen : hdrLen+cdrLen],
+		})
+		data = data[hdrLen+cdrLen:]
+	}
+	return
+}
+
+// asn1TS32298Schema is the built-in schema decoding the PGW and SGW records out of 3GPP TS 32.298
+const asn1TS32298Schema = `{
+	"framing": "*ts32297",
+	"records": {
+		"78": {
+			"name": "sGWRecord",
+			"ref": "SGWRecord",
+			"type": "*sequence"
+		},
+		"79": {
+			"name": "pGWRecord",
+			"ref": "PGWRecord",
+			"type": "*sequence"
+		}
+	},
+	"types": {
+		"ChangeOfCharCondition": {
+			"3": {
+				"name": "dataVolumeGPRSUplink",
+				"type": "*integer"
+			},
+			"4": {
+				"name": "dataVolumeGPRSDownlink",
+				"type": "*integer"
+			},
+			"5": {
+				"name": "changeCondition",
+				"type": "*integer"
+			},
+			"6": {
+				"name": "changeTime",
+				"type": "*timestamp"
+			},
+			"8": {
+				"name": "userLocationInformation",
+				"type": "*octet_string"
+			}
+		},
+		"ChangeOfServiceCondition": {
+			"1": {
+				"name": "ratingGroup",
+				"type": "*integer"
+			},
+			"2": {
+				"name": "chargingRuleBaseName",
+				"type": "*string"
+			},
+			"3": {
+				"name": "resultCode",
+				"type": "*integer"
+			},
+			"4": {
+				"name": "localSequenceNumber",
+				"type": "*integer"
+			},
+			"5": {
+				"name": "timeOfFirstUsage",
+				"type": "*timestamp"
+			},
+			"6": {
+				"name": "timeOfLastUsage",
+				"type": "*timestamp"
+			},
+			"7": {
+				"name": "timeUsage",
+				"type": "*integer"
+			},
+			"8": {
+				"name": "serviceConditionChange",
+				"type": "*octet_string"
+			},
+			"12": {
+				"name": "datavolumeFBCUplink",
+				"type": "*integer"
+			},
+			"13": {
+				"name": "datavolumeFBCDownlink",
+				"type": "*integer"
+			},
+			"14": {
+				"name": "timeOfReport",
+				"type": "*timestamp"
+			},
+			"17": {
+				"name": "serviceIdentifier",
+				"type": "*integer"
+			},
+			"20": {
+				"name": "userLocationInformation",
+				"type": "*octet_string"
+			}
+		},
+		"PGWRecord": {
+			"0": {
+				"name": "recordType",
+				"type": "*integer"
+			},
+			"3": {
+				"name": "servedIMSI",
+				"type": "*tbcd"
+			},
+			"4": {
+				"name": "p-GWAddress",
+				"type": "*ip_address"
+			},
+			"5": {
+				"name": "chargingID",
+				"type": "*integer"
+			},
+			"6": {
+				"item": {
+					"name": "gsnAddress",
+					"type": "*ip_address"
+				},
+				"name": "servingNodeAddress",
+				"type": "*list"
+			},
+			"7": {
+				"name": "accessPointNameNI",
+				"type": "*string"
+			},
+			"8": {
+				"name": "pdpPDNType",
+				"type": "*octet_string"
+			},
+			"9": {
+				"name": "servedPDPPDNAddress",
+				"type": "*ip_address"
+			},
+			"13": {
+				"name": "recordOpeningTime",
+				"type": "*timestamp"
+			},
+			"14": {
+				"name": "duration",
+				"type": "*integer"
+			},
+			"15": {
+				"name": "causeForRecClosing",
+				"type": "*integer"
+			},
+			"17": {
+				"name": "recordSequenceNumber",
+				"type": "*integer"
+			},
+			"18": {
+				"name": "nodeID",
+				"type": "*string"
+			},
+			"20": {
+				"name": "localSequenceNumber",
+				"type": "*integer"
+			},
+			"22": {
+				"name": "servedMSISDN",
+				"type": "*isdn_address"
+			},
+			"23": {
+				"name": "chargingCharacteristics",
+				"type": "*octet_string"
+			},
+			"27": {
+				"name": "servingNodePLMNIdentifier",
+				"type": "*plmn_id"
+			},
+			"29": {
+				"name": "servedIMEISV",
+				"type": "*tbcd"
+			},
+			"30": {
+				"name": "rATType",
+				"type": "*integer"
+			},
+			"31": {
+				"name": "mSTimeZone",
+				"type": "*octet_string"
+			},
+			"32": {
+				"name": "userLocationInformation",
+				"type": "*octet_string"
+			},
+			"34": {
+				"item": {
+					"name": "changeOfServiceCondition",
+					"ref": "ChangeOfServiceCondition",
+					"type": "*sequence"
+				},
+				"name": "listOfServiceData",
+				"type": "*list"
+			},
+			"35": {
+				"item": {
+					"name": "servingNodeType",
+					"type": "*integer"
+				},
+				"name": "servingNodeType",
+				"type": "*list"
+			},
+			"38": {
+				"name": "startTime",
+				"type": "*timestamp"
+			},
+			"39": {
+				"name": "stopTime",
+				"type": "*timestamp"
+			}
+		},
+		"SGWRecord": {
+			"0": {
+				"name": "recordType",
+				"type": "*integer"
+			},
+			"3": {
+				"name": "servedIMSI",
+				"type": "*tbcd"
+			},
+			"4": {
+				"name": "s-GWAddress",
+				"type": "*ip_address"
+			},
+			"5": {
+				"name": "chargingID",
+				"type": "*integer"
+			},
+			"6": {
+				"item": {
+					"name": "gsnAddress",
+					"type": "*ip_address"
+				},
+				"name": "servingNodeAddress",
+				"type": "*list"
+			},
+			"7": {
+				"name": "accessPointNameNI",
+				"type": "*string"
+			},
+			"8": {
+				"name": "pdpPDNType",
+				"type": "*octet_string"
+			},
+			"9": {
+				"name": "servedPDPPDNAddress",
+				"type": "*ip_address"
+			},
+			"12": {
+				"item": {
+					"name": "changeOfCharCondition",
+					"ref": "ChangeOfCharCondition",
+					"type": "*sequence"
+				},
+				"name": "listOfTrafficVolumes",
+				"type": "*list"
+			},
+			"13": {
+				"name": "recordOpeningTime",
+				"type": "*timestamp"
+			},
+			"14": {
+				"name": "duration",
+				"type": "*integer"
+			},
+			"15": {
+				"name": "causeForRecClosing",
+				"type": "*integer"
+			},
+			"17": {
+				"name": "recordSequenceNumber",
+				"type": "*integer"
+			},
+			"18": {
+				"name": "nodeID",
+				"type": "*string"
+			},
+			"20": {
+				"name": "localSequenceNumber",
+				"type": "*integer"
+			},
+			"22": {
+				"name": "servedMSISDN",
+				"type": "*isdn_address"
+			},
+			"23": {
+				"name": "chargingCharacteristics",
+				"type": "*octet_string"
+			},
+			"27": {
+				"name": "servingNodePLMNIdentifier",
+				"type": "*plmn_id"
+			},
+			"29": {
+				"name": "servedIMEISV",
+				"type": "*tbcd"
+			},
+			"30": {
+				"name": "rATType",
+				"type": "*integer"
+			},
+			"31": {
+				"name": "mSTimeZone",
+				"type": "*octet_string"
+			},
+			"32": {
+				"name": "userLocationInformation",
+				"type": "*octet_string"
+			},
+			"35": {
+				"item": {
+					"name": "servingNodeType",
+					"type": "*integer"
+				},
+				"name": "servingNodeType",
+				"type": "*list"
+			},
+			"38": {
+				"name": "startTime",
+				"type": "*timestamp"
+			},
+			"39": {
+				"name": "stopTime",
+				"type": "*timestamp"
+			}
+		}
+	}
+}`
@@ -0,0 +1,224 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package ers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+// berBytes encodes the identifier octets and content using the definite length form
+func berBytes(id []byte, content ...byte) (b []byte) {
+	b = append(b, id...)
+	if len(content) < 0x80 {
+		b = append(b, byte(len(content)))
+	} else {
+		b = append(b, 0x82, byte(len(content)>>8), byte(len(content)))
+	}
+	return append(b, content...)
+}
+
+// testPGWRecord returns a pGWRecord encoded in BER
+func testPGWRecord() (rec []byte) {
+	var content []byte
+	for _, fld := range [][]byte{
+		berBytes([]byte{0x80}, 0x55), // recordType
+		berBytes([]byte{0x83}, 0x02, 0x08, 0x11, 0x32, 0x54, 0x76, 0x98, 0xf0),      // servedIMSI
+		berBytes([]byte{0xa4}, berBytes([]byte{0x80}, 10, 0, 0, 1)...),              // p-GWAddress
+		berBytes([]byte{0x85}, 0x00, 0x00, 0x30, 0x39),                              // chargingID
+		berBytes([]byte{0xa6}, berBytes([]byte{0x80}, 192, 168, 0, 1)...),           // servingNodeAddress
+		berBytes([]byte{0x87}, []byte("internet")...),                               // accessPointNameNI
+		berBytes([]byte{0x8d}, 0x20, 0x10, 0x18, 0x12, 0x30, 0x45, '+', 0x03, 0x00), // recordOpeningTime
+		berBytes([]byte{0x8e}, 0x01, 0x2c),                                          // duration
+		berBytes([]byte{0x96}, 0x91, 0x04, 0x17, 0x32, 0x54, 0x76, 0xf8),            // servedMSISDN
+		berBytes([]byte{0x9b}, 0x02, 0xf8, 0x10),                                    // servingNodePLMNIdentifier
+		berBytes([]byte{0x9f, 0x63}, 0x00),                                          // not defined within schema
+	} {
+		content = append(content, fld...)
+	}
+	// listOfServiceData using the indefinite length form
+	content = append(content, 0xbf, 0x22, 0x80)
+	content = append(content, berBytes([]byte{0x30},
+		append(append(berBytes([]byte{0x81}, 0x0a),
+			berBytes([]byte{0x8c}, 0x03, 0xe8)...),
+			berBytes([]byte{0x8d}, 0x07, 0xd0)...)...)...)
+	content = append(content, 0x00, 0x00)
+	return berBytes([]byte{0xbf, 0x4f}, content...)
+}
+
+func TestDecodeBER(t *testing.T) {
+	rec := testPGWRecord()
+	tlv, rest, err := decodeBER(append(rec, 0x01))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rest, []byte{0x01}) {
+		t.Errorf("Expected rest: %x, received: %x", []byte{0x01}, rest)
+	}
+	if tlv.class != berClassContext || !tlv.constructed || tlv.tag != 79 {
+		t.Errorf("Unexpected element: %+v", tlv)
+	}
+	if len(tlv.children) != 12 {
+		t.Fatalf("Expected 12 children, received: %d", len(tlv.children))
+	}
+	if lst := tlv.children[11]; lst.tag != 34 || len(lst.children) != 1 ||
+		len(lst.children[0].children) != 3 {
+		t.Errorf("Unexpected indefinite length element: %+v", lst)
+	}
+	long := berBytes([]byte{0x04}, make([]byte, 300)...)
+	if tlv, rest, err = decodeBER(long); err != nil {
+		t.Fatal(err)
+	} else if len(tlv.value) != 300 || len(rest) != 0 {
+		t.Errorf("Unexpected long form element: %d bytes, rest: %x", len(tlv.value), rest)
+	}
+	for _, data := range [][]byte{
+		{0x80},
+		{0x80, 0x02, 0x01},
+		{0x9f},
+		{0x80, 0x80, 0x00, 0x00},
+		{0xa0, 0x80, 0x80, 0x01, 0x01},
+		{0x80, 0x85, 0x01, 0x01, 0x01, 0x01, 0x01},
+	} {
+		if _, _, err := decodeBER(data); err == nil {
+			t.Errorf("Expected error for: %x", data)
+		}
+	}
+
+	// constructed elements with indefinite length nested within each other
+	nested := func(depth int) (data []byte) {
+		for i := 0; i < depth; i++ {
+			data = append(data, 0xa0, 0x80)
+		}
+		for i := 0; i < depth; i++ {
+			data = append(data, 0x00, 0x00)
+		}
+		return
+	}
+	if _, _, err := decodeBER(nested(maxBERDepth)); err != nil {
+		t.Error(err)
+	}
+	if _, _, err := decodeBER(nested(maxBERDepth + 1)); err == nil ||
+		err.Error() != "BER elements nested deeper than 32" {
+		t.Errorf("Expected depth error, received: %v", err)
+	}
+}
+
+func TestASN1FieldDecode(t *testing.T) {
+	sch, err := NewASN1SchemaFromFile(utils.EmptyString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tlv, _, err := decodeBER(testPGWRecord())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rcv, err := sch.Records[tlv.tag].decode(tlv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := map[string]interface{}{
+		"recordType":                int64(85),
+		"servedIMSI":                "208011234567890",
+		"p-GWAddress":               "10.0.0.1",
+		"chargingID":                int64(12345),
+		"servingNodeAddress":        []interface{}{"192.168.0.1"},
+		"accessPointNameNI":         "internet",
+		"recordOpeningTime":         "2020-10-18T12:30:45+03:00",
+		"duration":                  int64(300),
+		"servedMSISDN":              "40712345678",
+		"servingNodePLMNIdentifier": "20801",
+		"listOfServiceData": []interface{}{map[string]interface{}{
+			"ratingGroup":           int64(10),
+			"datavolumeFBCUplink":   int64(1000),
+			"datavolumeFBCDownlink": int64(2000),
+		}},
+	}
+	if !reflect.DeepEqual(exp, rcv) {
+		t.Errorf("Expected: %s, received: %s", utils.ToJSON(exp), utils.ToJSON(rcv))
+	}
+}
+
+func TestASN1DecodeValues(t *testing.T) {
+	if rcv, err := decodeASN1Integer([]byte{0xff, 0x38}); err != nil {
+		t.Error(err)
+	} else if rcv != -200 {
+		t.Errorf("Expected: -200, received: %d", rcv)
+	}
+	if _, err := decodeASN1Integer(nil); err == nil {
+		t.Error("Expected error")
+	}
+	if rcv := decodeTBCD([]byte{0x21, 0xf3}); rcv != "123" {
+		t.Errorf("Expected: 123, received: %s", rcv)
+	}
+	if rcv, err := decodePLMNId([]byte{0x13, 0x00, 0x62}); err != nil {
+		t.Error(err)
+	} else if rcv != "310260" {
+		t.Errorf("Expected: 310260, received: %s", rcv)
+	}
+	if rcv, err := decodeASN1Timestamp([]byte{0x20, 0x01, 0x02, 0x03, 0x04, 0x05, '-', 0x05, 0x30}); err != nil {
+		t.Error(err)
+	} else if rcv != "2020-01-02T03:04:05-05:30" {
+		t.Errorf("Expected: 2020-01-02T03:04:05-05:30, received: %s", rcv)
+	}
+	if _, err := decodeASN1Timestamp([]byte{0x20, 0x0a, 0x02, 0x03, 0x04, 0x05}); err == nil {
+		t.Error("Expected error")
+	}
+	if rcv, err := decodeASN1IPAddress(&berTLV{tag: 2, value: []byte("fe80::1")}); err != nil {
+		t.Error(err)
+	} else if rcv != "fe80::1" {
+		t.Errorf("Expected: fe80::1, received: %s", rcv)
+	}
+}
+
+func TestNewASN1Schema(t *testing.T) {
+	sch, err := NewASN1Schema([]byte(`{
+"records": {"0": {"name": "callRecord", "type": "*sequence", "fields": {
+	"1": {"name": "caller", "type": "*isdn_address"},
+	"2": {"name": "tags", "type": "*list"}}}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sch.Framing != asn1FramingTS32297 {
+		t.Errorf("Expected framing: %s, received: %s", asn1FramingTS32297, sch.Framing)
+	}
+	rcv, err := sch.Records[0].decode(&berTLV{constructed: true, children: []*berTLV{
+		{class: berClassContext, tag: 1, value: []byte{0x91, 0x10}},
+		{class: berClassContext, tag: 2, constructed: true, children: []*berTLV{{value: []byte{0xab}}}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := map[string]interface{}{"caller": "01", "tags": []interface{}{"ab"}}
+	if !reflect.DeepEqual(exp, rcv) {
+		t.Errorf("Expected: %s, received: %s", utils.ToJSON(exp), utils.ToJSON(rcv))
+	}
+	for _, schema := range []string{
+		`{"records": {}}`,
+		`{"framing": "*per", "records": {"0": {"name": "rec", "type": "*sequence"}}}`,
+		`{"records": {"0": {"name": "rec", "type": "*real"}}}`,
+		`{"records": {"0": {"name": "rec", "type": "*sequence", "ref": "Missing"}}}`,
+		`{"records": `,
+	} {
+		if _, err := NewASN1Schema([]byte(schema)); err == nil {
+			t.Errorf("Expected error for schema: %s", schema)
+		}
+	}
+}
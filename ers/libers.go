@@ -19,9 +19,29 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package ers
 
 import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path"
 	"path/filepath"
+	"strconv"
+	"strings"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/cgrates/cgrates/agents"
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
 	"github.com/cgrates/cgrates/utils"
 	"github.com/fsnotify/fsnotify"
 )
@@ -65,3 +85,275 @@ func watchDir(dirPath string, f func(itmPath, itmID string) error,
 	}()
 	return
 }
+
+// newAWSSession creates the session used by the AWS readers
+// the credentials are taken from the environment if not specified
+func newAWSSession(endpoint, region, id, key, token string) (*session.Session, error) {
+	cfg := aws.Config{Endpoint: aws.String(endpoint)}
+	if len(region) != 0 {
+		cfg.Region = aws.String(region)
+	}
+	if len(id) != 0 &&
+		len(key) != 0 {
+		cfg.Credentials = credentials.NewStaticCredentials(id, key, token)
+	}
+	return session.NewSessionWithOptions(
+		session.Options{
+			Config: cfg,
+		},
+	)
+}
+
+// compressed file formats recognized by their first bytes
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	bzip2Magic = []byte("BZh")
+	zipMagic   = []byte("PK\x03\x04")
+)
+
+// hasFileSuffix checks if the file should be processed by a reader expecting the suffix
+// the compressed files are also accepted since their content is only known after decompression
+func hasFileSuffix(fName, suffix string) bool {
+	for _, sfx := range []string{suffix, utils.GzipSuffix, utils.Bzip2Suffix, utils.ZipSuffix} {
+		if strings.HasSuffix(fName, sfx) {
+			return true
+		}
+	}
+	return false
+}
+
+// compressionFormat returns the compression of the file based on extension or magic bytes
+// empty string is returned for the uncompressed files
+func compressionFormat(absPath string) (format string, err error) {
+	for _, sfx := range []string{utils.GzipSuffix, utils.Bzip2Suffix, utils.ZipSuffix} {
+		if strings.HasSuffix(absPath, sfx) {
+			return sfx, nil
+		}
+	}
+	var file *os.File
+	if file, err = os.Open(absPath); err != nil {
+		return
+	}
+	defer file.Close()
+	magic := make([]byte, 4)
+	var n int
+	if n, err = io.ReadFull(file, magic); err != nil &&
+		err != io.EOF && err != io.ErrUnexpectedEOF {
+		return
+	}
+	err = nil
+	magic = magic[:n]
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		format = utils.GzipSuffix
+	case bytes.HasPrefix(magic, bzip2Magic):
+		format = utils.Bzip2Suffix
+	case bytes.HasPrefix(magic, zipMagic):
+		format = utils.ZipSuffix
+	}
+	return
+}
+
+// decompressingProcessor wraps the processFile function of a file reader so the
+// compressed files are transparently decompressed before being processed
+// each file out of the archive is processed individually, under its own name (see decompressFile),
+// after being extracted in a temporary folder inside the processedPath so it can be moved there afterwards
+// the archive is removed once all its files were processed and moved to the processedPath
+// maxSize limits the total size of the files extracted out of one archive, 0 for unlimited
+func decompressingProcessor(processFile func(fPath, fName string) error,
+	suffix, processedPath string, maxSize int64) func(fPath, fName string) error {
+	return func(fPath, fName string) (err error) {
+		absPath := path.Join(fPath, fName)
+		var info os.FileInfo
+		if info, err = os.Stat(absPath); err != nil {
+			return
+		} else if info.IsDir() { // ignore the folders
+			return
+		}
+		var format string
+		if format, err = compressionFormat(absPath); err != nil {
+			return
+		} else if format == utils.EmptyString {
+			return processFile(fPath, fName)
+		}
+		var tmpDir string
+		if tmpDir, err = ioutil.TempDir(processedPath, "."+fName+"_"); err != nil { // system temporary folder if no processedPath
+			return
+		}
+		defer os.RemoveAll(tmpDir)
+		erCheckpoints.setArchive(tmpDir, fileCheckpointKey(fName, info)) // its files are checkpointed inside the archive one
+		defer func() { erCheckpoints.removeArchive(tmpDir, err == nil) }()
+		var fNames []string
+		if fNames, err = decompressFile(absPath, format, suffix, tmpDir, maxSize); err != nil {
+			return fmt.Errorf("decompressing <%s>: %s", absPath, err.Error())
+		}
+		var failed []string
+		for _, innerName := range fNames {
+			if err := processFile(tmpDir, innerName); err != nil {
+				utils.Logger.Warning(
+					fmt.Sprintf("<%s> processing file %s from archive %s, error: %s",
+						utils.ERs, innerName, absPath, err.Error()))
+				failed = append(failed, innerName)
+			}
+		}
+		if len(failed) != 0 {
+			return fmt.Errorf("failed processing files %s from archive <%s>",
+				strings.Join(failed, utils.INFIELD_SEP), absPath)
+		}
+		if processedPath != utils.EmptyString { // all files were moved to the processed folder
+			err = os.Remove(absPath)
+		}
+		return
+	}
+}
+
+// errDecompressedSizeExceeded is returned when the files extracted out of an archive are bigger than allowed
+var errDecompressedSizeExceeded = errors.New("decompressed size exceeded")
+
+// decompressFile extracts the content of the compressed file into dstDir
+// returning the names of the files extracted
+// out of zip archives only the files with the reader suffix are extracted,
+// each under its path inside the archive, with the folders separated by underscore
+// the extraction fails if the files extracted are bigger than maxSize, 0 meaning unlimited
+func decompressFile(absPath, format, suffix, dstDir string, maxSize int64) (fNames []string, err error) {
+	if format == utils.ZipSuffix {
+		var zr *zip.ReadCloser
+		if zr, err = zip.OpenReader(absPath); err != nil {
+			return
+		}
+		defer zr.Close()
+		if maxSize <= 0 {
+			maxSize = math.MaxInt64 - 1
+		}
+		names := make(map[string]struct{})
+		for i, zf := range zr.File {
+			if zf.FileInfo().IsDir() ||
+				!strings.HasSuffix(zf.Name, suffix) {
+				continue
+			}
+			fName := zipEntryName(zf.Name)
+			if _, has := names[fName]; has { // same path repeated inside the archive
+				fName = strconv.Itoa(i) + "_" + fName
+			}
+			names[fName] = struct{}{}
+			var rc io.ReadCloser
+			if rc, err = zf.Open(); err != nil {
+				return
+			}
+			var n int64
+			n, err = writeFile(path.Join(dstDir, fName), rc, maxSize)
+			rc.Close()
+			if err != nil {
+				return
+			}
+			fNames = append(fNames, fName)
+			maxSize -= n // the limit applies to all the files extracted
+		}
+		return
+	}
+	var file *os.File
+	if file, err = os.Open(absPath); err != nil {
+		return
+	}
+	defer file.Close()
+	fName := path.Base(absPath)
+	var rdr io.Reader
+	switch format {
+	case utils.GzipSuffix:
+		var gzr *gzip.Reader
+		if gzr, err = gzip.NewReader(bufio.NewReader(file)); err != nil {
+			return
+		}
+		defer gzr.Close()
+		rdr = gzr
+		fName = strings.TrimSuffix(fName, utils.GzipSuffix)
+		if fName == utils.EmptyString && gzr.Name != utils.EmptyString { // original name stored inside the archive
+			fName = path.Base(gzr.Name)
+		}
+	case utils.Bzip2Suffix:
+		rdr = bzip2.NewReader(bufio.NewReader(file))
+		fName = strings.TrimSuffix(fName, utils.Bzip2Suffix)
+	default:
+		return nil, fmt.Errorf("unsupported compression format: <%s>", format)
+	}
+	if maxSize <= 0 {
+		maxSize = math.MaxInt64 - 1
+	}
+	if _, err = writeFile(path.Join(dstDir, fName), rdr, maxSize); err != nil {
+		return
+	}
+	return []string{fName}, nil
+}
+
+// zipEntryName returns the file name used for the zip entry, its path inside
+// the archive having the folders separated by underscore
+func zipEntryName(zName string) string {
+	return strings.Replace(strings.TrimLeft(path.Clean(utils.Slash+zName), utils.Slash),
+		utils.Slash, "_", -1)
+}
+
+// writeFile copies the content of rdr in the file, failing if more than maxSize bytes are read
+func writeFile(fPath string, rdr io.Reader, maxSize int64) (n int64, err error) {
+	var file *os.File
+	if file, err = os.Create(fPath); err != nil {
+		return
+	}
+	if n, err = io.Copy(file, io.LimitReader(rdr, maxSize+1)); err != nil { // one more byte to detect the oversize
+		file.Close()
+		return
+	}
+	if err = file.Close(); err != nil {
+		return
+	}
+	if n > maxSize {
+		err = errDecompressedSizeExceeded
+	}
+	return
+}
+
+// dispatchEvent builds the event out of msg based on the reader filters and fields and dispatches it to ERs
+// with wait the processing result is returned, unblocking with errReaderStopped if the reader exits in the meantime
+// ctx aborts the dispatching if done before ERs accepted the event
+// pass is false if the event was filtered out by the reader
+func dispatchEvent(msg map[string]interface{}, rdrCfg *config.EventReaderCfg,
+	cgrCfg *config.CGRConfig, fltrS *engine.FilterS, rdrEvents chan *erEvent,
+	rdrExit chan struct{}, ctx context.Context, wait bool) (pass bool, err error) {
+	reqVars := make(map[string]interface{})
+	agReq := agents.NewAgentRequest(
+		config.NewNavigableMap(msg), reqVars,
+		nil, nil, rdrCfg.Tenant,
+		cgrCfg.GeneralCfg().DefaultTenant,
+		utils.FirstNonEmpty(rdrCfg.Timezone,
+			cgrCfg.GeneralCfg().DefaultTimezone),
+		fltrS, nil, nil) // create an AgentRequest
+	if pass, err = fltrS.Pass(agReq.Tenant, rdrCfg.Filters,
+		agReq); err != nil || !pass {
+		return
+	}
+	if err = agReq.SetFields(rdrCfg.Fields); err != nil {
+		return
+	}
+	var procErr chan error
+	if wait {
+		procErr = make(chan error, 1)
+	}
+	select {
+	case rdrEvents <- &erEvent{cgrEvent: agReq.CGRRequest.AsCGREvent(
+		agReq.Tenant, utils.NestingSep),
+		rdrCfg:  rdrCfg,
+		procErr: procErr}:
+	case <-rdrExit:
+		return pass, errReaderStopped
+	case <-ctx.Done():
+		return pass, ctx.Err()
+	}
+	if !wait {
+		return
+	}
+	select {
+	case err = <-procErr:
+	case <-rdrExit:
+		err = errReaderStopped
+	}
+	return
+}
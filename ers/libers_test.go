@@ -0,0 +1,291 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package ers
+
+import (
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestHasFileSuffix(t *testing.T) {
+	for fName, exp := range map[string]bool{
+		"cdrs.csv":     true,
+		"cdrs.csv.gz":  true,
+		"cdrs.csv.bz2": true,
+		"cdrs.zip":     true,
+		"cdrs.xml":     false,
+		"cdrs.csv.tmp": false,
+	} {
+		if rcv := hasFileSuffix(fName, utils.CSVSuffix); rcv != exp {
+			t.Errorf("Expected %v for %s, received: %v", exp, fName, rcv)
+		}
+	}
+}
+
+func TestDecompressingProcessor(t *testing.T) {
+	srcDir, err := ioutil.TempDir(utils.EmptyString, "ers_src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(srcDir)
+	procDir, err := ioutil.TempDir(utils.EmptyString, "ers_processed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(procDir)
+
+	// gzip content detected by magic bytes, named after the archive and not the gzip header
+	gzFile, err := os.Create(path.Join(srcDir, "cdrs1.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gzw := gzip.NewWriter(gzFile)
+	gzw.Name = "switch1.csv"
+	gzw.Write([]byte("1001,1002\n"))
+	gzw.Close()
+	gzFile.Close()
+	if err = os.Rename(path.Join(srcDir, "cdrs1.gz"), path.Join(srcDir, "cdrs1.csv")); err != nil {
+		t.Fatal(err)
+	}
+	// bzip2 of "1001,1002\n"
+	if err = ioutil.WriteFile(path.Join(srcDir, "cdrs2.csv.bz2"), []byte{
+		0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0xb4, 0x17, 0x10, 0x7e, 0x00,
+		0x00, 0x03, 0xd8, 0x00, 0x00, 0x10, 0x00, 0x04, 0x70, 0x00, 0x20, 0x00, 0x21, 0x83, 0x41,
+		0x9a, 0x00, 0x63, 0xc5, 0xe2, 0xee, 0x48, 0xa7, 0x0a, 0x12, 0x16, 0x82, 0xe2, 0x0f, 0xc0},
+		0644); err != nil {
+		t.Fatal(err)
+	}
+	// multi-file zip, the file without the reader suffix should be ignored
+	zipFile, err := os.Create(path.Join(srcDir, "cdrs3.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(zipFile)
+	for name, content := range map[string]string{
+		"batch/switch2.csv": "1001,1002\n",
+		"batch/switch3.csv": "1001,1002\n",
+		"batch/README.txt":  "readme",
+	} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	zw.Close()
+	zipFile.Close()
+	if err = ioutil.WriteFile(path.Join(srcDir, "cdrs4.csv"), []byte("1001,1002\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// the gzip header name is used only if the archive has no name besides the extension
+	if gzFile, err = os.Create(path.Join(srcDir, ".gz")); err != nil {
+		t.Fatal(err)
+	}
+	gzw = gzip.NewWriter(gzFile)
+	gzw.Name = "switch5.csv"
+	gzw.Write([]byte("1001,1002\n"))
+	gzw.Close()
+	gzFile.Close()
+
+	processed := make(map[string]string)
+	processFile := decompressingProcessor(func(fPath, fName string) (err error) {
+		var content []byte
+		if content, err = ioutil.ReadFile(path.Join(fPath, fName)); err != nil {
+			return
+		}
+		processed[fName] = string(content)
+		return os.Rename(path.Join(fPath, fName), path.Join(procDir, fName))
+	}, utils.CSVSuffix, procDir, 0)
+	for _, fName := range []string{"cdrs1.csv", "cdrs2.csv.bz2", "cdrs3.zip", "cdrs4.csv", ".gz"} {
+		if err = processFile(srcDir, fName); err != nil {
+			t.Errorf("processing %s received error: %v", fName, err)
+		}
+	}
+	expProcessed := map[string]string{
+		"cdrs1.csv":         "1001,1002\n",
+		"cdrs2.csv":         "1001,1002\n",
+		"batch_switch2.csv": "1001,1002\n",
+		"batch_switch3.csv": "1001,1002\n",
+		"cdrs4.csv":         "1001,1002\n",
+		"switch5.csv":       "1001,1002\n",
+	}
+	if !reflect.DeepEqual(expProcessed, processed) {
+		t.Errorf("Expected: %s, received: %s", utils.ToJSON(expProcessed), utils.ToJSON(processed))
+	}
+	if files, err := ioutil.ReadDir(srcDir); err != nil {
+		t.Error(err)
+	} else if len(files) != 0 {
+		t.Errorf("Expected the source folder empty, found %d files", len(files))
+	}
+	if files, err := ioutil.ReadDir(procDir); err != nil {
+		t.Error(err)
+	} else if len(files) != len(expProcessed) {
+		t.Errorf("Expected %d processed files, found %d", len(expProcessed), len(files))
+	}
+}
+
+func TestDecompressingProcessorFailed(t *testing.T) {
+	srcDir, err := ioutil.TempDir(utils.EmptyString, "ers_src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(srcDir)
+	procDir, err := ioutil.TempDir(utils.EmptyString, "ers_processed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(procDir)
+	gzFile, err := os.Create(path.Join(srcDir, "cdrs.csv.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gzw := gzip.NewWriter(gzFile)
+	gzw.Write([]byte("1001,1002\n"))
+	gzw.Close()
+	gzFile.Close()
+	processFile := decompressingProcessor(func(fPath, fName string) (err error) {
+		return utils.ErrNotImplemented
+	}, utils.CSVSuffix, procDir, 0)
+	if err = processFile(srcDir, "cdrs.csv.gz"); err == nil {
+		t.Error("Expected error")
+	}
+	if _, err = os.Stat(path.Join(srcDir, "cdrs.csv.gz")); err != nil {
+		t.Errorf("Expected the archive kept after failure, received: %v", err)
+	}
+	if files, err := ioutil.ReadDir(procDir); err != nil {
+		t.Error(err)
+	} else if len(files) != 0 {
+		t.Errorf("Expected the temporary files removed, found %d files", len(files))
+	}
+	if err = ioutil.WriteFile(path.Join(srcDir, "broken.csv.gz"), []byte("not gzip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = processFile(srcDir, "broken.csv.gz"); err == nil {
+		t.Error("Expected error")
+	}
+}
+
+func TestDecompressFileZip(t *testing.T) {
+	srcDir, err := ioutil.TempDir(utils.EmptyString, "ers_src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(srcDir)
+	zipFile, err := os.Create(path.Join(srcDir, "cdrs.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(zipFile)
+	for _, name := range []string{"switch1/cdrs.csv", "switch2/cdrs.csv",
+		"../cdrs.csv", "switch1/cdrs.csv"} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte("1001,1002\n"))
+	}
+	zw.Close()
+	zipFile.Close()
+	dstDir, err := ioutil.TempDir(utils.EmptyString, "ers_dst")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dstDir)
+	expNames := []string{"switch1_cdrs.csv", "switch2_cdrs.csv", "cdrs.csv", "3_switch1_cdrs.csv"}
+	if fNames, err := decompressFile(path.Join(srcDir, "cdrs.zip"), utils.ZipSuffix,
+		utils.CSVSuffix, dstDir, 40); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(expNames, fNames) {
+		t.Errorf("Expected: %+v, received: %+v", expNames, fNames)
+	}
+	if files, err := ioutil.ReadDir(dstDir); err != nil {
+		t.Error(err)
+	} else if len(files) != len(expNames) {
+		t.Errorf("Expected %d files, found %d", len(expNames), len(files))
+	}
+	if _, err := decompressFile(path.Join(srcDir, "cdrs.zip"), utils.ZipSuffix,
+		utils.CSVSuffix, dstDir, 39); err != errDecompressedSizeExceeded {
+		t.Errorf("Expected: %v, received: %v", errDecompressedSizeExceeded, err)
+	}
+}
+
+func TestDecompressFileMaxSize(t *testing.T) {
+	srcDir, err := ioutil.TempDir(utils.EmptyString, "ers_src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(srcDir)
+	gzFile, err := os.Create(path.Join(srcDir, "cdrs.csv.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gzw := gzip.NewWriter(gzFile)
+	gzw.Write(make([]byte, 1024))
+	gzw.Close()
+	gzFile.Close()
+	if _, err = decompressFile(path.Join(srcDir, "cdrs.csv.gz"), utils.GzipSuffix,
+		utils.CSVSuffix, srcDir, 1023); err != errDecompressedSizeExceeded {
+		t.Errorf("Expected: %v, received: %v", errDecompressedSizeExceeded, err)
+	}
+	if fNames, err := decompressFile(path.Join(srcDir, "cdrs.csv.gz"), utils.GzipSuffix,
+		utils.CSVSuffix, srcDir, 1024); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual([]string{"cdrs.csv"}, fNames) {
+		t.Errorf("received: %+v", fNames)
+	}
+}
+
+func TestDispatchEvent(t *testing.T) {
+	cfg, _ := config.NewDefaultCGRConfig()
+	rdrCfg := cfg.ERsCfg().Readers[0]
+	rdrCfg.Fields = []*config.FCTemplate{{
+		Tag:   utils.Account,
+		Path:  utils.MetaCgreq + utils.NestingSep + utils.Account,
+		Type:  utils.META_COMPOSED,
+		Value: config.NewRSRParsersMustCompile("~*req.Account", true, utils.INFIELD_SEP),
+	}}
+	rdrEvents := make(chan *erEvent, 1)
+	rdrExit := make(chan struct{})
+	fltrS := new(engine.FilterS)
+	if pass, err := dispatchEvent(map[string]interface{}{utils.Account: "1001"}, rdrCfg, cfg,
+		fltrS, rdrEvents, rdrExit, context.Background(), false); err != nil || !pass {
+		t.Errorf("received pass: %v, error: %v", pass, err)
+	}
+	if ev := <-rdrEvents; ev.procErr != nil ||
+		ev.cgrEvent.Event[utils.Account] != "1001" {
+		t.Errorf("received: %s", utils.ToJSON(ev.cgrEvent))
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rdrEvents = make(chan *erEvent) // nobody is reading the events
+	if _, err := dispatchEvent(map[string]interface{}{utils.Account: "1001"}, rdrCfg, cfg,
+		fltrS, rdrEvents, rdrExit, ctx, true); err != context.Canceled {
+		t.Errorf("Expected: %v, received: %v", context.Canceled, err)
+	}
+}
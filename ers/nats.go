@@ -0,0 +1,244 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package ers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/nats-io/nats.go"
+)
+
+const (
+	defaultNATSSubject      = "cgrates_cdrs"
+	defaultNATSConsumerName = "cgrates"
+)
+
+// NewNATSER return a new NATS JetStream event reader
+func NewNATSER(cfg *config.CGRConfig, cfgIdx int,
+	rdrEvents chan *erEvent, rdrErr chan error,
+	fltrS *engine.FilterS, rdrExit chan struct{}) (er EventReader, err error) {
+	rdr := &NATSER{
+		cgrCfg:    cfg,
+		cfgIdx:    cfgIdx,
+		fltrS:     fltrS,
+		rdrEvents: rdrEvents,
+		rdrExit:   rdrExit,
+		rdrErr:    rdrErr,
+	}
+	if concReq := rdr.Config().ConcurrentReqs; concReq != -1 {
+		rdr.cap = make(chan struct{}, concReq)
+		for i := 0; i < concReq; i++ {
+			rdr.cap <- struct{}{}
+		}
+	}
+	er = rdr
+	err = rdr.setURL(rdr.Config().SourcePath)
+	return
+}
+
+// NATSER implements EventReader interface for NATS JetStream messages
+// using a durable consumer with explicit acknowledgement
+type NATSER struct {
+	cgrCfg *config.CGRConfig
+	cfgIdx int // index of config instance within ERsCfg.Readers
+	fltrS  *engine.FilterS
+
+	dialURL      string
+	subject      string
+	stream       string // optional stream created for the subject if missing
+	consumerName string // name of the durable consumer
+	ackWait      time.Duration
+	maxDeliver   int // messages failing after this number of deliveries are dropped, 0 for unlimited
+
+	rdrEvents chan *erEvent // channel to dispatch the events created to
+	rdrExit   chan struct{}
+	rdrErr    chan error
+	cap       chan struct{}
+}
+
+// Config returns the curent configuration
+func (rdr *NATSER) Config() *config.EventReaderCfg {
+	return rdr.cgrCfg.ERsCfg().Readers[rdr.cfgIdx]
+}
+
+// Serve will subscribe to the NATS subject
+func (rdr *NATSER) Serve() (err error) {
+	if rdr.Config().RunDelay == time.Duration(0) { // 0 disables the automatic read, maybe done per API
+		return
+	}
+	var conn *nats.Conn
+	if conn, err = nats.Connect(rdr.dialURL,
+		nats.ClosedHandler(func(*nats.Conn) {
+			select {
+			case <-rdr.rdrExit: // closed by us
+			default:
+				rdr.rdrErr <- fmt.Errorf("nats connection for subject <%s> closed", rdr.subject)
+			}
+		})); err != nil {
+		return
+	}
+	var js nats.JetStreamContext
+	if js, err = conn.JetStream(); err != nil {
+		conn.Close()
+		return
+	}
+	if err = rdr.subscribe(js); err != nil {
+		conn.Close()
+		return
+	}
+	go func() { // close the connection when the reader is stopped
+		select {
+		case <-rdr.rdrExit:
+			utils.Logger.Info(
+				fmt.Sprintf("<%s> stop monitoring nats subject <%s>",
+					utils.ERs, rdr.subject))
+			conn.Close()
+			return
+		}
+	}()
+	return
+}
+
+// subscribe creates the durable consumer if missing and starts consuming
+func (rdr *NATSER) subscribe(js nats.JetStreamContext) (err error) {
+	if err = engine.EnsureNATSStream(js, rdr.stream, rdr.subject); err != nil {
+		return
+	}
+	opts := []nats.SubOpt{
+		nats.Durable(rdr.consumerName),
+		nats.ManualAck(),
+		nats.AckExplicit(),
+	}
+	if rdr.ackWait != 0 {
+		opts = append(opts, nats.AckWait(rdr.ackWait))
+	}
+	if rdr.maxDeliver != 0 {
+		opts = append(opts, nats.MaxDeliver(rdr.maxDeliver))
+	}
+	if concReq := rdr.Config().ConcurrentReqs; concReq != -1 {
+		opts = append(opts, nats.MaxAckPending(concReq))
+	}
+	_, err = js.Subscribe(rdr.subject, rdr.handleMessage, opts...)
+	return
+}
+
+func (rdr *NATSER) handleMessage(msg *nats.Msg) {
+	if rdr.Config().ConcurrentReqs != -1 {
+		<-rdr.cap // do not try to read if the limit is reached
+	}
+	go func() {
+		if rdr.Config().ConcurrentReqs != -1 {
+			defer func() { rdr.cap <- struct{}{} }()
+		}
+		if err := rdr.processMessage(msg.Data); err != nil {
+			if err == errReaderStopped { // unacknowledged message will be redelivered
+				return
+			}
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> processing message from subject %s error: %s",
+					utils.ERs, msg.Subject, err.Error()))
+			rdr.redeliver(msg)
+			return
+		}
+		if err := msg.Ack(); err != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> acknowledging message from subject %s error: %s",
+					utils.ERs, msg.Subject, err.Error()))
+		}
+		if rdr.Config().ProcessedPath != utils.EmptyString { // post it
+			if err := engine.PostersCache.PostNATS(rdr.Config().ProcessedPath,
+				rdr.cgrCfg.GeneralCfg().PosterAttempts, msg.Data); err != nil {
+				utils.Logger.Warning(
+					fmt.Sprintf("<%s> writing message from subject %s error: %s",
+						utils.ERs, msg.Subject, err.Error()))
+			}
+		}
+	}()
+}
+
+// redeliver asks the server to redeliver the failed message
+// or terminates it if it was delivered for maxDeliver times
+func (rdr *NATSER) redeliver(msg *nats.Msg) {
+	if rdr.maxDeliver > 0 {
+		if meta, err := msg.Metadata(); err == nil &&
+			meta.NumDelivered >= uint64(rdr.maxDeliver) {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> dropping message %d from stream %s after %d deliveries",
+					utils.ERs, meta.Sequence.Stream, meta.Stream, meta.NumDelivered))
+			msg.Term()
+			return
+		}
+	}
+	if err := msg.Nak(); err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> rejecting message from subject %s error: %s",
+				utils.ERs, msg.Subject, err.Error()))
+	}
+}
+
+func (rdr *NATSER) processMessage(msg []byte) (err error) {
+	var decodedMessage map[string]interface{}
+	if err = json.Unmarshal(msg, &decodedMessage); err != nil {
+		return
+	}
+	_, err = dispatchEvent(decodedMessage, rdr.Config(), rdr.cgrCfg,
+		rdr.fltrS, rdr.rdrEvents, rdr.rdrExit, context.Background(), true)
+	return
+}
+
+func (rdr *NATSER) setURL(dialURL string) (err error) {
+	var u *url.URL
+	if u, err = url.Parse(dialURL); err != nil {
+		return
+	}
+	qry := u.Query()
+
+	rdr.dialURL = strings.Split(dialURL, "?")[0]
+	rdr.subject = defaultNATSSubject
+	if vals, has := qry[utils.NATSSubject]; has && len(vals) != 0 {
+		rdr.subject = vals[0]
+	}
+	if vals, has := qry[utils.NATSStream]; has && len(vals) != 0 {
+		rdr.stream = vals[0]
+	}
+	rdr.consumerName = defaultNATSConsumerName
+	if vals, has := qry[utils.NATSConsumerName]; has && len(vals) != 0 {
+		rdr.consumerName = vals[0]
+	}
+	if vals, has := qry[utils.NATSAckWait]; has && len(vals) != 0 {
+		if rdr.ackWait, err = utils.ParseDurationWithNanosecs(vals[0]); err != nil {
+			return fmt.Errorf("invalid %s: <%s>", utils.NATSAckWait, vals[0])
+		}
+	}
+	if vals, has := qry[utils.NATSMaxDeliver]; has && len(vals) != 0 {
+		if rdr.maxDeliver, err = strconv.Atoi(vals[0]); err != nil {
+			return fmt.Errorf("invalid %s: <%s>", utils.NATSMaxDeliver, vals[0])
+		}
+	}
+	return
+}
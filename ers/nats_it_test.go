@@ -0,0 +1,98 @@
+// +build integration
+
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package ers
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// TestNATSER runs against a local nats-server started with JetStream enabled (nats-server -js)
+func TestNATSER(t *testing.T) {
+	cfg, err := config.NewCGRConfigFromJsonStringWithDefaults(`{
+"ers": {
+	"enabled": true,
+	"readers": [
+		{
+			"id": "nats",
+			"type": "*nats_json_map",
+			"run_delay":  "-1",
+			"concurrent_requests": 1024,
+			"source_path": "nats://localhost:4222?subject=cgrates_ers&stream=CGRATES_ERS&consumer_name=cgrates_ers&ack_wait=1s&max_deliver=2",
+			"tenant": "cgrates.org",
+			"filters": [],
+			"flags": [],
+			"fields":[
+				{"tag": "CGRID", "type": "*composed", "value": "~*req.CGRID", "path": "*cgreq.CGRID"},
+			],
+		},
+	],
+},
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rdrEvents = make(chan *erEvent, 1)
+	rdrErr = make(chan error, 1)
+	rdrExit = make(chan struct{}, 1)
+	var rdr EventReader
+	if rdr, err = NewNATSER(cfg, 1, rdrEvents,
+		rdrErr, new(engine.FilterS), rdrExit); err != nil {
+		t.Fatal(err)
+	}
+	if err = rdr.Serve(); err != nil {
+		t.Fatal(err)
+	}
+	pstr, err := engine.NewNATSPoster("nats://localhost:4222?subject=cgrates_ers&stream=CGRATES_ERS", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pstr.Close()
+	randomCGRID := utils.UUIDSha1Prefix()
+	if err = pstr.Post([]byte(fmt.Sprintf(`{"CGRID": "%s"}`, randomCGRID)), utils.EmptyString); err != nil {
+		t.Fatal(err)
+	}
+	for i, procErr := range []error{errors.New("SERVER_ERROR"), nil} { // first failure should be redelivered
+		select {
+		case err = <-rdrErr:
+			t.Fatal(err)
+		case ev := <-rdrEvents:
+			if ev.cgrEvent.Event[utils.CGRID] != randomCGRID {
+				t.Errorf("Expected %s ,received %s", randomCGRID, utils.ToJSON(ev.cgrEvent))
+			}
+			ev.procErr <- procErr
+		case <-time.After(10 * time.Second):
+			t.Fatalf("Timeout waiting for delivery %d", i+1)
+		}
+	}
+	select {
+	case ev := <-rdrEvents:
+		t.Errorf("Unexpected redelivery of acknowledged message: %s", utils.ToJSON(ev.cgrEvent))
+	case <-time.After(2 * time.Second):
+	}
+	close(rdrExit)
+}
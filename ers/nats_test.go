@@ -0,0 +1,62 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package ers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNATSSetURL(t *testing.T) {
+	rdr := new(NATSER)
+	expNATS := &NATSER{
+		dialURL:      "nats://localhost:4222",
+		subject:      "cgrates.cdrs",
+		stream:       "CDRS",
+		consumerName: "ers",
+		ackWait:      30 * time.Second,
+		maxDeliver:   5,
+	}
+	if err := rdr.setURL("nats://localhost:4222?subject=cgrates.cdrs&stream=CDRS&consumer_name=ers&ack_wait=30s&max_deliver=5"); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(expNATS, rdr) {
+		t.Errorf("Expected: %+v ,received: %+v", expNATS, rdr)
+	}
+	rdr = new(NATSER)
+	expNATS = &NATSER{
+		dialURL:      "nats://localhost:4222",
+		subject:      defaultNATSSubject,
+		consumerName: defaultNATSConsumerName,
+	}
+	if err := rdr.setURL("nats://localhost:4222"); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(expNATS, rdr) {
+		t.Errorf("Expected: %+v ,received: %+v", expNATS, rdr)
+	}
+	for _, dialURL := range []string{
+		"nats://localhost:4222?ack_wait=a",
+		"nats://localhost:4222?max_deliver=a",
+		":",
+	} {
+		if err := new(NATSER).setURL(dialURL); err == nil {
+			t.Errorf("Expected error for: %s", dialURL)
+		}
+	}
+}
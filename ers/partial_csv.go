@@ -91,11 +91,20 @@ func (rdr *PartialCSVFileER) Config() *config.EventReaderCfg {
 }
 
 func (rdr *PartialCSVFileER) Serve() (err error) {
+	processFile := decompressingProcessor(rdr.processFile,
+		utils.CSVSuffix, rdr.Config().ProcessedPath,
+		rdr.cgrCfg.ERsCfg().MaxDecompressedSize) // handle the compressed files transparently
+	if utils.IsRemoteFilePath(rdr.Config().SourcePath) { // SFTP or FTP(S) folder, polled each RunDelay
+		return serveRemote(rdr.Config(), utils.CSVSuffix, processFile,
+			rdr.cgrCfg.GeneralCfg().ConnectTimeout, rdr.cgrCfg.ERsCfg().CheckpointPath, rdr.rdrExit)
+	}
 	switch rdr.Config().RunDelay {
 	case time.Duration(0): // 0 disables the automatic read, maybe done per API
 		return
 	case time.Duration(-1):
-		return watchDir(rdr.rdrDir, rdr.processFile,
+		resumeFiles(rdr.cgrCfg.ERsCfg().CheckpointPath, rdr.Config().ID,
+			rdr.rdrDir, processFile) // files interrupted by a restart
+		return watchDir(rdr.rdrDir, processFile,
 			utils.ERs, rdr.rdrExit)
 	default:
 		go func() {
@@ -111,11 +120,11 @@ func (rdr *PartialCSVFileER) Serve() (err error) {
 				}
 				filesInDir, _ := ioutil.ReadDir(rdr.rdrDir)
 				for _, file := range filesInDir {
-					if !strings.HasSuffix(file.Name(), utils.CSVSuffix) { // hardcoded file extension for csv event reader
+					if !hasFileSuffix(file.Name(), utils.CSVSuffix) { // hardcoded file extension for csv event reader
 						continue // used in order to filter the files from directory
 					}
 					go func(fileName string) {
-						if err := rdr.processFile(rdr.rdrDir, fileName); err != nil {
+						if err := processFile(rdr.rdrDir, fileName); err != nil {
 							utils.Logger.Warning(
 								fmt.Sprintf("<%s> processing file %s, error: %s",
 									utils.ERs, fileName, err.Error()))
@@ -154,7 +163,9 @@ func (rdr *PartialCSVFileER) processFile(fPath, fName string) (err error) {
 	evsPosted := 0
 	timeStart := time.Now()
 	reqVars := map[string]interface{}{utils.FileName: fName}
+	cp := newFileCheckpoint(rdr.cgrCfg.ERsCfg().CheckpointPath, rdr.Config().ID, fPath, fName)
 	for {
+		cp.set(rowNr) // previous rows were processed
 		var record []string
 		if record, err = csvReader.Read(); err != nil {
 			if err == io.EOF {
@@ -201,9 +212,11 @@ func (rdr *PartialCSVFileER) processFile(fPath, fName string) (err error) {
 		partial, _ := agReq.CGRRequest.FieldAsString([]string{utils.Partial})
 		if val, has := rdr.cache.Get(cgrID); !has {
 			if utils.IsSliceMember([]string{"false", utils.EmptyString}, partial) { // complete CDR
-				rdr.rdrEvents <- &erEvent{cgrEvent: agReq.CGRRequest.AsCGREvent(agReq.Tenant, utils.NestingSep),
-					rdrCfg: rdr.Config()}
-				evsPosted++
+				if !cp.processed(rowNr) { // the rows processed before restart are read again only for merging
+					cp.dispatch(rdr.rdrEvents, &erEvent{cgrEvent: agReq.CGRRequest.AsCGREvent(agReq.Tenant, utils.NestingSep),
+						rdrCfg: rdr.Config()}, rowNr)
+					evsPosted++
+				}
 			} else {
 				rdr.cache.Set(cgrID,
 					[]*utils.CGREvent{agReq.CGRRequest.AsCGREvent(agReq.Tenant, utils.NestingSep)}, nil)
@@ -235,9 +248,11 @@ func (rdr *PartialCSVFileER) processFile(fPath, fName string) (err error) {
 						cgrEv.Event[key] = value
 					}
 				}
-				rdr.rdrEvents <- &erEvent{cgrEvent: cgrEv,
-					rdrCfg: rdr.Config()}
-				evsPosted++
+				if !cp.processed(rowNr) {
+					cp.dispatch(rdr.rdrEvents, &erEvent{cgrEvent: cgrEv,
+						rdrCfg: rdr.Config()}, rowNr)
+					evsPosted++
+				}
 				rdr.cache.Remove(cgrID)
 			} else {
 
@@ -247,6 +262,7 @@ func (rdr *PartialCSVFileER) processFile(fPath, fName string) (err error) {
 		}
 
 	}
+	cp.wait()
 	if rdr.Config().ProcessedPath != "" {
 		// Finished with file, move it to processed folder
 		outPath := path.Join(rdr.Config().ProcessedPath, fName)
@@ -254,6 +270,7 @@ func (rdr *PartialCSVFileER) processFile(fPath, fName string) (err error) {
 			return
 		}
 	}
+	cp.finish()
 
 	utils.Logger.Info(
 		fmt.Sprintf("%s finished processing file <%s>. Total records processed: %d, events posted: %d, run duration: %s",
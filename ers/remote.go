@@ -0,0 +1,542 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package ers
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/jlaffaye/ftp"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// remoteFile identifies the content of a remote file, changing while the file is written
+type remoteFile struct {
+	Size    int64
+	ModTime time.Time
+}
+
+// equal checks if the file did not change
+func (rf remoteFile) equal(other remoteFile) bool {
+	return rf.Size == other.Size && rf.ModTime.Equal(other.ModTime)
+}
+
+// remoteState is persisted so the files are not processed twice after a restart
+type remoteState struct {
+	Failed     map[string]remoteFile // files failed processing, retried only if they change on the server
+	Unfinished []string              // files processed but not yet moved or removed from the server
+}
+
+// errSFTPKnownHosts is returned when connecting over SFTP without known_hosts to verify the server
+var errSFTPKnownHosts = errors.New("missing known_hosts for SFTP")
+
+// remoteClient is the connection towards the SFTP or FTP(S) server
+type remoteClient interface {
+	list(dir string) (files map[string]remoteFile, err error) // regular files with their size and modification time
+	retrieve(fPath string, offset int64) (io.ReadCloser, error)
+	rename(from, to string) error
+	remove(fPath string) error
+	close() error
+}
+
+// serveRemote polls the remote source_path each run_delay, downloading the files
+// locally and processing them with the same logic as the local ones
+// the state of the files is persisted inside the stateDir, the local download folder if empty
+func serveRemote(rdrCfg *config.EventReaderCfg, suffix string,
+	processFile func(fPath, fName string) error,
+	connTimeout time.Duration, stateDir string, rdrExit chan struct{}) (err error) {
+	if rdrCfg.RunDelay <= 0 { // 0 disables the automatic read, no inotify for remote folders
+		return
+	}
+	localDir := path.Join(os.TempDir(), "cgr_ers_"+rdrCfg.ID)
+	if stateDir == utils.EmptyString {
+		stateDir = localDir
+	}
+	var rs *remoteSource
+	if rs, err = newRemoteSource(rdrCfg.SourcePath, localDir,
+		path.Join(stateDir, url.PathEscape(rdrCfg.ID)+remoteStateSuffix), connTimeout); err != nil {
+		return
+	}
+	if err = os.MkdirAll(rs.localDir, 0755); err != nil {
+		return
+	}
+	if err = rs.loadState(); err != nil {
+		return
+	}
+	utils.Logger.Info(fmt.Sprintf("<%s> polling remote path <%s://%s%s>",
+		utils.ERs, rs.scheme, rs.addr, rs.dir))
+	go func() {
+		for {
+			if err := rs.poll(suffix, processFile); err != nil {
+				utils.Logger.Warning(
+					fmt.Sprintf("<%s> polling remote path <%s://%s%s>, error: %s",
+						utils.ERs, rs.scheme, rs.addr, rs.dir, err.Error()))
+				rs.disconnect() // connect again on next run
+			}
+			select {
+			case <-rdrExit:
+				rs.disconnect()
+				utils.Logger.Info(
+					fmt.Sprintf("<%s> stop polling remote path <%s://%s%s>",
+						utils.ERs, rs.scheme, rs.addr, rs.dir))
+				return
+			case <-time.After(rdrCfg.RunDelay):
+			}
+		}
+	}()
+	return
+}
+
+// remoteStateSuffix is appended to the reader ID to name the file persisting the remoteState
+const remoteStateSuffix = ".remote"
+
+// newRemoteSource parses the remote source_path
+func newRemoteSource(srcPath, localDir, statePath string, connTimeout time.Duration) (rs *remoteSource, err error) {
+	var u *url.URL
+	if u, err = url.Parse(srcPath); err != nil {
+		return
+	}
+	rs = &remoteSource{
+		scheme:      u.Scheme,
+		addr:        u.Host,
+		dir:         u.Path,
+		localDir:    localDir,
+		statePath:   statePath,
+		connTimeout: connTimeout,
+		failed:      make(map[string]remoteFile),
+		unfinished:  utils.NewStringSet(nil),
+	}
+	if rs.dir == utils.EmptyString {
+		rs.dir = "."
+	}
+	if u.Port() == utils.EmptyString {
+		port := "21"
+		if rs.scheme == utils.SFTPScheme {
+			port = "22"
+		}
+		rs.addr = net.JoinHostPort(u.Hostname(), port)
+	}
+	if u.User != nil {
+		rs.user = u.User.Username()
+		rs.passwd, _ = u.User.Password()
+	}
+	qry := u.Query()
+	rs.processedDir = qry.Get(utils.RemoteFolderPath)
+	rs.keyPath = qry.Get(utils.SSHKeyPath)
+	rs.knownHosts = qry.Get(utils.SSHKnownHosts)
+	return
+}
+
+// remoteSource downloads the files out of a SFTP or FTP(S) folder
+type remoteSource struct {
+	scheme       string
+	addr         string
+	user         string
+	passwd       string
+	dir          string // remote folder to read the files from
+	processedDir string // remote folder to move the files to after processing, removed if empty
+	keyPath      string // private key for SFTP authentication
+	knownHosts   string // known_hosts file used to check the SFTP server key
+	localDir     string // files are downloaded here before processing
+	statePath    string // file persisting the failed and unfinished files, not persisted if empty
+	connTimeout  time.Duration
+
+	clnt       remoteClient
+	listed     map[string]remoteFile // files found by the previous poll, downloaded only if unchanged since
+	failed     map[string]remoteFile // files failed processing, left on the server
+	unfinished *utils.StringSet      // files processed but not yet moved or removed from the server
+}
+
+// loadState restores the failed and unfinished files persisted before restart
+func (rs *remoteSource) loadState() (err error) {
+	var content []byte
+	if content, err = ioutil.ReadFile(rs.statePath); err != nil {
+		if os.IsNotExist(err) { // nothing persisted yet
+			err = nil
+		}
+		return
+	}
+	var state remoteState
+	if err = json.Unmarshal(content, &state); err != nil {
+		return fmt.Errorf("loading remote state from <%s>: %s", rs.statePath, err.Error())
+	}
+	if state.Failed != nil {
+		rs.failed = state.Failed
+	}
+	rs.unfinished = utils.NewStringSet(state.Unfinished)
+	return
+}
+
+// saveState persists the failed and unfinished files, replacing the previous state atomically
+func (rs *remoteSource) saveState() (err error) {
+	if rs.statePath == utils.EmptyString {
+		return
+	}
+	var content []byte
+	if content, err = json.Marshal(&remoteState{
+		Failed:     rs.failed,
+		Unfinished: rs.unfinished.AsSlice(),
+	}); err != nil {
+		return
+	}
+	tmpPath := rs.statePath + ".tmp"
+	if err = ioutil.WriteFile(tmpPath, content, 0644); err != nil {
+		return
+	}
+	return os.Rename(tmpPath, rs.statePath)
+}
+
+// connect opens the connection towards the server
+func (rs *remoteSource) connect() (err error) {
+	switch rs.scheme {
+	case utils.SFTPScheme:
+		rs.clnt, err = rs.dialSFTP()
+	case utils.FTPScheme, utils.FTPSScheme:
+		rs.clnt, err = rs.dialFTP()
+	default:
+		err = fmt.Errorf("unsupported scheme: <%s>", rs.scheme)
+	}
+	return
+}
+
+// disconnect closes the connection, ignoring the errors since it may be broken already
+func (rs *remoteSource) disconnect() {
+	if rs.clnt != nil {
+		rs.clnt.close()
+		rs.clnt = nil
+	}
+}
+
+func (rs *remoteSource) dialSFTP() (clnt remoteClient, err error) {
+	var auth []ssh.AuthMethod
+	if rs.keyPath != utils.EmptyString {
+		var key []byte
+		if key, err = ioutil.ReadFile(rs.keyPath); err != nil {
+			return
+		}
+		var signer ssh.Signer
+		if signer, err = ssh.ParsePrivateKey(key); err != nil {
+			return
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if rs.passwd != utils.EmptyString {
+		auth = append(auth, ssh.Password(rs.passwd))
+	}
+	if rs.knownHosts == utils.EmptyString { // never connect without verifying the server
+		return nil, errSFTPKnownHosts
+	}
+	var hostKeyCallback ssh.HostKeyCallback
+	if hostKeyCallback, err = knownhosts.New(rs.knownHosts); err != nil {
+		return
+	}
+	var sshClnt *ssh.Client
+	if sshClnt, err = ssh.Dial(utils.TCP, rs.addr, &ssh.ClientConfig{
+		User:            rs.user,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         rs.connTimeout,
+	}); err != nil {
+		return
+	}
+	var sftpClnt *sftp.Client
+	if sftpClnt, err = sftp.NewClient(sshClnt); err != nil {
+		sshClnt.Close()
+		return
+	}
+	return &sftpClient{sshClnt: sshClnt, Client: sftpClnt}, nil
+}
+
+func (rs *remoteSource) dialFTP() (clnt remoteClient, err error) {
+	opts := []ftp.DialOption{ftp.DialWithTimeout(rs.connTimeout)}
+	if rs.scheme == utils.FTPSScheme {
+		host, _, _ := net.SplitHostPort(rs.addr)
+		opts = append(opts, ftp.DialWithExplicitTLS(&tls.Config{ServerName: host}))
+	}
+	var conn *ftp.ServerConn
+	if conn, err = ftp.Dial(rs.addr, opts...); err != nil {
+		return
+	}
+	user, passwd := rs.user, rs.passwd
+	if user == utils.EmptyString {
+		user, passwd = "anonymous", "anonymous"
+	}
+	if err = conn.Login(user, passwd); err != nil {
+		conn.Quit()
+		return
+	}
+	return &ftpClient{ServerConn: conn}, nil
+}
+
+// poll processes once the files within the remote folder
+// a file is downloaded only if its size and modification time did not change since the previous poll
+// so the files still uploaded are not processed
+// the errors returned are communication ones, the processing ones being only logged
+func (rs *remoteSource) poll(suffix string, processFile func(fPath, fName string) error) (err error) {
+	if rs.clnt == nil {
+		if err = rs.connect(); err != nil {
+			return
+		}
+	}
+	for _, fName := range rs.unfinished.AsSlice() { // resume after disconnect
+		if err = rs.finish(fName); err != nil {
+			return
+		}
+	}
+	var files map[string]remoteFile
+	if files, err = rs.clnt.list(rs.dir); err != nil {
+		return
+	}
+	listed := rs.listed
+	rs.listed = files
+	var changed bool
+	for fName := range rs.failed {
+		if _, has := files[fName]; !has { // removed from the server meanwhile
+			delete(rs.failed, fName)
+			changed = true
+		}
+	}
+	if changed {
+		if err = rs.saveState(); err != nil {
+			return
+		}
+	}
+	fNames := make([]string, 0, len(files))
+	for fName, rf := range files {
+		if strings.HasPrefix(fName, ".") || // hidden files might be still uploaded
+			!hasFileSuffix(fName, suffix) {
+			continue
+		}
+		if failed, has := rs.failed[fName]; has && failed.equal(rf) { // retried only if replaced
+			continue
+		}
+		if prev, has := listed[fName]; !has || !prev.equal(rf) { // upload might be in progress
+			continue
+		}
+		fNames = append(fNames, fName)
+	}
+	sort.Strings(fNames)
+	for _, fName := range fNames {
+		if err = rs.download(fName, files[fName]); err != nil {
+			return
+		}
+		errProc := processFile(rs.localDir, fName)
+		if errRm := os.Remove(path.Join(rs.localDir, fName)); errRm != nil &&
+			!os.IsNotExist(errRm) { // already moved to processed_path
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> removing local copy of %s, error: %s",
+					utils.ERs, fName, errRm.Error()))
+		}
+		if errProc != nil { // the original stays on the server
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> processing remote file %s, error: %s",
+					utils.ERs, fName, errProc.Error()))
+			rs.failed[fName] = files[fName]
+			if err = rs.saveState(); err != nil {
+				return
+			}
+			continue
+		}
+		delete(rs.failed, fName)
+		rs.unfinished.Add(fName)
+		if err = rs.saveState(); err != nil {
+			return
+		}
+		if err = rs.finish(fName); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// download copies the remote file into localDir
+// the partial downloads are resumed from where they were interrupted, as long as the remote file
+// kept the size and modification time stored next to the partial download, otherwise started over
+// the local copy keeps the remote modification time so its checkpoint survives restarts
+func (rs *remoteSource) download(fName string, rf remoteFile) (err error) {
+	size := rf.Size
+	partPath := path.Join(rs.localDir, "."+fName+".part")
+	metaPath := partPath + ".meta"
+	var offset int64
+	if info, errStat := os.Stat(partPath); errStat == nil {
+		if content, errRead := ioutil.ReadFile(metaPath); errRead == nil {
+			var partRf remoteFile
+			if json.Unmarshal(content, &partRf) == nil &&
+				partRf.equal(rf) {
+				offset = info.Size()
+			}
+		}
+	}
+	if offset > size { // not matching the remote file
+		offset = 0
+	}
+	if offset == 0 { // started over
+		var content []byte
+		if content, err = json.Marshal(rf); err != nil {
+			return
+		}
+		if err = ioutil.WriteFile(metaPath, content, 0644); err != nil {
+			return
+		}
+		if err = ioutil.WriteFile(partPath, nil, 0644); err != nil {
+			return
+		}
+	}
+	if offset < size {
+		var file *os.File
+		if file, err = os.OpenFile(partPath, os.O_WRONLY|os.O_APPEND, 0644); err != nil {
+			return
+		}
+		var rdr io.ReadCloser
+		if rdr, err = rs.clnt.retrieve(path.Join(rs.dir, fName), offset); err != nil {
+			file.Close()
+			return
+		}
+		_, err = io.Copy(file, rdr)
+		rdr.Close()
+		if errClose := file.Close(); err == nil {
+			err = errClose
+		}
+		if err != nil {
+			return
+		}
+	}
+	if !rf.ModTime.IsZero() {
+		if err = os.Chtimes(partPath, rf.ModTime, rf.ModTime); err != nil {
+			return
+		}
+	}
+	if err = os.Rename(partPath, path.Join(rs.localDir, fName)); err != nil {
+		return
+	}
+	return os.Remove(metaPath)
+}
+
+// finish moves or removes the remote file after processing
+func (rs *remoteSource) finish(fName string) (err error) {
+	fPath := path.Join(rs.dir, fName)
+	if rs.processedDir == utils.EmptyString {
+		err = rs.clnt.remove(fPath)
+	} else {
+		err = rs.clnt.rename(fPath, path.Join(rs.processedDir, fName))
+	}
+	if err != nil {
+		return
+	}
+	rs.unfinished.Remove(fName)
+	return rs.saveState()
+}
+
+// sftpClient implements remoteClient for SFTP
+type sftpClient struct {
+	sshClnt *ssh.Client
+	*sftp.Client
+}
+
+func (c *sftpClient) list(dir string) (files map[string]remoteFile, err error) {
+	var infos []os.FileInfo
+	if infos, err = c.ReadDir(dir); err != nil {
+		return
+	}
+	files = make(map[string]remoteFile)
+	for _, info := range infos {
+		if info.Mode().IsRegular() {
+			files[info.Name()] = remoteFile{Size: info.Size(), ModTime: info.ModTime()}
+		}
+	}
+	return
+}
+
+func (c *sftpClient) retrieve(fPath string, offset int64) (rdr io.ReadCloser, err error) {
+	var file *sftp.File
+	if file, err = c.Open(fPath); err != nil {
+		return
+	}
+	if _, err = file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return
+	}
+	return file, nil
+}
+
+func (c *sftpClient) rename(from, to string) error {
+	return c.PosixRename(from, to)
+}
+
+func (c *sftpClient) remove(fPath string) error {
+	return c.Remove(fPath)
+}
+
+func (c *sftpClient) close() (err error) {
+	err = c.Close()
+	if errClose := c.sshClnt.Close(); err == nil {
+		err = errClose
+	}
+	return
+}
+
+// ftpClient implements remoteClient for FTP and FTPS
+type ftpClient struct {
+	*ftp.ServerConn
+}
+
+func (c *ftpClient) list(dir string) (files map[string]remoteFile, err error) {
+	var entries []*ftp.Entry
+	if entries, err = c.List(dir); err != nil {
+		return
+	}
+	files = make(map[string]remoteFile)
+	for _, entry := range entries {
+		if entry.Type == ftp.EntryTypeFile {
+			files[path.Base(entry.Name)] = remoteFile{Size: int64(entry.Size), ModTime: entry.Time}
+		}
+	}
+	return
+}
+
+func (c *ftpClient) retrieve(fPath string, offset int64) (io.ReadCloser, error) {
+	return c.RetrFrom(fPath, uint64(offset))
+}
+
+func (c *ftpClient) rename(from, to string) error {
+	return c.Rename(from, to)
+}
+
+func (c *ftpClient) remove(fPath string) error {
+	return c.Delete(fPath)
+}
+
+func (c *ftpClient) close() error {
+	return c.Quit()
+}
@@ -0,0 +1,309 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package ers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+const defaultS3Bucket = "cgrates_cdrs"
+
+// NewS3ER return a new S3 event reader
+func NewS3ER(cfg *config.CGRConfig, cfgIdx int,
+	rdrEvents chan *erEvent, rdrErr chan error,
+	fltrS *engine.FilterS, rdrExit chan struct{}) (er EventReader, err error) {
+	rdr := &S3ER{
+		cgrCfg:    cfg,
+		cfgIdx:    cfgIdx,
+		fltrS:     fltrS,
+		rdrEvents: rdrEvents,
+		rdrExit:   rdrExit,
+		rdrErr:    rdrErr,
+		failed:    make(map[string]struct{}),
+	}
+	if concReq := rdr.Config().ConcurrentReqs; concReq != -1 {
+		rdr.cap = make(chan struct{}, concReq)
+		for i := 0; i < concReq; i++ {
+			rdr.cap <- struct{}{}
+		}
+	}
+	er = rdr
+	err = rdr.setURL(rdr.Config().SourcePath)
+	return
+}
+
+// S3ER implements EventReader interface for S3 objects
+// each object contains one JSON encoded event and is removed from the
+// folder only after it was successfully processed
+// the objects failing the processing are moved to the failedFolderPath
+// or, if this is not defined, skipped by the next listings
+type S3ER struct {
+	cgrCfg *config.CGRConfig
+	cfgIdx int // index of config instance within ERsCfg.Readers
+	fltrS  *engine.FilterS
+
+	dialURL             string
+	awsRegion           string
+	awsID               string
+	awsKey              string
+	awsToken            string
+	bucket              string
+	folderPath          string
+	processedFolderPath string // objects are moved here after processing, otherwise deleted
+	failedFolderPath    string // objects failing the processing are moved here
+
+	failed   map[string]struct{} // keys of the objects which failed processing, if not moved
+	failedLk sync.Mutex
+
+	rdrEvents chan *erEvent // channel to dispatch the events created to
+	rdrExit   chan struct{}
+	rdrErr    chan error
+	cap       chan struct{}
+}
+
+// Config returns the curent configuration
+func (rdr *S3ER) Config() *config.EventReaderCfg {
+	return rdr.cgrCfg.ERsCfg().Readers[rdr.cfgIdx]
+}
+
+// Serve will start the gorutines needed to list the S3 bucket
+func (rdr *S3ER) Serve() (err error) {
+	if rdr.Config().RunDelay == time.Duration(0) { // 0 disables the automatic read, maybe done per API
+		return
+	}
+	var svc *s3.S3
+	if svc, err = rdr.newS3Client(); err != nil {
+		return
+	}
+	go func() {
+		for {
+			// Not automated, process and sleep approach
+			select {
+			case <-rdr.rdrExit:
+				utils.Logger.Info(
+					fmt.Sprintf("<%s> stop monitoring s3 bucket <%s>",
+						utils.ERs, rdr.bucket))
+				return
+			default:
+			}
+			if err := rdr.readLoop(svc); err != nil {
+				rdr.rdrErr <- err
+				return
+			}
+			time.Sleep(rdr.Config().RunDelay)
+		}
+	}()
+	return
+}
+
+func (rdr *S3ER) newS3Client() (svc *s3.S3, err error) {
+	ses, err := newAWSSession(rdr.dialURL, rdr.awsRegion,
+		rdr.awsID, rdr.awsKey, rdr.awsToken)
+	if err != nil {
+		return
+	}
+	return s3.New(ses, aws.NewConfig().WithS3ForcePathStyle(true)), nil // path style is needed by the S3 compatible servers
+}
+
+// readLoop processes the objects found in the folder
+// it returns after all of them were processed so the next listing will not include them
+func (rdr *S3ER) readLoop(svc *s3.S3) (err error) {
+	var wg sync.WaitGroup
+	err = svc.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket: aws.String(rdr.bucket),
+		Prefix: aws.String(rdr.folderPrefix()),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, obj := range page.Contents {
+			key := aws.StringValue(obj.Key)
+			if rdr.processedFolderPath != utils.EmptyString &&
+				strings.HasPrefix(key, rdr.processedFolderPath+utils.Slash) {
+				continue // already processed
+			}
+			if rdr.failedFolderPath != utils.EmptyString &&
+				strings.HasPrefix(key, rdr.failedFolderPath+utils.Slash) ||
+				rdr.hasFailed(key) {
+				continue // failed before
+			}
+			if rdr.Config().ConcurrentReqs != -1 {
+				<-rdr.cap // do not process more objects if the limit is reached
+			}
+			wg.Add(1)
+			go func(key string) {
+				defer wg.Done()
+				if rdr.Config().ConcurrentReqs != -1 {
+					defer func() { rdr.cap <- struct{}{} }()
+				}
+				if err := rdr.processObject(svc, key); err != nil &&
+					err != errReaderStopped {
+					utils.Logger.Warning(
+						fmt.Sprintf("<%s> processing object %s error: %s",
+							utils.ERs, key, err.Error()))
+				}
+			}(key)
+		}
+		return true
+	})
+	wg.Wait()
+	return
+}
+
+// processObject reads the object and removes it after a successful processing
+func (rdr *S3ER) processObject(svc *s3.S3, key string) (err error) {
+	var obj *s3.GetObjectOutput
+	if obj, err = svc.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(rdr.bucket),
+		Key:    aws.String(key),
+	}); err != nil {
+		return
+	}
+	var msg []byte
+	msg, err = ioutil.ReadAll(obj.Body)
+	obj.Body.Close()
+	if err != nil {
+		return
+	}
+	if err = rdr.processMessage(msg); err != nil {
+		if err != errReaderStopped { // processed on restart otherwise
+			rdr.setFailed(svc, key)
+		}
+		return
+	}
+	if err = rdr.moveObject(svc, key, rdr.processedFolderPath); err != nil {
+		return
+	}
+	if rdr.Config().ProcessedPath != utils.EmptyString { // post it
+		if err = engine.PostersCache.PostS3(rdr.Config().ProcessedPath,
+			rdr.cgrCfg.GeneralCfg().PosterAttempts, msg,
+			strings.TrimSuffix(key[strings.LastIndex(key, utils.Slash)+1:], utils.JSNSuffix)); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// moveObject moves the object into the dstFolder, only removing it if the dstFolder is empty
+func (rdr *S3ER) moveObject(svc *s3.S3, key, dstFolder string) (err error) {
+	if dstFolder != utils.EmptyString {
+		if _, err = svc.CopyObject(&s3.CopyObjectInput{
+			Bucket:     aws.String(rdr.bucket),
+			CopySource: aws.String(rdr.bucket + utils.Slash + url.PathEscape(key)),
+			Key: aws.String(dstFolder + utils.Slash +
+				strings.TrimPrefix(key, rdr.folderPrefix())),
+		}); err != nil {
+			return
+		}
+	}
+	_, err = svc.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(rdr.bucket),
+		Key:    aws.String(key),
+	})
+	return
+}
+
+// setFailed moves the object into the failedFolderPath so it is not processed again
+// without failedFolderPath or if the move fails, the object is skipped by the next listings
+func (rdr *S3ER) setFailed(svc *s3.S3, key string) {
+	if rdr.failedFolderPath != utils.EmptyString {
+		err := rdr.moveObject(svc, key, rdr.failedFolderPath)
+		if err == nil {
+			return
+		}
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> moving failed object %s error: %s",
+				utils.ERs, key, err.Error()))
+	}
+	rdr.failedLk.Lock()
+	rdr.failed[key] = struct{}{}
+	rdr.failedLk.Unlock()
+}
+
+// hasFailed checks if the object failed processing before
+func (rdr *S3ER) hasFailed(key string) (has bool) {
+	rdr.failedLk.Lock()
+	_, has = rdr.failed[key]
+	rdr.failedLk.Unlock()
+	return
+}
+
+// folderPrefix returns the prefix of the objects read
+func (rdr *S3ER) folderPrefix() string {
+	if rdr.folderPath == utils.EmptyString {
+		return utils.EmptyString
+	}
+	return rdr.folderPath + utils.Slash
+}
+
+func (rdr *S3ER) processMessage(msg []byte) (err error) {
+	var decodedMessage map[string]interface{}
+	if err = json.Unmarshal(msg, &decodedMessage); err != nil {
+		return
+	}
+	_, err = dispatchEvent(decodedMessage, rdr.Config(), rdr.cgrCfg,
+		rdr.fltrS, rdr.rdrEvents, rdr.rdrExit, context.Background(), true)
+	return
+}
+
+func (rdr *S3ER) setURL(dialURL string) (err error) {
+	var u *url.URL
+	if u, err = url.Parse(dialURL); err != nil {
+		return
+	}
+	qry := u.Query()
+
+	rdr.dialURL = strings.TrimSuffix(strings.Split(dialURL, "?")[0], "/") // used to remove / to point to correct endpoint
+	rdr.bucket = defaultS3Bucket
+	if vals, has := qry[utils.AWSQueueID]; has && len(vals) != 0 {
+		rdr.bucket = vals[0]
+	}
+	if vals, has := qry[utils.AWSFolderPath]; has && len(vals) != 0 {
+		rdr.folderPath = strings.Trim(vals[0], utils.Slash)
+	}
+	if vals, has := qry[utils.S3ProcessedFolderPath]; has && len(vals) != 0 {
+		rdr.processedFolderPath = strings.Trim(vals[0], utils.Slash)
+	}
+	if vals, has := qry[utils.S3FailedFolderPath]; has && len(vals) != 0 {
+		rdr.failedFolderPath = strings.Trim(vals[0], utils.Slash)
+	}
+	if vals, has := qry[utils.AWSRegion]; has && len(vals) != 0 {
+		rdr.awsRegion = vals[0]
+	}
+	if vals, has := qry[utils.AWSKey]; has && len(vals) != 0 {
+		rdr.awsID = vals[0]
+	}
+	if vals, has := qry[utils.AWSSecret]; has && len(vals) != 0 {
+		rdr.awsKey = vals[0]
+	}
+	if vals, has := qry[utils.AWSToken]; has && len(vals) != 0 {
+		rdr.awsToken = vals[0]
+	}
+	return
+}
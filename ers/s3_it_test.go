@@ -0,0 +1,110 @@
+// +build integration
+
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package ers
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// TestS3ER runs against a local S3 compatible server (ie: MinIO)
+func TestS3ER(t *testing.T) {
+	cfg, err := config.NewCGRConfigFromJsonStringWithDefaults(`{
+"ers": {
+	"enabled": true,
+	"readers": [
+		{
+			"id": "s3",
+			"type": "*s3_json_map",
+			"run_delay":  "100ms",
+			"concurrent_requests": 1024,
+			"source_path": "http://localhost:9000?queue_id=cgrates-ers&folder_path=in&processed_folder_path=out&aws_region=us-east-1&aws_key=minioadmin&aws_secret=minioadmin",
+			"tenant": "cgrates.org",
+			"filters": [],
+			"flags": [],
+			"fields":[
+				{"tag": "CGRID", "type": "*composed", "value": "~*req.CGRID", "path": "*cgreq.CGRID"},
+			],
+		},
+	],
+},
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rdrEvents = make(chan *erEvent, 1)
+	rdrErr = make(chan error, 1)
+	rdrExit = make(chan struct{}, 1)
+	var rdr EventReader
+	if rdr, err = NewS3ER(cfg, 1, rdrEvents,
+		rdrErr, new(engine.FilterS), rdrExit); err != nil {
+		t.Fatal(err)
+	}
+	svc, err := rdr.(*S3ER).newS3Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc.CreateBucket(&s3.CreateBucketInput{Bucket: aws.String("cgrates-ers")}) // ignore the error in case it already exists
+	randomCGRID := utils.UUIDSha1Prefix()
+	if _, err = svc.PutObject(&s3.PutObjectInput{
+		Bucket: aws.String("cgrates-ers"),
+		Key:    aws.String("in/" + randomCGRID + utils.JSNSuffix),
+		Body:   bytes.NewReader([]byte(fmt.Sprintf(`{"CGRID": "%s"}`, randomCGRID))),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err = rdr.Serve(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err = <-rdrErr:
+		t.Error(err)
+	case ev := <-rdrEvents:
+		if ev.cgrEvent.Event[utils.CGRID] != randomCGRID {
+			t.Errorf("Expected %s ,received %s", randomCGRID, utils.ToJSON(ev.cgrEvent))
+		}
+		ev.procErr <- nil
+	case <-time.After(10 * time.Second):
+		t.Fatal("Timeout")
+	}
+	time.Sleep(100 * time.Millisecond)
+	close(rdrExit)
+	if _, err = svc.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String("cgrates-ers"),
+		Key:    aws.String("out/" + randomCGRID + utils.JSNSuffix),
+	}); err != nil {
+		t.Errorf("Expected the object moved to the processed folder, received: %v", err)
+	}
+	if _, err = svc.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String("cgrates-ers"),
+		Key:    aws.String("in/" + randomCGRID + utils.JSNSuffix),
+	}); err == nil {
+		t.Error("Expected the object removed from the source folder")
+	}
+}
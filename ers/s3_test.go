@@ -0,0 +1,65 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package ers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestS3SetURL(t *testing.T) {
+	rdr := new(S3ER)
+	expS3 := &S3ER{
+		dialURL:             "http://localhost:9000",
+		awsRegion:           "eu-west-2",
+		awsID:               "testID",
+		awsKey:              "testKey",
+		awsToken:            "testToken",
+		bucket:              "cdrs",
+		folderPath:          "in/cdrs",
+		processedFolderPath: "out",
+		failedFolderPath:    "failed/cdrs",
+	}
+	if err := rdr.setURL("http://localhost:9000/?queue_id=cdrs&folder_path=/in/cdrs/&processed_folder_path=out&failed_folder_path=failed/cdrs/&aws_region=eu-west-2&aws_key=testID&aws_secret=testKey&aws_token=testToken"); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(expS3, rdr) {
+		t.Errorf("Expected: %+v ,received: %+v", expS3, rdr)
+	}
+	if prfx := rdr.folderPrefix(); prfx != "in/cdrs/" {
+		t.Errorf("Expected: %q ,received: %q", "in/cdrs/", prfx)
+	}
+	rdr = new(S3ER)
+	expS3 = &S3ER{
+		dialURL: "http://localhost:9000",
+		bucket:  defaultS3Bucket,
+	}
+	if err := rdr.setURL("http://localhost:9000"); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(expS3, rdr) {
+		t.Errorf("Expected: %+v ,received: %+v", expS3, rdr)
+	}
+	if prfx := rdr.folderPrefix(); prfx != utils.EmptyString {
+		t.Errorf("Expected empty prefix, received: %q", prfx)
+	}
+	if err := new(S3ER).setURL(":"); err == nil {
+		t.Error("Expected error")
+	}
+}
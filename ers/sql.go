@@ -19,12 +19,12 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package ers
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"strings"
 	"time"
 
-	"github.com/cgrates/cgrates/agents"
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/engine"
 	"github.com/cgrates/cgrates/utils"
@@ -38,6 +38,7 @@ import (
 const (
 	dbName         = "db_name"
 	tableName      = "table_name"
+	keyColumn      = "key_column"
 	sslMode        = "sslmode"
 	defaultSSLMode = "disable"
 	defaultDBName  = "cgrates"
@@ -80,6 +81,7 @@ type SQLEventReader struct {
 	connString string
 	connType   string
 	tableName  string
+	keyColumn  string // rows are read ordered by it, the one being removed after processing being checkpointed
 
 	expConnString string
 	expConnType   string
@@ -112,13 +114,24 @@ func (rdr *SQLEventReader) Serve() (err error) {
 	return
 }
 
+// checkpointed returns true if the rows are processed in order of key_column with the last key checkpointed
+func (rdr *SQLEventReader) checkpointed() bool {
+	return rdr.keyColumn != utils.EmptyString &&
+		rdr.cgrCfg.ERsCfg().CheckpointPath != utils.EmptyString
+}
+
 func (rdr *SQLEventReader) readLoop(db *gorm.DB) {
+	checkpointed := rdr.checkpointed()
 	for {
 		if db = db.Table(rdr.tableName).Select("*"); db.Error != nil {
 			rdr.rdrErr <- db.Error
 			return
 		}
-		rows, err := db.Rows()
+		qry := db
+		if checkpointed {
+			qry = db.Order(rdr.keyColumn)
+		}
+		rows, err := qry.Rows()
 		if err != nil {
 			rdr.rdrErr <- err
 			return
@@ -142,7 +155,7 @@ func (rdr *SQLEventReader) readLoop(db *gorm.DB) {
 				rdr.rdrErr <- err
 				return
 			}
-			if rdr.Config().ConcurrentReqs != -1 {
+			if !checkpointed && rdr.Config().ConcurrentReqs != -1 {
 				<-rdr.cap // do not try to read if the limit is reached
 			}
 			columns := make([]interface{}, len(colNames))
@@ -154,12 +167,17 @@ func (rdr *SQLEventReader) readLoop(db *gorm.DB) {
 				rdr.rdrErr <- err
 				return
 			}
+			if checkpointed {
+				db = rdr.processKeyedRow(db, columns, colNames)
+				continue
+			}
 			go func(columns []interface{}, colNames []string) {
 				msg := make(map[string]interface{})
 				for i, colName := range colNames {
 					msg[colName] = columns[i]
 				}
-				if err := rdr.processMessage(msg); err != nil {
+				if _, err := dispatchEvent(msg, rdr.Config(), rdr.cgrCfg,
+					rdr.fltrS, rdr.rdrEvents, rdr.rdrExit, context.Background(), false); err != nil {
 					utils.Logger.Warning(
 						fmt.Sprintf("<%s> processing message %s error: %s",
 							utils.ERs, utils.ToJSON(msg), err.Error()))
@@ -184,28 +202,45 @@ func (rdr *SQLEventReader) readLoop(db *gorm.DB) {
 	}
 }
 
-func (rdr *SQLEventReader) processMessage(msg map[string]interface{}) (err error) {
-	reqVars := make(map[string]interface{})
-	agReq := agents.NewAgentRequest(
-		config.NewNavigableMap(msg), reqVars,
-		nil, nil, rdr.Config().Tenant,
-		rdr.cgrCfg.GeneralCfg().DefaultTenant,
-		utils.FirstNonEmpty(rdr.Config().Timezone,
-			rdr.cgrCfg.GeneralCfg().DefaultTimezone),
-		rdr.fltrS, nil, nil) // create an AgentRequest
-	var pass bool
-	if pass, err = rdr.fltrS.Pass(agReq.Tenant, rdr.Config().Filters,
-		agReq); err != nil || !pass {
-		return
+// processKeyedRow processes the row synchronously and records its key as processed before
+// removing it so a row processed but not removed before a restart is not processed again
+// only the row with the exact key is skipped since the rows might be committed out of key order
+func (rdr *SQLEventReader) processKeyedRow(db *gorm.DB, columns []interface{}, colNames []string) *gorm.DB {
+	msg := make(map[string]interface{})
+	for i, colName := range colNames {
+		msg[colName] = columns[i]
 	}
-	if err = agReq.SetFields(rdr.Config().Fields); err != nil {
-		return
+	cpDir := rdr.cgrCfg.ERsCfg().CheckpointPath
+	key := utils.IfaceAsString(msg[rdr.keyColumn])
+	lastKey, err := erCheckpoints.lastKey(cpDir, rdr.Config().ID)
+	if err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> reader <%s> reading checkpoint, error: %s",
+				utils.ERs, rdr.Config().ID, err.Error()))
 	}
-	rdr.rdrEvents <- &erEvent{
-		cgrEvent: agReq.CGRRequest.AsCGREvent(agReq.Tenant, utils.NestingSep),
-		rdrCfg:   rdr.Config(),
+	if lastKey != utils.EmptyString && key == lastKey { // processed but not removed before restart
+		return db.Delete(msg)
 	}
-	return
+	if _, err = dispatchEvent(msg, rdr.Config(), rdr.cgrCfg,
+		rdr.fltrS, rdr.rdrEvents, rdr.rdrExit, context.Background(), true); err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> processing message %s error: %s",
+				utils.ERs, utils.ToJSON(msg), err.Error()))
+	}
+	if err = erCheckpoints.setLastKey(cpDir, rdr.Config().ID, key); err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> reader <%s> saving checkpoint, error: %s",
+				utils.ERs, rdr.Config().ID, err.Error()))
+	}
+	db = db.Delete(msg) // to ensure we don't read it again
+	if rdr.Config().ProcessedPath != utils.EmptyString {
+		if err = rdr.postCDR(columns); err != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> posting message %s error: %s",
+					utils.ERs, utils.ToJSON(msg), err.Error()))
+		}
+	}
+	return db
 }
 
 func (rdr *SQLEventReader) setURL(inURL, outURL string) (err error) {
@@ -231,6 +266,9 @@ func (rdr *SQLEventReader) setURL(inURL, outURL string) (err error) {
 	if vals, has := qry[tableName]; has && len(vals) != 0 {
 		rdr.tableName = vals[0]
 	}
+	if vals, has := qry[keyColumn]; has && len(vals) != 0 {
+		rdr.keyColumn = vals[0]
+	}
 	switch rdr.connType {
 	case utils.MYSQL:
 		rdr.connString = fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8&loc=Local&parseTime=true&sql_mode='ALLOW_INVALID_DATES'",
@@ -0,0 +1,260 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package ers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+const (
+	defaultSQSQueueID     = "cgrates_cdrs"
+	defaultSQSWaitTime    = 20 // maximum long polling interval allowed by SQS
+	defaultSQSMaxMessages = 10 // maximum number of messages received at once allowed by SQS
+)
+
+// NewSQSER return a new SQS event reader
+func NewSQSER(cfg *config.CGRConfig, cfgIdx int,
+	rdrEvents chan *erEvent, rdrErr chan error,
+	fltrS *engine.FilterS, rdrExit chan struct{}) (er EventReader, err error) {
+	rdr := &SQSER{
+		cgrCfg:    cfg,
+		cfgIdx:    cfgIdx,
+		fltrS:     fltrS,
+		rdrEvents: rdrEvents,
+		rdrExit:   rdrExit,
+		rdrErr:    rdrErr,
+	}
+	if concReq := rdr.Config().ConcurrentReqs; concReq != -1 {
+		rdr.cap = make(chan struct{}, concReq)
+		for i := 0; i < concReq; i++ {
+			rdr.cap <- struct{}{}
+		}
+	}
+	er = rdr
+	err = rdr.setURL(rdr.Config().SourcePath)
+	return
+}
+
+// SQSER implements EventReader interface for SQS messages
+// the messages are deleted from the queue only after they were successfully processed
+type SQSER struct {
+	cgrCfg *config.CGRConfig
+	cfgIdx int // index of config instance within ERsCfg.Readers
+	fltrS  *engine.FilterS
+
+	dialURL           string
+	awsRegion         string
+	awsID             string
+	awsKey            string
+	awsToken          string
+	queueID           string
+	waitTime          int64 // long polling interval in seconds
+	visibilityTimeout int64 // seconds the received messages are hidden from other consumers, 0 for the queue default
+	maxMessages       int64
+
+	rdrEvents chan *erEvent // channel to dispatch the events created to
+	rdrExit   chan struct{}
+	rdrErr    chan error
+	cap       chan struct{}
+}
+
+// Config returns the curent configuration
+func (rdr *SQSER) Config() *config.EventReaderCfg {
+	return rdr.cgrCfg.ERsCfg().Readers[rdr.cfgIdx]
+}
+
+// Serve will start the gorutines needed to poll the SQS queue
+func (rdr *SQSER) Serve() (err error) {
+	if rdr.Config().RunDelay == time.Duration(0) { // 0 disables the automatic read, maybe done per API
+		return
+	}
+	var svc *sqs.SQS
+	if svc, err = rdr.newSQSClient(); err != nil {
+		return
+	}
+	var queueURL *string
+	if queueURL, err = rdr.getQueueURL(svc); err != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() { // cancel the long polling when the reader is stopped
+		select {
+		case <-rdr.rdrExit:
+			utils.Logger.Info(
+				fmt.Sprintf("<%s> stop monitoring sqs queue <%s>",
+					utils.ERs, rdr.queueID))
+			cancel()
+			return
+		}
+	}()
+	go rdr.readLoop(ctx, svc, queueURL) // read until the reader is stopped
+	return
+}
+
+func (rdr *SQSER) newSQSClient() (svc *sqs.SQS, err error) {
+	ses, err := newAWSSession(rdr.dialURL, rdr.awsRegion,
+		rdr.awsID, rdr.awsKey, rdr.awsToken)
+	if err != nil {
+		return
+	}
+	return sqs.New(ses), nil
+}
+
+// getQueueURL returns the URL of the queue, creating the queue if it does not exist
+func (rdr *SQSER) getQueueURL(svc *sqs.SQS) (queueURL *string, err error) {
+	var result *sqs.GetQueueUrlOutput
+	if result, err = svc.GetQueueUrl(&sqs.GetQueueUrlInput{
+		QueueName: aws.String(rdr.queueID),
+	}); err == nil {
+		return result.QueueUrl, nil
+	}
+	if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != sqs.ErrCodeQueueDoesNotExist {
+		return
+	}
+	var createResult *sqs.CreateQueueOutput
+	if createResult, err = svc.CreateQueue(&sqs.CreateQueueInput{
+		QueueName: aws.String(rdr.queueID),
+	}); err != nil {
+		return
+	}
+	return createResult.QueueUrl, nil
+}
+
+func (rdr *SQSER) readLoop(ctx context.Context, svc *sqs.SQS, queueURL *string) {
+	for {
+		input := &sqs.ReceiveMessageInput{
+			QueueUrl:            queueURL,
+			MaxNumberOfMessages: aws.Int64(rdr.maxMessages),
+			WaitTimeSeconds:     aws.Int64(rdr.waitTime),
+		}
+		if rdr.visibilityTimeout != 0 {
+			input.VisibilityTimeout = aws.Int64(rdr.visibilityTimeout)
+		}
+		result, err := svc.ReceiveMessageWithContext(ctx, input)
+		if err != nil {
+			if ctx.Err() != nil { // canceled by us when stopping the reader
+				return
+			}
+			rdr.rdrErr <- err
+			return
+		}
+		for _, msg := range result.Messages {
+			if rdr.Config().ConcurrentReqs != -1 {
+				<-rdr.cap // do not process more messages if the limit is reached
+			}
+			go func(msg *sqs.Message) {
+				if rdr.Config().ConcurrentReqs != -1 {
+					defer func() { rdr.cap <- struct{}{} }()
+				}
+				body := []byte(aws.StringValue(msg.Body))
+				if err := rdr.processMessage(body); err != nil {
+					if err != errReaderStopped { // the message becomes visible again after the visibility timeout
+						utils.Logger.Warning(
+							fmt.Sprintf("<%s> processing message %s error: %s",
+								utils.ERs, aws.StringValue(msg.MessageId), err.Error()))
+					}
+					return
+				}
+				if _, err := svc.DeleteMessage(&sqs.DeleteMessageInput{
+					QueueUrl:      queueURL,
+					ReceiptHandle: msg.ReceiptHandle,
+				}); err != nil {
+					utils.Logger.Warning(
+						fmt.Sprintf("<%s> deleting message %s error: %s",
+							utils.ERs, aws.StringValue(msg.MessageId), err.Error()))
+				}
+				if rdr.Config().ProcessedPath != utils.EmptyString { // post it
+					if err := engine.PostersCache.PostSQS(rdr.Config().ProcessedPath,
+						rdr.cgrCfg.GeneralCfg().PosterAttempts, body); err != nil {
+						utils.Logger.Warning(
+							fmt.Sprintf("<%s> writing message %s error: %s",
+								utils.ERs, aws.StringValue(msg.MessageId), err.Error()))
+					}
+				}
+			}(msg)
+		}
+	}
+}
+
+func (rdr *SQSER) processMessage(msg []byte) (err error) {
+	var decodedMessage map[string]interface{}
+	if err = json.Unmarshal(msg, &decodedMessage); err != nil {
+		return
+	}
+	_, err = dispatchEvent(decodedMessage, rdr.Config(), rdr.cgrCfg,
+		rdr.fltrS, rdr.rdrEvents, rdr.rdrExit, context.Background(), true)
+	return
+}
+
+func (rdr *SQSER) setURL(dialURL string) (err error) {
+	var u *url.URL
+	if u, err = url.Parse(dialURL); err != nil {
+		return
+	}
+	qry := u.Query()
+
+	rdr.dialURL = strings.TrimSuffix(strings.Split(dialURL, "?")[0], "/") // used to remove / to point to correct endpoint
+	rdr.queueID = defaultSQSQueueID
+	if vals, has := qry[utils.AWSQueueID]; has && len(vals) != 0 {
+		rdr.queueID = vals[0]
+	}
+	if vals, has := qry[utils.AWSRegion]; has && len(vals) != 0 {
+		rdr.awsRegion = vals[0]
+	}
+	if vals, has := qry[utils.AWSKey]; has && len(vals) != 0 {
+		rdr.awsID = vals[0]
+	}
+	if vals, has := qry[utils.AWSSecret]; has && len(vals) != 0 {
+		rdr.awsKey = vals[0]
+	}
+	if vals, has := qry[utils.AWSToken]; has && len(vals) != 0 {
+		rdr.awsToken = vals[0]
+	}
+	rdr.waitTime = defaultSQSWaitTime
+	if vals, has := qry[utils.SQSWaitTime]; has && len(vals) != 0 {
+		if rdr.waitTime, err = strconv.ParseInt(vals[0], 10, 64); err != nil {
+			return fmt.Errorf("invalid %s: <%s>", utils.SQSWaitTime, vals[0])
+		}
+	}
+	if vals, has := qry[utils.SQSVisibilityTimeout]; has && len(vals) != 0 {
+		if rdr.visibilityTimeout, err = strconv.ParseInt(vals[0], 10, 64); err != nil {
+			return fmt.Errorf("invalid %s: <%s>", utils.SQSVisibilityTimeout, vals[0])
+		}
+	}
+	rdr.maxMessages = defaultSQSMaxMessages
+	if vals, has := qry[utils.SQSMaxMessages]; has && len(vals) != 0 {
+		if rdr.maxMessages, err = strconv.ParseInt(vals[0], 10, 64); err != nil {
+			return fmt.Errorf("invalid %s: <%s>", utils.SQSMaxMessages, vals[0])
+		}
+	}
+	return
+}
@@ -0,0 +1,108 @@
+// +build integration
+
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package ers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// TestSQSER runs against a local SQS compatible server (ie: ElasticMQ)
+func TestSQSER(t *testing.T) {
+	cfg, err := config.NewCGRConfigFromJsonStringWithDefaults(`{
+"ers": {
+	"enabled": true,
+	"readers": [
+		{
+			"id": "sqs",
+			"type": "*sqs_json_map",
+			"run_delay":  "-1",
+			"concurrent_requests": 1024,
+			"source_path": "http://localhost:9324?queue_id=cgrates_ers&aws_region=us-east-1&aws_key=x&aws_secret=x&wait_time=1",
+			"tenant": "cgrates.org",
+			"filters": [],
+			"flags": [],
+			"fields":[
+				{"tag": "CGRID", "type": "*composed", "value": "~*req.CGRID", "path": "*cgreq.CGRID"},
+			],
+		},
+	],
+},
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rdrEvents = make(chan *erEvent, 1)
+	rdrErr = make(chan error, 1)
+	rdrExit = make(chan struct{}, 1)
+	var rdr EventReader
+	if rdr, err = NewSQSER(cfg, 1, rdrEvents,
+		rdrErr, new(engine.FilterS), rdrExit); err != nil {
+		t.Fatal(err)
+	}
+	sqsRdr := rdr.(*SQSER)
+	svc, err := sqsRdr.newSQSClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	queueURL, err := sqsRdr.getQueueURL(svc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = rdr.Serve(); err != nil {
+		t.Fatal(err)
+	}
+	randomCGRID := utils.UUIDSha1Prefix()
+	if _, err = svc.SendMessage(&sqs.SendMessageInput{
+		MessageBody: aws.String(fmt.Sprintf(`{"CGRID": "%s"}`, randomCGRID)),
+		QueueUrl:    queueURL,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err = <-rdrErr:
+		t.Error(err)
+	case ev := <-rdrEvents:
+		if ev.cgrEvent.Event[utils.CGRID] != randomCGRID {
+			t.Errorf("Expected %s ,received %s", randomCGRID, utils.ToJSON(ev.cgrEvent))
+		}
+		ev.procErr <- nil
+	case <-time.After(10 * time.Second):
+		t.Fatal("Timeout")
+	}
+	time.Sleep(100 * time.Millisecond)
+	if attrs, err := svc.GetQueueAttributes(&sqs.GetQueueAttributesInput{
+		QueueUrl:       queueURL,
+		AttributeNames: []*string{aws.String(sqs.QueueAttributeNameApproximateNumberOfMessages)},
+	}); err != nil {
+		t.Error(err)
+	} else if nr := aws.StringValue(attrs.Attributes[sqs.QueueAttributeNameApproximateNumberOfMessages]); nr != "0" {
+		t.Errorf("Expected the message deleted from queue, %s left", nr)
+	}
+	close(rdrExit)
+}
@@ -0,0 +1,66 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package ers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSQSSetURL(t *testing.T) {
+	rdr := new(SQSER)
+	expSQS := &SQSER{
+		dialURL:           "http://localhost:9324",
+		awsRegion:         "eu-west-2",
+		awsID:             "testID",
+		awsKey:            "testKey",
+		awsToken:          "testToken",
+		queueID:           "cdrs",
+		waitTime:          5,
+		visibilityTimeout: 60,
+		maxMessages:       1,
+	}
+	if err := rdr.setURL("http://localhost:9324/?queue_id=cdrs&aws_region=eu-west-2&aws_key=testID&aws_secret=testKey&aws_token=testToken&wait_time=5&visibility_timeout=60&max_messages=1"); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(expSQS, rdr) {
+		t.Errorf("Expected: %+v ,received: %+v", expSQS, rdr)
+	}
+	rdr = new(SQSER)
+	expSQS = &SQSER{
+		dialURL:     "http://localhost:9324",
+		queueID:     defaultSQSQueueID,
+		waitTime:    defaultSQSWaitTime,
+		maxMessages: defaultSQSMaxMessages,
+	}
+	if err := rdr.setURL("http://localhost:9324"); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(expSQS, rdr) {
+		t.Errorf("Expected: %+v ,received: %+v", expSQS, rdr)
+	}
+	for _, dialURL := range []string{
+		"http://localhost:9324?wait_time=a",
+		"http://localhost:9324?visibility_timeout=a",
+		"http://localhost:9324?max_messages=a",
+		":",
+	} {
+		if err := new(SQSER).setURL(dialURL); err == nil {
+			t.Errorf("Expected error for: %s", dialURL)
+		}
+	}
+}
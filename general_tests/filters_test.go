@@ -116,3 +116,47 @@ func TestInlineFilterPassFiltersForEvent(t *testing.T) {
 		t.Errorf("Expecting: %+v, received: %+v", true, pass)
 	}
 }
+
+func TestInlineFilterPassIPNetDestinations(t *testing.T) {
+	engine.Cache.Clear(nil) // make sure the internal connection is not reused from other tests
+	cfg, _ := config.NewDefaultCGRConfig()
+	cfg.FilterSCfg().ApierSConns = []string{utils.ConcatenatedKey(utils.MetaInternal, utils.MetaApier)}
+	internalAPIerSv1Chan := make(chan rpcclient.ClientConnector, 1)
+	connMgr := engine.NewConnManager(cfg, map[string]chan rpcclient.ClientConnector{
+		utils.ConcatenatedKey(utils.MetaInternal, utils.MetaApier): internalAPIerSv1Chan,
+	})
+	data := engine.NewInternalDB(nil, nil, true, cfg.DataDbCfg().Items)
+	dmFilterPass := engine.NewDataManager(data, cfg.CacheCfg(), connMgr)
+	filterS := engine.NewFilterS(cfg, connMgr, dmFilterPass)
+	if err := dmFilterPass.SetDestination(&engine.Destination{Id: "TRUNKS_CUST1",
+		Prefixes: []string{"10.1.0.0/16", "2001:db8::/32"}}, utils.NonTransactional); err != nil {
+		t.Fatal(err)
+	}
+	internalAPIerSv1Chan <- &v1.APIerSv1{DataManager: dmFilterPass}
+	engine.SetConnManager(connMgr)
+	config.SetCgrConfig(cfg)
+	pEv := config.NewNavigableMap(map[string]interface{}{utils.MetaReq: map[string]interface{}{
+		"SourceIP": "10.1.12.4",
+	}})
+	fEv := config.NewNavigableMap(map[string]interface{}{utils.MetaReq: map[string]interface{}{
+		"SourceIP": "10.2.12.4",
+	}})
+	if pass, err := filterS.Pass("cgrates.org",
+		[]string{"*ipnet:~*req.SourceIP:TRUNKS_CUST1"}, pEv); err != nil {
+		t.Error(err)
+	} else if !pass {
+		t.Errorf("Expecting: %+v, received: %+v", true, pass)
+	}
+	if pass, err := filterS.Pass("cgrates.org",
+		[]string{"*ipnet:~*req.SourceIP:TRUNKS_CUST1"}, fEv); err != nil {
+		t.Error(err)
+	} else if pass {
+		t.Errorf("Expecting: %+v, received: %+v", false, pass)
+	}
+	if pass, err := filterS.Pass("cgrates.org",
+		[]string{"*notipnet:~*req.SourceIP:TRUNKS_CUST1;192.168.0.0/24"}, fEv); err != nil {
+		t.Error(err)
+	} else if !pass {
+		t.Errorf("Expecting: %+v, received: %+v", true, pass)
+	}
+}
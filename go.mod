@@ -34,16 +34,18 @@ require (
 	github.com/hashicorp/golang-lru v0.5.2-0.20190520140433-59383c442f7d // indirect
 	github.com/ishidawataru/sctp v0.0.0-20180918013207-6e2cb1366111 // indirect
 	github.com/jinzhu/gorm v1.9.10
+	github.com/jlaffaye/ftp v0.0.0-20201112195030-9aae4d151126
 	github.com/lib/pq v1.2.0
 	github.com/mailru/easyjson v0.7.0 // indirect
 	github.com/mattn/go-runewidth v0.0.5-0.20181218000649-703b5e6b11ae // indirect
 	github.com/mediocregopher/radix.v2 v0.0.0-20181115013041-b67df6e626f9
 	github.com/miekg/dns v1.1.17
 	github.com/mitchellh/mapstructure v1.1.2
+	github.com/nats-io/nats.go v1.11.0
 	github.com/nyaruka/phonenumbers v1.0.45
 	github.com/peterh/liner v1.1.1-0.20190305032635-6f820f8f90ce
 	github.com/philhofer/fwd v1.0.0 // indirect
-	github.com/pkg/errors v0.8.2-0.20190227000051-27936f6d90f9 // indirect
+	github.com/pkg/sftp v1.12.0
 	github.com/pquerna/ffjson v0.0.0-20190813045741-dac163c6c0a9 // indirect
 	github.com/segmentio/kafka-go v0.3.3
 	github.com/streadway/amqp v0.0.0-20190827072141-edfb9018d271
@@ -53,7 +55,8 @@ require (
 	github.com/xdg/stringprep v1.0.1-0.20180714160509-73f8eece6fdc // indirect
 	go.mongodb.org/mongo-driver v1.1.1
 	go.opencensus.io v0.22.1-0.20190713072201-b4a14686f0a9 // indirect
-	golang.org/x/net v0.0.0-20190909003024-a7b16738d86b
+	golang.org/x/crypto v0.0.0-20210314154223-e6e6c4f2bb5b
+	golang.org/x/net v0.0.0-20210226172049-e18ecbb05110
 	golang.org/x/oauth2 v0.0.0-20190604053449-0f29369cfe45
 	google.golang.org/api v0.10.0
 	pack.ag/amqp v0.12.2
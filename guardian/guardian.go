@@ -59,11 +59,14 @@ func (gl *GuardianLocker) lockItem(itmID string) {
 	select {
 	case <-itmLock.lk:
 		gl.lkMux.Unlock()
+		utils.RuntimeMetrics.ObserveHistogram(utils.MetricGuardianLockWait, 0)
 		return
 	default: // move further so we can unlock
 	}
 	gl.lkMux.Unlock()
+	sTime := time.Now()
 	<-itmLock.lk
+	utils.RuntimeMetrics.ObserveDuration(utils.MetricGuardianLockWait, sTime)
 }
 
 func (gl *GuardianLocker) unlockItem(itmID string) {
@@ -130,7 +130,9 @@ func (db *DataDBService) ServiceName() string {
 
 // ShouldRun returns if the service should be running
 func (db *DataDBService) ShouldRun() bool {
-	return db.mandatoryDB() || db.cfg.SessionSCfg().Enabled
+	return db.mandatoryDB() || db.cfg.SessionSCfg().Enabled ||
+		(db.cfg.ERsCfg().Enabled || db.cfg.CdrsCfg().Enabled) &&
+			db.cfg.DedupCfg().Store == utils.MetaDataDB
 }
 
 // mandatoryDB returns if the current configuration needs the DB
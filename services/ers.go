@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"sync"
 
+	v1 "github.com/cgrates/cgrates/apier/v1"
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/engine"
 	"github.com/cgrates/cgrates/ers"
@@ -31,13 +32,15 @@ import (
 )
 
 // NewEventReaderService returns the EventReader Service
-func NewEventReaderService(cfg *config.CGRConfig, filterSChan chan *engine.FilterS,
-	exitChan chan bool, connMgr *engine.ConnManager) servmanager.Service {
+func NewEventReaderService(cfg *config.CGRConfig, dm *DataDBService, filterSChan chan *engine.FilterS,
+	exitChan chan bool, server *utils.Server, connMgr *engine.ConnManager) servmanager.Service {
 	return &EventReaderService{
 		rldChan:     make(chan struct{}, 1),
 		cfg:         cfg,
+		dm:          dm,
 		filterSChan: filterSChan,
 		exitChan:    exitChan,
+		server:      server,
 		connMgr:     connMgr,
 	}
 }
@@ -46,8 +49,10 @@ func NewEventReaderService(cfg *config.CGRConfig, filterSChan chan *engine.Filte
 type EventReaderService struct {
 	sync.RWMutex
 	cfg         *config.CGRConfig
+	dm          *DataDBService // used by the *datadb dedup store
 	filterSChan chan *engine.FilterS
 	exitChan    chan bool
+	server      *utils.Server
 
 	ers      *ers.ERService
 	rldChan  chan struct{}
@@ -73,13 +78,16 @@ func (erS *EventReaderService) Start() (err error) {
 	utils.Logger.Info(fmt.Sprintf("<%s> starting <%s> subsystem", utils.CoreS, utils.ERs))
 
 	// build the service
-	erS.ers = ers.NewERService(erS.cfg, filterS, erS.stopChan, erS.connMgr)
+	erS.ers = ers.NewERService(erS.cfg, erS.dm.GetDM(), filterS, erS.stopChan, erS.server, erS.connMgr)
 	go func(ers *ers.ERService, rldChan chan struct{}) {
 		if err := ers.ListenAndServe(rldChan); err != nil {
 			utils.Logger.Err(fmt.Sprintf("<%s> error: <%s>", utils.ERs, err.Error()))
 			erS.exitChan <- true
 		}
 	}(erS.ers, erS.rldChan)
+	if !erS.cfg.DispatcherSCfg().Enabled {
+		erS.server.RpcRegister(v1.NewErSv1(erS.ers))
+	}
 	return
 }
 
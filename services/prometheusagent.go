@@ -0,0 +1,123 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/cgrates/cgrates/agents"
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/servmanager"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/cgrates/rpcclient"
+)
+
+// NewPrometheusAgent returns the Prometheus Agent
+func NewPrometheusAgent(cfg *config.CGRConfig, filterSChan chan *engine.FilterS,
+	server *utils.Server, connMgr *engine.ConnManager) servmanager.Service {
+	return &PrometheusAgent{
+		cfg:         cfg,
+		filterSChan: filterSChan,
+		server:      server,
+		connMgr:     connMgr,
+	}
+}
+
+// PrometheusAgent implements Agent interface
+type PrometheusAgent struct {
+	sync.RWMutex
+	cfg         *config.CGRConfig
+	filterSChan chan *engine.FilterS
+	server      *utils.Server
+
+	pa         *agents.PrometheusAgent
+	connMgr    *engine.ConnManager
+	registered bool // the HTTP handler cannot be unregistered so we register it only once
+}
+
+// Start should handle the sercive start
+func (pa *PrometheusAgent) Start() (err error) {
+	if pa.IsRunning() {
+		return fmt.Errorf("service aleady running")
+	}
+
+	filterS := <-pa.filterSChan
+	pa.filterSChan <- filterS
+
+	pa.Lock()
+	defer pa.Unlock()
+	utils.Logger.Info(fmt.Sprintf("Starting Prometheus agent on path: %s",
+		pa.cfg.PrometheusAgentCfg().Path))
+	pa.pa = agents.NewPrometheusAgent(pa.cfg, filterS, pa.connMgr)
+	if !pa.registered {
+		pa.server.RegisterHttpHandler(pa.cfg.PrometheusAgentCfg().Path, pa)
+		pa.registered = true
+	}
+	return
+}
+
+// ServeHTTP passes the request to the agent if the service is running
+func (pa *PrometheusAgent) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	pa.RLock()
+	prmA := pa.pa
+	pa.RUnlock()
+	if prmA == nil {
+		http.NotFound(w, r)
+		return
+	}
+	prmA.ServeHTTP(w, r)
+}
+
+// GetIntenternalChan returns the internal connection chanel
+func (pa *PrometheusAgent) GetIntenternalChan() (conn chan rpcclient.ClientConnector) {
+	return nil
+}
+
+// Reload handles the change of config
+func (pa *PrometheusAgent) Reload() (err error) {
+	return // the agent reads the config on each request, the path is not reloaded
+}
+
+// Shutdown stops the service
+func (pa *PrometheusAgent) Shutdown() (err error) {
+	pa.Lock()
+	pa.pa = nil // the registered handler will reply with not found
+	pa.Unlock()
+	return
+}
+
+// IsRunning returns if the service is running
+func (pa *PrometheusAgent) IsRunning() bool {
+	pa.RLock()
+	defer pa.RUnlock()
+	return pa != nil && pa.pa != nil
+}
+
+// ServiceName returns the service name
+func (pa *PrometheusAgent) ServiceName() string {
+	return utils.PrometheusAgent
+}
+
+// ShouldRun returns if the service should be running
+func (pa *PrometheusAgent) ShouldRun() bool {
+	return pa.cfg.PrometheusAgentCfg().Enabled
+}
@@ -0,0 +1,116 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package services
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/servmanager"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/cgrates/rpcclient"
+)
+
+// NewTracingService returns the Tracing Service
+func NewTracingService(cfg *config.CGRConfig) servmanager.Service {
+	return &TracingService{
+		cfg: cfg,
+	}
+}
+
+// TracingService implements Service interface
+type TracingService struct {
+	sync.RWMutex
+	cfg *config.CGRConfig
+
+	tr *utils.Tracer
+}
+
+// Start should handle the sercive start
+func (trS *TracingService) Start() (err error) {
+	if trS.IsRunning() {
+		return fmt.Errorf("service aleady running")
+	}
+	trS.Lock()
+	defer trS.Unlock()
+	return trS.start()
+}
+
+// start builds the tracer out of config and makes it active
+func (trS *TracingService) start() (err error) {
+	trCfg := trS.cfg.TracingCfg()
+	var exp utils.SpanExporter
+	if exp, err = utils.NewSpanExporter(trCfg.Exporter, trCfg.ExportPath,
+		trS.cfg.GeneralCfg().NodeID, trS.cfg.GeneralCfg().ReplyTimeout); err != nil {
+		utils.Logger.Crit(fmt.Sprintf("<%s> Could not init, error: %s", utils.Tracing, err.Error()))
+		return
+	}
+	utils.Logger.Info(fmt.Sprintf("<%s> exporting spans to %s using %s",
+		utils.Tracing, trCfg.ExportPath, trCfg.Exporter))
+	trS.tr = utils.NewTracer(trS.cfg.GeneralCfg().NodeID, exp,
+		trCfg.BufferSize, trCfg.FlushInterval)
+	utils.SetTracer(trS.tr)
+	return
+}
+
+// GetIntenternalChan returns the internal connection chanel
+func (trS *TracingService) GetIntenternalChan() (conn chan rpcclient.ClientConnector) {
+	return nil
+}
+
+// Reload handles the change of config
+// the old tracer is kept active if the new one cannot be started
+func (trS *TracingService) Reload() (err error) {
+	trS.Lock()
+	defer trS.Unlock()
+	oldTr := trS.tr
+	if err = trS.start(); err != nil {
+		return
+	}
+	oldTr.Shutdown() // export the remaining spans
+	return
+}
+
+// Shutdown stops the service
+func (trS *TracingService) Shutdown() (err error) {
+	trS.Lock()
+	utils.SetTracer(nil)
+	trS.tr.Shutdown() // export the remaining spans
+	trS.tr = nil
+	trS.Unlock()
+	return
+}
+
+// IsRunning returns if the service is running
+func (trS *TracingService) IsRunning() bool {
+	trS.RLock()
+	defer trS.RUnlock()
+	return trS != nil && trS.tr != nil
+}
+
+// ServiceName returns the service name
+func (trS *TracingService) ServiceName() string {
+	return utils.Tracing
+}
+
+// ShouldRun returns if the service should be running
+func (trS *TracingService) ShouldRun() bool {
+	return trS.cfg.TracingCfg().Enabled
+}
@@ -169,8 +169,10 @@ func (srvMngr *ServiceManager) StartServices() (err error) {
 		utils.RadiusAgent:     srvMngr.GetConfig().RadiusAgentCfg().Enabled,
 		utils.DiameterAgent:   srvMngr.GetConfig().DiameterAgentCfg().Enabled,
 		utils.HTTPAgent:       len(srvMngr.GetConfig().HttpAgentCfg()) != 0,
+		utils.PrometheusAgent: srvMngr.GetConfig().PrometheusAgentCfg().Enabled,
 		utils.LoaderS:         srvMngr.GetConfig().LoaderCfg().Enabled(),
 		utils.AnalyzerS:       srvMngr.GetConfig().AnalyzerSCfg().Enabled,
+		utils.Tracing:         srvMngr.GetConfig().TracingCfg().Enabled,
 		utils.DispatcherS:     srvMngr.GetConfig().DispatcherSCfg().Enabled,
 	} {
 		if shouldRun {
@@ -288,6 +290,10 @@ func (srvMngr *ServiceManager) handleReload() {
 			if err = srvMngr.reloadService(utils.HTTPAgent); err != nil {
 				return
 			}
+		case <-srvMngr.GetConfig().GetReloadChan(config.PrometheusAgentJson):
+			if err = srvMngr.reloadService(utils.PrometheusAgent); err != nil {
+				return
+			}
 		case <-srvMngr.GetConfig().GetReloadChan(config.LoaderJson):
 			if err = srvMngr.reloadService(utils.LoaderS); err != nil {
 				return
@@ -296,6 +302,10 @@ func (srvMngr *ServiceManager) handleReload() {
 			if err = srvMngr.reloadService(utils.AnalyzerS); err != nil {
 				return
 			}
+		case <-srvMngr.GetConfig().GetReloadChan(config.TracingJson):
+			if err = srvMngr.reloadService(utils.Tracing); err != nil {
+				return
+			}
 		case <-srvMngr.GetConfig().GetReloadChan(config.DispatcherSJson):
 			if err = srvMngr.reloadService(utils.DispatcherS); err != nil {
 				return
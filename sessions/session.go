@@ -109,6 +109,15 @@ func (s *Session) cgrID() (cgrID string) {
 	return
 }
 
+// logFields returns the fields identifying the session in the structured logs
+func (s *Session) logFields() utils.LogFields {
+	return utils.LogFields{
+		utils.LogFieldTenant:   s.Tenant,
+		utils.LogFieldCGRID:    s.CGRID,
+		utils.LogFieldOriginID: s.EventStart.GetStringIgnoreErrors(utils.OriginID),
+	}
+}
+
 // Clone is a thread safe method to clone the sessions information
 func (s *Session) Clone() (cln *Session) {
 	s.RLock()
@@ -130,6 +139,61 @@ func (s *Session) Clone() (cln *Session) {
 	return
 }
 
+// asStoredSession is a thread safe method to convert the session into its DataDB backup representation
+func (s *Session) asStoredSession() (ss *engine.StoredSession) {
+	s.RLock()
+	ss = &engine.StoredSession{
+		CGRID:         s.CGRID,
+		Tenant:        s.Tenant,
+		ResourceID:    s.ResourceID,
+		ClientConnID:  s.ClientConnID,
+		EventStart:    s.EventStart.Clone(),
+		DebitInterval: s.DebitInterval,
+		ArgDispatcher: s.ArgDispatcher,
+		SRuns:         make([]*engine.StoredSRun, len(s.SRuns)),
+	}
+	for i, sR := range s.SRuns {
+		clSR := sR.Clone()
+		ss.SRuns[i] = &engine.StoredSRun{
+			Event:         clSR.Event,
+			CD:            clSR.CD,
+			EventCost:     clSR.EventCost,
+			ExtraDuration: clSR.ExtraDuration,
+			LastUsage:     clSR.LastUsage,
+			TotalUsage:    clSR.TotalUsage,
+			NextAutoDebit: clSR.NextAutoDebit,
+		}
+	}
+	s.RUnlock()
+	return
+}
+
+// newSessionFromStoredSession recreates the Session out of its DataDB backup
+func newSessionFromStoredSession(ss *engine.StoredSession) (s *Session) {
+	s = &Session{
+		CGRID:         ss.CGRID,
+		Tenant:        ss.Tenant,
+		ResourceID:    ss.ResourceID,
+		ClientConnID:  ss.ClientConnID,
+		EventStart:    ss.EventStart,
+		DebitInterval: ss.DebitInterval,
+		ArgDispatcher: ss.ArgDispatcher,
+		SRuns:         make([]*SRun, len(ss.SRuns)),
+	}
+	for i, sR := range ss.SRuns {
+		s.SRuns[i] = &SRun{
+			Event:         sR.Event,
+			CD:            sR.CD,
+			EventCost:     sR.EventCost,
+			ExtraDuration: sR.ExtraDuration,
+			LastUsage:     sR.LastUsage,
+			TotalUsage:    sR.TotalUsage,
+			NextAutoDebit: sR.NextAutoDebit,
+		}
+	}
+	return
+}
+
 // AsExternalSessions returns the session as a list of ExternalSession using all SRuns (thread safe)
 func (s *Session) AsExternalSessions(tmz, nodeID string) (aSs []*ExternalSession) {
 	s.RLock()
@@ -716,3 +716,39 @@ func TestSessionstopDebitLoops(t *testing.T) {
 	}
 
 }
+
+func TestSessionAsStoredSession(t *testing.T) {
+	nextDbt := time.Date(2020, 4, 18, 14, 25, 0, 0, time.UTC)
+	s := &Session{
+		CGRID:         "CGRID",
+		Tenant:        "cgrates.org",
+		ResourceID:    "resourceID",
+		ClientConnID:  "ClientConnID",
+		EventStart:    engine.NewMapEvent(map[string]interface{}{utils.OriginID: "ORIGIN_ID"}),
+		DebitInterval: 18,
+		SRuns: []*SRun{
+			&SRun{
+				Event:         engine.NewMapEvent(map[string]interface{}{utils.RunID: utils.MetaDefault}),
+				CD:            &engine.CallDescriptor{Category: "call"},
+				EventCost:     &engine.EventCost{CGRID: "CGRID", RunID: utils.MetaDefault},
+				ExtraDuration: time.Second,
+				LastUsage:     5 * time.Second,
+				TotalUsage:    10 * time.Second,
+				NextAutoDebit: &nextDbt,
+			},
+		},
+		ArgDispatcher: &utils.ArgDispatcher{APIKey: utils.StringPointer("apikey")},
+		debitStop:     make(chan struct{}),
+	}
+	ss := s.asStoredSession()
+	if len(ss.SRuns) != 1 ||
+		ss.SRuns[0].TotalUsage != 10*time.Second ||
+		!ss.SRuns[0].NextAutoDebit.Equal(nextDbt) {
+		t.Errorf("unexpected stored session: %s", utils.ToJSON(ss))
+	}
+	exp := s.Clone()
+	exp.ArgDispatcher = s.ArgDispatcher
+	if rcv := newSessionFromStoredSession(ss); !reflect.DeepEqual(exp, rcv) {
+		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(exp), utils.ToJSON(rcv))
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"os"
 	"reflect"
 	"strings"
 	"sync"
@@ -96,6 +97,31 @@ type SessionS struct {
 // ListenAndServe starts the service and binds it to the listen loop
 func (sS *SessionS) ListenAndServe(exitChan chan bool) (err error) {
 	utils.Logger.Info(fmt.Sprintf("<%s> starting <%s> subsystem", utils.CoreS, utils.SessionS))
+	utils.RuntimeMetrics.RegisterGaugeFunc(utils.MetricSessions,
+		"Sessions handled by SessionS, per state.", sS.sessionsMetrics)
+	if bkpIntvl := sS.cgrCfg.SessionSCfg().BackupInterval; bkpIntvl != 0 {
+		if err = sS.restoreSessions(); err != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> failed restoring sessions from backup, err: <%s>",
+					utils.SessionS, err.Error()))
+			err = nil
+		}
+		go func() {
+			for { // backup the active sessions periodically
+				select {
+				case e := <-exitChan:
+					exitChan <- e
+					return
+				case <-time.After(bkpIntvl):
+					if err := sS.backupSessions(); err != nil {
+						utils.Logger.Warning(
+							fmt.Sprintf("<%s> failed backing up sessions, err: <%s>",
+								utils.SessionS, err.Error()))
+					}
+				}
+			}
+		}()
+	}
 	if sS.cgrCfg.SessionSCfg().ChannelSyncInterval != 0 {
 		go func() {
 			for { // Schedule sync channels to run repeately
@@ -117,12 +143,94 @@ func (sS *SessionS) ListenAndServe(exitChan chan bool) (err error) {
 
 // Shutdown is called by engine to clear states
 func (sS *SessionS) Shutdown() (err error) {
+	utils.RuntimeMetrics.UnregisterMetric(utils.MetricSessions)
+	if sS.cgrCfg.SessionSCfg().BackupInterval != 0 { // keep the sessions for the next start
+		for _, s := range sS.getSessions("", false) {
+			s.Lock()
+			s.stopSTerminator()
+			s.stopDebitLoops()
+			s.Unlock()
+		}
+		return sS.backupSessions()
+	}
 	for _, s := range sS.getSessions("", false) { // Force sessions shutdown
 		sS.terminateSession(s, nil, nil, nil, false)
 	}
 	return
 }
 
+// backupID returns the key of the sessions backup in DataDB, the node_id if configured,
+// otherwise one built out of the hostname and listeners so it stays the same after restart
+func (sS *SessionS) backupID() string {
+	if !sS.cgrCfg.NodeIDGenerated() {
+		return sS.cgrCfg.GeneralCfg().NodeID
+	}
+	hostname, _ := os.Hostname()
+	return utils.Sha1(hostname, sS.cgrCfg.ListenCfg().RPCJSONListen,
+		sS.cgrCfg.ListenCfg().RPCGOBListen, sS.cgrCfg.ListenCfg().HTTPListen)
+}
+
+// backupSessions overwrites the backup of active sessions in DataDB
+func (sS *SessionS) backupSessions() (err error) {
+	nodeID := sS.backupID()
+	aSs := sS.getSessions("", false)
+	if len(aSs) == 0 {
+		if err = sS.dm.RemoveSessionsBackup(nodeID); err == utils.ErrNotFound {
+			err = nil
+		}
+		return
+	}
+	now := time.Now()
+	sSs := make([]*engine.StoredSession, len(aSs))
+	for i, s := range aSs {
+		sSs[i] = s.asStoredSession()
+		sSs[i].UpdatedAt = now
+	}
+	return sS.dm.SetSessionsBackup(nodeID, sSs)
+}
+
+// restoreSessions activates the sessions found in the DataDB backup,
+// the ones not known anymore by agents being removed on next syncSessions
+func (sS *SessionS) restoreSessions() (err error) {
+	var sSs []*engine.StoredSession
+	if sSs, err = sS.dm.GetSessionsBackup(sS.backupID()); err != nil {
+		if err == utils.ErrNotFound {
+			err = nil
+		}
+		return
+	}
+	var restored int
+	for _, ss := range sSs {
+		s := newSessionFromStoredSession(ss)
+		if sS.isIndexed(s, false) {
+			continue
+		}
+		s.Lock()
+		sS.registerSession(s, false)
+		sS.initSessionDebitLoops(s)
+		sS.setSTerminator(s)
+		s.Unlock()
+		restored++
+	}
+	utils.Logger.Info(fmt.Sprintf("<%s> restored %d sessions from backup",
+		utils.SessionS, restored))
+	return
+}
+
+// sessionsMetrics returns the number of active and passive sessions
+func (sS *SessionS) sessionsMetrics() []*utils.MetricSample {
+	sS.aSsMux.RLock()
+	aSs := len(sS.aSessions)
+	sS.aSsMux.RUnlock()
+	sS.pSsMux.RLock()
+	pSs := len(sS.pSessions)
+	sS.pSsMux.RUnlock()
+	return []*utils.MetricSample{
+		{Labels: map[string]string{utils.MetricLabelState: utils.MetricStateActive}, Value: float64(aSs)},
+		{Labels: map[string]string{utils.MetricLabelState: utils.MetricStatePassive}, Value: float64(pSs)},
+	}
+}
+
 // OnBiJSONConnect is called by rpc2.Client on each new connection
 func (sS *SessionS) OnBiJSONConnect(c *rpc2.Client) {
 	sS.biJMux.Lock()
@@ -302,19 +410,21 @@ func (sS *SessionS) forceSTerminate(s *Session, extraDebit time.Duration, lastUs
 	if extraDebit != 0 {
 		for i := range s.SRuns {
 			if _, err = sS.debitSession(s, i, extraDebit, lastUsed); err != nil {
-				utils.Logger.Warning(
+				utils.Logger.Log(utils.LOGLEVEL_WARNING,
 					fmt.Sprintf(
 						"<%s> failed debitting cgrID %s, sRunIdx: %d, err: %s",
-						utils.SessionS, s.cgrID(), i, err.Error()))
+						utils.SessionS, s.cgrID(), i, err.Error()),
+					s.logFields())
 			}
 		}
 	}
 	// we apply the correction before
 	if err = sS.endSession(s, nil, nil, nil, false); err != nil {
-		utils.Logger.Warning(
+		utils.Logger.Log(utils.LOGLEVEL_WARNING,
 			fmt.Sprintf(
 				"<%s> failed force terminating session with ID <%s>, err: <%s>",
-				utils.SessionS, s.cgrID(), err.Error()))
+				utils.SessionS, s.cgrID(), err.Error()),
+			s.logFields())
 	}
 	// post the CDRs
 	if len(sS.cgrCfg.SessionSCfg().CDRsConns) != 0 {
@@ -454,15 +564,23 @@ func (sS *SessionS) debitLoopSession(s *Session, sRunIdx int,
 	now := time.Now()
 	if s.SRuns[sRunIdx].NextAutoDebit != nil &&
 		now.Before(*s.SRuns[sRunIdx].NextAutoDebit) {
-		time.Sleep(now.Sub(*s.SRuns[sRunIdx].NextAutoDebit))
+		time.Sleep(s.SRuns[sRunIdx].NextAutoDebit.Sub(now))
 	}
 	for {
 		s.Lock()
+		if nextDbt := s.SRuns[sRunIdx].NextAutoDebit; nextDbt != nil { // late compared to the planned debit
+			lag := time.Since(*nextDbt)
+			if lag < 0 {
+				lag = 0
+			}
+			utils.RuntimeMetrics.ObserveHistogram(utils.MetricSessionsDebitLag, lag.Seconds())
+		}
 		var maxDebit time.Duration
 		if maxDebit, err = sS.debitSession(s, sRunIdx, dbtIvl, nil); err != nil {
-			utils.Logger.Warning(
+			utils.Logger.Log(utils.LOGLEVEL_WARNING,
 				fmt.Sprintf("<%s> could not complete debit operation on session: <%s>, error: <%s>",
-					utils.SessionS, s.cgrID(), err.Error()))
+					utils.SessionS, s.cgrID(), err.Error()),
+				s.logFields())
 			dscReason := utils.ErrServerError.Error()
 			if err.Error() == utils.ErrUnauthorizedDestination.Error() {
 				dscReason = err.Error()
@@ -473,12 +591,13 @@ func (sS *SessionS) debitLoopSession(s *Session, sRunIdx int,
 					s.Unlock()
 					return
 				}
-				utils.Logger.Warning(
+				utils.Logger.Log(utils.LOGLEVEL_WARNING,
 					fmt.Sprintf("<%s> could not disconnect session: %s, error: %s",
-						utils.SessionS, s.cgrID(), err.Error()))
+						utils.SessionS, s.cgrID(), err.Error()),
+					s.logFields())
 			}
 			if err = sS.forceSTerminate(s, 0, nil); err != nil {
-				utils.Logger.Warning(fmt.Sprintf("<%s> failed force-terminating session: <%s>, err: <%s>", utils.SessionS, s.cgrID(), err))
+				utils.Logger.Log(utils.LOGLEVEL_WARNING, fmt.Sprintf("<%s> failed force-terminating session: <%s>, err: <%s>", utils.SessionS, s.cgrID(), err), s.logFields())
 			}
 			s.Unlock()
 			return
@@ -487,8 +606,8 @@ func (sS *SessionS) debitLoopSession(s *Session, sRunIdx int,
 		s.SRuns[sRunIdx].NextAutoDebit = utils.TimePointer(time.Now().Add(dbtIvl))
 		if maxDebit < dbtIvl && sS.cgrCfg.SessionSCfg().MinDurLowBalance != time.Duration(0) { // warn client for low balance
 			if sS.cgrCfg.SessionSCfg().MinDurLowBalance >= dbtIvl {
-				utils.Logger.Warning(fmt.Sprintf("<%s> can not run warning for the session: <%s> since the remaining time:<%s> is higher than the debit interval:<%s>.",
-					utils.SessionS, s.cgrID(), sS.cgrCfg.SessionSCfg().MinDurLowBalance, dbtIvl))
+				utils.Logger.Log(utils.LOGLEVEL_WARNING, fmt.Sprintf("<%s> can not run warning for the session: <%s> since the remaining time:<%s> is higher than the debit interval:<%s>.",
+					utils.SessionS, s.cgrID(), sS.cgrCfg.SessionSCfg().MinDurLowBalance, dbtIvl), s.logFields())
 			} else if maxDebit <= sS.cgrCfg.SessionSCfg().MinDurLowBalance {
 				go sS.warnSession(s.ClientConnID, s.EventStart.Clone())
 			}
@@ -508,12 +627,13 @@ func (sS *SessionS) debitLoopSession(s *Session, sRunIdx int,
 						return
 					}
 				}
-				utils.Logger.Warning(
+				utils.Logger.Log(utils.LOGLEVEL_WARNING,
 					fmt.Sprintf("<%s> could not disconnect session: <%s>, error: <%s>",
-						utils.SessionS, s.cgrID(), err.Error()))
+						utils.SessionS, s.cgrID(), err.Error()),
+					s.logFields())
 				if err = sS.forceSTerminate(s, 0, nil); err != nil {
-					utils.Logger.Warning(fmt.Sprintf("<%s> failed force-terminating session: <%s>, err: <%s>",
-						utils.SessionS, s.cgrID(), err))
+					utils.Logger.Log(utils.LOGLEVEL_WARNING, fmt.Sprintf("<%s> failed force-terminating session: <%s>, err: <%s>",
+						utils.SessionS, s.cgrID(), err), s.logFields())
 				}
 			}
 			return
@@ -940,6 +1060,7 @@ func (sS *SessionS) filterSessions(sf *utils.SessionFilter, psv bool) (aSs []*Ex
 		return
 	}
 	ss := sS.getSessionsFromCGRIDs(psv, cgrIDs...)
+	fltrS := engine.NewFilterS(sS.cgrCfg, sS.connMgr, sS.dm) // resolves the operands of *expr rules
 	pass := func(filterRules []*engine.FilterRule,
 		me engine.MapEvent) (pass bool) {
 		pass = true
@@ -950,7 +1071,7 @@ func (sS *SessionS) filterSessions(sf *utils.SessionFilter, psv bool) (aSs []*Ex
 		ev := config.NewNavigableMap(map[string]interface{}{utils.MetaReq: me.Data()})
 		for _, fltr := range filterRules {
 			// we don't know how many values we have so we need to build the fieldValues DataProvider
-			if pass, err = fltr.Pass(ev); err != nil || !pass {
+			if pass, err = fltrS.PassRule(tenant, fltr, ev); err != nil || !pass {
 				pass = false
 				return
 			}
@@ -996,6 +1117,7 @@ func (sS *SessionS) filterSessionsCount(sf *utils.SessionFilter, psv bool) (coun
 		return
 	}
 	ss := sS.getSessionsFromCGRIDs(psv, cgrIDs...)
+	fltrS := engine.NewFilterS(sS.cgrCfg, sS.connMgr, sS.dm) // resolves the operands of *expr rules
 	pass := func(filterRules []*engine.FilterRule,
 		me engine.MapEvent) (pass bool) {
 		pass = true
@@ -1006,7 +1128,7 @@ func (sS *SessionS) filterSessionsCount(sf *utils.SessionFilter, psv bool) (coun
 		ev := config.NewNavigableMap(map[string]interface{}{utils.MetaReq: me.Data()})
 		for _, fltr := range filterRules {
 			// we don't know how many values we have so we need to build the fieldValues DataProvider
-			if pass, err = fltr.Pass(ev); err != nil || !pass {
+			if pass, err = fltrS.PassRule(tenant, fltr, ev); err != nil || !pass {
 				return
 			}
 		}
@@ -1210,19 +1332,26 @@ func (sS *SessionS) getRelocateSession(cgrID string, initOriginID,
 func (sS *SessionS) syncSessions() {
 	queriedCGRIDs := engine.NewSafEvent(nil) // need this to be
 	var err error
-	for _, clnt := range sS.biJClients() {
-		errChan := make(chan error)
-		go func() {
+	sS.biJMux.RLock()
+	biJIDs := make(map[string]*biJClient, len(sS.biJIDs))
+	for connID, clnt := range sS.biJIDs {
+		biJIDs[connID] = clnt
+	}
+	sS.biJMux.RUnlock()
+	for connID, clnt := range biJIDs {
+		errChan := make(chan error, 1) // buffered so the late replies do not block the goroutine
+		go func(connID string, clnt *biJClient) {
 			var queriedSessionIDs []*SessionID
 			if err := clnt.conn.Call(utils.SessionSv1GetActiveSessionIDs,
 				utils.EmptyString, &queriedSessionIDs); err != nil {
 				errChan <- err
+				return
 			}
 			for _, sessionID := range queriedSessionIDs {
-				queriedCGRIDs.Set(sessionID.CGRID(), struct{}{})
+				queriedCGRIDs.Set(sessionID.CGRID(), connID)
 			}
 			errChan <- nil
-		}()
+		}(connID, clnt)
 		select {
 		case err = <-errChan:
 			if err != nil {
@@ -1236,13 +1365,23 @@ func (sS *SessionS) syncSessions() {
 
 	}
 	var toBeRemoved []string
+	var toBeRebound []*Session
 	sS.aSsMux.RLock()
-	for cgrid := range sS.aSessions {
+	for cgrid, s := range sS.aSessions {
 		if !queriedCGRIDs.HasField(cgrid) {
 			toBeRemoved = append(toBeRemoved, cgrid)
+		} else {
+			toBeRebound = append(toBeRebound, s)
 		}
 	}
 	sS.aSsMux.RUnlock()
+	for _, s := range toBeRebound { // sessions restored from backup point to connections not existing anymore
+		s.Lock()
+		if _, has := biJIDs[s.ClientConnID]; !has {
+			s.ClientConnID = queriedCGRIDs.GetStringIgnoreErrors(s.CGRID)
+		}
+		s.Unlock()
+	}
 	for _, cgrID := range toBeRemoved {
 		ss := sS.getSessions(cgrID, false)
 		if len(ss) == 0 {
@@ -2111,6 +2111,38 @@ func TestSessionSfilterSessions(t *testing.T) {
 	}
 }
 
+func TestSessionSfilterSessionsExpr(t *testing.T) {
+	sSCfg, _ := config.NewDefaultCGRConfig()
+	sS := NewSessionS(sSCfg, nil, nil)
+	sEv := engine.NewMapEvent(map[string]interface{}{
+		utils.ToR:      utils.VOICE,
+		utils.OriginID: "12345",
+		utils.Account:  "account1",
+		utils.Subject:  "subject1",
+	})
+	sr2 := sEv.Clone()
+	sr2[utils.Subject] = "subject2"
+	sS.registerSession(&Session{
+		CGRID:      GetSetCGRID(sEv),
+		EventStart: sEv,
+		SRuns: []*SRun{
+			&SRun{Event: sEv, CD: &engine.CallDescriptor{RunID: "RunID"}},
+			&SRun{Event: sr2, CD: &engine.CallDescriptor{RunID: "RunID2"}},
+		},
+	}, false)
+	fltrs := &utils.SessionFilter{Filters: []string{"*expr::*string:~*req.Subject:subject2||*string:~*req.Account:account2"}}
+	if rcv := sS.filterSessions(fltrs, false); len(rcv) != 1 || rcv[0].Subject != "subject2" {
+		t.Errorf("Expected the session of subject2, received: %s", utils.ToJSON(rcv))
+	}
+	if noSess := sS.filterSessionsCount(fltrs, false); noSess != 1 {
+		t.Errorf("Expected %v , received: %v", 1, noSess)
+	}
+	fltrs = &utils.SessionFilter{Filters: []string{"*expr::!*string:~*req.Subject:subject2&&*string:~*req.Account:account1"}}
+	if rcv := sS.filterSessions(fltrs, false); len(rcv) != 1 || rcv[0].Subject != "subject1" {
+		t.Errorf("Expected the session of subject1, received: %s", utils.ToJSON(rcv))
+	}
+}
+
 func TestSessionSfilterSessionsCount(t *testing.T) {
 	sSCfg, _ := config.NewDefaultCGRConfig()
 	sSCfg.SessionSCfg().SessionIndexes = utils.StringMap{
@@ -2208,3 +2240,83 @@ func TestSessionSfilterSessionsCount(t *testing.T) {
 		t.Errorf("Expected %v , received: %s", 2, utils.ToJSON(noSess))
 	}
 }
+
+func TestSessionSBackupRestoreSessions(t *testing.T) {
+	sSCfg, _ := config.NewDefaultCGRConfig()
+	sSCfg.SessionSCfg().BackupInterval = time.Hour
+	dm := engine.NewDataManager(engine.NewInternalDB(nil, nil, true, sSCfg.DataDbCfg().Items),
+		config.CgrConfig().CacheCfg(), nil)
+	sS := NewSessionS(sSCfg, dm, nil)
+	s := &Session{
+		CGRID:      "session1",
+		Tenant:     "cgrates.org",
+		EventStart: engine.NewMapEvent(map[string]interface{}{utils.OriginID: "ORIGIN_ID"}),
+		SRuns: []*SRun{
+			&SRun{
+				Event:      engine.NewMapEvent(map[string]interface{}{utils.RunID: utils.MetaDefault}),
+				CD:         &engine.CallDescriptor{RunID: utils.MetaDefault},
+				TotalUsage: 10 * time.Second,
+			},
+		},
+	}
+	sS.registerSession(s, false)
+	if err := sS.Shutdown(); err != nil {
+		t.Error(err)
+	}
+	if ss, err := dm.GetSessionsBackup(sS.backupID()); err != nil {
+		t.Error(err)
+	} else if len(ss) != 1 || ss[0].CGRID != "session1" || ss[0].UpdatedAt.IsZero() {
+		t.Errorf("unexpected backup: %s", utils.ToJSON(ss))
+	}
+
+	sS = NewSessionS(sSCfg, dm, nil)
+	if err := sS.restoreSessions(); err != nil {
+		t.Error(err)
+	}
+	if rcv := sS.getSessions("session1", false); len(rcv) != 1 {
+		t.Fatalf("expecting restored session, received: %s", utils.ToJSON(rcv))
+	} else if !reflect.DeepEqual(s.SRuns, rcv[0].SRuns) {
+		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(s.SRuns), utils.ToJSON(rcv[0].SRuns))
+	}
+
+	sS.unregisterSession("session1", false)
+	if err := sS.backupSessions(); err != nil {
+		t.Error(err)
+	}
+	if _, err := dm.GetSessionsBackup(sS.backupID()); err != utils.ErrNotFound {
+		t.Errorf("Expected %v, received: %v", utils.ErrNotFound, err)
+	}
+}
+
+func TestSessionSBackupRestoreDefaultNodeID(t *testing.T) {
+	sSCfg, _ := config.NewDefaultCGRConfig()
+	sSCfg.SessionSCfg().BackupInterval = time.Hour
+	dm := engine.NewDataManager(engine.NewInternalDB(nil, nil, true, sSCfg.DataDbCfg().Items),
+		config.CgrConfig().CacheCfg(), nil)
+	sS := NewSessionS(sSCfg, dm, nil)
+	sS.registerSession(&Session{
+		CGRID:      "session1",
+		Tenant:     "cgrates.org",
+		EventStart: engine.NewMapEvent(map[string]interface{}{utils.OriginID: "ORIGIN_ID"}),
+	}, false)
+	if err := sS.backupSessions(); err != nil {
+		t.Error(err)
+	}
+	// restart with the default config, generating a new node_id
+	rstCfg, _ := config.NewDefaultCGRConfig()
+	if rstCfg.GeneralCfg().NodeID == sSCfg.GeneralCfg().NodeID {
+		t.Fatalf("Expected a new node_id, received: %s", rstCfg.GeneralCfg().NodeID)
+	}
+	sS = NewSessionS(rstCfg, dm, nil)
+	if err := sS.restoreSessions(); err != nil {
+		t.Error(err)
+	}
+	if rcv := sS.getSessions("session1", false); len(rcv) != 1 {
+		t.Errorf("expecting restored session, received: %s", utils.ToJSON(rcv))
+	}
+	// a configured node_id is used as it is
+	rstCfg.GeneralCfg().NodeID = "node1"
+	if rcv := sS.backupID(); rcv != "node1" {
+		t.Errorf("Expected node1, received: %s", rcv)
+	}
+}
@@ -0,0 +1,257 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package utils
+
+import (
+	"errors"
+	"net/rpc"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/rpc2"
+	"github.com/cgrates/rpcclient"
+)
+
+// RPCAnalyzer is implemented by AnalyzerS in order to capture the RPC traffic
+// outgoing is true for the requests sent by the engine (ConnManager or BiRPC towards the clients)
+type RPCAnalyzer interface {
+	LogTraffic(method string, params, reply interface{}, err error,
+		enc, from, to string, outgoing bool, sTime, eTime time.Time)
+}
+
+// rpcAPI holds the request information until the reply is sent
+type rpcAPI struct {
+	Method    string
+	Params    interface{}
+	StartTime time.Time
+	Error     string // used for the replies read from the other side
+}
+
+// errFromString converts the error received in the RPC header
+func errFromString(errStr string) error {
+	if errStr == EmptyString {
+		return nil
+	}
+	return errors.New(errStr)
+}
+
+// NewAnalyzerServerCodec wraps the rpc.ServerCodec in order to send the traffic to the analyzer
+func NewAnalyzerServerCodec(sc rpc.ServerCodec, anz RPCAnalyzer, enc, from, to string) rpc.ServerCodec {
+	return &analyzerServerCodec{
+		sc:   sc,
+		anz:  anz,
+		enc:  enc,
+		from: from,
+		to:   to,
+		reqs: make(map[uint64]*rpcAPI),
+	}
+}
+
+type analyzerServerCodec struct {
+	sc   rpc.ServerCodec
+	anz  RPCAnalyzer
+	enc  string
+	from string
+	to   string
+
+	reqsLk sync.Mutex
+	reqs   map[uint64]*rpcAPI
+	reqIdx uint64 // sequence of the request which is currently read
+}
+
+func (c *analyzerServerCodec) ReadRequestHeader(r *rpc.Request) (err error) {
+	if err = c.sc.ReadRequestHeader(r); err != nil {
+		return
+	}
+	c.reqsLk.Lock()
+	c.reqIdx = r.Seq
+	c.reqs[c.reqIdx] = &rpcAPI{
+		Method:    r.ServiceMethod,
+		StartTime: time.Now(),
+	}
+	c.reqsLk.Unlock()
+	return
+}
+
+func (c *analyzerServerCodec) ReadRequestBody(x interface{}) (err error) {
+	err = c.sc.ReadRequestBody(x)
+	c.reqsLk.Lock()
+	if api, has := c.reqs[c.reqIdx]; has {
+		api.Params = x
+	}
+	c.reqsLk.Unlock()
+	return
+}
+
+func (c *analyzerServerCodec) WriteResponse(r *rpc.Response, x interface{}) (err error) {
+	c.reqsLk.Lock()
+	api, has := c.reqs[r.Seq]
+	delete(c.reqs, r.Seq)
+	c.reqsLk.Unlock()
+	err = c.sc.WriteResponse(r, x)
+	if has {
+		c.anz.LogTraffic(api.Method, api.Params, x, errFromString(r.Error),
+			c.enc, c.from, c.to, false, api.StartTime, time.Now())
+	}
+	return
+}
+
+func (c *analyzerServerCodec) Close() error {
+	return c.sc.Close()
+}
+
+// NewAnalyzerBiRPCCodec wraps the rpc2.Codec in order to send the traffic to the analyzer
+// both the requests received and the ones sent to the client are captured
+func NewAnalyzerBiRPCCodec(sc rpc2.Codec, anz RPCAnalyzer, enc, from, to string) rpc2.Codec {
+	return &analyzerBiRPCCodec{
+		sc:   sc,
+		anz:  anz,
+		enc:  enc,
+		from: from,
+		to:   to,
+		reqs: make(map[uint64]*rpcAPI),
+		reps: make(map[uint64]*rpcAPI),
+	}
+}
+
+type analyzerBiRPCCodec struct {
+	sc   rpc2.Codec
+	anz  RPCAnalyzer
+	enc  string
+	from string
+	to   string
+
+	reqsLk sync.Mutex
+	reqs   map[uint64]*rpcAPI // requests received from the client
+	reqIdx uint64
+
+	repsLk sync.Mutex
+	reps   map[uint64]*rpcAPI // requests sent to the client
+	repIdx uint64
+}
+
+// ReadHeader must read a message and populate either the request
+// or the response by inspecting the incoming message.
+func (c *analyzerBiRPCCodec) ReadHeader(req *rpc2.Request, resp *rpc2.Response) (err error) {
+	if err = c.sc.ReadHeader(req, resp); err != nil {
+		return
+	}
+	if req.Method != EmptyString {
+		c.reqsLk.Lock()
+		c.reqIdx = req.Seq
+		c.reqs[c.reqIdx] = &rpcAPI{
+			Method:    req.Method,
+			StartTime: time.Now(),
+		}
+		c.reqsLk.Unlock()
+		return
+	}
+	c.repsLk.Lock()
+	c.repIdx = resp.Seq
+	if api, has := c.reps[c.repIdx]; has {
+		api.Error = resp.Error
+	}
+	c.repsLk.Unlock()
+	return
+}
+
+// ReadRequestBody into args argument of handler function.
+func (c *analyzerBiRPCCodec) ReadRequestBody(x interface{}) (err error) {
+	err = c.sc.ReadRequestBody(x)
+	c.reqsLk.Lock()
+	if api, has := c.reqs[c.reqIdx]; has {
+		api.Params = x
+	}
+	c.reqsLk.Unlock()
+	return
+}
+
+// ReadResponseBody into reply argument of handler function.
+func (c *analyzerBiRPCCodec) ReadResponseBody(x interface{}) (err error) {
+	err = c.sc.ReadResponseBody(x)
+	c.repsLk.Lock()
+	api, has := c.reps[c.repIdx]
+	delete(c.reps, c.repIdx)
+	c.repsLk.Unlock()
+	if has {
+		c.anz.LogTraffic(api.Method, api.Params, x, errFromString(api.Error),
+			c.enc, c.to, c.from, true, api.StartTime, time.Now())
+	}
+	return
+}
+
+// WriteRequest must be safe for concurrent use by multiple goroutines.
+func (c *analyzerBiRPCCodec) WriteRequest(req *rpc2.Request, x interface{}) error {
+	c.repsLk.Lock()
+	c.reps[req.Seq] = &rpcAPI{
+		Method:    req.Method,
+		Params:    x,
+		StartTime: time.Now(),
+	}
+	c.repsLk.Unlock()
+	return c.sc.WriteRequest(req, x)
+}
+
+// WriteResponse must be safe for concurrent use by multiple goroutines.
+func (c *analyzerBiRPCCodec) WriteResponse(r *rpc2.Response, x interface{}) (err error) {
+	c.reqsLk.Lock()
+	api, has := c.reqs[r.Seq]
+	delete(c.reqs, r.Seq)
+	c.reqsLk.Unlock()
+	err = c.sc.WriteResponse(r, x)
+	if has {
+		c.anz.LogTraffic(api.Method, api.Params, x, errFromString(r.Error),
+			c.enc, c.from, c.to, false, api.StartTime, time.Now())
+	}
+	return
+}
+
+// Close is called when client/server finished with the connection.
+func (c *analyzerBiRPCCodec) Close() error {
+	return c.sc.Close()
+}
+
+// NewAnalyzerConnector wraps the connection in order to send the traffic to the analyzer
+func NewAnalyzerConnector(conn rpcclient.ClientConnector, anz RPCAnalyzer,
+	enc, from, to string) rpcclient.ClientConnector {
+	return &analyzerConnector{
+		conn: conn,
+		anz:  anz,
+		enc:  enc,
+		from: from,
+		to:   to,
+	}
+}
+
+type analyzerConnector struct {
+	conn rpcclient.ClientConnector
+	anz  RPCAnalyzer
+	enc  string
+	from string
+	to   string
+}
+
+// Call implements rpcclient.ClientConnector interface
+func (c *analyzerConnector) Call(serviceMethod string, args, reply interface{}) (err error) {
+	sTime := time.Now()
+	err = c.conn.Call(serviceMethod, args, reply)
+	c.anz.LogTraffic(serviceMethod, args, reply, err,
+		c.enc, c.from, c.to, true, sTime, time.Now())
+	return
+}
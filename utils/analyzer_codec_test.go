@@ -0,0 +1,154 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package utils
+
+import (
+	"errors"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testAnzCall struct {
+	method   string
+	params   interface{}
+	reply    interface{}
+	err      error
+	enc      string
+	from     string
+	to       string
+	outgoing bool
+}
+
+type testAnalyzer struct {
+	calls chan *testAnzCall
+}
+
+func (tA *testAnalyzer) LogTraffic(method string, params, reply interface{}, err error,
+	enc, from, to string, outgoing bool, sTime, eTime time.Time) {
+	tA.calls <- &testAnzCall{method: method, params: params, reply: reply,
+		err: err, enc: enc, from: from, to: to, outgoing: outgoing}
+}
+
+type TestAnzService struct{}
+
+func (*TestAnzService) Echo(args *string, reply *string) error {
+	if *args == "err" {
+		return errors.New("ECHO_ERROR")
+	}
+	*reply = *args
+	return nil
+}
+
+type testAnzConn struct{}
+
+func (*testAnzConn) Call(serviceMethod string, args, reply interface{}) error {
+	*reply.(*string) = Pong
+	return nil
+}
+
+func TestAnalyzerConnector(t *testing.T) {
+	anz := &testAnalyzer{calls: make(chan *testAnzCall, 1)}
+	conn := NewAnalyzerConnector(new(testAnzConn), anz, MetaInternal, "node1", "*internal")
+	var reply string
+	if err := conn.Call(CoreSv1Ping, "args", &reply); err != nil {
+		t.Fatal(err)
+	} else if reply != Pong {
+		t.Errorf("Expected: %q, received: %q", Pong, reply)
+	}
+	exp := &testAnzCall{method: CoreSv1Ping, params: "args", reply: &reply,
+		enc: MetaInternal, from: "node1", to: "*internal", outgoing: true}
+	if rcv := <-anz.calls; !reflect.DeepEqual(exp, rcv) {
+		t.Errorf("Expected: %+v, received: %+v", exp, rcv)
+	}
+}
+
+func TestAnalyzerServerCodec(t *testing.T) {
+	anz := &testAnalyzer{calls: make(chan *testAnzCall, 1)}
+	srv := rpc.NewServer()
+	if err := srv.Register(new(TestAnzService)); err != nil {
+		t.Fatal(err)
+	}
+	sConn, cConn := net.Pipe()
+	go srv.ServeCodec(NewAnalyzerServerCodec(jsonrpc.NewServerCodec(sConn), anz,
+		MetaJSON, "127.0.0.1:1", "127.0.0.1:2"))
+	clnt := jsonrpc.NewClient(cConn)
+	defer clnt.Close()
+
+	var reply string
+	if err := clnt.Call("TestAnzService.Echo", "test", &reply); err != nil {
+		t.Fatal(err)
+	}
+	rcv := <-anz.calls
+	if rcv.method != "TestAnzService.Echo" ||
+		rcv.enc != MetaJSON ||
+		rcv.from != "127.0.0.1:1" ||
+		rcv.to != "127.0.0.1:2" ||
+		rcv.outgoing ||
+		rcv.err != nil {
+		t.Errorf("Unexpected call: %+v", rcv)
+	}
+	if prm, canCast := rcv.params.(*string); !canCast || *prm != "test" {
+		t.Errorf("Unexpected params: %+v", rcv.params)
+	}
+	if rply, canCast := rcv.reply.(*string); !canCast || *rply != "test" {
+		t.Errorf("Unexpected reply: %+v", rcv.reply)
+	}
+
+	if err := clnt.Call("TestAnzService.Echo", "err", &reply); err == nil || err.Error() != "ECHO_ERROR" {
+		t.Errorf("Expected error ECHO_ERROR, received: %v", err)
+	}
+	if rcv = <-anz.calls; rcv.err == nil || rcv.err.Error() != "ECHO_ERROR" {
+		t.Errorf("Unexpected call: %+v", rcv)
+	}
+}
+
+func TestServerCodecAnalyzerPerRequest(t *testing.T) {
+	registerRPCMethods(EmptyString, new(TestAnzService)) // not counted as unknown methods
+	s := NewServer()
+	srv := rpc.NewServer()
+	if err := srv.Register(new(TestAnzService)); err != nil {
+		t.Fatal(err)
+	}
+	sConn, cConn := net.Pipe()
+	go srv.ServeCodec(s.newServerCodec(jsonrpc.NewServerCodec(sConn),
+		MetaJSON, "127.0.0.1:1", "127.0.0.1:2"))
+	clnt := jsonrpc.NewClient(cConn)
+	defer clnt.Close()
+	var reply string
+	if err := clnt.Call("TestAnzService.Echo", "before", &reply); err != nil {
+		t.Fatal(err)
+	}
+	// enabled after the connection was opened
+	anz := &testAnalyzer{calls: make(chan *testAnzCall, 2)}
+	s.SetAnalyzer(anz)
+	if err := clnt.Call("TestAnzService.Echo", "after", &reply); err != nil {
+		t.Fatal(err)
+	}
+	rcv := <-anz.calls
+	if prm, canCast := rcv.params.(*string); canCast && *prm == "before" { // logged after its reply was sent
+		rcv = <-anz.calls
+	}
+	if prm, canCast := rcv.params.(*string); !canCast || *prm != "after" {
+		t.Errorf("Unexpected call: %+v", rcv)
+	}
+}
@@ -1384,6 +1384,8 @@ type SessionFilter struct {
 type ArgDispatcher struct {
 	APIKey  *string
 	RouteID *string
+	TraceID *string // distributed tracing context, propagated by the ConnManager
+	SpanID  *string
 }
 
 type RatingPlanCostArg struct {
@@ -1429,3 +1431,33 @@ type DPRArgs struct {
 	OriginRealm     string
 	DisconnectCause int
 }
+
+// ERsCheckpoint is the reading progress of one reader, used to resume after restarts
+type ERsCheckpoint struct {
+	ReaderID  string
+	Files     map[string]int64 `json:",omitempty"` // records already processed out of the files being read, indexed on name, size and modification time
+	LastKey   string           `json:",omitempty"` // key of the last row processed by the *sql readers
+	UpdatedAt time.Time
+}
+
+// Clone returns a copy of the checkpoint
+func (cp *ERsCheckpoint) Clone() (cln *ERsCheckpoint) {
+	cln = &ERsCheckpoint{
+		ReaderID:  cp.ReaderID,
+		LastKey:   cp.LastKey,
+		UpdatedAt: cp.UpdatedAt,
+	}
+	if cp.Files != nil {
+		cln.Files = make(map[string]int64, len(cp.Files))
+		for fName, offset := range cp.Files {
+			cln.Files[fName] = offset
+		}
+	}
+	return
+}
+
+// ArgsERsCheckpoint selects the checkpoint of a reader, optionally limited to one file
+type ArgsERsCheckpoint struct {
+	ReaderID string
+	FileName string
+}
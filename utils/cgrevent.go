@@ -132,27 +132,28 @@ func (ev *CGREvent) consumeArgDispatcher() (arg *ArgDispatcher) {
 	if ev == nil {
 		return
 	}
-	//check if we have APIKey in event and in case it has add it in ArgDispatcher
-	apiKeyIface, hasApiKey := ev.Event[MetaApiKey]
-	if hasApiKey {
-		delete(ev.Event, MetaApiKey)
-		arg = &ArgDispatcher{
-			APIKey: StringPointer(apiKeyIface.(string)),
-		}
-	}
-	//check if we have RouteID in event and in case it has add it in ArgDispatcher
-	routeIDIface, hasRouteID := ev.Event[MetaRouteID]
-	if !hasRouteID {
+	//check if we have APIKey, RouteID or the tracing context in event and in case it has add them in ArgDispatcher
+	argD := &ArgDispatcher{
+		APIKey:  ev.consumeStringField(MetaApiKey),
+		RouteID: ev.consumeStringField(MetaRouteID),
+		TraceID: ev.consumeStringField(MetaTraceID),
+		SpanID:  ev.consumeStringField(MetaSpanID),
+	}
+	if argD.APIKey == nil && argD.RouteID == nil &&
+		argD.TraceID == nil && argD.SpanID == nil {
 		return
 	}
-	delete(ev.Event, MetaRouteID)
-	if !hasApiKey { //in case we don't have APIKey, but we have RouteID we need to initialize the struct
-		return &ArgDispatcher{
-			RouteID: StringPointer(routeIDIface.(string)),
-		}
+	return argD
+}
+
+// consumeStringField removes the field from event and returns its value as string
+func (ev *CGREvent) consumeStringField(fldName string) (val *string) {
+	fldIface, has := ev.Event[fldName]
+	if !has {
+		return
 	}
-	arg.RouteID = StringPointer(routeIDIface.(string))
-	return
+	delete(ev.Event, fldName)
+	return StringPointer(IfaceAsString(fldIface))
 }
 
 // ConsumeSupplierPaginator will consume supplierPaginator if presented
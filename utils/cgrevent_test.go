@@ -331,6 +331,27 @@ func TestCGREventconsumeArgDispatcher(t *testing.T) {
 
 }
 
+func TestCGREventconsumeArgDispatcherTracing(t *testing.T) {
+	cgrEvent := &CGREvent{
+		Event: map[string]interface{}{
+			Account:     "1001",
+			MetaTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+			MetaSpanID:  "00f067aa0ba902b7",
+		},
+	}
+	eOut := &ArgDispatcher{
+		TraceID: StringPointer("4bf92f3577b34da6a3ce929d0e0e4736"),
+		SpanID:  StringPointer("00f067aa0ba902b7"),
+	}
+	if rcv := cgrEvent.consumeArgDispatcher(); !reflect.DeepEqual(eOut, rcv) {
+		t.Errorf("Expecting:  %s, received: %s", ToJSON(eOut), ToJSON(rcv))
+	}
+	eEv := map[string]interface{}{Account: "1001"}
+	if !reflect.DeepEqual(eEv, cgrEvent.Event) {
+		t.Errorf("Expecting:  %s, received: %s", ToJSON(eEv), ToJSON(cgrEvent.Event))
+	}
+}
+
 func TestCGREventconsumeSupplierPaginator(t *testing.T) {
 	//empty check
 	cgrEvent := new(CGREvent)
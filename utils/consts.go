@@ -21,7 +21,7 @@ package utils
 var (
 	CDRExportFormats = NewStringSet([]string{DRYRUN, MetaFileCSV, MetaFileFWV, MetaHTTPjsonCDR, MetaHTTPjsonMap,
 		MetaHTTPjson, MetaHTTPPost, MetaAMQPjsonCDR, MetaAMQPjsonMap, MetaAMQPV1jsonMap, MetaSQSjsonMap,
-		MetaKafkajsonMap, MetaS3jsonMap})
+		MetaKafkajsonMap, MetaS3jsonMap, MetaNatsjsonMap})
 	MainCDRFields = NewStringSet([]string{CGRID, Source, OriginHost, OriginID, ToR, RequestType, Tenant, Category,
 		Account, Subject, Destination, SetupTime, AnswerTime, Usage, COST, RATED, Partial, RunID,
 		PreRated, CostSource, CostDetails, ExtraInfo, OrderID})
@@ -44,6 +44,7 @@ var (
 		MetaSQSjsonMap:    CONTENT_JSON,
 		MetaKafkajsonMap:  CONTENT_JSON,
 		MetaS3jsonMap:     CONTENT_JSON,
+		MetaNatsjsonMap:   CONTENT_JSON,
 	}
 	CDREFileSuffixes = map[string]string{
 		MetaHTTPjsonCDR:   JSNSuffix,
@@ -54,6 +55,7 @@ var (
 		MetaSQSjsonMap:    JSNSuffix,
 		MetaKafkajsonMap:  JSNSuffix,
 		MetaS3jsonMap:     JSNSuffix,
+		MetaNatsjsonMap:   JSNSuffix,
 		MetaHTTPPost:      FormSuffix,
 		MetaFileCSV:       CSVSuffix,
 		MetaFileFWV:       FWVSuffix,
@@ -68,7 +70,7 @@ var (
 		CacheDispatcherProfiles, CacheDispatcherHosts, CacheResourceFilterIndexes,
 		CacheStatFilterIndexes, CacheThresholdFilterIndexes, CacheSupplierFilterIndexes,
 		CacheAttributeFilterIndexes, CacheChargerFilterIndexes, CacheDispatcherFilterIndexes,
-		CacheDispatcherRoutes, CacheDiameterMessages, CacheRPCResponses, CacheClosedSessions,
+		CacheDispatcherRoutes, CacheDiameterMessages, CacheRadiusPackets, CacheRPCResponses, CacheClosedSessions,
 		CacheCDRIDs, CacheLoadIDs, CacheRPCConnections, CacheRatingProfilesTmp, CacheUCH})
 	CacheInstanceToPrefix = map[string]string{
 		CacheDestinations:            DESTINATION_PREFIX,
@@ -227,6 +229,10 @@ const (
 	ZERO_RATING_SUBJECT_PREFIX   = "*zero"
 	OK                           = "OK"
 	MetaFileXML                  = "*file_xml"
+	MetaFileASN1                 = "*file_asn1"
+	MetaFileJSON                 = "*file_json"
+	MetaText                     = "*text"
+	MetaOTLPHTTP                 = "*otlp_http"
 	CDRE                         = "cdre"
 	MASK_CHAR                    = "*"
 	CONCATENATED_KEY_SEP         = ":"
@@ -274,6 +280,8 @@ const (
 	ThresholdProfilePrefix       = "thp_"
 	StatQueuePrefix              = "stq_"
 	LoadIDPrefix                 = "lid_"
+	SessionsBackupPrefix         = "sbk_"
+	DedupPrefix                  = "ddp_"
 	LOADINST_KEY                 = "load_history"
 	CREATE_CDRS_TABLES_SQL       = "create_cdrs_tables.sql"
 	CREATE_TARIFFPLAN_TABLES_SQL = "create_tariffplan_tables.sql"
@@ -283,6 +291,7 @@ const (
 	META_HANDLER                 = "*handler"
 	MetaHTTPPost                 = "*http_post"
 	MetaHTTPjson                 = "*http_json"
+	MetaHTTPform                 = "*http_form"
 	MetaHTTPjsonCDR              = "*http_json_cdr"
 	MetaHTTPjsonMap              = "*http_json_map"
 	MetaAMQPjsonCDR              = "*amqp_json_cdr"
@@ -293,6 +302,7 @@ const (
 	MetaSQL                      = "*sql"
 	MetaMySQL                    = "*mysql"
 	MetaS3jsonMap                = "*s3_json_map"
+	MetaNatsjsonMap              = "*nats_json_map"
 	CONFIG_PATH                  = "/etc/cgrates/"
 	DISCONNECT_CAUSE             = "DisconnectCause"
 	MetaFlatstore                = "*flatstore"
@@ -343,6 +353,8 @@ const (
 	XML                         = "xml"
 	MetaGOB                     = "*gob"
 	MetaJSON                    = "*json"
+	MetaBiJSON                  = "*birpc_json"
+	MetaWSjson                  = "*ws_json"
 	MetaMSGPACK                 = "*msgpack"
 	MetaDateTime                = "*datetime"
 	MetaMaskedDestination       = "*masked_destination"
@@ -395,6 +407,9 @@ const (
 	XMLSuffix                   = ".xml"
 	CSVSuffix                   = ".csv"
 	FWVSuffix                   = ".fwv"
+	GzipSuffix                  = ".gz"
+	Bzip2Suffix                 = ".bz2"
+	ZipSuffix                   = ".zip"
 	CONTENT_JSON                = "json"
 	CONTENT_FORM                = "form"
 	CONTENT_TEXT                = "text"
@@ -614,6 +629,7 @@ const (
 	AttrValueSep              = "="
 	ANDSep                    = "&"
 	PipeSep                   = "|"
+	HyphenSep                 = "-"
 	MetaApp                   = "*app"
 	MetaAppID                 = "*appid"
 	MetaCmd                   = "*cmd"
@@ -630,6 +646,7 @@ const (
 	RemoteHost                = "RemoteHost"
 	Local                     = "local"
 	TCP                       = "tcp"
+	UDP                       = "udp"
 	CGRDebitInterval          = "CGRDebitInterval"
 	Version                   = "Version"
 	MetaTenant                = "*tenant"
@@ -637,6 +654,23 @@ const (
 	MetaDuration              = "*duration"
 	MetaLibPhoneNumber        = "*libphonenumber"
 	MetaIP2Hex                = "*ip2hex"
+	MetaUpper                 = "*upper"
+	MetaLower                 = "*lower"
+	MetaTrim                  = "*trim"
+	MetaSubstr                = "*substr"
+	MetaPad                   = "*pad"
+	MetaSHA256                = "*sha256"
+	MetaMD5                   = "*md5"
+	MetaBase64Encode          = "*base64_encode"
+	MetaBase64Decode          = "*base64_decode"
+	MetaString2Hex            = "*string2hex"
+	MetaHex2String            = "*hex2string"
+	MetaURLEncode             = "*url_encode"
+	MetaTimeString            = "*time_string"
+	MetaUnixTime              = "*unixtime"
+	MetaUnixTimeMs            = "*unixtime_ms"
+	MetaSIPURI                = "*sipuri"
+	MetaSIP                   = "*sip"
 	MetaReload                = "*reload"
 	MetaLoad                  = "*load"
 	MetaRemove                = "*remove"
@@ -649,6 +683,8 @@ const (
 	TLSNoCaps                 = "tls"
 	MetaRouteID               = "*route_id"
 	MetaApiKey                = "*api_key"
+	MetaTraceID               = "*trace_id"
+	MetaSpanID                = "*span_id"
 	UsageID                   = "UsageID"
 	Rcode                     = "Rcode"
 	Replacement               = "Replacement"
@@ -704,10 +740,14 @@ const (
 	MetaLoaders               = "*loaders"
 	TmpSuffix                 = ".tmp"
 	MetaDiamreq               = "*diamreq"
+	MetaRadDAReq              = "*radDAReq"
+	MetaDMR                   = "*dmr"
+	MetaCoA                   = "*coa"
 	MetaCost                  = "*cost"
 	MetaGroup                 = "*group"
 	InternalRPCSet            = "InternalRPCSet"
 	FileName                  = "FileName"
+	ASN1Record                = "ASN1Record"
 	MetaRadauth               = "*radauth"
 	UserPassword              = "UserPassword"
 	RadauthFailed             = "RADAUTH_FAILED"
@@ -914,7 +954,7 @@ const (
 	ArgDispatcherField = "ArgDispatcher"
 )
 
-//Filter types
+// Filter types
 const (
 	MetaNot            = "*not"
 	MetaString         = "*string"
@@ -932,6 +972,9 @@ const (
 	MetaGreaterOrEqual = "*gte"
 	MetaResources      = "*resources"
 	MetaEqual          = "*eq"
+	MetaRegex          = "*regex"
+	MetaIPNet          = "*ipnet"
+	MetaExpr           = "*expr"
 
 	MetaNotString       = "*notstring"
 	MetaNotPrefix       = "*notprefix"
@@ -944,6 +987,8 @@ const (
 	MetaNotDestinations = "*notdestinations"
 	MetaNotResources    = "*notresources"
 	MetaNotEqual        = "*noteq"
+	MetaNotRegex        = "*notregex"
+	MetaNotIPNet        = "*notipnet"
 
 	MetaEC = "*ec"
 )
@@ -1055,6 +1100,7 @@ const (
 	APIerSv1RemoveFilter                = "APIerSv1.RemoveFilter"
 	APIerSv1SetFilter                   = "APIerSv1.SetFilter"
 	APIerSv1GetFilterIDs                = "APIerSv1.GetFilterIDs"
+	APIerSv1ExplainFilters              = "APIerSv1.ExplainFilters"
 	APIerSv1GetRatingProfile            = "APIerSv1.GetRatingProfile"
 	APIerSv1RemoveRatingProfile         = "APIerSv1.RemoveRatingProfile"
 	APIerSv1SetRatingProfile            = "APIerSv1.SetRatingProfile"
@@ -1256,10 +1302,12 @@ const (
 )
 
 const (
-	CoreS         = "CoreS"
-	CoreSv1       = "CoreSv1"
-	CoreSv1Status = "CoreSv1.Status"
-	CoreSv1Ping   = "CoreSv1.Ping"
+	CoreS              = "CoreS"
+	CoreSv1            = "CoreSv1"
+	CoreSv1Status      = "CoreSv1.Status"
+	CoreSv1Ping        = "CoreSv1.Ping"
+	CoreSv1Metrics     = "CoreSv1.Metrics"
+	CoreSv1SetLogLevel = "CoreSv1.SetLogLevel"
 )
 
 // SupplierS APIs
@@ -1392,10 +1440,90 @@ const (
 	DispatcherServicePing           = "DispatcherService.Ping"
 )
 
+// ErS APIs
+const (
+	ErSv1                = "ErSv1"
+	ErSv1Ping            = "ErSv1.Ping"
+	ErSv1GetCheckpoint   = "ErSv1.GetCheckpoint"
+	ErSv1ResetCheckpoint = "ErSv1.ResetCheckpoint"
+	ErSv1GetDedupStats   = "ErSv1.GetDedupStats"
+)
+
 // AnalyzerS APIs
 const (
-	AnalyzerSv1     = "AnalyzerSv1"
-	AnalyzerSv1Ping = "AnalyzerSv1.Ping"
+	AnalyzerSv1            = "AnalyzerSv1"
+	AnalyzerSv1Ping        = "AnalyzerSv1.Ping"
+	AnalyzerSv1StringQuery = "AnalyzerSv1.StringQuery"
+)
+
+// AnalyzerS fields
+const (
+	RequestEncoding    = "RequestEncoding"
+	RequestSource      = "RequestSource"
+	RequestDestination = "RequestDestination"
+	RequestMethod      = "RequestMethod"
+	ReplyError         = "ReplyError"
+)
+
+// Runtime metrics
+const (
+	MetricRPCRequests      = "cgrates_rpc_requests_total"
+	MetricRPCErrors        = "cgrates_rpc_errors_total"
+	MetricRPCDuration      = "cgrates_rpc_request_duration_seconds"
+	MetricConnCalls        = "cgrates_conn_calls_total"
+	MetricConnFailures     = "cgrates_conn_failures_total"
+	MetricConnPools        = "cgrates_conn_pools"
+	MetricCacheHits        = "cgrates_cache_hits_total"
+	MetricCacheMisses      = "cgrates_cache_misses_total"
+	MetricCacheEvictions   = "cgrates_cache_evictions_total"
+	MetricCacheItems       = "cgrates_cache_items"
+	MetricGuardianLockWait = "cgrates_guardian_lock_wait_seconds"
+	MetricSessions         = "cgrates_sessions"
+	MetricSessionsDebitLag = "cgrates_sessions_debit_loop_lag_seconds"
+	MetricERsRead          = "cgrates_ers_events_read_total"
+	MetricERsProcessed     = "cgrates_ers_events_processed_total"
+	MetricERsFailed        = "cgrates_ers_events_failed_total"
+	MetricSpansDropped     = "cgrates_tracing_spans_dropped_total"
+
+	MetricLabelMethod    = "method"
+	MetricLabelEncoding  = "encoding"
+	MetricLabelConnID    = "conn_id"
+	MetricLabelReason    = "reason"
+	MetricLabelStrategy  = "strategy"
+	MetricLabelPartition = "partition"
+	MetricLabelState     = "state"
+	MetricLabelReaderID  = "reader_id"
+
+	MetricReasonConnect = "connect"
+	MetricReasonNetwork = "network"
+	MetricStateActive   = "active"
+	MetricStatePassive  = "passive"
+	MetricMethodUnknown = "unknown"
+)
+
+// Structured logs
+const (
+	LogFieldTimestamp = "timestamp"
+	LogFieldLevel     = "level"
+	LogFieldNodeID    = "node_id"
+	LogFieldSubsystem = "subsystem"
+	LogFieldMessage   = "message"
+	LogFieldTenant    = "tenant"
+	LogFieldCGRID     = "cgrid"
+	LogFieldOriginID  = "origin_id"
+)
+
+// Tracing
+const (
+	Tracing        = "Tracing"
+	SpanAttrConnID = "cgrates.conn_id"
+	SpanAttrHostID = "cgrates.dispatcher_host_id"
+)
+
+// Dedup
+const (
+	Dedup     = "Dedup"
+	MetaDedup = "*dedup"
 )
 
 // LoaderS APIs
@@ -1443,6 +1571,7 @@ const (
 	CDRsV1ProcessExternalCDR = "CDRsV1.ProcessExternalCDR"
 	CDRsV1StoreSessionCost   = "CDRsV1.StoreSessionCost"
 	CDRsV1ProcessEvent       = "CDRsV1.ProcessEvent"
+	CDRsV1GetDedupStats      = "CDRsV1.GetDedupStats"
 	CDRsV1Ping               = "CDRsV1.Ping"
 	CDRsV2                   = "CDRsV2"
 	CDRsV2StoreSessionCost   = "CDRsV2.StoreSessionCost"
@@ -1456,7 +1585,7 @@ const (
 	SchedulerSv1Reload = "SchedulerSv1.Reload"
 )
 
-//cgr_ variables
+// cgr_ variables
 const (
 	CGR_ACCOUNT          = "cgr_account"
 	CGR_SUPPLIER         = "cgr_supplier"
@@ -1472,7 +1601,7 @@ const (
 	CGRFlags             = "cgr_flags"
 )
 
-//CSV file name
+// CSV file name
 const (
 	TimingsCsv            = "Timings.csv"
 	DestinationsCsv       = "Destinations.csv"
@@ -1559,6 +1688,7 @@ const (
 	CacheChargerFilterIndexes    = "*charger_filter_indexes"
 	CacheDispatcherFilterIndexes = "*dispatcher_filter_indexes"
 	CacheDiameterMessages        = "*diameter_messages"
+	CacheRadiusPackets           = "*radius_packets"
 	CacheRPCResponses            = "*rpc_responses"
 	CacheClosedSessions          = "*closed_sessions"
 	MetaPrecaching               = "*precaching"
@@ -1569,6 +1699,8 @@ const (
 	CacheCDRIDs                  = "*cdr_ids"
 	CacheRatingProfilesTmp       = "*tmp_rating_profiles"
 	CacheUCH                     = "*uch"
+	CacheSessionsBackup          = "*sessions_backup"
+	CacheDedupKeys               = "*dedup_keys"
 )
 
 // Prefix for indexing
@@ -1591,6 +1723,7 @@ const (
 	FreeSWITCHAgent = "FreeSWITCHAgent"
 	AsteriskAgent   = "AsteriskAgent"
 	HTTPAgent       = "HTTPAgent"
+	PrometheusAgent = "PrometheusAgent"
 )
 
 // Poster
@@ -1603,6 +1736,42 @@ const (
 	KafkaTopic   = "topic"
 	KafkaGroupID = "group_id"
 	KafkaMaxWait = "max_wait"
+
+	AMQPQueueID         = "queue_id"
+	AMQPExchange        = "exchange"
+	AMQPExchangeType    = "exchange_type"
+	AMQPRoutingKey      = "routing_key"
+	AMQPPrefetchCount   = "prefetch_count"
+	AMQPDeadLetterQueue = "dead_letter_queue"
+
+	AWSToken              = "aws_token"
+	AWSQueueID            = "queue_id"
+	AWSFolderPath         = "folder_path"
+	S3ProcessedFolderPath = "processed_folder_path"
+	S3FailedFolderPath    = "failed_folder_path"
+	SQSWaitTime           = "wait_time"
+	SQSVisibilityTimeout  = "visibility_timeout"
+	SQSMaxMessages        = "max_messages"
+
+	NATSPoster       = "NATSPoster"
+	NATSSubject      = "subject"
+	NATSStream       = "stream"
+	NATSConsumerName = "consumer_name"
+	NATSAckWait      = "ack_wait"
+	NATSMaxDeliver   = "max_deliver"
+
+	SFTPScheme       = "sftp"
+	FTPScheme        = "ftp"
+	FTPSScheme       = "ftps"
+	SSHKeyPath       = "key_path"
+	SSHKnownHosts    = "known_hosts"
+	RemoteFolderPath = "processed_folder_path"
+
+	HTTPAuthUser     = "auth_user"
+	HTTPAuthPassword = "auth_password"
+	HTTPHMACSecret   = "hmac_secret"
+	HTTPHMACHeader   = "hmac_header"
+	HTTPMaxBodySize  = "max_body_size"
 )
 
 // Google_API
@@ -1635,6 +1804,25 @@ var (
 	MetaZeroLeft = "*zeroleft"
 )
 
+// SIP URI parts
+const (
+	SIPURIUser    = "user"
+	SIPURIHost    = "host"
+	SIPURIPort    = "port"
+	SIPURIDisplay = "display"
+	SIPURIScheme  = "scheme"
+	SIPURIURI     = "uri"
+	SIPURIParam   = "param"
+)
+
+// SIP message start line fields
+const (
+	SIPMethod       = "Method"
+	SIPRequestURI   = "Request-URI"
+	SIPStatusCode   = "Status-Code"
+	SIPReasonPhrase = "Reason-Phrase"
+)
+
 func buildCacheInstRevPrefixes() {
 	CachePrefixToInstance = make(map[string]string)
 	for k, v := range CacheInstanceToPrefix {
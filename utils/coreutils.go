@@ -951,3 +951,10 @@ func IsURL(path string) bool {
 	return strings.HasPrefix(path, "https://") ||
 		strings.HasPrefix(path, "http://")
 }
+
+// IsRemoteFilePath returns if the path points towards a SFTP or FTP(S) folder
+func IsRemoteFilePath(path string) bool {
+	return strings.HasPrefix(path, SFTPScheme+"://") ||
+		strings.HasPrefix(path, FTPScheme+"://") ||
+		strings.HasPrefix(path, FTPSScheme+"://")
+}
@@ -19,9 +19,13 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package utils
 
 import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	"fmt"
 	"net"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -49,6 +53,12 @@ type DataConverter interface {
 	Convert(interface{}) (interface{}, error)
 }
 
+// hasConverterName checks if params select the named converter, with or without its own params
+func hasConverterName(params, convName string) bool {
+	return params == convName ||
+		strings.HasPrefix(params, convName+InInFieldSep)
+}
+
 // NewDataConverter is a factory of converters
 func NewDataConverter(params string) (conv DataConverter, err error) {
 	switch {
@@ -80,6 +90,53 @@ func NewDataConverter(params string) (conv DataConverter, err error) {
 			return NewPhoneNumberConverter("")
 		}
 		return NewPhoneNumberConverter(params[len(MetaLibPhoneNumber)+1:])
+	case params == MetaUpper:
+		return new(UpperConverter), nil
+	case params == MetaLower:
+		return new(LowerConverter), nil
+	case hasConverterName(params, MetaTrim):
+		if len(params) == len(MetaTrim) {
+			return NewTrimConverter("")
+		}
+		return NewTrimConverter(params[len(MetaTrim)+1:])
+	case hasConverterName(params, MetaSubstr):
+		if len(params) == len(MetaSubstr) {
+			return NewSubstrConverter("")
+		}
+		return NewSubstrConverter(params[len(MetaSubstr)+1:])
+	case hasConverterName(params, MetaPad):
+		if len(params) == len(MetaPad) {
+			return NewPadConverter("")
+		}
+		return NewPadConverter(params[len(MetaPad)+1:])
+	case params == MetaSHA256:
+		return new(SHA256Converter), nil
+	case params == MetaMD5:
+		return new(MD5Converter), nil
+	case params == MetaBase64Encode:
+		return new(Base64EncodeConverter), nil
+	case params == MetaBase64Decode:
+		return new(Base64DecodeConverter), nil
+	case params == MetaString2Hex:
+		return new(String2HexConverter), nil
+	case params == MetaHex2String:
+		return new(Hex2StringConverter), nil
+	case params == MetaURLEncode:
+		return new(URLEncodeConverter), nil
+	case hasConverterName(params, MetaTimeString):
+		if len(params) == len(MetaTimeString) {
+			return NewTimeStringConverter("")
+		}
+		return NewTimeStringConverter(params[len(MetaTimeString)+1:])
+	case params == MetaUnixTime:
+		return new(UnixTimeConverter), nil
+	case params == MetaUnixTimeMs:
+		return new(UnixTimeMsConverter), nil
+	case hasConverterName(params, MetaSIPURI):
+		if len(params) == len(MetaSIPURI) {
+			return NewSIPURIConverter("")
+		}
+		return NewSIPURIConverter(params[len(MetaSIPURI)+1:])
 	default:
 		return nil, fmt.Errorf("unsupported converter definition: <%s>", params)
 	}
@@ -300,3 +357,331 @@ func (*IP2HexConverter) Convert(in interface{}) (out interface{}, err error) {
 	}
 	return "0x" + string([]byte(hx)[len(hx)-8:]), nil
 }
+
+// UpperConverter will transform the string into upper case
+type UpperConverter struct{}
+
+// Convert implements DataConverter interface
+func (*UpperConverter) Convert(in interface{}) (out interface{}, err error) {
+	return strings.ToUpper(IfaceAsString(in)), nil
+}
+
+// LowerConverter will transform the string into lower case
+type LowerConverter struct{}
+
+// Convert implements DataConverter interface
+func (*LowerConverter) Convert(in interface{}) (out interface{}, err error) {
+	return strings.ToLower(IfaceAsString(in)), nil
+}
+
+// NewTrimConverter creates a new trim converter
+// without params the leading and trailing white spaces are removed
+// otherwise the characters inside params are removed
+func NewTrimConverter(params string) (hdlr DataConverter, err error) {
+	return &TrimConverter{Cutset: params}, nil
+}
+
+// TrimConverter removes the leading and trailing characters within the Cutset
+type TrimConverter struct {
+	Cutset string
+}
+
+// Convert implements DataConverter interface
+func (tc *TrimConverter) Convert(in interface{}) (out interface{}, err error) {
+	if tc.Cutset == EmptyString {
+		return strings.TrimSpace(IfaceAsString(in)), nil
+	}
+	return strings.Trim(IfaceAsString(in), tc.Cutset), nil
+}
+
+// NewSubstrConverter creates a new substr converter out of start[:length] params
+// a negative start will count from the end of the string
+func NewSubstrConverter(params string) (hdlr DataConverter, err error) {
+	sc := &SubstrConverter{Length: -1}
+	var paramsSplt []string
+	if params != EmptyString {
+		paramsSplt = strings.Split(params, InInFieldSep)
+	}
+	switch len(paramsSplt) {
+	case 2:
+		if sc.Length, err = strconv.Atoi(paramsSplt[1]); err != nil || sc.Length < 0 {
+			return nil, fmt.Errorf("%s converter needs positive integer as length, have: <%s>",
+				MetaSubstr, paramsSplt[1])
+		}
+		fallthrough
+	case 1:
+		if sc.Start, err = strconv.Atoi(paramsSplt[0]); err != nil {
+			return nil, fmt.Errorf("%s converter needs integer as start, have: <%s>",
+				MetaSubstr, paramsSplt[0])
+		}
+	default:
+		return nil, fmt.Errorf("unsupported %s converter parameters: <%s>",
+			MetaSubstr, params)
+	}
+	return sc, nil
+}
+
+// SubstrConverter extracts Length characters starting with Start
+// a negative Length will extract until the end of the string
+type SubstrConverter struct {
+	Start  int
+	Length int
+}
+
+// Convert implements DataConverter interface
+func (sc *SubstrConverter) Convert(in interface{}) (out interface{}, err error) {
+	inRunes := []rune(IfaceAsString(in))
+	start := sc.Start
+	if start < 0 {
+		if start += len(inRunes); start < 0 {
+			start = 0
+		}
+	}
+	if start > len(inRunes) {
+		return EmptyString, nil
+	}
+	end := len(inRunes)
+	if sc.Length >= 0 && start+sc.Length < end {
+		end = start + sc.Length
+	}
+	return string(inRunes[start:end]), nil
+}
+
+// NewPadConverter creates a new pad converter out of length[:padding[:*left|*right]] params
+// by default the string is padded with spaces on the right
+func NewPadConverter(params string) (hdlr DataConverter, err error) {
+	pc := &PadConverter{Padding: " ", Side: MetaRight}
+	var paramsSplt []string
+	if params != EmptyString {
+		paramsSplt = strings.Split(params, InInFieldSep)
+	}
+	switch len(paramsSplt) {
+	case 3:
+		if pc.Side = paramsSplt[2]; pc.Side != MetaLeft && pc.Side != MetaRight {
+			return nil, fmt.Errorf("%s converter needs %s or %s as side, have: <%s>",
+				MetaPad, MetaLeft, MetaRight, paramsSplt[2])
+		}
+		fallthrough
+	case 2:
+		if pc.Padding = paramsSplt[1]; pc.Padding == EmptyString {
+			return nil, fmt.Errorf("%s converter needs non empty padding", MetaPad)
+		}
+		fallthrough
+	case 1:
+		if pc.Length, err = strconv.Atoi(paramsSplt[0]); err != nil || pc.Length < 0 {
+			return nil, fmt.Errorf("%s converter needs positive integer as length, have: <%s>",
+				MetaPad, paramsSplt[0])
+		}
+	default:
+		return nil, fmt.Errorf("unsupported %s converter parameters: <%s>",
+			MetaPad, params)
+	}
+	return pc, nil
+}
+
+// PadConverter pads the string up to Length using the Padding on the Side specified
+// longer strings are returned unchanged
+type PadConverter struct {
+	Length  int
+	Padding string
+	Side    string
+}
+
+// Convert implements DataConverter interface
+func (pc *PadConverter) Convert(in interface{}) (out interface{}, err error) {
+	inStr := IfaceAsString(in)
+	padLen := pc.Length - len([]rune(inStr))
+	if padLen <= 0 {
+		return inStr, nil
+	}
+	padding := []rune(strings.Repeat(pc.Padding, padLen))[:padLen]
+	if pc.Side == MetaLeft {
+		return string(padding) + inStr, nil
+	}
+	return inStr + string(padding), nil
+}
+
+// SHA256Converter returns the hex encoded SHA256 checksum of the string
+type SHA256Converter struct{}
+
+// Convert implements DataConverter interface
+func (*SHA256Converter) Convert(in interface{}) (out interface{}, err error) {
+	sum := sha256.Sum256([]byte(IfaceAsString(in)))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// MD5Converter returns the hex encoded MD5 checksum of the string
+type MD5Converter struct{}
+
+// Convert implements DataConverter interface
+func (*MD5Converter) Convert(in interface{}) (out interface{}, err error) {
+	sum := md5.Sum([]byte(IfaceAsString(in)))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Base64EncodeConverter encodes the string using the standard base64 encoding
+type Base64EncodeConverter struct{}
+
+// Convert implements DataConverter interface
+func (*Base64EncodeConverter) Convert(in interface{}) (out interface{}, err error) {
+	return base64.StdEncoding.EncodeToString([]byte(IfaceAsString(in))), nil
+}
+
+// Base64DecodeConverter decodes the string using the standard base64 encoding
+type Base64DecodeConverter struct{}
+
+// Convert implements DataConverter interface
+func (*Base64DecodeConverter) Convert(in interface{}) (out interface{}, err error) {
+	var b []byte
+	if b, err = base64.StdEncoding.DecodeString(IfaceAsString(in)); err != nil {
+		return
+	}
+	return string(b), nil
+}
+
+// String2HexConverter will transform the string into its hex representation
+type String2HexConverter struct{}
+
+// Convert implements DataConverter interface
+func (*String2HexConverter) Convert(in interface{}) (out interface{}, err error) {
+	return hex.EncodeToString([]byte(IfaceAsString(in))), nil
+}
+
+// Hex2StringConverter will transform the hex representation (optionally prefixed with 0x) into string
+type Hex2StringConverter struct{}
+
+// Convert implements DataConverter interface
+func (*Hex2StringConverter) Convert(in interface{}) (out interface{}, err error) {
+	inStr := IfaceAsString(in)
+	if strings.HasPrefix(inStr, "0x") || strings.HasPrefix(inStr, "0X") {
+		inStr = inStr[2:]
+	}
+	var b []byte
+	if b, err = hex.DecodeString(inStr); err != nil {
+		return
+	}
+	return string(b), nil
+}
+
+// URLEncodeConverter escapes the string so it can be safely placed inside an URL query
+type URLEncodeConverter struct{}
+
+// Convert implements DataConverter interface
+func (*URLEncodeConverter) Convert(in interface{}) (out interface{}, err error) {
+	return url.QueryEscape(IfaceAsString(in)), nil
+}
+
+// convertersTimezone is used by the time converters when the input is missing the timezone
+var convertersTimezone string
+
+// SetConvertersTimezone sets the timezone considered by the time converters, normally the general default_timezone
+func SetConvertersTimezone(tz string) {
+	convertersTimezone = tz
+}
+
+// convertibleAsTime returns the time out of the converter input
+// strings are parsed with ParseTimeDetectLayout, considering convertersTimezone when the timezone is missing
+func convertibleAsTime(in interface{}) (t time.Time, err error) {
+	if t, canCast := in.(time.Time); canCast {
+		return t, nil
+	}
+	return ParseTimeDetectLayout(IfaceAsString(in), convertersTimezone)
+}
+
+// NewTimeStringConverter creates a new time string converter out of layout[:timezone] params
+// since the layout can contain the separator, the last param is considered timezone only if it loads as location
+// without params the time is formated as RFC3339 in its own location
+func NewTimeStringConverter(params string) (hdlr DataConverter, err error) {
+	tsc := &TimeStringConverter{Layout: params}
+	if idx := strings.LastIndex(params, InInFieldSep); idx != -1 {
+		if loc, err := time.LoadLocation(params[idx+1:]); err == nil &&
+			params[idx+1:] != EmptyString {
+			tsc.Layout, tsc.Location = params[:idx], loc
+		}
+	}
+	if tsc.Layout == EmptyString {
+		tsc.Layout = time.RFC3339
+	}
+	return tsc, nil
+}
+
+// TimeStringConverter formats the time using the Layout, converted into Location if specified
+type TimeStringConverter struct {
+	Layout   string
+	Location *time.Location
+}
+
+// Convert implements DataConverter interface
+func (tsc *TimeStringConverter) Convert(in interface{}) (out interface{}, err error) {
+	var t time.Time
+	if t, err = convertibleAsTime(in); err != nil {
+		return
+	}
+	if tsc.Location != nil {
+		t = t.In(tsc.Location)
+	}
+	return t.Format(tsc.Layout), nil
+}
+
+// UnixTimeConverter converts the time into unix timestamp (seconds) encapsulated in int64
+type UnixTimeConverter struct{}
+
+// Convert implements DataConverter interface
+func (*UnixTimeConverter) Convert(in interface{}) (out interface{}, err error) {
+	var t time.Time
+	if t, err = convertibleAsTime(in); err != nil {
+		return
+	}
+	return t.Unix(), nil
+}
+
+// UnixTimeMsConverter converts the time into unix timestamp in milliseconds encapsulated in int64
+type UnixTimeMsConverter struct{}
+
+// Convert implements DataConverter interface
+func (*UnixTimeMsConverter) Convert(in interface{}) (out interface{}, err error) {
+	var t time.Time
+	if t, err = convertibleAsTime(in); err != nil {
+		return
+	}
+	return t.UnixNano() / int64(time.Millisecond), nil
+}
+
+// NewSIPURIConverter creates a new SIP URI converter out of part[:param_name] params
+// possible parts: user, host, port, display, scheme, uri or param followed by the parameter name
+func NewSIPURIConverter(params string) (hdlr DataConverter, err error) {
+	if params == EmptyString {
+		return nil, ErrMandatoryIeMissingNoCaps
+	}
+	fldPath := strings.Split(params, InInFieldSep)
+	switch fldPath[0] {
+	case SIPURIUser, SIPURIHost, SIPURIPort, SIPURIDisplay, SIPURIScheme, SIPURIURI:
+		if len(fldPath) == 1 {
+			return &SIPURIConverter{FldPath: fldPath}, nil
+		}
+	case SIPURIParam:
+		if len(fldPath) == 2 && fldPath[1] != EmptyString {
+			return &SIPURIConverter{FldPath: fldPath}, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported %s converter parameters: <%s>",
+		MetaSIPURI, params)
+}
+
+// SIPURIConverter extracts the part of the SIP URI within FldPath
+// a missing part or parameter is converted into empty string
+type SIPURIConverter struct {
+	FldPath []string
+}
+
+// Convert implements DataConverter interface
+func (sc *SIPURIConverter) Convert(in interface{}) (out interface{}, err error) {
+	var sipURI *SIPURI
+	if sipURI, err = NewSIPURI(IfaceAsString(in)); err != nil {
+		return
+	}
+	if out, err = sipURI.FieldAsString(sc.FldPath); err == ErrNotFound {
+		return EmptyString, nil
+	}
+	return
+}
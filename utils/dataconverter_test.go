@@ -629,3 +629,150 @@ func TestHexConvertor(t *testing.T) {
 		t.Errorf("expecting: %+v, received: %+v", expected, rpl)
 	}
 }
+
+func TestNewDataConverterStrings(t *testing.T) {
+	for params, eDc := range map[string]DataConverter{
+		MetaUpper:         new(UpperConverter),
+		MetaLower:         new(LowerConverter),
+		MetaTrim:          &TrimConverter{},
+		"*trim:0+":        &TrimConverter{Cutset: "0+"},
+		"*substr:2":       &SubstrConverter{Start: 2, Length: -1},
+		"*substr:-4:2":    &SubstrConverter{Start: -4, Length: 2},
+		"*pad:5":          &PadConverter{Length: 5, Padding: " ", Side: MetaRight},
+		"*pad:10:0:*left": &PadConverter{Length: 10, Padding: "0", Side: MetaLeft},
+		MetaSHA256:        new(SHA256Converter),
+		MetaMD5:           new(MD5Converter),
+		MetaBase64Encode:  new(Base64EncodeConverter),
+		MetaBase64Decode:  new(Base64DecodeConverter),
+		MetaString2Hex:    new(String2HexConverter),
+		MetaHex2String:    new(Hex2StringConverter),
+		MetaURLEncode:     new(URLEncodeConverter),
+	} {
+		if dc, err := NewDataConverter(params); err != nil {
+			t.Errorf("params: <%s>, error: %s", params, err)
+		} else if !reflect.DeepEqual(eDc, dc) {
+			t.Errorf("params: <%s>, expecting: %+v, received: %+v", params, eDc, dc)
+		}
+	}
+	for _, params := range []string{"*trimx", "*substring:1", "*padding:5",
+		MetaSubstr, "*substr:a", "*substr:1:-1", "*substr:1:2:3",
+		MetaPad, "*pad:a", "*pad:-1", "*pad:5::*left", "*pad:5:0:*middle", "*pad:5:0:*left:1"} {
+		if _, err := NewDataConverter(params); err == nil {
+			t.Errorf("params: <%s>, expecting error", params)
+		}
+	}
+}
+
+func TestStringConvertersConvert(t *testing.T) {
+	for _, tc := range []struct {
+		params string
+		in     interface{}
+		out    interface{}
+	}{
+		{params: MetaUpper, in: "cgrates.org", out: "CGRATES.ORG"},
+		{params: MetaLower, in: "CGRateS.org", out: "cgrates.org"},
+		{params: MetaTrim, in: " \t1001\n", out: "1001"},
+		{params: "*trim:0", in: "0010010", out: "1001"},
+		{params: "*substr:1", in: "+4986517174963", out: "4986517174963"},
+		{params: "*substr:1:2", in: "+4986517174963", out: "49"},
+		{params: "*substr:-3", in: "+4986517174963", out: "963"},
+		{params: "*substr:-20:3", in: "+4986517174963", out: "+49"},
+		{params: "*substr:20", in: "+4986517174963", out: ""},
+		{params: "*substr:1:2", in: "äöüß", out: "öü"},
+		{params: "*pad:6", in: "1001", out: "1001  "},
+		{params: "*pad:6:0:*left", in: 1001, out: "001001"},
+		{params: "*pad:7:ab:*left", in: "1001", out: "aba1001"},
+		{params: "*pad:2", in: "1001", out: "1001"},
+		{params: MetaSHA256, in: "cgrates", out: "6fc1d115a36fe337d486347dda7fd5a45bd636840af5a42bd88026f0a5f35f65"},
+		{params: MetaMD5, in: "cgrates", out: "323b06367fe545449590a0f4a300f7c2"},
+		{params: MetaBase64Encode, in: "cgrates:1001", out: "Y2dyYXRlczoxMDAx"},
+		{params: MetaBase64Decode, in: "Y2dyYXRlczoxMDAx", out: "cgrates:1001"},
+		{params: MetaString2Hex, in: "1001", out: "31303031"},
+		{params: MetaHex2String, in: "31303031", out: "1001"},
+		{params: MetaHex2String, in: "0x31303031", out: "1001"},
+		{params: MetaURLEncode, in: "a b&c=d/é", out: "a+b%26c%3Dd%2F%C3%A9"},
+	} {
+		if out, err := NewDataConverterMustCompile(tc.params).Convert(tc.in); err != nil {
+			t.Errorf("params: <%s>, error: %s", tc.params, err)
+		} else if !reflect.DeepEqual(tc.out, out) {
+			t.Errorf("params: <%s>, expecting: %q, received: %q", tc.params, tc.out, out)
+		}
+	}
+	if _, err := new(Base64DecodeConverter).Convert("not base64!"); err == nil {
+		t.Error("expecting error")
+	}
+	if _, err := new(Hex2StringConverter).Convert("0xZZ"); err == nil {
+		t.Error("expecting error")
+	}
+}
+
+func TestDataConvertersConvertStringChained(t *testing.T) {
+	dcs := DataConverters{
+		NewDataConverterMustCompile(MetaTrim),
+		NewDataConverterMustCompile("*substr:0:4"),
+		NewDataConverterMustCompile(MetaUpper),
+		NewDataConverterMustCompile("*pad:6:X:*left"),
+	}
+	if out, err := dcs.ConvertString("  abcdef "); err != nil {
+		t.Error(err)
+	} else if out != "XXABCD" {
+		t.Errorf("expecting: XXABCD, received: %s", out)
+	}
+}
+
+func TestNewTimeStringConverter(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for params, eDc := range map[string]DataConverter{
+		MetaTimeString:                                   &TimeStringConverter{Layout: time.RFC3339},
+		"*time_string:20060102150405":                    &TimeStringConverter{Layout: "20060102150405"},
+		"*time_string:2006-01-02 15:04:05":               &TimeStringConverter{Layout: "2006-01-02 15:04:05"},
+		"*time_string:2006-01-02 15:04:05:Europe/Berlin": &TimeStringConverter{Layout: "2006-01-02 15:04:05", Location: berlin},
+		"*time_string::UTC":                              &TimeStringConverter{Layout: time.RFC3339, Location: time.UTC},
+		MetaUnixTime:                                     new(UnixTimeConverter),
+		MetaUnixTimeMs:                                   new(UnixTimeMsConverter),
+	} {
+		if dc, err := NewDataConverter(params); err != nil {
+			t.Errorf("params: <%s>, error: %s", params, err)
+		} else if !reflect.DeepEqual(eDc, dc) {
+			t.Errorf("params: <%s>, expecting: %+v, received: %+v", params, eDc, dc)
+		}
+	}
+}
+
+func TestTimeConvertersConvert(t *testing.T) {
+	tm := time.Date(2020, 3, 29, 0, 30, 15, 123000000, time.UTC)
+	for _, tc := range []struct {
+		params string
+		in     interface{}
+		out    interface{}
+	}{
+		{params: MetaTimeString, in: tm, out: "2020-03-29T00:30:15Z"},
+		{params: "*time_string:2006-01-02 15:04:05:Europe/Berlin", in: tm, out: "2020-03-29 01:30:15"},
+		{params: "*time_string:2006-01-02 15:04:05 MST:Europe/Berlin", in: "2020-03-29T01:30:15Z", out: "2020-03-29 03:30:15 CEST"},
+		{params: "*time_string:20060102150405", in: "2020-03-29T02:30:15+02:00", out: "20200329023015"},
+		{params: "*time_string:20060102150405:UTC", in: "2020-03-29T02:30:15+02:00", out: "20200329003015"},
+		{params: "*time_string:02/01/2006", in: int64(1585441815), out: "29/03/2020"},
+		{params: MetaUnixTime, in: tm, out: int64(1585441815)},
+		{params: MetaUnixTime, in: "2020-03-29 00:30:15", out: int64(1585441815)},
+		{params: MetaUnixTimeMs, in: tm, out: int64(1585441815123)},
+	} {
+		if out, err := NewDataConverterMustCompile(tc.params).Convert(tc.in); err != nil {
+			t.Errorf("params: <%s>, error: %s", tc.params, err)
+		} else if !reflect.DeepEqual(tc.out, out) {
+			t.Errorf("params: <%s>, expecting: %v, received: %v", tc.params, tc.out, out)
+		}
+	}
+	if _, err := NewDataConverterMustCompile(MetaUnixTime).Convert("not a time"); err == nil {
+		t.Error("expecting error")
+	}
+	SetConvertersTimezone("Europe/Berlin")
+	defer SetConvertersTimezone(EmptyString)
+	if out, err := NewDataConverterMustCompile(MetaUnixTime).Convert("2020-03-29 01:30:15"); err != nil {
+		t.Error(err)
+	} else if out != int64(1585441815) {
+		t.Errorf("expecting: 1585441815, received: %v", out)
+	}
+}
@@ -19,11 +19,18 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package utils
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 	"log/syslog"
+	"os"
 	"reflect"
 	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
 var Logger LoggerInterface
@@ -40,7 +47,7 @@ func init() {
 
 //functie Newlogger (logger type)
 func Newlogger(loggertype, id string) (err error) {
-	Logger = new(StdLogger)
+	Logger = &StdLogger{subsysLvls: make(map[string]int)}
 	nodeID = id
 	var l *syslog.Writer
 	if loggertype == MetaSysLog {
@@ -59,6 +66,8 @@ func Newlogger(loggertype, id string) (err error) {
 type LoggerInterface interface {
 	SetSyslog(log *syslog.Writer)
 	SetLogLevel(level int)
+	SetSubsystemLogLevel(subsys string, level int)
+	SetLogFormat(format string) error
 	GetSyslog() *syslog.Writer
 	Close() error
 	Emerg(m string) error
@@ -69,6 +78,7 @@ type LoggerInterface interface {
 	Notice(m string) error
 	Info(m string) error
 	Debug(m string) error
+	Log(level int, m string, fields LogFields) error
 	Write(p []byte) (n int, err error)
 }
 
@@ -84,15 +94,39 @@ const (
 	LOGLEVEL_DEBUG
 )
 
+var logLevelNames = []string{"EMERGENCY", "ALERT", "CRITICAL", "ERROR", "WARNING", "NOTICE", "INFO", "DEBUG"}
+
+// jsonLog writes the JSON entries on the output of the standard logger, without prefix
+var jsonLog = log.New(os.Stderr, EmptyString, 0)
+
+// LogFields are the structured fields attached to a log entry, ie: tenant, cgrid, origin_id
+type LogFields map[string]string
+
+// String returns the fields as sorted key=value pairs, used by the text format
+func (lf LogFields) String() string {
+	keys := make([]string, 0, len(lf))
+	for k := range lf {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for i, k := range keys {
+		keys[i] = k + "=" + lf[k]
+	}
+	return strings.Join(keys, " ")
+}
+
 // Logs to standard output
 type StdLogger struct {
-	logLevel int
-	syslog   *syslog.Writer
+	sync.RWMutex
+	logLevel   int
+	subsysLvls map[string]int // log level overwrites per subsystem
+	jsonFormat bool
+	syslog     *syslog.Writer
 }
 
 func (sl *StdLogger) Close() (err error) {
 	if sl.syslog != nil {
-		sl.Close()
+		err = sl.syslog.Close()
 	}
 	return
 }
@@ -114,112 +148,159 @@ func (sl *StdLogger) GetSyslog() *syslog.Writer {
 
 // SetLogLevel changes the log level
 func (sl *StdLogger) SetLogLevel(level int) {
+	sl.Lock()
 	sl.logLevel = level
+	sl.Unlock()
 }
 
-// Alert logs to syslog with alert level
-func (sl *StdLogger) Alert(m string) (err error) {
-	if sl.logLevel < LOGLEVEL_ALERT {
-		return
-	}
-	if sl.syslog != nil {
-		sl.syslog.Alert(m)
+// SetSubsystemLogLevel changes the log level of one subsystem, a negative level removes the overwrite
+func (sl *StdLogger) SetSubsystemLogLevel(subsys string, level int) {
+	sl.Lock()
+	if level < 0 {
+		delete(sl.subsysLvls, subsys)
 	} else {
-		log.Print("CGRateS <" + nodeID + "> [ALERT] " + m)
+		sl.subsysLvls[subsys] = level
+	}
+	sl.Unlock()
+}
+
+// SetLogFormat changes the format of the log entries <*text|*json>
+func (sl *StdLogger) SetLogFormat(format string) (err error) {
+	switch format {
+	case MetaText, EmptyString:
+		sl.Lock()
+		sl.jsonFormat = false
+		sl.Unlock()
+	case MetaJSON:
+		sl.Lock()
+		sl.jsonFormat = true
+		sl.Unlock()
+	default:
+		err = fmt.Errorf("unsupported log format: <%s>", format)
 	}
 	return
 }
 
+// Alert logs to syslog with alert level
+func (sl *StdLogger) Alert(m string) (err error) {
+	return sl.Log(LOGLEVEL_ALERT, m, nil)
+}
+
 // Crit logs to syslog with critical level
 func (sl *StdLogger) Crit(m string) (err error) {
-	if sl.logLevel < LOGLEVEL_CRITICAL {
-		return
-	}
-	if sl.syslog != nil {
-		sl.syslog.Crit(m)
-	} else {
-		log.Print("CGRateS <" + nodeID + "> [CRITICAL] " + m)
-	}
-	return
+	return sl.Log(LOGLEVEL_CRITICAL, m, nil)
 }
 
 // Debug logs to syslog with debug level
 func (sl *StdLogger) Debug(m string) (err error) {
-	if sl.logLevel < LOGLEVEL_DEBUG {
-		return
-	}
-	if sl.syslog != nil {
-		sl.syslog.Debug(m)
-	} else {
-		log.Print("CGRateS <" + nodeID + "> [DEBUG] " + m)
-	}
-	return
+	return sl.Log(LOGLEVEL_DEBUG, m, nil)
 }
 
 // Emerg logs to syslog with emergency level
 func (sl *StdLogger) Emerg(m string) (err error) {
-	if sl.logLevel < LOGLEVEL_EMERGENCY {
-		return
-	}
-	if sl.syslog != nil {
-		sl.syslog.Emerg(m)
-	} else {
-		log.Print("CGRateS <" + nodeID + "> [EMERGENCY] " + m)
-	}
-	return
+	return sl.Log(LOGLEVEL_EMERGENCY, m, nil)
 }
 
 // Err logs to syslog with error level
 func (sl *StdLogger) Err(m string) (err error) {
-	if sl.logLevel < LOGLEVEL_ERROR {
-		return
-	}
-	if sl.syslog != nil {
-		sl.syslog.Err(m)
-	} else {
-		log.Print("CGRateS <" + nodeID + "> [ERROR] " + m)
-	}
-	return
+	return sl.Log(LOGLEVEL_ERROR, m, nil)
 }
 
 // Info logs to syslog with info level
 func (sl *StdLogger) Info(m string) (err error) {
-	if sl.logLevel < LOGLEVEL_INFO {
-		return
-	}
-	if sl.syslog != nil {
-		sl.syslog.Info(m)
-	} else {
-		log.Print("CGRateS <" + nodeID + "> [INFO] " + m)
-	}
-	return
+	return sl.Log(LOGLEVEL_INFO, m, nil)
 }
 
 // Notice logs to syslog with notice level
 func (sl *StdLogger) Notice(m string) (err error) {
-	if sl.logLevel < LOGLEVEL_NOTICE {
+	return sl.Log(LOGLEVEL_NOTICE, m, nil)
+}
+
+// Warning logs to syslog with warning level
+func (sl *StdLogger) Warning(m string) (err error) {
+	return sl.Log(LOGLEVEL_WARNING, m, nil)
+}
+
+// Log logs the message with the given level and structured fields
+// the subsystem is taken out of the message prefix, ie: "<SessionS> ..."
+func (sl *StdLogger) Log(level int, m string, fields LogFields) (err error) {
+	subsys, msg := splitLogSubsystem(m)
+	sl.RLock()
+	lvl, has := sl.subsysLvls[subsys]
+	if !has {
+		lvl = sl.logLevel
+	}
+	jsonFormat := sl.jsonFormat
+	sl.RUnlock()
+	if lvl < level {
 		return
 	}
+	if jsonFormat {
+		m = jsonLogEntry(level, subsys, msg, fields)
+	} else if len(fields) != 0 {
+		m += " " + fields.String()
+	}
 	if sl.syslog != nil {
-		sl.syslog.Notice(m)
+		switch level {
+		case LOGLEVEL_EMERGENCY:
+			sl.syslog.Emerg(m)
+		case LOGLEVEL_ALERT:
+			sl.syslog.Alert(m)
+		case LOGLEVEL_CRITICAL:
+			sl.syslog.Crit(m)
+		case LOGLEVEL_ERROR:
+			sl.syslog.Err(m)
+		case LOGLEVEL_WARNING:
+			sl.syslog.Warning(m)
+		case LOGLEVEL_NOTICE:
+			sl.syslog.Notice(m)
+		case LOGLEVEL_INFO:
+			sl.syslog.Info(m)
+		default:
+			sl.syslog.Debug(m)
+		}
+	} else if jsonFormat {
+		jsonLog.Print(m)
 	} else {
-		log.Print("CGRateS <" + nodeID + "> [NOTICE] " + m)
+		log.Print("CGRateS <" + nodeID + "> [" + logLevelName(level) + "] " + m)
 	}
 	return
 }
 
-// Warning logs to syslog with warning level
-func (sl *StdLogger) Warning(m string) (err error) {
-	if sl.logLevel < LOGLEVEL_WARNING {
-		return
+// splitLogSubsystem returns the subsystem out of the "<Subsystem> message" format and the remaining message
+func splitLogSubsystem(m string) (subsys, msg string) {
+	if !strings.HasPrefix(m, "<") {
+		return EmptyString, m
 	}
+	idx := strings.IndexByte(m, '>')
+	if idx == -1 || strings.ContainsAny(m[1:idx], " \t") {
+		return EmptyString, m
+	}
+	return m[1:idx], strings.TrimLeft(m[idx+1:], ", ")
+}
 
-	if sl.syslog != nil {
-		sl.syslog.Warning(m)
-	} else {
-		log.Print("CGRateS <" + nodeID + "> [WARNING] " + m)
+func logLevelName(level int) string {
+	if level < LOGLEVEL_EMERGENCY || level > LOGLEVEL_DEBUG {
+		return strconv.Itoa(level)
 	}
-	return
+	return logLevelNames[level]
+}
+
+// jsonLogEntry returns the log entry encoded as one JSON object
+func jsonLogEntry(level int, subsys, msg string, fields LogFields) string {
+	entry := make(map[string]string, len(fields)+5)
+	for k, v := range fields {
+		entry[k] = v
+	}
+	entry[LogFieldTimestamp] = time.Now().UTC().Format(time.RFC3339Nano)
+	entry[LogFieldLevel] = logLevelName(level)
+	entry[LogFieldNodeID] = nodeID
+	entry[LogFieldMessage] = msg
+	if subsys != EmptyString {
+		entry[LogFieldSubsystem] = subsys
+	}
+	b, _ := json.Marshal(entry) // map of strings, it cannot fail
+	return string(b)
 }
 
 // LogStack logs to syslog the stack trace using debug level
@@ -228,3 +309,16 @@ func LogStack() {
 	runtime.Stack(buf, false)
 	Logger.Debug(string(buf))
 }
+
+// ArgsSetLogLevel are the arguments of CoreSv1.SetLogLevel
+type ArgsSetLogLevel struct {
+	Subsystem string // the subsystem to change the level for, empty or *all for the global level
+	Level     int    // the new level, negative to remove the subsystem overwrite
+}
+
+// ArgsSetLogLevelWithArgDispatcher is used by CoreSv1.SetLogLevel through DispatcherS
+type ArgsSetLogLevelWithArgDispatcher struct {
+	*ArgDispatcher
+	TenantArg
+	ArgsSetLogLevel
+}
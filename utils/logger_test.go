@@ -0,0 +1,89 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestSplitLogSubsystem(t *testing.T) {
+	for m, exp := range map[string][2]string{
+		"<SessionS> debit failed":        {"SessionS", "debit failed"},
+		"<SessionS>, debit failed":       {"SessionS", "debit failed"},
+		"no subsystem here":              {"", "no subsystem here"},
+		"<not a subsystem> debit failed": {"", "<not a subsystem> debit failed"},
+		"<unterminated subsystem":        {"", "<unterminated subsystem"},
+	} {
+		if subsys, msg := splitLogSubsystem(m); subsys != exp[0] || msg != exp[1] {
+			t.Errorf("for <%s> expected: %q %q, received: %q %q", m, exp[0], exp[1], subsys, msg)
+		}
+	}
+}
+
+func TestLogFieldsString(t *testing.T) {
+	lf := LogFields{LogFieldTenant: "cgrates.org", LogFieldCGRID: "cgrid1"}
+	if exp, rcv := "cgrid=cgrid1 tenant=cgrates.org", lf.String(); exp != rcv {
+		t.Errorf("expected: %q, received: %q", exp, rcv)
+	}
+}
+
+func TestStdLoggerJSONSubsystemLevels(t *testing.T) {
+	buf := new(bytes.Buffer)
+	jsonLog.SetOutput(buf)
+	defer jsonLog.SetOutput(os.Stderr)
+	sl := &StdLogger{logLevel: LOGLEVEL_WARNING, subsysLvls: make(map[string]int)}
+	if err := sl.SetLogFormat(MetaJSON); err != nil {
+		t.Fatal(err)
+	}
+	sl.Debug("<SessionS> filtered out")
+	if buf.Len() != 0 {
+		t.Fatalf("unexpected log entry: %s", buf.String())
+	}
+	sl.SetSubsystemLogLevel(SessionS, LOGLEVEL_DEBUG)
+	sl.Debug("<RALs> filtered out")
+	sl.Log(LOGLEVEL_DEBUG, "<SessionS> debit loop", LogFields{LogFieldCGRID: "cgrid1"})
+	var entry map[string]string
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("%v: %s", err, buf.String())
+	}
+	delete(entry, LogFieldTimestamp)
+	exp := map[string]string{
+		LogFieldLevel:     "DEBUG",
+		LogFieldNodeID:    nodeID,
+		LogFieldSubsystem: SessionS,
+		LogFieldMessage:   "debit loop",
+		LogFieldCGRID:     "cgrid1",
+	}
+	if !reflect.DeepEqual(exp, entry) {
+		t.Errorf("expected: %s, received: %s", ToJSON(exp), ToJSON(entry))
+	}
+	buf.Reset()
+	sl.SetSubsystemLogLevel(SessionS, -1)
+	sl.Debug("<SessionS> filtered out")
+	if buf.Len() != 0 {
+		t.Errorf("unexpected log entry: %s", buf.String())
+	}
+	if err := sl.SetLogFormat("*xml"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
@@ -0,0 +1,456 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// the types of the runtime metrics
+const (
+	MetricCounter   = "counter"
+	MetricGauge     = "gauge"
+	MetricHistogram = "histogram"
+)
+
+// metricLabelSep separates the label values in the series index
+const metricLabelSep = "\x00"
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of the latency histograms
+var DefaultLatencyBuckets = []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+var prometheusLabelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// RuntimeMetrics is the registry collecting the internal metrics of the engine subsystems
+var RuntimeMetrics = newRuntimeMetrics()
+
+// newRuntimeMetrics returns the registry with the metrics recorded by the engine subsystems
+func newRuntimeMetrics() (mr *MetricsRegistry) {
+	mr = NewMetricsRegistry()
+	mr.RegisterCounter(MetricRPCRequests, "RPC requests served, per method and encoding.", MetricLabelMethod, MetricLabelEncoding)
+	mr.RegisterCounter(MetricRPCErrors, "RPC requests served with error, per method.", MetricLabelMethod)
+	mr.RegisterHistogram(MetricRPCDuration, "Duration of the served RPC requests, per method.", DefaultLatencyBuckets, MetricLabelMethod)
+	mr.RegisterCounter(MetricConnCalls, "Calls sent through the ConnManager, per connection ID.", MetricLabelConnID)
+	mr.RegisterCounter(MetricConnFailures, "Failed calls through the ConnManager, per connection ID and reason.", MetricLabelConnID, MetricLabelReason)
+	mr.RegisterCounter(MetricCacheEvictions, "Items removed from cache (expired, evicted by limit or removed), per partition.", MetricLabelPartition)
+	mr.RegisterHistogram(MetricGuardianLockWait, "Time spent waiting for the guardian locks.", DefaultLatencyBuckets)
+	mr.RegisterHistogram(MetricSessionsDebitLag, "Delay of the session debit loop compared to the debit interval.", DefaultLatencyBuckets)
+	mr.RegisterCounter(MetricERsRead, "Events read by the EventReader, per reader ID.", MetricLabelReaderID)
+	mr.RegisterCounter(MetricERsProcessed, "Events processed successfully by the EventReader, per reader ID.", MetricLabelReaderID)
+	mr.RegisterCounter(MetricERsFailed, "Events failed processing by the EventReader, per reader ID.", MetricLabelReaderID)
+	mr.RegisterCounter(MetricSpansDropped, "Finished tracing spans dropped because the export buffer was full.")
+	return
+}
+
+// NewMetricsRegistry returns an empty MetricsRegistry
+func NewMetricsRegistry() *MetricsRegistry {
+	return &MetricsRegistry{families: make(map[string]*metricFamily)}
+}
+
+// MetricsRegistry keeps the counters, gauges and histograms of the runtime metrics
+type MetricsRegistry struct {
+	sync.RWMutex
+	families map[string]*metricFamily
+}
+
+// MetricFamily is the exported value of one metric with all its samples
+type MetricFamily struct {
+	Name    string
+	Help    string
+	Type    string
+	Samples []*MetricSample
+}
+
+// MetricSample is the value of a metric for one set of labels
+type MetricSample struct {
+	Labels  map[string]string `json:",omitempty"`
+	Value   float64           // the value of counters and gauges, the sum of the observations for histograms
+	Count   uint64            `json:",omitempty"` // number of observations for histograms
+	Buckets []*MetricBucket   `json:",omitempty"` // cumulative observations for histograms
+}
+
+// MetricBucket is the number of observations less or equal to UpperBound
+type MetricBucket struct {
+	UpperBound float64
+	Count      uint64
+}
+
+type metricFamily struct {
+	name       string
+	help       string
+	typ        string
+	labelNames []string
+	buckets    []float64
+	series     map[string]*metricSeries // indexed on the label values
+	collect    func() []*MetricSample   // computes the samples on read instead of series
+}
+
+// metricSeries holds the values of one set of labels, updated atomically
+type metricSeries struct {
+	valueBits    uint64 // the float64 value as bits, first for 64-bit alignment
+	count        uint64
+	labelValues  []string
+	bucketCounts []uint64 // observations per bucket, not cumulative
+}
+
+func (ms *metricSeries) add(val float64) {
+	for {
+		oldBits := atomic.LoadUint64(&ms.valueBits)
+		if atomic.CompareAndSwapUint64(&ms.valueBits, oldBits,
+			math.Float64bits(math.Float64frombits(oldBits)+val)) {
+			return
+		}
+	}
+}
+
+func (ms *metricSeries) set(val float64) {
+	atomic.StoreUint64(&ms.valueBits, math.Float64bits(val))
+}
+
+func (ms *metricSeries) value() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&ms.valueBits))
+}
+
+func (mr *MetricsRegistry) register(mf *metricFamily) {
+	mr.Lock()
+	if _, has := mr.families[mf.name]; !has || mf.collect != nil {
+		mr.families[mf.name] = mf
+	}
+	mr.Unlock()
+}
+
+// RegisterCounter registers a counter, no-op if the metric is already registered
+func (mr *MetricsRegistry) RegisterCounter(name, help string, labelNames ...string) {
+	mr.register(&metricFamily{name: name, help: help, typ: MetricCounter,
+		labelNames: labelNames, series: make(map[string]*metricSeries)})
+}
+
+// RegisterGauge registers a gauge, no-op if the metric is already registered
+func (mr *MetricsRegistry) RegisterGauge(name, help string, labelNames ...string) {
+	mr.register(&metricFamily{name: name, help: help, typ: MetricGauge,
+		labelNames: labelNames, series: make(map[string]*metricSeries)})
+}
+
+// RegisterHistogram registers a histogram with the sorted bucket upper bounds, no-op if the metric is already registered
+func (mr *MetricsRegistry) RegisterHistogram(name, help string, buckets []float64, labelNames ...string) {
+	mr.register(&metricFamily{name: name, help: help, typ: MetricHistogram,
+		labelNames: labelNames, buckets: buckets, series: make(map[string]*metricSeries)})
+}
+
+// RegisterGaugeFunc registers a gauge computed by collect on each read, replacing the previous one with the same name
+func (mr *MetricsRegistry) RegisterGaugeFunc(name, help string, collect func() []*MetricSample) {
+	mr.register(&metricFamily{name: name, help: help, typ: MetricGauge, collect: collect})
+}
+
+// RegisterCounterFunc registers a counter computed by collect on each read, replacing the previous one with the same name
+// used for the counters kept by the subsystems themselves
+func (mr *MetricsRegistry) RegisterCounterFunc(name, help string, collect func() []*MetricSample) {
+	mr.register(&metricFamily{name: name, help: help, typ: MetricCounter, collect: collect})
+}
+
+// UnregisterMetric removes the metric from the registry
+func (mr *MetricsRegistry) UnregisterMetric(name string) {
+	mr.Lock()
+	delete(mr.families, name)
+	mr.Unlock()
+}
+
+// getSeries returns the series for the label values, creating it if needed
+func (mr *MetricsRegistry) getSeries(name, typ string, labelValues []string) (mf *metricFamily, ms *metricSeries) {
+	key := strings.Join(labelValues, metricLabelSep)
+	mr.RLock()
+	mf, has := mr.families[name]
+	if !has || mf.typ != typ || mf.collect != nil ||
+		len(labelValues) != len(mf.labelNames) {
+		mr.RUnlock()
+		return nil, nil
+	}
+	ms, has = mf.series[key]
+	mr.RUnlock()
+	if has {
+		return
+	}
+	mr.Lock()
+	if ms, has = mf.series[key]; !has {
+		ms = &metricSeries{labelValues: labelValues}
+		if typ == MetricHistogram {
+			ms.bucketCounts = make([]uint64, len(mf.buckets))
+		}
+		mf.series[key] = ms
+	}
+	mr.Unlock()
+	return
+}
+
+// AddCounter adds val to the counter, ignored if the metric is not registered
+func (mr *MetricsRegistry) AddCounter(name string, val float64, labelValues ...string) {
+	if _, ms := mr.getSeries(name, MetricCounter, labelValues); ms != nil {
+		ms.add(val)
+	}
+}
+
+// IncCounter increments the counter with one
+func (mr *MetricsRegistry) IncCounter(name string, labelValues ...string) {
+	mr.AddCounter(name, 1, labelValues...)
+}
+
+// SetGauge sets the value of the gauge, ignored if the metric is not registered
+func (mr *MetricsRegistry) SetGauge(name string, val float64, labelValues ...string) {
+	if _, ms := mr.getSeries(name, MetricGauge, labelValues); ms != nil {
+		ms.set(val)
+	}
+}
+
+// ObserveHistogram records one observation, ignored if the metric is not registered
+func (mr *MetricsRegistry) ObserveHistogram(name string, val float64, labelValues ...string) {
+	if mf, ms := mr.getSeries(name, MetricHistogram, labelValues); ms != nil {
+		ms.add(val)
+		atomic.AddUint64(&ms.count, 1) // before the bucket so the +Inf bucket is never behind
+		if i := sort.SearchFloat64s(mf.buckets, val); i < len(ms.bucketCounts) {
+			atomic.AddUint64(&ms.bucketCounts[i], 1)
+		}
+	}
+}
+
+// ObserveDuration records the time passed since sTime in seconds
+func (mr *MetricsRegistry) ObserveDuration(name string, sTime time.Time, labelValues ...string) {
+	mr.ObserveHistogram(name, time.Since(sTime).Seconds(), labelValues...)
+}
+
+// GetMetrics returns the metrics with the name starting with one of the prefixes, all if no prefix is given
+func (mr *MetricsRegistry) GetMetrics(prfxs []string) (mfs []*MetricFamily) {
+	var collects []*MetricFamily
+	var collectFuncs []func() []*MetricSample
+	mr.RLock()
+	for name, mf := range mr.families {
+		if len(prfxs) != 0 && !hasPrefix(name, prfxs) {
+			continue
+		}
+		expMf := &MetricFamily{Name: mf.name, Help: mf.help, Type: mf.typ}
+		if mf.collect != nil { // executed after unlock since they can take own locks
+			collects = append(collects, expMf)
+			collectFuncs = append(collectFuncs, mf.collect)
+			continue
+		}
+		for _, ms := range mf.series {
+			expMf.Samples = append(expMf.Samples, ms.asMetricSample(mf))
+		}
+		mfs = append(mfs, expMf)
+	}
+	mr.RUnlock()
+	for i, expMf := range collects {
+		expMf.Samples = collectFuncs[i]()
+		mfs = append(mfs, expMf)
+	}
+	sort.Slice(mfs, func(i, j int) bool { return mfs[i].Name < mfs[j].Name })
+	for _, mf := range mfs {
+		sort.Slice(mf.Samples, func(i, j int) bool {
+			return mf.Samples[i].labelsKey() < mf.Samples[j].labelsKey()
+		})
+	}
+	return
+}
+
+func hasPrefix(s string, prfxs []string) bool {
+	for _, prfx := range prfxs {
+		if strings.HasPrefix(s, prfx) {
+			return true
+		}
+	}
+	return false
+}
+
+// asMetricSample exports the series
+func (ms *metricSeries) asMetricSample(mf *metricFamily) (smpl *MetricSample) {
+	smpl = &MetricSample{Value: ms.value(), Count: atomic.LoadUint64(&ms.count)}
+	if len(mf.labelNames) != 0 {
+		smpl.Labels = make(map[string]string, len(mf.labelNames))
+		for i, lblName := range mf.labelNames {
+			smpl.Labels[lblName] = ms.labelValues[i]
+		}
+	}
+	if mf.typ == MetricHistogram {
+		smpl.Buckets = make([]*MetricBucket, len(mf.buckets))
+		var cnt uint64
+		for i, upBnd := range mf.buckets {
+			cnt += atomic.LoadUint64(&ms.bucketCounts[i])
+			smpl.Buckets[i] = &MetricBucket{UpperBound: upBnd, Count: cnt}
+		}
+	}
+	return
+}
+
+// labelNames returns the sorted label names of the sample
+func (smpl *MetricSample) labelNames() (lblNames []string) {
+	lblNames = make([]string, 0, len(smpl.Labels))
+	for lblName := range smpl.Labels {
+		lblNames = append(lblNames, lblName)
+	}
+	sort.Strings(lblNames)
+	return
+}
+
+// labelsKey is used to sort the samples on their labels
+func (smpl *MetricSample) labelsKey() string {
+	lblNames := smpl.labelNames()
+	for i, lblName := range lblNames {
+		lblNames[i] = lblName + metricLabelSep + smpl.Labels[lblName]
+	}
+	return strings.Join(lblNames, metricLabelSep)
+}
+
+// prometheusLabels formats the labels of the sample, with the extra label appended if not empty
+func (smpl *MetricSample) prometheusLabels(extraName, extraValue string) string {
+	lbls := make([]string, 0, len(smpl.Labels)+1)
+	for _, lblName := range smpl.labelNames() {
+		lbls = append(lbls, fmt.Sprintf("%s=\"%s\"", lblName, EscapePrometheusLabel(smpl.Labels[lblName])))
+	}
+	if extraName != EmptyString {
+		lbls = append(lbls, fmt.Sprintf("%s=\"%s\"", extraName, EscapePrometheusLabel(extraValue)))
+	}
+	if len(lbls) == 0 {
+		return EmptyString
+	}
+	return "{" + strings.Join(lbls, FIELDS_SEP) + "}"
+}
+
+// EscapePrometheusLabel escapes the label value for the Prometheus text exposition format
+func EscapePrometheusLabel(val string) string {
+	return prometheusLabelReplacer.Replace(val)
+}
+
+// FormatPrometheusValue formats the sample value for the Prometheus text exposition format
+func FormatPrometheusValue(val float64) string {
+	switch {
+	case math.IsNaN(val):
+		return "NaN"
+	case math.IsInf(val, 1):
+		return "+Inf"
+	case math.IsInf(val, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(val, 'g', -1, 64)
+}
+
+// WritePrometheus writes the metrics in the Prometheus text exposition format
+func (mr *MetricsRegistry) WritePrometheus(w io.Writer) (err error) {
+	var buf bytes.Buffer
+	for _, mf := range mr.GetMetrics(nil) {
+		fmt.Fprintf(&buf, "# HELP %s %s\n# TYPE %s %s\n", mf.Name, mf.Help, mf.Name, mf.Type)
+		for _, smpl := range mf.Samples {
+			if mf.Type != MetricHistogram {
+				fmt.Fprintf(&buf, "%s%s %s\n", mf.Name, smpl.prometheusLabels(EmptyString, EmptyString),
+					FormatPrometheusValue(smpl.Value))
+				continue
+			}
+			for _, bkt := range smpl.Buckets {
+				fmt.Fprintf(&buf, "%s_bucket%s %d\n", mf.Name,
+					smpl.prometheusLabels("le", FormatPrometheusValue(bkt.UpperBound)), bkt.Count)
+			}
+			fmt.Fprintf(&buf, "%s_bucket%s %d\n", mf.Name, smpl.prometheusLabels("le", "+Inf"), smpl.Count)
+			fmt.Fprintf(&buf, "%s_sum%s %s\n", mf.Name, smpl.prometheusLabels(EmptyString, EmptyString),
+				FormatPrometheusValue(smpl.Value))
+			fmt.Fprintf(&buf, "%s_count%s %d\n", mf.Name, smpl.prometheusLabels(EmptyString, EmptyString), smpl.Count)
+		}
+	}
+	_, err = w.Write(buf.Bytes())
+	return
+}
+
+// ServeHTTP implements http.Handler interface exposing the metrics in the Prometheus text format
+func (mr *MetricsRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := mr.WritePrometheus(w); err != nil {
+		Logger.Warning(fmt.Sprintf("<%s> error: %s writing the runtime metrics", CoreS, err.Error()))
+	}
+}
+
+// rpcMethods are the API methods registered on the Server
+// the other methods are recorded as MetricMethodUnknown in order to limit the label values
+var rpcMethods sync.Map // map[method]struct{}
+
+// registerRPCMethods adds the exported methods of the receiver to rpcMethods
+func registerRPCMethods(name string, rcvr interface{}) {
+	rcvType := reflect.TypeOf(rcvr)
+	if name == EmptyString {
+		name = reflect.Indirect(reflect.ValueOf(rcvr)).Type().Name()
+	}
+	for i := 0; i < rcvType.NumMethod(); i++ {
+		rpcMethods.Store(name+NestingSep+rcvType.Method(i).Name, struct{}{})
+	}
+}
+
+// rpcMethodLabel returns the label value for the method
+func rpcMethodLabel(method string) string {
+	if _, has := rpcMethods.Load(method); has {
+		return method
+	}
+	return MetricMethodUnknown
+}
+
+// rpcMetrics records the served RPC requests into the RuntimeMetrics
+type rpcMetrics struct{}
+
+// LogTraffic implements RPCAnalyzer interface
+func (rpcMetrics) LogTraffic(method string, _, _ interface{}, err error,
+	enc, _, _ string, outgoing bool, sTime, eTime time.Time) {
+	if outgoing { // not served by us
+		return
+	}
+	method = rpcMethodLabel(method)
+	RuntimeMetrics.IncCounter(MetricRPCRequests, method, enc)
+	if err != nil {
+		RuntimeMetrics.IncCounter(MetricRPCErrors, method)
+	}
+	RuntimeMetrics.ObserveHistogram(MetricRPCDuration, eTime.Sub(sTime).Seconds(), method)
+}
+
+// rpcAnalyzers passes the traffic to all the analyzers
+type rpcAnalyzers []RPCAnalyzer
+
+// LogTraffic implements RPCAnalyzer interface
+func (anzs rpcAnalyzers) LogTraffic(method string, params, reply interface{}, err error,
+	enc, from, to string, outgoing bool, sTime, eTime time.Time) {
+	for _, anz := range anzs {
+		anz.LogTraffic(method, params, reply, err, enc, from, to, outgoing, sTime, eTime)
+	}
+}
+
+// ArgsGetMetrics filters the runtime metrics
+type ArgsGetMetrics struct {
+	MetricNames []string // prefixes of the returned metric names, all if empty
+}
+
+// ArgsGetMetricsWithArgDispatcher used by CoreSv1.Metrics API
+type ArgsGetMetricsWithArgDispatcher struct {
+	*ArgDispatcher
+	TenantArg
+	ArgsGetMetrics
+}
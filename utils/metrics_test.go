@@ -0,0 +1,138 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package utils
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMetricsRegistryGetMetrics(t *testing.T) {
+	mr := NewMetricsRegistry()
+	mr.RegisterCounter("test_calls_total", "Calls.", MetricLabelMethod)
+	mr.RegisterGauge("test_conns", "Connections.")
+	mr.RegisterHistogram("test_duration_seconds", "Duration.", []float64{0.1, 1}, MetricLabelMethod)
+	mr.RegisterGaugeFunc("test_items", "Items.", func() []*MetricSample {
+		return []*MetricSample{{Labels: map[string]string{MetricLabelPartition: "*filters"}, Value: 3}}
+	})
+	mr.IncCounter("test_calls_total", CoreSv1Ping)
+	mr.AddCounter("test_calls_total", 2, CoreSv1Status)
+	mr.IncCounter("test_calls_total", CoreSv1Ping)
+	mr.IncCounter("test_calls_total") // wrong number of labels
+	mr.IncCounter("test_missing_total", CoreSv1Ping)
+	mr.SetGauge("test_conns", 5)
+	mr.SetGauge("test_conns", 4)
+	mr.ObserveHistogram("test_duration_seconds", 0.05, CoreSv1Ping)
+	mr.ObserveHistogram("test_duration_seconds", 0.5, CoreSv1Ping)
+	mr.ObserveHistogram("test_duration_seconds", 2, CoreSv1Ping)
+
+	exp := []*MetricFamily{
+		{Name: "test_calls_total", Help: "Calls.", Type: MetricCounter,
+			Samples: []*MetricSample{
+				{Labels: map[string]string{MetricLabelMethod: CoreSv1Ping}, Value: 2},
+				{Labels: map[string]string{MetricLabelMethod: CoreSv1Status}, Value: 2},
+			}},
+		{Name: "test_conns", Help: "Connections.", Type: MetricGauge,
+			Samples: []*MetricSample{{Value: 4}}},
+		{Name: "test_duration_seconds", Help: "Duration.", Type: MetricHistogram,
+			Samples: []*MetricSample{{
+				Labels: map[string]string{MetricLabelMethod: CoreSv1Ping},
+				Value:  2.55,
+				Count:  3,
+				Buckets: []*MetricBucket{
+					{UpperBound: 0.1, Count: 1},
+					{UpperBound: 1, Count: 2},
+				},
+			}}},
+		{Name: "test_items", Help: "Items.", Type: MetricGauge,
+			Samples: []*MetricSample{{Labels: map[string]string{MetricLabelPartition: "*filters"}, Value: 3}}},
+	}
+	if rcv := mr.GetMetrics(nil); !reflect.DeepEqual(exp, rcv) {
+		t.Errorf("Expected: %s, received: %s", ToJSON(exp), ToJSON(rcv))
+	}
+	if rcv := mr.GetMetrics([]string{"test_conns", "test_items"}); !reflect.DeepEqual(exp[1:2], rcv[:1]) || len(rcv) != 2 {
+		t.Errorf("Expected: %s, received: %s", ToJSON(exp[1:]), ToJSON(rcv))
+	}
+	mr.UnregisterMetric("test_items")
+	if rcv := mr.GetMetrics([]string{"test_items"}); len(rcv) != 0 {
+		t.Errorf("Expected no metrics, received: %s", ToJSON(rcv))
+	}
+}
+
+func TestMetricsRegistryServeHTTP(t *testing.T) {
+	mr := NewMetricsRegistry()
+	mr.RegisterCounter("test_calls_total", "Calls.", MetricLabelMethod, MetricLabelEncoding)
+	mr.RegisterHistogram("test_wait_seconds", "Wait.", []float64{0.5})
+	mr.IncCounter("test_calls_total", CoreSv1Ping, "*json\"")
+	mr.ObserveHistogram("test_wait_seconds", 0.25)
+	rec := httptest.NewRecorder()
+	mr.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	exp := `# HELP test_calls_total Calls.
+# TYPE test_calls_total counter
+test_calls_total{encoding="*json\"",method="CoreSv1.Ping"} 1
+# HELP test_wait_seconds Wait.
+# TYPE test_wait_seconds histogram
+test_wait_seconds_bucket{le="0.5"} 1
+test_wait_seconds_bucket{le="+Inf"} 1
+test_wait_seconds_sum 0.25
+test_wait_seconds_count 1
+`
+	if rcv := rec.Body.String(); rcv != exp {
+		t.Errorf("Expected:\n%s\nreceived:\n%s", exp, rcv)
+	}
+}
+
+type TestMetricsSv1 struct{}
+
+func (TestMetricsSv1) Ping(ign *CGREvent, reply *string) error {
+	*reply = Pong
+	return nil
+}
+
+func TestRPCMetricsLogTraffic(t *testing.T) {
+	sTime := time.Now()
+	registerRPCMethods(EmptyString, new(TestMetricsSv1))
+	rpcMetrics{}.LogTraffic("TestMetricsSv1.Ping", nil, nil, ErrNotFound,
+		MetaJSON, "127.0.0.1:1", "127.0.0.1:2", false, sTime, sTime.Add(time.Millisecond))
+	rpcMetrics{}.LogTraffic("TestMetricsSv1.Ping1", nil, nil, ErrNotFound,
+		MetaJSON, "127.0.0.1:1", "127.0.0.1:2", false, sTime, sTime.Add(time.Millisecond))
+	rpcMetrics{}.LogTraffic("TestMetricsSv1.Ping2", nil, nil, ErrNotFound,
+		MetaJSON, "127.0.0.1:1", "127.0.0.1:2", false, sTime, sTime.Add(time.Millisecond))
+	rpcMetrics{}.LogTraffic("TestMetricsSv1.Ping", nil, nil, nil, // outgoing requests are not counted
+		MetaJSON, "127.0.0.1:1", "127.0.0.1:2", true, sTime, sTime.Add(time.Millisecond))
+	var buf bytes.Buffer
+	if err := RuntimeMetrics.WritePrometheus(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, exp := range []string{
+		`cgrates_rpc_requests_total{encoding="*json",method="TestMetricsSv1.Ping"} 1`,
+		`cgrates_rpc_errors_total{method="TestMetricsSv1.Ping"} 1`,
+		`cgrates_rpc_request_duration_seconds_bucket{method="TestMetricsSv1.Ping",le="0.001"} 1`,
+		`cgrates_rpc_request_duration_seconds_count{method="TestMetricsSv1.Ping"} 1`,
+		`cgrates_rpc_requests_total{encoding="*json",method="unknown"} 2`,
+	} {
+		if !bytes.Contains(buf.Bytes(), []byte(exp)) {
+			t.Errorf("Expected %q in:\n%s", exp, buf.String())
+		}
+	}
+}
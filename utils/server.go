@@ -67,14 +67,60 @@ type Server struct {
 	httpsMux        *http.ServeMux
 	httpMux         *http.ServeMux
 	isDispatched    bool
+	anz             RPCAnalyzer // captures the RPC traffic when AnalyzerS is active
 }
 
 func (s *Server) SetDispatched() {
 	s.isDispatched = true
 }
 
+// SetAnalyzer sets the analyzer that will capture the RPC traffic, nil to disable it
+func (s *Server) SetAnalyzer(anz RPCAnalyzer) {
+	s.Lock()
+	s.anz = anz
+	s.Unlock()
+}
+
+// getAnalyzer returns the analyzer recording the runtime metrics, together with AnalyzerS if active
+func (s *Server) getAnalyzer() (anz RPCAnalyzer) {
+	s.RLock()
+	anz = s.anz
+	s.RUnlock()
+	if anz == nil {
+		return rpcMetrics{}
+	}
+	return rpcAnalyzers{rpcMetrics{}, anz}
+}
+
+// serverAnalyzer looks up the analyzer on each request so the connections opened
+// before AnalyzerS was enabled or disabled follow its state
+type serverAnalyzer struct {
+	s *Server
+}
+
+// LogTraffic implements RPCAnalyzer
+func (sa serverAnalyzer) LogTraffic(method string, params, reply interface{}, err error,
+	enc, from, to string, outgoing bool, sTime, eTime time.Time) {
+	sa.s.getAnalyzer().LogTraffic(method, params, reply, err,
+		enc, from, to, outgoing, sTime, eTime)
+}
+
+// newServerCodec wraps the codec so the RPC traffic is analyzed
+func (s *Server) newServerCodec(sc rpc.ServerCodec, enc, from, to string) rpc.ServerCodec {
+	return NewAnalyzerServerCodec(sc, serverAnalyzer{s}, enc, from, to)
+}
+
+// newJSONServerCodec returns the JSON codec based on dispatcher status
+func (s *Server) newJSONServerCodec(conn io.ReadWriteCloser) rpc.ServerCodec {
+	if s.isDispatched {
+		return NewCustomJSONServerCodec(conn)
+	}
+	return jsonrpc.NewServerCodec(conn)
+}
+
 func (s *Server) RpcRegister(rcvr interface{}) {
 	rpc.Register(rcvr)
+	registerRPCMethods(EmptyString, rcvr)
 	s.Lock()
 	s.rpcEnabled = true
 	s.Unlock()
@@ -82,6 +128,7 @@ func (s *Server) RpcRegister(rcvr interface{}) {
 
 func (s *Server) RpcRegisterName(name string, rcvr interface{}) {
 	rpc.RegisterName(name, rcvr)
+	registerRPCMethods(name, rcvr)
 	s.Lock()
 	s.rpcEnabled = true
 	s.Unlock()
@@ -122,6 +169,7 @@ func (s *Server) BiRPCRegisterName(method string, handlerFunc interface{}) {
 		s.Unlock()
 	}
 	s.birpcSrv.Handle(method, handlerFunc)
+	rpcMethods.Store(method, struct{}{})
 }
 
 func (s *Server) BiRPCRegister(rcvr interface{}) {
@@ -138,6 +186,7 @@ func (s *Server) BiRPCRegister(rcvr interface{}) {
 		method := rcvType.Method(i)
 		if method.Name != "Call" {
 			s.birpcSrv.Handle("SMGenericV1."+method.Name, method.Func.Interface())
+			rpcMethods.Store("SMGenericV1."+method.Name, struct{}{})
 		}
 	}
 }
@@ -173,11 +222,8 @@ func (s *Server) ServeJSON(addr string) {
 			continue
 		}
 		//utils.Logger.Info(fmt.Sprintf("<CGRServer> New incoming connection: %v", conn.RemoteAddr()))
-		if s.isDispatched {
-			go rpc.ServeCodec(NewCustomJSONServerCodec(conn))
-		} else {
-			go jsonrpc.ServeConn(conn)
-		}
+		go rpc.ServeCodec(s.newServerCodec(s.newJSONServerCodec(conn), MetaJSON,
+			conn.RemoteAddr().String(), conn.LocalAddr().String()))
 
 	}
 
@@ -214,14 +260,17 @@ func (s *Server) ServeGOB(addr string) {
 		}
 
 		//utils.Logger.Info(fmt.Sprintf("<CGRServer> New incoming connection: %v", conn.RemoteAddr()))
-		go rpc.ServeConn(conn)
+		go rpc.ServeCodec(s.newServerCodec(NewGobServerCodec(conn), MetaGOB,
+			conn.RemoteAddr().String(), conn.LocalAddr().String()))
 	}
 }
 
-func handleRequest(w http.ResponseWriter, r *http.Request) {
+func (s *Server) handleRequest(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	w.Header().Set("Content-Type", "application/json")
-	res := NewRPCRequest(r.Body).Call()
+	rpcReq := NewRPCRequest(r.Body)
+	res := rpcReq.CallCodec(s.newServerCodec(jsonrpc.NewServerCodec(rpcReq), MetaHTTPjson,
+		r.RemoteAddr, r.Host))
 	io.Copy(w, res)
 }
 
@@ -263,9 +312,9 @@ func (s *Server) ServeHTTP(addr string, jsonRPCURL string, wsRPCURL string,
 
 		Logger.Info("<HTTP> enabling handler for JSON-RPC")
 		if useBasicAuth {
-			s.httpMux.HandleFunc(jsonRPCURL, use(handleRequest, basicAuth(userList)))
+			s.httpMux.HandleFunc(jsonRPCURL, use(s.handleRequest, basicAuth(userList)))
 		} else {
-			s.httpMux.HandleFunc(jsonRPCURL, handleRequest)
+			s.httpMux.HandleFunc(jsonRPCURL, s.handleRequest)
 		}
 	}
 	if enabled && wsRPCURL != "" {
@@ -274,11 +323,8 @@ func (s *Server) ServeHTTP(addr string, jsonRPCURL string, wsRPCURL string,
 		s.Unlock()
 		Logger.Info("<HTTP> enabling handler for WebSocket connections")
 		wsHandler := websocket.Handler(func(ws *websocket.Conn) {
-			if s.isDispatched {
-				rpc.ServeCodec(NewCustomJSONServerCodec(ws))
-			} else {
-				jsonrpc.ServeConn(ws)
-			}
+			rpc.ServeCodec(s.newServerCodec(s.newJSONServerCodec(ws), MetaWSjson,
+				ws.Request().RemoteAddr, ws.Request().Host))
 		})
 		if useBasicAuth {
 			s.httpMux.HandleFunc(wsRPCURL, use(func(w http.ResponseWriter, r *http.Request) {
@@ -327,7 +373,8 @@ func (s *Server) ServeBiJSON(addr string, onConn func(*rpc2.Client), onDis func(
 				log.Fatal(err)
 				return // stop if we get Accept error
 			}
-			go s.birpcSrv.ServeCodec(rpc2_jsonrpc.NewJSONCodec(conn))
+			go s.birpcSrv.ServeCodec(NewAnalyzerBiRPCCodec(rpc2_jsonrpc.NewJSONCodec(conn),
+				serverAnalyzer{s}, MetaBiJSON, conn.RemoteAddr().String(), conn.LocalAddr().String()))
 		}
 	}(lBiJSON)
 	<-s.stopbiRPCServer // wait until server is stoped to close the listener
@@ -372,7 +419,12 @@ func (r *rpcRequest) Close() error {
 
 // Call invokes the RPC request, waits for it to complete, and returns the results.
 func (r *rpcRequest) Call() io.Reader {
-	go jsonrpc.ServeConn(r)
+	return r.CallCodec(jsonrpc.NewServerCodec(r))
+}
+
+// CallCodec invokes the RPC request using the codec built on top of this request
+func (r *rpcRequest) CallCodec(codec rpc.ServerCodec) io.Reader {
+	go rpc.ServeCodec(codec)
 	<-r.done
 	return r.rw
 }
@@ -453,7 +505,8 @@ func (s *Server) ServeGOBTLS(addr, serverCrt, serverKey, caCert string,
 			continue
 		}
 		//utils.Logger.Info(fmt.Sprintf("<CGRServer> New incoming connection: %v", conn.RemoteAddr()))
-		go rpc.ServeConn(conn)
+		go rpc.ServeCodec(s.newServerCodec(NewGobServerCodec(conn), MetaGOB,
+			conn.RemoteAddr().String(), conn.LocalAddr().String()))
 	}
 }
 
@@ -492,11 +545,8 @@ func (s *Server) ServeJSONTLS(addr, serverCrt, serverKey, caCert string,
 			}
 			continue
 		}
-		if s.isDispatched {
-			go rpc.ServeCodec(NewCustomJSONServerCodec(conn))
-		} else {
-			go jsonrpc.ServeConn(conn)
-		}
+		go rpc.ServeCodec(s.newServerCodec(s.newJSONServerCodec(conn), MetaJSON,
+			conn.RemoteAddr().String(), conn.LocalAddr().String()))
 	}
 }
 
@@ -516,9 +566,9 @@ func (s *Server) ServeHTTPTLS(addr, serverCrt, serverKey, caCert string, serverP
 		s.Unlock()
 		Logger.Info("<HTTPS> enabling handler for JSON-RPC")
 		if useBasicAuth {
-			s.httpsMux.HandleFunc(jsonRPCURL, use(handleRequest, basicAuth(userList)))
+			s.httpsMux.HandleFunc(jsonRPCURL, use(s.handleRequest, basicAuth(userList)))
 		} else {
-			s.httpsMux.HandleFunc(jsonRPCURL, handleRequest)
+			s.httpsMux.HandleFunc(jsonRPCURL, s.handleRequest)
 		}
 	}
 	if enabled && wsRPCURL != "" {
@@ -527,11 +577,8 @@ func (s *Server) ServeHTTPTLS(addr, serverCrt, serverKey, caCert string, serverP
 		s.Unlock()
 		Logger.Info("<HTTPS> enabling handler for WebSocket connections")
 		wsHandler := websocket.Handler(func(ws *websocket.Conn) {
-			if s.isDispatched {
-				rpc.ServeCodec(NewCustomJSONServerCodec(ws))
-			} else {
-				jsonrpc.ServeConn(ws)
-			}
+			rpc.ServeCodec(s.newServerCodec(s.newJSONServerCodec(ws), MetaWSjson,
+				ws.Request().RemoteAddr, ws.Request().Host))
 		})
 		if useBasicAuth {
 			s.httpsMux.HandleFunc(wsRPCURL, use(func(w http.ResponseWriter, r *http.Request) {
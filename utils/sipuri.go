@@ -0,0 +1,160 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SIPURI is the parsed representation of a SIP header value as: "Display" <sip:user@host:port;params>;header_params
+type SIPURI struct {
+	Display      string
+	Scheme       string
+	User         string
+	Host         string
+	Port         string
+	Params       map[string]string // URI parameters
+	HeaderParams map[string]string // parameters outside the angle brackets, ie: tag
+}
+
+// NewSIPURI parses the SIP header value into SIPURI
+func NewSIPURI(hdrVal string) (sipURI *SIPURI, err error) {
+	hdrVal = strings.TrimSpace(hdrVal)
+	sipURI = &SIPURI{
+		Params:       make(map[string]string),
+		HeaderParams: make(map[string]string),
+	}
+	uri := hdrVal
+	if idxOpen := strings.Index(hdrVal, "<"); idxOpen != -1 { // name-addr form
+		idxClose := strings.Index(hdrVal[idxOpen:], ">")
+		if idxClose == -1 {
+			return nil, fmt.Errorf("missing > in SIP URI: <%s>", hdrVal)
+		}
+		idxClose += idxOpen
+		sipURI.Display = strings.Trim(strings.TrimSpace(hdrVal[:idxOpen]), "\"")
+		uri = hdrVal[idxOpen+1 : idxClose]
+		parseSIPParams(hdrVal[idxClose+1:], sipURI.HeaderParams)
+	} else if idxParams := strings.Index(hdrVal, ";"); idxParams != -1 { // addr-spec form, params belong to header
+		uri = hdrVal[:idxParams]
+		parseSIPParams(hdrVal[idxParams:], sipURI.HeaderParams)
+	}
+	idxScheme := strings.Index(uri, ":")
+	if idxScheme == -1 {
+		return nil, fmt.Errorf("missing scheme in SIP URI: <%s>", hdrVal)
+	}
+	sipURI.Scheme = strings.ToLower(uri[:idxScheme])
+	uri = uri[idxScheme+1:]
+	if idxHdrs := strings.Index(uri, "?"); idxHdrs != -1 { // ignore URI headers
+		uri = uri[:idxHdrs]
+	}
+	if idxParams := strings.Index(uri, ";"); idxParams != -1 {
+		parseSIPParams(uri[idxParams:], sipURI.Params)
+		uri = uri[:idxParams]
+	}
+	if idxAt := strings.LastIndex(uri, "@"); idxAt != -1 {
+		sipURI.User = uri[:idxAt]
+		if idxPass := strings.Index(sipURI.User, ":"); idxPass != -1 { // strip password
+			sipURI.User = sipURI.User[:idxPass]
+		}
+		uri = uri[idxAt+1:]
+	}
+	sipURI.Host = uri
+	if strings.HasPrefix(uri, "[") { // IPv6 reference
+		if idxClose := strings.Index(uri, "]"); idxClose != -1 {
+			sipURI.Host = uri[:idxClose+1]
+			if strings.HasPrefix(uri[idxClose+1:], ":") {
+				sipURI.Port = uri[idxClose+2:]
+			}
+		}
+	} else if idxPort := strings.Index(uri, ":"); idxPort != -1 {
+		sipURI.Host, sipURI.Port = uri[:idxPort], uri[idxPort+1:]
+	}
+	if sipURI.Host == EmptyString {
+		return nil, fmt.Errorf("missing host in SIP URI: <%s>", hdrVal)
+	}
+	return
+}
+
+// parseSIPParams populates the params out of ;name=value;flag string
+func parseSIPParams(params string, out map[string]string) {
+	for _, param := range strings.Split(params, ";") {
+		if param = strings.TrimSpace(param); param == EmptyString {
+			continue
+		}
+		nameVal := strings.SplitN(param, "=", 2)
+		var val string
+		if len(nameVal) == 2 {
+			val = strings.Trim(nameVal[1], "\"")
+		}
+		out[strings.ToLower(nameVal[0])] = val
+	}
+}
+
+// URI returns the URI without display name and parameters
+func (sipURI *SIPURI) URI() (uri string) {
+	uri = sipURI.Scheme + InInFieldSep
+	if sipURI.User != EmptyString {
+		uri += sipURI.User + "@"
+	}
+	uri += sipURI.Host
+	if sipURI.Port != EmptyString {
+		uri += InInFieldSep + sipURI.Port
+	}
+	return
+}
+
+// FieldAsString returns the part of the SIP URI within the path
+// possible paths: user, host, port, display, scheme, uri or param followed by the parameter name
+func (sipURI *SIPURI) FieldAsString(fldPath []string) (val string, err error) {
+	if len(fldPath) == 0 {
+		return EmptyString, ErrNotFound
+	}
+	switch fldPath[0] {
+	case SIPURIUser:
+		val = sipURI.User
+	case SIPURIHost:
+		val = sipURI.Host
+	case SIPURIPort:
+		val = sipURI.Port
+	case SIPURIDisplay:
+		val = sipURI.Display
+	case SIPURIScheme:
+		val = sipURI.Scheme
+	case SIPURIURI:
+		val = sipURI.URI()
+	case SIPURIParam:
+		if len(fldPath) != 2 {
+			return EmptyString, ErrNotFound
+		}
+		var has bool
+		if val, has = sipURI.Params[strings.ToLower(fldPath[1])]; has {
+			return
+		}
+		if val, has = sipURI.HeaderParams[strings.ToLower(fldPath[1])]; !has {
+			return EmptyString, ErrNotFound
+		}
+		return
+	default:
+		return EmptyString, ErrNotFound
+	}
+	if len(fldPath) != 1 {
+		return EmptyString, ErrNotFound
+	}
+	return
+}
@@ -0,0 +1,126 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewSIPURI(t *testing.T) {
+	for hdrVal, eSIPURI := range map[string]*SIPURI{
+		"sip:1001@cgrates.org": {
+			Scheme: "sip", User: "1001", Host: "cgrates.org",
+			Params: map[string]string{}, HeaderParams: map[string]string{},
+		},
+		`"Alice Doe" <sips:1001:secret@cgrates.org:5061;transport=tcp;lr>;tag=1928301774`: {
+			Display: "Alice Doe", Scheme: "sips", User: "1001", Host: "cgrates.org", Port: "5061",
+			Params:       map[string]string{"transport": "tcp", "lr": ""},
+			HeaderParams: map[string]string{"tag": "1928301774"},
+		},
+		"<sip:+4986517174963@[2001:db8::10]:5060?Subject=call>": {
+			Scheme: "sip", User: "+4986517174963", Host: "[2001:db8::10]", Port: "5060",
+			Params: map[string]string{}, HeaderParams: map[string]string{},
+		},
+		"sip:10.0.0.1:5060;tag=abc": {
+			Scheme: "sip", Host: "10.0.0.1", Port: "5060",
+			Params: map[string]string{}, HeaderParams: map[string]string{"tag": "abc"},
+		},
+	} {
+		if sipURI, err := NewSIPURI(hdrVal); err != nil {
+			t.Errorf("header: <%s>, error: %s", hdrVal, err)
+		} else if !reflect.DeepEqual(eSIPURI, sipURI) {
+			t.Errorf("header: <%s>, expecting: %s, received: %s", hdrVal, ToJSON(eSIPURI), ToJSON(sipURI))
+		}
+	}
+	for _, hdrVal := range []string{"1001@cgrates.org", "<sip:1001@cgrates.org", "sip:1001@"} {
+		if _, err := NewSIPURI(hdrVal); err == nil {
+			t.Errorf("header: <%s>, expecting error", hdrVal)
+		}
+	}
+}
+
+func TestSIPURIFieldAsString(t *testing.T) {
+	sipURI, err := NewSIPURI(`"Alice" <sip:1001@cgrates.org:5060;transport=tcp>;tag=1928301774`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		fldPath []string
+		out     string
+		err     error
+	}{
+		{fldPath: []string{SIPURIUser}, out: "1001"},
+		{fldPath: []string{SIPURIHost}, out: "cgrates.org"},
+		{fldPath: []string{SIPURIPort}, out: "5060"},
+		{fldPath: []string{SIPURIDisplay}, out: "Alice"},
+		{fldPath: []string{SIPURIScheme}, out: "sip"},
+		{fldPath: []string{SIPURIURI}, out: "sip:1001@cgrates.org:5060"},
+		{fldPath: []string{SIPURIParam, "Transport"}, out: "tcp"},
+		{fldPath: []string{SIPURIParam, "tag"}, out: "1928301774"},
+		{fldPath: []string{SIPURIParam, "lr"}, err: ErrNotFound},
+		{fldPath: []string{SIPURIParam}, err: ErrNotFound},
+		{fldPath: []string{SIPURIUser, "extra"}, err: ErrNotFound},
+		{fldPath: []string{"password"}, err: ErrNotFound},
+		{fldPath: []string{}, err: ErrNotFound},
+	} {
+		if out, err := sipURI.FieldAsString(tc.fldPath); err != tc.err {
+			t.Errorf("path: %+v, expecting error: %v, received: %v", tc.fldPath, tc.err, err)
+		} else if out != tc.out {
+			t.Errorf("path: %+v, expecting: <%s>, received: <%s>", tc.fldPath, tc.out, out)
+		}
+	}
+}
+
+func TestSIPURIConverter(t *testing.T) {
+	if dc, err := NewDataConverter("*sipuri:param:tag"); err != nil {
+		t.Error(err)
+	} else if eDc := (&SIPURIConverter{FldPath: []string{SIPURIParam, "tag"}}); !reflect.DeepEqual(eDc, dc) {
+		t.Errorf("expecting: %+v, received: %+v", eDc, dc)
+	}
+	for _, params := range []string{MetaSIPURI, "*sipuri:password", "*sipuri:user:extra",
+		"*sipuri:param", "*sipuri:param:"} {
+		if _, err := NewDataConverter(params); err == nil {
+			t.Errorf("params: <%s>, expecting error", params)
+		}
+	}
+	for _, params := range []string{"*sipurix", "*sipurix:user"} {
+		if _, err := NewDataConverter(params); err == nil ||
+			err.Error() != "unsupported converter definition: <"+params+">" {
+			t.Errorf("params: <%s>, unexpected error: %v", params, err)
+		}
+	}
+	hdrVal := `"Alice" <sip:1001@cgrates.org;transport=tcp>;tag=1928301774`
+	for params, eOut := range map[string]string{
+		"*sipuri:user":            "1001",
+		"*sipuri:host":            "cgrates.org",
+		"*sipuri:port":            "",
+		"*sipuri:display":         "Alice",
+		"*sipuri:param:transport": "tcp",
+		"*sipuri:param:lr":        "",
+	} {
+		if out, err := NewDataConverterMustCompile(params).Convert(hdrVal); err != nil {
+			t.Errorf("params: <%s>, error: %s", params, err)
+		} else if out != eOut {
+			t.Errorf("params: <%s>, expecting: <%s>, received: <%v>", params, eOut, out)
+		}
+	}
+	if _, err := NewDataConverterMustCompile("*sipuri:user").Convert("1001"); err == nil {
+		t.Error("expecting error")
+	}
+}
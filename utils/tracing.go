@@ -0,0 +1,311 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"reflect"
+	"sync"
+	"time"
+)
+
+var (
+	tracer   *Tracer // active tracer, nil when the tracing is disabled
+	tracerLk sync.RWMutex
+
+	argDispatcherType = reflect.TypeOf((*ArgDispatcher)(nil))
+	argDispatcherIdxs sync.Map // reflect.Type -> []int, index of the ArgDispatcher field for each argument type
+)
+
+// SetTracer sets the tracer used by the ConnManager and DispatcherS, nil to disable the tracing
+func SetTracer(tr *Tracer) {
+	tracerLk.Lock()
+	tracer = tr
+	tracerLk.Unlock()
+}
+
+// GetTracer returns the active tracer or nil if the tracing is disabled
+func GetTracer() (tr *Tracer) {
+	tracerLk.RLock()
+	tr = tracer
+	tracerLk.RUnlock()
+	return
+}
+
+// StartSpan starts a span on the active tracer, continuing the trace received in args
+// returns nil if the tracing is disabled
+func StartSpan(name string, args interface{}) *Span {
+	tr := GetTracer()
+	if tr == nil {
+		return nil
+	}
+	return tr.StartSpan(name, args)
+}
+
+// SpanExporter sends the finished spans to their destination
+type SpanExporter interface {
+	ExportSpans(spans []*Span) error
+	Close() error
+}
+
+// NewTracer returns a Tracer buffering up to bufferSize spans and exporting them on each flushInterval
+func NewTracer(nodeID string, exp SpanExporter, bufferSize int,
+	flushInterval time.Duration) (tr *Tracer) {
+	tr = &Tracer{
+		nodeID:        nodeID,
+		exp:           exp,
+		spans:         make(chan *Span, bufferSize),
+		flushInterval: flushInterval,
+		stopChan:      make(chan struct{}),
+		doneChan:      make(chan struct{}),
+	}
+	go tr.exportLoop()
+	return
+}
+
+// Tracer creates the spans and exports them in batches
+type Tracer struct {
+	nodeID        string
+	exp           SpanExporter
+	spans         chan *Span
+	flushInterval time.Duration
+	stopChan      chan struct{}
+	doneChan      chan struct{}
+}
+
+// StartSpan starts a new span as child of the span found in args
+// a new trace is started if args do not carry one
+func (tr *Tracer) StartSpan(name string, args interface{}) (sp *Span) {
+	sp = &Span{
+		SpanID:    newSpanID(),
+		Name:      name,
+		NodeID:    tr.nodeID,
+		StartTime: time.Now(),
+		tracer:    tr,
+	}
+	if argD := getArgDispatcher(args); argD != nil && argD.TraceID != nil &&
+		*argD.TraceID != EmptyString {
+		sp.TraceID = *argD.TraceID
+		if argD.SpanID != nil {
+			sp.ParentSpanID = *argD.SpanID
+		}
+	} else {
+		sp.TraceID = newTraceID()
+	}
+	return
+}
+
+// Shutdown exports the buffered spans and closes the exporter
+func (tr *Tracer) Shutdown() {
+	close(tr.stopChan)
+	<-tr.doneChan
+}
+
+func (tr *Tracer) exportLoop() {
+	tm := time.NewTicker(tr.flushInterval)
+	defer func() {
+		tm.Stop()
+		if err := tr.exp.Close(); err != nil {
+			Logger.Warning(
+				"<" + Tracing + "> failed closing the exporter, error: " + err.Error())
+		}
+		close(tr.doneChan)
+	}()
+	for {
+		select {
+		case <-tr.stopChan:
+			tr.flush()
+			return
+		case <-tm.C:
+			tr.flush()
+		}
+	}
+}
+
+// flush exports the spans buffered until now
+func (tr *Tracer) flush() {
+	nrSpans := len(tr.spans)
+	if nrSpans == 0 {
+		return
+	}
+	spans := make([]*Span, nrSpans)
+	for i := range spans {
+		spans[i] = <-tr.spans
+	}
+	if err := tr.exp.ExportSpans(spans); err != nil {
+		Logger.Warning(
+			"<" + Tracing + "> failed exporting spans, error: " + err.Error())
+	}
+}
+
+// Span is one timed call, part of a distributed trace
+type Span struct {
+	TraceID      string
+	SpanID       string
+	ParentSpanID string `json:",omitempty"`
+	Name         string
+	NodeID       string
+	StartTime    time.Time
+	EndTime      time.Time
+	Attributes   map[string]string `json:",omitempty"`
+	Error        string            `json:",omitempty"`
+
+	tracer *Tracer
+}
+
+// SetAttribute adds an attribute to the span
+func (sp *Span) SetAttribute(key, val string) {
+	if sp == nil {
+		return
+	}
+	if sp.Attributes == nil {
+		sp.Attributes = make(map[string]string)
+	}
+	sp.Attributes[key] = val
+}
+
+// Inject returns a copy of args with the span set as parent in the ArgDispatcher so the next hop continues the trace
+// args are not modified since they can be shared with other goroutines
+// returns args unchanged if they do not carry the ArgDispatcher
+func (sp *Span) Inject(args interface{}) (traced interface{}) {
+	if sp == nil {
+		return args
+	}
+	v := reflect.ValueOf(args)
+	if v.Kind() != reflect.Ptr || v.IsNil() ||
+		v.Elem().Kind() != reflect.Struct {
+		return args
+	}
+	idx := argDispatcherIdx(v.Elem().Type())
+	if len(idx) == 0 {
+		return args
+	}
+	cp := reflect.New(v.Elem().Type()) // shallow copy, the structs on the path towards the field are copied as well
+	cp.Elem().Set(v.Elem())
+	fld := cp.Elem()
+	for i, fldIdx := range idx {
+		if i != 0 && fld.Kind() == reflect.Ptr { // the ArgDispatcher is promoted from an embedded struct
+			if fld.IsNil() || !fld.CanSet() {
+				return args
+			}
+			embCp := reflect.New(fld.Type().Elem())
+			embCp.Elem().Set(fld.Elem())
+			fld.Set(embCp)
+			fld = embCp.Elem()
+		}
+		fld = fld.Field(fldIdx)
+	}
+	if !fld.CanSet() {
+		return args
+	}
+	argD := new(ArgDispatcher)
+	if orig := fld.Interface().(*ArgDispatcher); orig != nil {
+		*argD = *orig
+	}
+	argD.TraceID = StringPointer(sp.TraceID)
+	argD.SpanID = StringPointer(sp.SpanID)
+	fld.Set(reflect.ValueOf(argD))
+	return cp.Interface()
+}
+
+// End finishes the span and queues it for export
+// the span is dropped if the export buffer is full
+func (sp *Span) End(err error) {
+	if sp == nil {
+		return
+	}
+	sp.EndTime = time.Now()
+	if err != nil {
+		sp.Error = err.Error()
+	}
+	select {
+	case sp.tracer.spans <- sp:
+	default:
+		RuntimeMetrics.IncCounter(MetricSpansDropped)
+	}
+}
+
+// getArgDispatcher returns the ArgDispatcher carried by args
+func getArgDispatcher(args interface{}) *ArgDispatcher {
+	if argD, canCast := args.(*ArgDispatcher); canCast {
+		return argD
+	}
+	fld, has := argDispatcherField(args)
+	if !has {
+		return nil
+	}
+	return fld.Interface().(*ArgDispatcher)
+}
+
+// argDispatcherField returns the settable ArgDispatcher field of args
+// args needs to be a pointer to a struct with the ArgDispatcher field, direct or embedded
+func argDispatcherField(args interface{}) (fld reflect.Value, has bool) {
+	v := reflect.ValueOf(args)
+	if v.Kind() != reflect.Ptr || v.IsNil() ||
+		v.Elem().Kind() != reflect.Struct {
+		return
+	}
+	v = v.Elem()
+	idx := argDispatcherIdx(v.Type())
+	if len(idx) == 0 {
+		return
+	}
+	for i, fldIdx := range idx {
+		if i != 0 { // the ArgDispatcher is promoted from an embedded struct
+			if v.Kind() == reflect.Ptr {
+				if v.IsNil() {
+					return
+				}
+				v = v.Elem()
+			}
+		}
+		v = v.Field(fldIdx)
+	}
+	return v, v.CanSet()
+}
+
+// argDispatcherIdx returns the index of the ArgDispatcher field within the struct type, empty if missing
+func argDispatcherIdx(typ reflect.Type) (idx []int) {
+	if x, cached := argDispatcherIdxs.Load(typ); cached {
+		return x.([]int)
+	}
+	if sf, has := typ.FieldByName(ArgDispatcherField); has &&
+		sf.Type == argDispatcherType {
+		idx = sf.Index
+	}
+	argDispatcherIdxs.Store(typ, idx)
+	return
+}
+
+// newTraceID returns a random 16 bytes trace ID, hex encoded
+func newTraceID() string {
+	return randomHexID(16)
+}
+
+// newSpanID returns a random 8 bytes span ID, hex encoded
+func newSpanID() string {
+	return randomHexID(8)
+}
+
+func randomHexID(size int) string {
+	b := make([]byte, size)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
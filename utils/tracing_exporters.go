@@ -0,0 +1,225 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	otlpSpanKindClient = 3
+	otlpStatusOK       = 1
+	otlpStatusError    = 2
+)
+
+// NewSpanExporter returns the exporter of the given type
+func NewSpanExporter(exporter, exportPath, nodeID string, timeout time.Duration) (SpanExporter, error) {
+	switch exporter {
+	case MetaFileJSON:
+		return NewSpanFileExporter(exportPath)
+	case MetaOTLPHTTP:
+		return NewSpanOTLPExporter(exportPath, nodeID, timeout), nil
+	default:
+		return nil, fmt.Errorf("unsupported span exporter: <%s>", exporter)
+	}
+}
+
+// NewSpanFileExporter returns the exporter writing the spans as JSON lines in the file at path
+func NewSpanFileExporter(path string) (exp *SpanFileExporter, err error) {
+	exp = new(SpanFileExporter)
+	if exp.fd, err = os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// SpanFileExporter writes one JSON encoded span per line
+type SpanFileExporter struct {
+	sync.Mutex
+	fd *os.File
+}
+
+// ExportSpans appends the spans to the file
+func (exp *SpanFileExporter) ExportSpans(spans []*Span) (err error) {
+	exp.Lock()
+	defer exp.Unlock()
+	w := bufio.NewWriter(exp.fd)
+	enc := json.NewEncoder(w)
+	for _, sp := range spans {
+		if err = enc.Encode(sp); err != nil {
+			return
+		}
+	}
+	return w.Flush()
+}
+
+// Close closes the file
+func (exp *SpanFileExporter) Close() error {
+	exp.Lock()
+	defer exp.Unlock()
+	return exp.fd.Close()
+}
+
+// NewSpanOTLPExporter returns the exporter posting the spans to an OTLP/HTTP collector
+// using the JSON encoding, ie: http://127.0.0.1:4318/v1/traces
+func NewSpanOTLPExporter(url, nodeID string, timeout time.Duration) *SpanOTLPExporter {
+	return &SpanOTLPExporter{
+		url:    url,
+		nodeID: nodeID,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// SpanOTLPExporter exports the spans to an OpenTelemetry collector
+type SpanOTLPExporter struct {
+	url    string
+	nodeID string
+	client *http.Client
+}
+
+// ExportSpans posts the spans to the collector
+func (exp *SpanOTLPExporter) ExportSpans(spans []*Span) (err error) {
+	var body []byte
+	if body, err = json.Marshal(exp.otlpRequest(spans)); err != nil {
+		return
+	}
+	var resp *http.Response
+	if resp, err = exp.client.Post(exp.url, "application/json", bytes.NewReader(body)); err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		rply, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, rply)
+	}
+	return
+}
+
+// Close is part of SpanExporter interface
+func (exp *SpanOTLPExporter) Close() error {
+	exp.client.CloseIdleConnections()
+	return nil
+}
+
+// otlpRequest builds the ExportTraceServiceRequest in its JSON form
+func (exp *SpanOTLPExporter) otlpRequest(spans []*Span) (req *otlpTraceRequest) {
+	oSpans := make([]*otlpSpan, len(spans))
+	for i, sp := range spans {
+		oSpans[i] = &otlpSpan{
+			TraceID:           sp.TraceID,
+			SpanID:            sp.SpanID,
+			ParentSpanID:      sp.ParentSpanID,
+			Name:              sp.Name,
+			Kind:              otlpSpanKindClient,
+			StartTimeUnixNano: strconv.FormatInt(sp.StartTime.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(sp.EndTime.UnixNano(), 10),
+			Attributes:        otlpAttributes(sp.Attributes),
+			Status:            otlpStatus{Code: otlpStatusOK},
+		}
+		if sp.Error != EmptyString {
+			oSpans[i].Status = otlpStatus{Code: otlpStatusError, Message: sp.Error}
+		}
+	}
+	return &otlpTraceRequest{
+		ResourceSpans: []*otlpResourceSpans{{
+			Resource: otlpResource{
+				Attributes: otlpAttributes(map[string]string{
+					"service.name":        CGRateS,
+					"service.instance.id": exp.nodeID,
+				}),
+			},
+			ScopeSpans: []*otlpScopeSpans{{
+				Scope: otlpScope{Name: CGRateS},
+				Spans: oSpans,
+			}},
+		}},
+	}
+}
+
+func otlpAttributes(attrs map[string]string) (oAttrs []*otlpKeyValue) {
+	if len(attrs) == 0 {
+		return
+	}
+	oAttrs = make([]*otlpKeyValue, 0, len(attrs))
+	keys := MapKeys(attrs)
+	sort.Strings(keys) // keep the output stable
+	for _, key := range keys {
+		oAttrs = append(oAttrs, &otlpKeyValue{Key: key,
+			Value: otlpAnyValue{StringValue: attrs[key]}})
+	}
+	return
+}
+
+type otlpTraceRequest struct {
+	ResourceSpans []*otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource      `json:"resource"`
+	ScopeSpans []*otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []*otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope   `json:"scope"`
+	Spans []*otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []*otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
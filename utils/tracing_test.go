@@ -0,0 +1,220 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package utils
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testSpanExporter struct {
+	spans  []*Span
+	closed bool
+}
+
+func (exp *testSpanExporter) ExportSpans(spans []*Span) error {
+	exp.spans = append(exp.spans, spans...)
+	return nil
+}
+
+func (exp *testSpanExporter) Close() error {
+	exp.closed = true
+	return nil
+}
+
+func TestTracerStartSpan(t *testing.T) {
+	tr := &Tracer{nodeID: "node1"}
+	args := &CGREventWithArgDispatcher{CGREvent: &CGREvent{Tenant: "cgrates.org"}}
+	root := tr.StartSpan(SessionSv1AuthorizeEvent, args)
+	if len(root.TraceID) != 32 || len(root.SpanID) != 16 ||
+		root.ParentSpanID != EmptyString || root.NodeID != "node1" {
+		t.Errorf("Unexpected span: %s", ToJSON(root))
+	}
+	args.ArgDispatcher = &ArgDispatcher{
+		APIKey:  StringPointer("sup12345"),
+		TraceID: StringPointer(root.TraceID),
+		SpanID:  StringPointer(root.SpanID),
+	}
+	child := tr.StartSpan(AttributeSv1ProcessEvent, args)
+	if child.TraceID != root.TraceID || child.ParentSpanID != root.SpanID ||
+		child.SpanID == root.SpanID {
+		t.Errorf("Unexpected span: %s", ToJSON(child))
+	}
+	if sp := StartSpan(AttributeSv1ProcessEvent, args); sp != nil {
+		t.Errorf("Expected no span with tracing disabled, received: %s", ToJSON(sp))
+	}
+}
+
+func TestSpanInject(t *testing.T) {
+	sp := &Span{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"}
+	argD := &ArgDispatcher{APIKey: StringPointer("sup12345")}
+	args := &CGREventWithArgDispatcher{ArgDispatcher: argD, CGREvent: &CGREvent{Tenant: "cgrates.org"}}
+	traced, canCast := sp.Inject(args).(*CGREventWithArgDispatcher)
+	if !canCast {
+		t.Fatalf("Unexpected args: %+v", traced)
+	}
+	exp := &ArgDispatcher{
+		APIKey:  StringPointer("sup12345"),
+		TraceID: StringPointer(sp.TraceID),
+		SpanID:  StringPointer(sp.SpanID),
+	}
+	if !reflect.DeepEqual(exp, traced.ArgDispatcher) {
+		t.Errorf("Expected: %s, received: %s", ToJSON(exp), ToJSON(traced.ArgDispatcher))
+	}
+	if traced.CGREvent != args.CGREvent {
+		t.Errorf("Expected the same CGREvent, received: %s", ToJSON(traced.CGREvent))
+	}
+	if args.ArgDispatcher != argD || argD.TraceID != nil { // the original args are not modified
+		t.Errorf("Unexpected ArgDispatcher: %s", ToJSON(args.ArgDispatcher))
+	}
+
+	// ArgDispatcher promoted from an embedded struct
+	argsTnt := &ArgsGetMetricsWithArgDispatcher{}
+	if tracedTnt := sp.Inject(argsTnt).(*ArgsGetMetricsWithArgDispatcher); tracedTnt.ArgDispatcher == nil ||
+		*tracedTnt.ArgDispatcher.SpanID != sp.SpanID {
+		t.Errorf("Unexpected ArgDispatcher: %s", ToJSON(tracedTnt.ArgDispatcher))
+	}
+	if argsTnt.ArgDispatcher != nil {
+		t.Errorf("Expected nil ArgDispatcher, received: %s", ToJSON(argsTnt.ArgDispatcher))
+	}
+	embArgs := &struct{ *CGREventWithArgDispatcher }{&CGREventWithArgDispatcher{}}
+	if tracedEmb := sp.Inject(embArgs).(*struct{ *CGREventWithArgDispatcher }); tracedEmb.ArgDispatcher == nil ||
+		tracedEmb.CGREventWithArgDispatcher == embArgs.CGREventWithArgDispatcher {
+		t.Errorf("Unexpected args: %s", ToJSON(tracedEmb))
+	}
+	if embArgs.ArgDispatcher != nil {
+		t.Errorf("Expected nil ArgDispatcher, received: %s", ToJSON(embArgs.ArgDispatcher))
+	}
+
+	// arguments without ArgDispatcher are returned unchanged
+	for _, args := range []interface{}{nil, "string", &CGREvent{},
+		&struct{ *CGREventWithArgDispatcher }{}} {
+		if rcv := sp.Inject(args); rcv != args {
+			t.Errorf("Expected: %+v, received: %+v", args, rcv)
+		}
+	}
+	var nilSpan *Span
+	if rcv := nilSpan.Inject(args); rcv != args {
+		t.Errorf("Expected: %+v, received: %+v", args, rcv)
+	}
+	nilSpan.SetAttribute(SpanAttrConnID, MetaInternal)
+	nilSpan.End(nil)
+}
+
+func TestTracerExport(t *testing.T) {
+	exp := new(testSpanExporter)
+	tr := NewTracer("node1", exp, 2, time.Hour)
+	for i := 0; i < 3; i++ {
+		sp := tr.StartSpan(CoreSv1Status, nil)
+		sp.SetAttribute(SpanAttrConnID, MetaInternal)
+		sp.End(ErrNotFound)
+	}
+	tr.Shutdown()
+	if !exp.closed {
+		t.Error("Expected the exporter to be closed")
+	}
+	if len(exp.spans) != 2 { // the last one was dropped since the buffer is full
+		t.Fatalf("Expected 2 spans, received: %s", ToJSON(exp.spans))
+	}
+	if sp := exp.spans[0]; sp.Error != ErrNotFound.Error() || sp.EndTime.IsZero() ||
+		sp.Attributes[SpanAttrConnID] != MetaInternal {
+		t.Errorf("Unexpected span: %s", ToJSON(sp))
+	}
+}
+
+func TestSpanFileExporter(t *testing.T) {
+	fPath := path.Join(os.TempDir(), "cgr_traces_test.json")
+	defer os.Remove(fPath)
+	exp, err := NewSpanExporter(MetaFileJSON, fPath, "node1", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spans := []*Span{
+		{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7", Name: CoreSv1Status},
+		{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b8",
+			ParentSpanID: "00f067aa0ba902b7", Name: CoreSv1Ping},
+	}
+	if err = exp.ExportSpans(spans); err != nil {
+		t.Fatal(err)
+	}
+	if err = exp.Close(); err != nil {
+		t.Fatal(err)
+	}
+	content, err := ioutil.ReadFile(fPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, received: %q", content)
+	}
+	var rcv Span
+	if err = json.Unmarshal([]byte(lines[1]), &rcv); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*spans[1], rcv) {
+		t.Errorf("Expected: %s, received: %s", ToJSON(spans[1]), ToJSON(rcv))
+	}
+}
+
+func TestSpanOTLPExporter(t *testing.T) {
+	var rcv map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&rcv)
+	}))
+	defer srv.Close()
+	exp, err := NewSpanExporter(MetaOTLPHTTP, srv.URL+"/v1/traces", "node1", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sTime := time.Date(2020, 4, 1, 10, 0, 0, 0, time.UTC)
+	if err = exp.ExportSpans([]*Span{{
+		TraceID:      "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanID:       "00f067aa0ba902b8",
+		ParentSpanID: "00f067aa0ba902b7",
+		Name:         CoreSv1Ping,
+		StartTime:    sTime,
+		EndTime:      sTime.Add(time.Millisecond),
+		Attributes:   map[string]string{SpanAttrConnID: MetaInternal},
+		Error:        ErrNotFound.Error(),
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	exp.Close()
+	expBody := `{"resourceSpans":[{"resource":{"attributes":[{"key":"service.instance.id","value":{"stringValue":"node1"}},{"key":"service.name","value":{"stringValue":"CGRateS"}}]},"scopeSpans":[{"scope":{"name":"CGRateS"},"spans":[{"attributes":[{"key":"cgrates.conn_id","value":{"stringValue":"*internal"}}],"endTimeUnixNano":"1585735200001000000","kind":3,"name":"CoreSv1.Ping","parentSpanId":"00f067aa0ba902b7","spanId":"00f067aa0ba902b8","startTimeUnixNano":"1585735200000000000","status":{"code":2,"message":"NOT_FOUND"},"traceId":"4bf92f3577b34da6a3ce929d0e0e4736"}]}]}]}`
+	if body := ToJSON(rcv); strings.Join(strings.Fields(body), "") != expBody {
+		t.Errorf("Expected: %s, received: %s", expBody, body)
+	}
+	if _, err = NewSpanExporter(MetaHTTPPost, srv.URL, "node1", time.Second); err == nil ||
+		err.Error() != "unsupported span exporter: <*http_post>" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}